@@ -0,0 +1,106 @@
+package geoip
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Locator tells roughly where an IP address is
+type Locator interface {
+	Locate(ip string) (country string, region string, ok bool)
+}
+
+type ipRange struct {
+	start   netip.Addr
+	end     netip.Addr
+	country string
+	region  string
+}
+
+// Database is a GeoIP database read from a CSV file whose rows are
+// start_ip,end_ip,country,region, the region being optional
+type Database struct {
+	ranges []ipRange
+}
+
+// Load reads the database at path into memory
+func Load(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Read parses a database. A header row and comment rows starting with # are
+// skipped.
+func Read(r io.Reader) (*Database, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+
+	db := &Database{}
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rec) < 3 {
+			return nil, fmt.Errorf("line %d: expected start_ip,end_ip,country[,region]", line)
+		}
+
+		start, err := netip.ParseAddr(strings.TrimSpace(rec[0]))
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		end, err := netip.ParseAddr(strings.TrimSpace(rec[1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if start.Is4() != end.Is4() || end.Less(start) {
+			return nil, fmt.Errorf("line %d: invalid range %s-%s", line, start, end)
+		}
+
+		ipr := ipRange{start: start, end: end, country: strings.ToUpper(strings.TrimSpace(rec[2]))}
+		if len(rec) > 3 {
+			ipr.region = strings.TrimSpace(rec[3])
+		}
+		db.ranges = append(db.ranges, ipr)
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool {
+		return db.ranges[i].start.Less(db.ranges[j].start)
+	})
+	return db, nil
+}
+
+// Locate finds the range holding the address by binary search
+func (d *Database) Locate(ip string) (string, string, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", "", false
+	}
+	addr = addr.Unmap()
+
+	// the last range starting at or before the address
+	i := sort.Search(len(d.ranges), func(i int) bool {
+		return addr.Less(d.ranges[i].start)
+	}) - 1
+	if i < 0 || d.ranges[i].end.Less(addr) || d.ranges[i].start.Is4() != addr.Is4() {
+		return "", "", false
+	}
+	return d.ranges[i].country, d.ranges[i].region, true
+}
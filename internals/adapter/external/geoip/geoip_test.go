@@ -0,0 +1,42 @@
+package geoip
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocate(t *testing.T) {
+	db, err := Read(strings.NewReader(`start_ip,end_ip,country,region
+# private ranges are left out
+81.2.69.0,81.2.69.255,gb,England
+1.0.0.0,1.0.0.255,AU
+2001:db8::,2001:db8::ffff,DE,Berlin
+`))
+	if err != nil {
+		t.Fatalf("expected %v, got %v", nil, err)
+	}
+
+	tests := []struct {
+		ip      string
+		country string
+		region  string
+		ok      bool
+	}{
+		{ip: "81.2.69.142", country: "GB", region: "England", ok: true},
+		{ip: "::ffff:81.2.69.1", country: "GB", region: "England", ok: true},
+		{ip: "1.0.0.0", country: "AU", ok: true},
+		{ip: "2001:db8::1", country: "DE", region: "Berlin", ok: true},
+		{ip: "81.2.70.1"},
+		{ip: "0.0.0.1"},
+		{ip: "not-an-ip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			country, region, ok := db.Locate(tt.ip)
+			if country != tt.country || region != tt.region || ok != tt.ok {
+				t.Fatalf("expected %v %v %v, got %v %v %v", tt.country, tt.region, tt.ok, country, region, ok)
+			}
+		})
+	}
+}
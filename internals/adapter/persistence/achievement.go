@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/achievement"
+	"typing-speed/internals/core/typing"
+
+	"github.com/lib/pq"
+)
+
+type AchievementRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewAchievementRepository(db *sql.DB) port.AchievementRepository {
+	return &AchievementRepositoryImpl{
+		db: db,
+	}
+}
+
+// GetAchievementStats reads every metric the achievement rules look at
+func (r *AchievementRepositoryImpl) GetAchievementStats(ctx context.Context, email string) (*achievement.Stats, error) {
+	query := `
+		SELECT u.total_test, u.best_speed, u.longest_streak, u.level,
+		       (SELECT COUNT(*)
+		        FROM user_typing_data t
+		        WHERE t.email = u.email
+		          AND t.mode = '` + typing.Mode60s + `'
+		          AND t.total_words > 0
+		          AND t.typed_words - t.total_error >= t.total_words) AS perfect_tests
+		FROM users u
+		WHERE u.email = $1;
+	`
+
+	stats := &achievement.Stats{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, email).Scan(
+		&stats.TotalTests,
+		&stats.BestWPM,
+		&stats.LongestStreak,
+		&stats.Level,
+		&stats.PerfectTests,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &achievement.Stats{}, nil
+		}
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *AchievementRepositoryImpl) GetUnlockedAchievements(ctx context.Context, email string) ([]*achievement.Unlocked, error) {
+	query := `
+		SELECT code, unlocked_at
+		FROM user_achievements
+		WHERE email = $1
+		ORDER BY unlocked_at;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var unlocked []*achievement.Unlocked
+
+	for rows.Next() {
+		u := &achievement.Unlocked{}
+		if err := rows.Scan(&u.Code, &u.UnlockedAt); err != nil {
+			return nil, err
+		}
+		unlocked = append(unlocked, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return unlocked, nil
+}
+
+// InsertUnlockedAchievements stores new unlocks, ignoring ones the user already has
+func (r *AchievementRepositoryImpl) InsertUnlockedAchievements(ctx context.Context, email string, codes []string, unlockedAt time.Time) error {
+	query := `
+		INSERT INTO user_achievements (email, code, unlocked_at)
+		SELECT $1, code, $3
+		FROM unnest($2::text[]) AS code
+		ON CONFLICT (email, code) DO NOTHING;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, email, pq.Array(codes), unlockedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAchievementStats_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{
+		"total_test", "best_speed", "longest_streak", "level", "perfect_tests",
+	}).AddRow(120, 101, 8, 4, 2)
+
+	mock.ExpectQuery("SELECT (.+) AND t.mode = '60s' (.+) FROM users u WHERE u.email =").
+		WithArgs("test@test.com").
+		WillReturnRows(rows)
+
+	repo := NewAchievementRepository(db)
+	stats, err := repo.GetAchievementStats(context.Background(), "test@test.com")
+
+	require.NoError(t, err)
+	assert.Equal(t, 120, stats.TotalTests)
+	assert.Equal(t, 101, stats.BestWPM)
+	assert.Equal(t, 8, stats.LongestStreak)
+	assert.Equal(t, 4, stats.Level)
+	assert.Equal(t, 2, stats.PerfectTests)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertUnlockedAchievements_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	codes := []string{"wpm_50", "wpm_100"}
+
+	mock.ExpectExec("INSERT INTO user_achievements").
+		WithArgs("test@test.com", pq.Array(codes), now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewAchievementRepository(db)
+	err = repo.InsertUnlockedAchievements(context.Background(), "test@test.com", codes, now)
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
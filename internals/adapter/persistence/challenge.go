@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/challenge"
+	"typing-speed/internals/core/user"
+)
+
+type ChallengeRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewChallengeRepository(db *sql.DB) port.ChallengeRepository {
+	return &ChallengeRepositoryImpl{
+		db: db,
+	}
+}
+
+// publicName is the name of user u, hidden unless their profile is public
+const publicName = `CASE WHEN u.privacy = 'public' THEN u.name ELSE '` + user.AnonymousName + `' END`
+
+// GetChallengeLeaderboard ranks the first attempt of every user at the challenge of date
+func (r *ChallengeRepositoryImpl) GetChallengeLeaderboard(ctx context.Context, date string, limit int) ([]*challenge.LeaderboardEntry, error) {
+	query := `
+		SELECT ` + publicName + `, f.wpm, f.accuracy, f.created_at
+		FROM (
+			SELECT DISTINCT ON (email)
+			       email, wpm, created_at,
+			       CASE WHEN total_words = 0 THEN 0
+			            ELSE (typed_words - total_error) * 100 / total_words
+			       END AS accuracy
+			FROM user_typing_data
+			WHERE challenge_date = $1
+			ORDER BY email, created_at ASC
+		) f
+		JOIN users u ON u.email = f.email
+		ORDER BY f.wpm DESC, f.accuracy DESC, f.created_at ASC
+		LIMIT $2;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, date, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*challenge.LeaderboardEntry{}
+
+	for rows.Next() {
+		e := &challenge.LeaderboardEntry{Rank: len(entries) + 1}
+		if err := rows.Scan(&e.Name, &e.WPM, &e.Accuracy, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetChallengeHistory summarises the challenges held before the given date, newest first
+func (r *ChallengeRepositoryImpl) GetChallengeHistory(ctx context.Context, before string, limit int) ([]*challenge.Summary, error) {
+	query := `
+		WITH first_attempts AS (
+			SELECT DISTINCT ON (challenge_date, email)
+			       challenge_date, email, wpm, created_at
+			FROM user_typing_data
+			WHERE challenge_date IS NOT NULL AND challenge_date < $1
+			ORDER BY challenge_date, email, created_at ASC
+		), ranked AS (
+			SELECT challenge_date, email, wpm,
+			       COUNT(*) OVER (PARTITION BY challenge_date) AS participants,
+			       ROW_NUMBER() OVER (PARTITION BY challenge_date ORDER BY wpm DESC, created_at ASC) AS position
+			FROM first_attempts
+		)
+		SELECT r.challenge_date, r.participants, ` + publicName + `, r.wpm
+		FROM ranked r
+		JOIN users u ON u.email = r.email
+		WHERE r.position = 1
+		ORDER BY r.challenge_date DESC
+		LIMIT $2;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []*challenge.Summary{}
+
+	for rows.Next() {
+		var date time.Time
+		s := &challenge.Summary{}
+		if err := rows.Scan(&date, &s.Participants, &s.WinnerName, &s.WinnerWPM); err != nil {
+			return nil, err
+		}
+		s.Date = challenge.DateKey(date)
+		summaries = append(summaries, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
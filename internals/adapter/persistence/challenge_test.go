@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChallengeLeaderboard_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"name", "wpm", "accuracy", "created_at"}).
+		AddRow("Navneet", 95, 98, now).
+		AddRow("John", 80, 99, now)
+
+	mock.ExpectQuery("SELECT (.+) FROM user_typing_data WHERE challenge_date = (.+) ORDER BY email, created_at ASC").
+		WithArgs("2026-10-19", 50).
+		WillReturnRows(rows)
+
+	repo := NewChallengeRepository(db)
+	data, err := repo.GetChallengeLeaderboard(context.Background(), "2026-10-19", 50)
+
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+	assert.Equal(t, 1, data[0].Rank)
+	assert.Equal(t, "Navneet", data[0].Name)
+	assert.Equal(t, 2, data[1].Rank)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChallengeHistory_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	date := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"challenge_date", "participants", "name", "wpm"}).
+		AddRow(date, 12, "Navneet", 110)
+
+	mock.ExpectQuery("WITH first_attempts AS").
+		WithArgs("2026-10-19", 30).
+		WillReturnRows(rows)
+
+	repo := NewChallengeRepository(db)
+	data, err := repo.GetChallengeHistory(context.Background(), "2026-10-19", 30)
+
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.Equal(t, "2026-10-18", data[0].Date)
+	assert.Equal(t, 12, data[0].Participants)
+	assert.Equal(t, "Navneet", data[0].WinnerName)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,358 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/classroom"
+)
+
+type ClassRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewClassRepository(db *sql.DB) port.ClassRepository {
+	return &ClassRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *ClassRepositoryImpl) CreateClass(ctx context.Context, c *classroom.Class) error {
+	query := `
+		INSERT INTO classes (id, name, teacher, join_code)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at;
+	`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, c.ID, c.Name, c.Teacher, c.JoinCode).Scan(&c.CreatedAt)
+}
+
+const classColumns = `c.id, c.name, c.teacher, u.name, c.join_code, c.created_at,
+			(SELECT COUNT(*) FROM class_students cs WHERE cs.class_id = c.id)`
+
+func scanClass(row rowScanner, c *classroom.Class, extra ...any) error {
+	dest := append([]any{&c.ID, &c.Name, &c.Teacher, &c.TeacherName, &c.JoinCode, &c.CreatedAt, &c.Students}, extra...)
+	return row.Scan(dest...)
+}
+
+// GetClass returns nil when there is no such class
+func (r *ClassRepositoryImpl) GetClass(ctx context.Context, id string) (*classroom.Class, error) {
+	query := `
+		SELECT ` + classColumns + `
+		FROM classes c
+		JOIN users u ON u.email = c.teacher
+		WHERE c.id = $1;
+	`
+
+	return r.getClass(ctx, query, id)
+}
+
+// GetClassByJoinCode returns nil when no class has the code
+func (r *ClassRepositoryImpl) GetClassByJoinCode(ctx context.Context, code string) (*classroom.Class, error) {
+	query := `
+		SELECT ` + classColumns + `
+		FROM classes c
+		JOIN users u ON u.email = c.teacher
+		WHERE c.join_code = $1;
+	`
+
+	return r.getClass(ctx, query, code)
+}
+
+func (r *ClassRepositoryImpl) getClass(ctx context.Context, query string, args ...any) (*classroom.Class, error) {
+	c := &classroom.Class{}
+	err := scanClass(conn(ctx, r.db).QueryRowContext(ctx, query, args...), c)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// GetClassesByEmail returns the classes the user teaches or is enrolled
+// in, with their role in each, latest first
+func (r *ClassRepositoryImpl) GetClassesByEmail(ctx context.Context, email string) ([]*classroom.Class, error) {
+	query := `
+		SELECT ` + classColumns + `,
+			CASE WHEN c.teacher = $1 THEN 'teacher' ELSE 'student' END
+		FROM classes c
+		JOIN users u ON u.email = c.teacher
+		WHERE c.teacher = $1
+			OR EXISTS (SELECT 1 FROM class_students cs WHERE cs.class_id = c.id AND cs.email = $1)
+		ORDER BY c.created_at DESC;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	classes := []*classroom.Class{}
+	for rows.Next() {
+		c := &classroom.Class{}
+		if err := scanClass(rows, c, &c.Role); err != nil {
+			return nil, err
+		}
+		classes = append(classes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return classes, nil
+}
+
+// DeleteClass drops its students, assignments and submissions with it
+func (r *ClassRepositoryImpl) DeleteClass(ctx context.Context, id string) error {
+	query := `
+		DELETE FROM classes
+		WHERE id = $1;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+// AddStudent reports false when the user is already enrolled
+func (r *ClassRepositoryImpl) AddStudent(ctx context.Context, id string, email string) (bool, error) {
+	query := `
+		INSERT INTO class_students (class_id, email)
+		VALUES ($1, $2)
+		ON CONFLICT (class_id, email) DO NOTHING;
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, email)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// GetStudent returns nil when the user is not enrolled
+func (r *ClassRepositoryImpl) GetStudent(ctx context.Context, id string, email string) (*classroom.Student, error) {
+	query := `
+		SELECT u.name, cs.email, cs.joined_at
+		FROM class_students cs
+		JOIN users u ON u.email = cs.email
+		WHERE cs.class_id = $1 AND cs.email = $2;
+	`
+
+	s := &classroom.Student{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id, email).Scan(&s.Name, &s.Email, &s.JoinedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// GetStudents returns the students of the class by name
+func (r *ClassRepositoryImpl) GetStudents(ctx context.Context, id string) ([]*classroom.Student, error) {
+	query := `
+		SELECT u.name, cs.email, cs.joined_at
+		FROM class_students cs
+		JOIN users u ON u.email = cs.email
+		WHERE cs.class_id = $1
+		ORDER BY u.name, cs.email;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := []*classroom.Student{}
+	for rows.Next() {
+		s := &classroom.Student{}
+		if err := rows.Scan(&s.Name, &s.Email, &s.JoinedAt); err != nil {
+			return nil, err
+		}
+		students = append(students, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return students, nil
+}
+
+// RemoveStudent keeps the student's submissions. It reports false when the
+// user was not enrolled.
+func (r *ClassRepositoryImpl) RemoveStudent(ctx context.Context, id string, email string) (bool, error) {
+	query := `
+		DELETE FROM class_students
+		WHERE class_id = $1 AND email = $2;
+	`
+
+	return deleted(ctx, r.db, query, id, email)
+}
+
+func (r *ClassRepositoryImpl) CreateAssignment(ctx context.Context, a *classroom.Assignment) error {
+	query := `
+		INSERT INTO class_assignments (id, class_id, title, mode, text, min_accuracy, due_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at;
+	`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, a.ID, a.ClassID, a.Title, a.Mode, a.Text, a.MinAccuracy,
+		a.DueAt).Scan(&a.CreatedAt)
+}
+
+const assignmentColumns = `id, class_id, title, mode, text, min_accuracy, due_at, created_at`
+
+func scanAssignment(row rowScanner, a *classroom.Assignment) error {
+	return row.Scan(&a.ID, &a.ClassID, &a.Title, &a.Mode, &a.Text, &a.MinAccuracy, &a.DueAt, &a.CreatedAt)
+}
+
+// GetAssignment returns nil when the class has no such assignment
+func (r *ClassRepositoryImpl) GetAssignment(ctx context.Context, id string, assignmentID string) (*classroom.Assignment, error) {
+	query := `
+		SELECT ` + assignmentColumns + `
+		FROM class_assignments
+		WHERE class_id = $1 AND id = $2;
+	`
+
+	a := &classroom.Assignment{}
+	err := scanAssignment(conn(ctx, r.db).QueryRowContext(ctx, query, id, assignmentID), a)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// GetAssignments returns the assignments of the class, soonest due first
+func (r *ClassRepositoryImpl) GetAssignments(ctx context.Context, id string) ([]*classroom.Assignment, error) {
+	query := `
+		SELECT ` + assignmentColumns + `
+		FROM class_assignments
+		WHERE class_id = $1
+		ORDER BY due_at;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []*classroom.Assignment{}
+	for rows.Next() {
+		a := &classroom.Assignment{}
+		if err := scanAssignment(rows, a); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// GetCompletedAssignments returns the IDs of the assignments of the class
+// the student has passed
+func (r *ClassRepositoryImpl) GetCompletedAssignments(ctx context.Context, id string, email string) ([]string, error) {
+	query := `
+		SELECT DISTINCT a.id
+		FROM class_assignments a
+		JOIN class_submissions s ON s.assignment_id = a.id
+		WHERE a.class_id = $1 AND s.email = $2 AND s.passed;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, id, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var assignmentID string
+		if err := rows.Scan(&assignmentID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, assignmentID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// DeleteAssignment reports false when the class has no such assignment
+func (r *ClassRepositoryImpl) DeleteAssignment(ctx context.Context, id string, assignmentID string) (bool, error) {
+	query := `
+		DELETE FROM class_assignments
+		WHERE class_id = $1 AND id = $2;
+	`
+
+	return deleted(ctx, r.db, query, id, assignmentID)
+}
+
+func (r *ClassRepositoryImpl) InsertSubmission(ctx context.Context, s *classroom.Submission) error {
+	query := `
+		INSERT INTO class_submissions (test_id, assignment_id, email, wpm, accuracy, passed)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING submitted_at;
+	`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, s.TestID, s.AssignmentID, s.Email, s.WPM, s.Accuracy,
+		s.Passed).Scan(&s.SubmittedAt)
+}
+
+// GetSubmissions returns the submissions for the assignment, oldest first.
+// An empty email returns everyone's.
+func (r *ClassRepositoryImpl) GetSubmissions(ctx context.Context, assignmentID string, email string) ([]*classroom.Submission, error) {
+	query := `
+		SELECT assignment_id, email, test_id, wpm, accuracy, passed, submitted_at
+		FROM class_submissions
+		WHERE assignment_id = $1 AND ($2 = '' OR email = $2)
+		ORDER BY submitted_at;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, assignmentID, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	submissions := []*classroom.Submission{}
+	for rows.Next() {
+		s := &classroom.Submission{}
+		if err := rows.Scan(&s.AssignmentID, &s.Email, &s.TestID, &s.WPM, &s.Accuracy, &s.Passed,
+			&s.SubmittedAt); err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return submissions, nil
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetClassByJoinCode_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM classes c JOIN users u (.+) WHERE c.join_code = ").
+		WithArgs("ABCDEFGH").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewClassRepository(db)
+	class, err := repo.GetClassByJoinCode(context.Background(), "ABCDEFGH")
+
+	require.NoError(t, err)
+	assert.Nil(t, class)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetClassesByEmail_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "teacher", "teacher_name", "join_code", "created_at", "students",
+		"role"}).
+		AddRow("class-1", "Year 7", "t@school.io", "Teacher", "ABCDEFGH", time.Now(), 24, "student")
+
+	mock.ExpectQuery("SELECT (.+) FROM classes c (.+) WHERE c.teacher = (.+) OR EXISTS").
+		WithArgs("amy@school.io").
+		WillReturnRows(rows)
+
+	repo := NewClassRepository(db)
+	classes, err := repo.GetClassesByEmail(context.Background(), "amy@school.io")
+
+	require.NoError(t, err)
+	require.Len(t, classes, 1)
+	assert.Equal(t, "student", classes[0].Role)
+	assert.Equal(t, 24, classes[0].Students)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSubmissions_Everyone(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"assignment_id", "email", "test_id", "wpm", "accuracy", "passed", "submitted_at"}).
+		AddRow("a-1", "amy@school.io", "t-1", 45, 96, true, time.Now()).
+		AddRow("a-1", "bob@school.io", "t-2", 38, 80, false, time.Now())
+
+	mock.ExpectQuery("SELECT (.+) FROM class_submissions WHERE assignment_id = (.+) ORDER BY submitted_at").
+		WithArgs("a-1", "").
+		WillReturnRows(rows)
+
+	repo := NewClassRepository(db)
+	submissions, err := repo.GetSubmissions(context.Background(), "a-1", "")
+
+	require.NoError(t, err)
+	require.Len(t, submissions, 2)
+	assert.True(t, submissions[0].Passed)
+	assert.Equal(t, "t-2", submissions[1].TestID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/feed"
+
+	"github.com/lib/pq"
+)
+
+type FeedRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewFeedRepository(db *sql.DB) port.FeedRepository {
+	return &FeedRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *FeedRepositoryImpl) InsertEvent(ctx context.Context, e *feed.Event) error {
+	query := `
+		INSERT INTO feed_events (actor, kind, mode, language, wpm, achievement, level, race_id)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6, NULLIF($7, 0), $8)
+		RETURNING id, created_at;
+	`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, e.Actor, e.Kind, nullString(e.Mode), nullString(e.Language),
+		e.WPM, nullString(e.Achievement), e.Level, nullString(e.RaceID)).Scan(&e.ID, &e.CreatedAt)
+}
+
+// FanOut copies the event into the feeds of up to limit followers of its
+// actor, friends first, and returns who got it. Followers who muted or
+// blocked the actor, or were blocked by them, are skipped, and so is
+// everyone when the actor's profile is private.
+func (r *FeedRepositoryImpl) FanOut(ctx context.Context, e *feed.Event, limit int) ([]string, error) {
+	query := `
+		INSERT INTO feed_items (email, event_id)
+		SELECT f.follower, $1
+		FROM user_follows f
+		JOIN users u ON u.email = f.followee
+		WHERE f.followee = $2
+			AND u.privacy <> 'private'
+			AND NOT EXISTS (SELECT 1 FROM feed_mutes m WHERE m.email = f.follower AND m.muted = f.followee)
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker = f.follower AND b.blocked = f.followee)
+					OR (b.blocker = f.followee AND b.blocked = f.follower)
+			)
+		ORDER BY EXISTS (SELECT 1 FROM user_follows b WHERE b.follower = f.followee AND b.followee = f.follower) DESC,
+			f.created_at
+		LIMIT $3
+		RETURNING email;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, e.ID, e.Actor, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// TrimFeeds drops all but the latest keep items from the feeds of the users
+func (r *FeedRepositoryImpl) TrimFeeds(ctx context.Context, emails []string, keep int) error {
+	query := `
+		DELETE FROM feed_items
+		WHERE id IN (
+			SELECT id
+			FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY email ORDER BY id DESC) AS position
+				FROM feed_items
+				WHERE email = ANY($1)
+			) ranked
+			WHERE position > $2
+		);
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, pq.Array(emails), keep)
+	return err
+}
+
+// GetFeed returns the events in the user's feed before the given one, latest
+// first. Followed actors with more than fanOutLimit followers were not fanned
+// out to everyone, so their events are pulled in as well. Privacy, follows,
+// mutes and blocks are checked again as they are now, so changes to them
+// apply to items already in the feed.
+func (r *FeedRepositoryImpl) GetFeed(ctx context.Context, email string, before int64, limit int,
+	fanOutLimit int) ([]*feed.Event, error) {
+	query := `
+		WITH candidates AS (
+			SELECT i.event_id
+			FROM feed_items i
+			WHERE i.email = $1 AND ($2 = 0 OR i.event_id < $2)
+			UNION
+			SELECT pulled.id
+			FROM user_follows f
+			JOIN feed_events pulled ON pulled.actor = f.followee
+			WHERE f.follower = $1
+				AND ($2 = 0 OR pulled.id < $2)
+				AND (SELECT COUNT(*) FROM user_follows c WHERE c.followee = f.followee) > $4
+		)
+		SELECT e.id, e.actor, u.name, COALESCE(u.username, ''), e.kind, COALESCE(e.mode, ''),
+			COALESCE(e.language, ''), COALESCE(e.wpm, 0), COALESCE(e.achievement, ''), COALESCE(e.level, 0),
+			COALESCE(e.race_id::text, ''), e.created_at
+		FROM candidates c
+		JOIN feed_events e ON e.id = c.event_id
+		JOIN users u ON u.email = e.actor
+		WHERE EXISTS (SELECT 1 FROM user_follows f WHERE f.follower = $1 AND f.followee = e.actor)
+			AND NOT EXISTS (SELECT 1 FROM feed_mutes m WHERE m.email = $1 AND m.muted = e.actor)
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker = $1 AND b.blocked = e.actor) OR (b.blocker = e.actor AND b.blocked = $1)
+			)
+			AND (u.privacy = 'public' OR (u.privacy = 'friends'
+				AND EXISTS (SELECT 1 FROM user_follows b WHERE b.follower = e.actor AND b.followee = $1)))
+		ORDER BY e.id DESC
+		LIMIT $3;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, email, before, limit, fanOutLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*feed.Event{}
+	for rows.Next() {
+		e := &feed.Event{}
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Name, &e.Username, &e.Kind, &e.Mode, &e.Language, &e.WPM,
+			&e.Achievement, &e.Level, &e.RaceID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Mute does nothing when the user already mutes the other
+func (r *FeedRepositoryImpl) Mute(ctx context.Context, email string, muted string) error {
+	query := `
+		INSERT INTO feed_mutes (email, muted)
+		VALUES ($1, $2)
+		ON CONFLICT (email, muted) DO NOTHING;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, email, muted)
+	return err
+}
+
+// Unmute reports false when the user was not muting the other
+func (r *FeedRepositoryImpl) Unmute(ctx context.Context, email string, muted string) (bool, error) {
+	query := `
+		DELETE FROM feed_mutes
+		WHERE email = $1 AND muted = $2;
+	`
+
+	return deleted(ctx, r.db, query, email, muted)
+}
+
+// GetMuted returns who the user mutes, latest first
+func (r *FeedRepositoryImpl) GetMuted(ctx context.Context, email string) ([]*feed.Muted, error) {
+	query := `
+		SELECT u.name, u.email, m.created_at
+		FROM feed_mutes m
+		JOIN users u ON u.email = m.muted
+		WHERE m.email = $1
+		ORDER BY m.created_at DESC;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	muted := []*feed.Muted{}
+	for rows.Next() {
+		m := &feed.Muted{}
+		if err := rows.Scan(&m.Name, &m.Email, &m.Since); err != nil {
+			return nil, err
+		}
+		muted = append(muted, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return muted, nil
+}
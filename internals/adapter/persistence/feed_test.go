@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+	"typing-speed/internals/core/feed"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertFeedEvent_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery("INSERT INTO feed_events (.+) RETURNING id, created_at").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(7), now))
+
+	repo := NewFeedRepository(db)
+	e := &feed.Event{Actor: "a@mail.com", Kind: feed.KindLevelUp, Level: 4}
+	err = repo.InsertEvent(context.Background(), e)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), e.ID)
+	assert.Equal(t, now, e.CreatedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFeed_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "actor", "name", "username", "kind", "mode", "language", "wpm",
+		"achievement", "level", "race_id", "created_at"}).
+		AddRow(int64(12), "b@mail.com", "B", "bee", feed.KindPersonalBest, "60s", "english", 96, "", 0, "",
+			time.Now())
+
+	// the events of actors with too many followers to fan out to are pulled in
+	mock.ExpectQuery("WITH candidates AS \\( SELECT i.event_id FROM feed_items i WHERE i.email = \\$1 (.+) UNION "+
+		"SELECT pulled.id FROM user_follows f JOIN feed_events pulled (.+) > \\$4 \\) "+
+		"SELECT (.+) FROM candidates c JOIN feed_events e (.+) ORDER BY e.id DESC LIMIT").
+		WithArgs("a@mail.com", int64(20), 21, feed.FanOutLimit).
+		WillReturnRows(rows)
+
+	repo := NewFeedRepository(db)
+	items, err := repo.GetFeed(context.Background(), "a@mail.com", 20, 21, feed.FanOutLimit)
+
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, int64(12), items[0].ID)
+	assert.Equal(t, "bee", items[0].Username)
+	assert.Equal(t, 96, items[0].WPM)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFanOutAndTrim_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("INSERT INTO feed_items (.+) LIMIT \\$3 RETURNING email").
+		WithArgs(int64(7), "a@mail.com", feed.FanOutLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("b@mail.com").AddRow("c@mail.com"))
+	// only the feeds the event went into are trimmed
+	mock.ExpectExec("DELETE FROM feed_items (.+) WHERE email = ANY\\(\\$1\\)").
+		WithArgs(pq.Array([]string{"b@mail.com", "c@mail.com"}), feed.FeedLimit).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := NewFeedRepository(db)
+	followers, err := repo.FanOut(context.Background(), &feed.Event{ID: 7, Actor: "a@mail.com"}, feed.FanOutLimit)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b@mail.com", "c@mail.com"}, followers)
+
+	err = repo.TrimFeeds(context.Background(), followers, feed.FeedLimit)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,334 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/friend"
+)
+
+type FriendRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewFriendRepository(db *sql.DB) port.FriendRepository {
+	return &FriendRepositoryImpl{
+		db: db,
+	}
+}
+
+// Follow does nothing when the user already follows the other
+func (r *FriendRepositoryImpl) Follow(ctx context.Context, follower string, followee string) error {
+	query := `
+		INSERT INTO user_follows (follower, followee)
+		VALUES ($1, $2)
+		ON CONFLICT (follower, followee) DO NOTHING;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, follower, followee)
+	return err
+}
+
+// Unfollow reports false when the user was not following the other
+func (r *FriendRepositoryImpl) Unfollow(ctx context.Context, follower string, followee string) (bool, error) {
+	query := `
+		DELETE FROM user_follows
+		WHERE follower = $1 AND followee = $2;
+	`
+
+	return deleted(ctx, r.db, query, follower, followee)
+}
+
+func (r *FriendRepositoryImpl) GetRelationship(ctx context.Context, email string, other string) (*friend.Relationship, error) {
+	query := `
+		SELECT
+			EXISTS (SELECT 1 FROM user_follows WHERE follower = $1 AND followee = $2),
+			EXISTS (SELECT 1 FROM user_follows WHERE follower = $2 AND followee = $1),
+			EXISTS (SELECT 1 FROM user_blocks WHERE blocker = $1 AND blocked = $2),
+			EXISTS (SELECT 1 FROM user_blocks WHERE blocker = $2 AND blocked = $1);
+	`
+
+	rel := &friend.Relationship{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, email, other).
+		Scan(&rel.Following, &rel.FollowedBy, &rel.Blocking, &rel.BlockedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return rel, nil
+}
+
+// GetFollowers returns who follows the user, latest first
+func (r *FriendRepositoryImpl) GetFollowers(ctx context.Context, email string) ([]*friend.Friend, error) {
+	query := `
+		SELECT u.name, u.email,
+			EXISTS (SELECT 1 FROM user_follows b WHERE b.follower = f.followee AND b.followee = f.follower),
+			f.created_at
+		FROM user_follows f
+		JOIN users u ON u.email = f.follower
+		WHERE f.followee = $1
+		ORDER BY f.created_at DESC;
+	`
+
+	return r.queryFriends(ctx, query, email)
+}
+
+// GetFollowing returns who the user follows, latest first
+func (r *FriendRepositoryImpl) GetFollowing(ctx context.Context, email string) ([]*friend.Friend, error) {
+	query := `
+		SELECT u.name, u.email,
+			EXISTS (SELECT 1 FROM user_follows b WHERE b.follower = f.followee AND b.followee = f.follower),
+			f.created_at
+		FROM user_follows f
+		JOIN users u ON u.email = f.followee
+		WHERE f.follower = $1
+		ORDER BY f.created_at DESC;
+	`
+
+	return r.queryFriends(ctx, query, email)
+}
+
+// GetFriends returns the users who follow the user back, by name. They
+// became friends when the second of the two follows was made.
+func (r *FriendRepositoryImpl) GetFriends(ctx context.Context, email string) ([]*friend.Friend, error) {
+	query := `
+		SELECT u.name, u.email, TRUE, GREATEST(f.created_at, b.created_at)
+		FROM user_follows f
+		JOIN user_follows b ON b.follower = f.followee AND b.followee = f.follower
+		JOIN users u ON u.email = f.followee
+		WHERE f.follower = $1
+		ORDER BY u.name;
+	`
+
+	return r.queryFriends(ctx, query, email)
+}
+
+func (r *FriendRepositoryImpl) queryFriends(ctx context.Context, query string, args ...any) ([]*friend.Friend, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	friends := []*friend.Friend{}
+	for rows.Next() {
+		f := &friend.Friend{}
+		if err := rows.Scan(&f.Name, &f.Email, &f.Mutual, &f.Since); err != nil {
+			return nil, err
+		}
+		friends = append(friends, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return friends, nil
+}
+
+func (r *FriendRepositoryImpl) CreateRequest(ctx context.Context, req *friend.Request) error {
+	query := `
+		INSERT INTO friend_requests (from_email, to_email, status)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at;
+	`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, req.FromEmail, req.ToEmail, req.Status).
+		Scan(&req.ID, &req.CreatedAt)
+}
+
+const requestColumns = `fr.id, fr.from_email, fu.name, fr.to_email, tu.name, fr.status, fr.created_at, fr.responded_at`
+
+const requestTables = `friend_requests fr
+		JOIN users fu ON fu.email = fr.from_email
+		JOIN users tu ON tu.email = fr.to_email`
+
+func scanRequest(row rowScanner, req *friend.Request) error {
+	return row.Scan(&req.ID, &req.FromEmail, &req.FromName, &req.ToEmail, &req.ToName, &req.Status, &req.CreatedAt,
+		&req.RespondedAt)
+}
+
+// GetRequestForUpdate locks the request until the transaction ends, so it
+// is answered only once. It returns nil when there is no such request.
+func (r *FriendRepositoryImpl) GetRequestForUpdate(ctx context.Context, id int64) (*friend.Request, error) {
+	query := `
+		SELECT ` + requestColumns + `
+		FROM ` + requestTables + `
+		WHERE fr.id = $1
+		FOR UPDATE OF fr;
+	`
+
+	return r.getRequest(ctx, query, id)
+}
+
+// GetPendingRequest returns the open request from one user to the other, or nil
+func (r *FriendRepositoryImpl) GetPendingRequest(ctx context.Context, from string, to string) (*friend.Request, error) {
+	query := `
+		SELECT ` + requestColumns + `
+		FROM ` + requestTables + `
+		WHERE fr.from_email = $1 AND fr.to_email = $2 AND fr.status = 'pending';
+	`
+
+	return r.getRequest(ctx, query, from, to)
+}
+
+func (r *FriendRepositoryImpl) getRequest(ctx context.Context, query string, args ...any) (*friend.Request, error) {
+	req := &friend.Request{}
+	err := scanRequest(conn(ctx, r.db).QueryRowContext(ctx, query, args...), req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// GetIncomingRequests returns the open requests sent to the user, oldest first
+func (r *FriendRepositoryImpl) GetIncomingRequests(ctx context.Context, email string) ([]*friend.Request, error) {
+	query := `
+		SELECT ` + requestColumns + `
+		FROM ` + requestTables + `
+		WHERE fr.to_email = $1 AND fr.status = 'pending'
+		ORDER BY fr.created_at;
+	`
+
+	return r.queryRequests(ctx, query, email)
+}
+
+// GetOutgoingRequests returns the open requests the user sent, oldest first
+func (r *FriendRepositoryImpl) GetOutgoingRequests(ctx context.Context, email string) ([]*friend.Request, error) {
+	query := `
+		SELECT ` + requestColumns + `
+		FROM ` + requestTables + `
+		WHERE fr.from_email = $1 AND fr.status = 'pending'
+		ORDER BY fr.created_at;
+	`
+
+	return r.queryRequests(ctx, query, email)
+}
+
+func (r *FriendRepositoryImpl) queryRequests(ctx context.Context, query string, args ...any) ([]*friend.Request, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []*friend.Request{}
+	for rows.Next() {
+		req := &friend.Request{}
+		if err := scanRequest(rows, req); err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+func (r *FriendRepositoryImpl) UpdateRequestStatus(ctx context.Context, req *friend.Request) error {
+	query := `
+		UPDATE friend_requests
+		SET status = $2, responded_at = $3
+		WHERE id = $1;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, req.ID, req.Status, req.RespondedAt)
+	return err
+}
+
+// DeletePendingRequests drops the open requests between the two users, either way
+func (r *FriendRepositoryImpl) DeletePendingRequests(ctx context.Context, a string, b string) error {
+	query := `
+		DELETE FROM friend_requests
+		WHERE status = 'pending'
+			AND ((from_email = $1 AND to_email = $2) OR (from_email = $2 AND to_email = $1));
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, a, b)
+	return err
+}
+
+// Block does nothing when the user already blocks the other
+func (r *FriendRepositoryImpl) Block(ctx context.Context, blocker string, blocked string) error {
+	query := `
+		INSERT INTO user_blocks (blocker, blocked)
+		VALUES ($1, $2)
+		ON CONFLICT (blocker, blocked) DO NOTHING;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, blocker, blocked)
+	return err
+}
+
+// Unblock reports false when the user was not blocking the other
+func (r *FriendRepositoryImpl) Unblock(ctx context.Context, blocker string, blocked string) (bool, error) {
+	query := `
+		DELETE FROM user_blocks
+		WHERE blocker = $1 AND blocked = $2;
+	`
+
+	return deleted(ctx, r.db, query, blocker, blocked)
+}
+
+// GetBlocked returns who the user blocks, latest first
+func (r *FriendRepositoryImpl) GetBlocked(ctx context.Context, email string) ([]*friend.Friend, error) {
+	query := `
+		SELECT u.name, u.email, FALSE, b.created_at
+		FROM user_blocks b
+		JOIN users u ON u.email = b.blocked
+		WHERE b.blocker = $1
+		ORDER BY b.created_at DESC;
+	`
+
+	return r.queryFriends(ctx, query, email)
+}
+
+// GetFriendsLeaderboard ranks the user and their friends by average
+// performance, leaving out friends with a private profile
+func (r *FriendRepositoryImpl) GetFriendsLeaderboard(ctx context.Context, email string, limit int) ([]*friend.LeaderboardEntry, error) {
+	query := `
+		SELECT RANK() OVER (ORDER BY u.avg_performance DESC), u.name, u.email, u.avg_performance, u.avg_speed,
+			u.best_speed, u.avg_accuracy, u.total_test
+		FROM users u
+		WHERE u.email = $1 OR (u.privacy <> 'private' AND u.email IN (
+			SELECT f.followee
+			FROM user_follows f
+			JOIN user_follows b ON b.follower = f.followee AND b.followee = f.follower
+			WHERE f.follower = $1
+		))
+		ORDER BY u.avg_performance DESC, u.name
+		LIMIT $2;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, email, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*friend.LeaderboardEntry{}
+	for rows.Next() {
+		e := &friend.LeaderboardEntry{}
+		err := rows.Scan(&e.Rank, &e.Name, &e.Email, &e.Performance, &e.AvgSpeed, &e.BestSpeed, &e.AvgAccuracy,
+			&e.TotalTest)
+		if err != nil {
+			return nil, err
+		}
+		e.You = e.Email == email
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRelationship_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"following", "followed_by", "blocking", "blocked_by"}).
+		AddRow(true, true, false, false)
+
+	mock.ExpectQuery("SELECT EXISTS (.+) FROM user_follows (.+) FROM user_blocks").
+		WithArgs("a@test.com", "b@test.com").
+		WillReturnRows(rows)
+
+	repo := NewFriendRepository(db)
+	rel, err := repo.GetRelationship(context.Background(), "a@test.com", "b@test.com")
+
+	require.NoError(t, err)
+	assert.True(t, rel.Following)
+	assert.True(t, rel.FollowedBy)
+	assert.False(t, rel.BlockedBy)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFriendsLeaderboard_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"rank", "name", "email", "avg_performance", "avg_speed", "best_speed",
+		"avg_accuracy", "total_test"}).
+		AddRow(1, "Friend", "b@test.com", 90, 85, 110, 97, 40).
+		AddRow(2, "Me", "a@test.com", 75, 70, 95, 94, 12)
+
+	mock.ExpectQuery("SELECT RANK\\(\\) OVER (.+) FROM users u WHERE u.email = (.+) LIMIT").
+		WithArgs("a@test.com", 100).
+		WillReturnRows(rows)
+
+	repo := NewFriendRepository(db)
+	entries, err := repo.GetFriendsLeaderboard(context.Background(), "a@test.com", 100)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.False(t, entries[0].You)
+	assert.True(t, entries[1].You)
+	assert.Equal(t, 2, entries[1].Rank)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPendingRequest_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM friend_requests fr (.+) WHERE fr.from_email = (.+) AND fr.status = 'pending'").
+		WithArgs("a@test.com", "b@test.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewFriendRepository(db)
+	req, err := repo.GetPendingRequest(context.Background(), "a@test.com", "b@test.com")
+
+	require.NoError(t, err)
+	assert.Nil(t, req)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFriends_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"name", "email", "mutual", "since"}).
+		AddRow("Friend", "b@test.com", true, time.Now())
+
+	mock.ExpectQuery("SELECT (.+) FROM user_follows f JOIN user_follows b (.+) WHERE f.follower = (.+) ORDER BY u.name").
+		WithArgs("a@test.com").
+		WillReturnRows(rows)
+
+	repo := NewFriendRepository(db)
+	friends, err := repo.GetFriends(context.Background(), "a@test.com")
+
+	require.NoError(t, err)
+	require.Len(t, friends, 1)
+	assert.True(t, friends[0].Mutual)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/goal"
+)
+
+type GoalRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewGoalRepository(db *sql.DB) port.GoalRepository {
+	return &GoalRepositoryImpl{
+		db: db,
+	}
+}
+
+const goalColumns = `id, email, type, target, mode, deadline, baseline, progress, progress_day, days_met, completed_at, created_at`
+
+func scanGoal(row rowScanner, g *goal.Goal) error {
+	var progressDay sql.NullTime
+	err := row.Scan(
+		&g.ID,
+		&g.Email,
+		&g.Type,
+		&g.Target,
+		&g.Mode,
+		&g.Deadline,
+		&g.Baseline,
+		&g.Progress,
+		&progressDay,
+		&g.DaysMet,
+		&g.CompletedAt,
+		&g.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if progressDay.Valid {
+		g.ProgressDay = progressDay.Time.Format("2006-01-02")
+	}
+	return nil
+}
+
+func (r *GoalRepositoryImpl) CreateGoal(ctx context.Context, g *goal.Goal) error {
+	query := `
+		INSERT INTO user_goals (email, type, target, mode, deadline)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;
+	`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, g.Email, g.Type, g.Target, g.Mode, g.Deadline).
+		Scan(&g.ID, &g.CreatedAt)
+}
+
+func (r *GoalRepositoryImpl) GetGoals(ctx context.Context, email string) ([]*goal.Goal, error) {
+	query := `
+		SELECT ` + goalColumns + `
+		FROM user_goals
+		WHERE email = $1
+		ORDER BY created_at;
+	`
+
+	return r.queryGoals(ctx, query, email)
+}
+
+// GetActiveGoals returns the goals that are neither completed nor past their deadline
+func (r *GoalRepositoryImpl) GetActiveGoals(ctx context.Context, email string) ([]*goal.Goal, error) {
+	query := `
+		SELECT ` + goalColumns + `
+		FROM user_goals
+		WHERE email = $1 AND completed_at IS NULL AND (deadline IS NULL OR deadline > NOW())
+		ORDER BY created_at;
+	`
+
+	return r.queryGoals(ctx, query, email)
+}
+
+func (r *GoalRepositoryImpl) queryGoals(ctx context.Context, query string, args ...any) ([]*goal.Goal, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := []*goal.Goal{}
+
+	for rows.Next() {
+		g := &goal.Goal{}
+		if err := scanGoal(rows, g); err != nil {
+			return nil, err
+		}
+		goals = append(goals, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return goals, nil
+}
+
+func (r *GoalRepositoryImpl) GetGoal(ctx context.Context, email string, id int64) (*goal.Goal, error) {
+	query := `
+		SELECT ` + goalColumns + `
+		FROM user_goals
+		WHERE email = $1 AND id = $2;
+	`
+
+	g := &goal.Goal{}
+	err := scanGoal(conn(ctx, r.db).QueryRowContext(ctx, query, email, id), g)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // goal not found
+		}
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// UpdateGoal stores the user editable fields of a goal
+func (r *GoalRepositoryImpl) UpdateGoal(ctx context.Context, g *goal.Goal) error {
+	query := `
+		UPDATE user_goals
+		SET target = $3, mode = $4, deadline = $5, completed_at = $6
+		WHERE email = $1 AND id = $2;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, g.Email, g.ID, g.Target, g.Mode, g.Deadline, g.CompletedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *GoalRepositoryImpl) UpdateGoalProgress(ctx context.Context, g *goal.Goal) error {
+	query := `
+		UPDATE user_goals
+		SET baseline = $3, progress = $4, progress_day = $5, days_met = $6, completed_at = $7
+		WHERE email = $1 AND id = $2;
+	`
+
+	var progressDay *time.Time
+	if g.ProgressDay != "" {
+		day, err := time.Parse("2006-01-02", g.ProgressDay)
+		if err != nil {
+			return err
+		}
+		progressDay = &day
+	}
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, g.Email, g.ID, g.Baseline, g.Progress, progressDay, g.DaysMet, g.CompletedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteGoal removes the goal and reports whether it existed
+func (r *GoalRepositoryImpl) DeleteGoal(ctx context.Context, email string, id int64) (bool, error) {
+	query := `DELETE FROM user_goals WHERE email = $1 AND id = $2;`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, email, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+	"typing-speed/internals/core/goal"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetActiveGoals_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "email", "type", "target", "mode", "deadline", "baseline", "progress",
+		"progress_day", "days_met", "completed_at", "created_at",
+	}).
+		AddRow(1, "test@test.com", "speed", 80, "60s", nil, 60, 70, nil, 0, nil, now).
+		AddRow(2, "test@test.com", "daily_practice", 10, "", nil, 0, 300, day, 3, nil, now)
+
+	mock.ExpectQuery("SELECT (.+) FROM user_goals WHERE email = (.+) AND completed_at IS NULL " +
+		"AND \\(deadline IS NULL OR deadline > NOW\\(\\)\\)").
+		WithArgs("test@test.com").
+		WillReturnRows(rows)
+
+	repo := NewGoalRepository(db)
+	goals, err := repo.GetActiveGoals(context.Background(), "test@test.com")
+
+	require.NoError(t, err)
+	require.Len(t, goals, 2)
+	assert.Equal(t, goal.TypeSpeed, goals[0].Type)
+	assert.Equal(t, "", goals[0].ProgressDay)
+	assert.Equal(t, "2026-10-19", goals[1].ProgressDay)
+	assert.Equal(t, 3, goals[1].DaysMet)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteGoal_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM user_goals").
+		WithArgs("test@test.com", int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewGoalRepository(db)
+	found, err := repo.DeleteGoal(context.Background(), "test@test.com", 9)
+
+	require.NoError(t, err)
+	assert.False(t, found)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
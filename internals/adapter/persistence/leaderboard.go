@@ -0,0 +1,223 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/leaderboard"
+)
+
+type LeaderboardRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewLeaderboardRepository(db *sql.DB) port.LeaderboardRepository {
+	return &LeaderboardRepositoryImpl{
+		db: db,
+	}
+}
+
+// testAccuracy is the accuracy of a test in user_typing_data
+const testAccuracy = `CASE WHEN total_words = 0 THEN 0 ELSE (typed_words - total_error) * 100 / total_words END`
+
+// sortColumns are the value a leaderboard is sorted by and the one that
+// breaks ties, as named in the scored tests
+func sortColumns(sort string) (string, string) {
+	switch sort {
+	case leaderboard.SortAccuracy:
+		return "accuracy", "wpm"
+	case leaderboard.SortPerformance:
+		return "performance", "wpm"
+	default:
+		return "wpm", "accuracy"
+	}
+}
+
+// GetLeaderboard ranks the users with a public profile by their best test of
+// the mode and language in the window, and returns the page after the cursor
+func (r *LeaderboardRepositoryImpl) GetLeaderboard(ctx context.Context, q *leaderboard.Query) ([]*leaderboard.Entry, error) {
+	value, tiebreak := sortColumns(q.Sort)
+	order := value + ` DESC, ` + tiebreak + ` DESC, created_at, id`
+
+	query := `
+		WITH scored AS (
+			SELECT t.id, t.email, t.wpm, t.accuracy, t.wpm * t.accuracy AS performance, t.created_at
+			FROM (
+				SELECT id, email, wpm, ` + testAccuracy + ` AS accuracy, created_at
+				FROM user_typing_data
+				WHERE mode = $1 AND language = $2 AND ($3::timestamp IS NULL OR created_at >= $3)
+			) t
+			JOIN users u ON u.email = t.email
+			WHERE u.privacy = 'public' AND ($5 = '' OR u.country = $5) AND ($6 = '' OR u.region = $6)
+		), best AS (
+			SELECT DISTINCT ON (email) *
+			FROM scored
+			ORDER BY email, ` + order + `
+		), ranked AS (
+			SELECT *, ROW_NUMBER() OVER (ORDER BY ` + order + `) AS position
+			FROM best
+		)
+		SELECT r.position, u.name, COALESCE(u.username, ''), r.email, COALESCE(u.country, ''), r.id, r.wpm, r.accuracy, r.performance,
+			r.created_at
+		FROM ranked r
+		JOIN users u ON u.email = r.email`
+	args := []any{q.Mode, q.Language, utcTime(q.Since), q.Limit, q.Country, q.Region}
+
+	// keyset pagination: everything sorts ascending once the values are negated
+	if c := q.Cursor; c != nil {
+		query += `
+		WHERE (-r.` + value + `, -r.` + tiebreak + `, r.created_at, r.id) > (-$7::int, -$8::int, $9::timestamp, $10::uuid)`
+		args = append(args, c.Value, c.Tiebreak, utcTime(&c.CreatedAt), c.TestID)
+	}
+	query += `
+		ORDER BY r.position
+		LIMIT $4;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*leaderboard.Entry{}
+	for rows.Next() {
+		e := &leaderboard.Entry{}
+		if err := rows.Scan(&e.Rank, &e.Name, &e.Username, &e.Email, &e.Country, &e.TestID, &e.WPM, &e.Accuracy, &e.Performance,
+			&e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetCountrySummaries sums up the users with a public profile of every
+// country, the ones with the most users first
+func (r *LeaderboardRepositoryImpl) GetCountrySummaries(ctx context.Context) ([]*leaderboard.CountrySummary, error) {
+	query := `
+		WITH members AS (
+			SELECT email, country, name, COALESCE(username, '') AS username, avg_speed, best_speed, total_test
+			FROM users
+			WHERE country IS NOT NULL AND privacy = 'public'
+		), top AS (
+			SELECT DISTINCT ON (country) country, name, username, best_speed
+			FROM members
+			WHERE total_test > 0
+			ORDER BY country, best_speed DESC, email
+		)
+		SELECT m.country, COUNT(*), COALESCE(ROUND(AVG(m.avg_speed) FILTER (WHERE m.total_test > 0)), 0)::int,
+			COALESCE(t.name, ''), COALESCE(t.username, ''), COALESCE(t.best_speed, 0)
+		FROM members m
+		LEFT JOIN top t ON t.country = m.country
+		GROUP BY m.country, t.name, t.username, t.best_speed
+		ORDER BY COUNT(*) DESC, m.country;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []*leaderboard.CountrySummary{}
+	for rows.Next() {
+		c := &leaderboard.CountrySummary{}
+		top := &leaderboard.TopTypist{}
+		if err := rows.Scan(&c.Country, &c.Users, &c.AverageWPM, &top.Name, &top.Username, &top.WPM); err != nil {
+			return nil, err
+		}
+		// nobody in the country took a test yet
+		if top.Name != "" {
+			c.TopTypist = top
+		}
+		summaries = append(summaries, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// speeds is the speed of every user in each mode they took a test in, by the
+// metric. $1 is the metric; the average is the default.
+const speeds = `
+	SELECT email, mode, MAX(wpm) AS wpm
+	FROM personal_bests
+	WHERE $1 = 'best'
+	GROUP BY email, mode
+	UNION ALL
+	SELECT email, mode, ROUND(AVG(wpm))::int AS wpm
+	FROM user_typing_data
+	WHERE $1 <> 'best'
+	GROUP BY email, mode`
+
+// GetDistribution counts the users of each mode by speed
+func (r *LeaderboardRepositoryImpl) GetDistribution(ctx context.Context, metric string) ([]*leaderboard.Bucket, error) {
+	query := `
+		SELECT s.mode, s.wpm, COUNT(*)
+		FROM (` + speeds + `) s
+		GROUP BY s.mode, s.wpm
+		ORDER BY s.mode, s.wpm;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, metric)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []*leaderboard.Bucket{}
+	for rows.Next() {
+		b := &leaderboard.Bucket{}
+		if err := rows.Scan(&b.Mode, &b.WPM, &b.Users); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// GetUserSpeeds returns the speed of the user in each mode they took a test in
+func (r *LeaderboardRepositoryImpl) GetUserSpeeds(ctx context.Context, email string, metric string) (map[string]int, error) {
+	query := `
+		SELECT s.mode, s.wpm
+		FROM (` + speeds + `) s
+		WHERE s.email = $2
+		ORDER BY s.mode;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, metric, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	modes := map[string]int{}
+	for rows.Next() {
+		var mode string
+		var wpm int
+		if err := rows.Scan(&mode, &wpm); err != nil {
+			return nil, err
+		}
+		modes[mode] = wpm
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return modes, nil
+}
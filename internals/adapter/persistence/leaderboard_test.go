@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+	"typing-speed/internals/core/leaderboard"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLeaderboard_Cursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// the window start is bound as the UTC wall-clock time created_at holds
+	since := time.Date(2026, 10, 19, 0, 0, 0, 0, time.FixedZone("IST", 5*60*60+30*60))
+	createdAt := time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"position", "name", "username", "email", "country", "id", "wpm", "accuracy", "performance",
+		"created_at"}).
+		AddRow(51, "Navneet", "navneet", "n@mail.com", "IN", "test-1", 90, 96, 8640, createdAt)
+
+	mock.ExpectQuery("WITH scored AS (.+) ORDER BY email, accuracy DESC, wpm DESC, created_at, id (.+) "+
+		"WHERE \\(-r.accuracy, -r.wpm, r.created_at, r.id\\) > \\(.+\\$9::timestamp, \\$10::uuid\\) ORDER BY r.position LIMIT \\$4").
+		WithArgs("60s", "english", time.Date(2026, 10, 18, 18, 30, 0, 0, time.UTC), 51, "IN", "", 97, 92, createdAt, "test-0").
+		WillReturnRows(rows)
+
+	repo := NewLeaderboardRepository(db)
+	entries, err := repo.GetLeaderboard(context.Background(), &leaderboard.Query{
+		Mode:     "60s",
+		Language: "english",
+		Sort:     leaderboard.SortAccuracy,
+		Country:  "IN",
+		Since:    &since,
+		Limit:    51,
+		Cursor:   &leaderboard.Cursor{Value: 97, Tiebreak: 92, CreatedAt: createdAt, TestID: "test-0"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 51, entries[0].Rank)
+	assert.Equal(t, 8640, entries[0].Performance)
+	assert.Equal(t, "IN", entries[0].Country)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDistribution(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"mode", "wpm", "count"}).
+		AddRow("60s", 40, 3).
+		AddRow("60s", 80, 1)
+
+	mock.ExpectQuery("SELECT s.mode, s.wpm, COUNT\\(\\*\\) FROM \\((.+)personal_bests(.+)user_typing_data(.+)\\) s " +
+		"GROUP BY s.mode, s.wpm").
+		WithArgs(leaderboard.MetricBest).
+		WillReturnRows(rows)
+
+	repo := NewLeaderboardRepository(db)
+	buckets, err := repo.GetDistribution(context.Background(), leaderboard.MetricBest)
+
+	require.NoError(t, err)
+	require.Len(t, buckets, 2)
+	assert.Equal(t, 3, buckets[0].Users)
+	assert.Equal(t, 80, buckets[1].WPM)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCountrySummaries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"country", "count", "avg", "name", "username", "best_speed"}).
+		AddRow("IN", 12, 54, "Navneet", "navneet", 121).
+		AddRow("NZ", 1, 0, "", "", 0)
+
+	mock.ExpectQuery("WITH members AS (.+) FROM users WHERE country IS NOT NULL AND privacy = 'public'").
+		WillReturnRows(rows)
+
+	repo := NewLeaderboardRepository(db)
+	summaries, err := repo.GetCountrySummaries(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, 54, summaries[0].AverageWPM)
+	require.NotNil(t, summaries[0].TopTypist)
+	assert.Equal(t, 121, summaries[0].TopTypist.WPM)
+	assert.Nil(t, summaries[1].TopTypist)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,434 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/organization"
+	"typing-speed/internals/core/user"
+)
+
+type OrganizationRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewOrganizationRepository(db *sql.DB) port.OrganizationRepository {
+	return &OrganizationRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *OrganizationRepositoryImpl) CreateOrganization(ctx context.Context, o *organization.Organization) error {
+	query := `
+		INSERT INTO organizations (id, name, domain, domain_token, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at;
+	`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, o.ID, o.Name, nullString(o.Domain), nullString(o.Token),
+		o.CreatedBy).Scan(&o.CreatedAt)
+}
+
+const organizationColumns = `o.id, o.name, o.domain, o.domain_verified, o.domain_token, o.created_by, o.created_at,
+			(SELECT COUNT(*) FROM organization_members om WHERE om.organization_id = o.id)`
+
+func scanOrganization(row rowScanner, o *organization.Organization, extra ...any) error {
+	var domain, token sql.NullString
+	dest := append([]any{&o.ID, &o.Name, &domain, &o.Verified, &token, &o.CreatedBy, &o.CreatedAt, &o.Members}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	o.Domain = domain.String
+	o.Token = token.String
+	return nil
+}
+
+// GetOrganization returns nil when there is no such organization
+func (r *OrganizationRepositoryImpl) GetOrganization(ctx context.Context, id string) (*organization.Organization, error) {
+	query := `
+		SELECT ` + organizationColumns + `
+		FROM organizations o
+		WHERE o.id = $1;
+	`
+
+	return r.getOrganization(ctx, query, id)
+}
+
+// GetOrganizationByDomain returns the organization that verified the email
+// domain, or nil. Unverified claims do not count.
+func (r *OrganizationRepositoryImpl) GetOrganizationByDomain(ctx context.Context, domain string) (*organization.Organization, error) {
+	query := `
+		SELECT ` + organizationColumns + `
+		FROM organizations o
+		WHERE o.domain = $1 AND o.domain_verified;
+	`
+
+	return r.getOrganization(ctx, query, domain)
+}
+
+func (r *OrganizationRepositoryImpl) getOrganization(ctx context.Context, query string, args ...any) (*organization.Organization, error) {
+	o := &organization.Organization{}
+	err := scanOrganization(conn(ctx, r.db).QueryRowContext(ctx, query, args...), o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// GetOrganizationsByEmail returns the organizations the user is a member
+// of, with their role in each, by name
+func (r *OrganizationRepositoryImpl) GetOrganizationsByEmail(ctx context.Context, email string) ([]*organization.Organization, error) {
+	query := `
+		SELECT ` + organizationColumns + `, m.role
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.email = $1
+		ORDER BY o.name;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := []*organization.Organization{}
+	for rows.Next() {
+		o := &organization.Organization{}
+		if err := scanOrganization(rows, o, &o.Role); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return orgs, nil
+}
+
+func (r *OrganizationRepositoryImpl) UpdateOrganization(ctx context.Context, o *organization.Organization) error {
+	query := `
+		UPDATE organizations
+		SET name = $2, domain = $3, domain_token = $4, domain_verified = $5
+		WHERE id = $1;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, o.ID, o.Name, nullString(o.Domain), nullString(o.Token),
+		o.Verified)
+	return err
+}
+
+// DeleteOrganization drops its members and teams with it
+func (r *OrganizationRepositoryImpl) DeleteOrganization(ctx context.Context, id string) error {
+	query := `
+		DELETE FROM organizations
+		WHERE id = $1;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+// AddMember reports false when the user is already a member
+func (r *OrganizationRepositoryImpl) AddMember(ctx context.Context, id string, email string, role string) (bool, error) {
+	query := `
+		INSERT INTO organization_members (organization_id, email, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, email) DO NOTHING;
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, email, role)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// AddMembersByDomain makes every user with an email at the domain a member
+func (r *OrganizationRepositoryImpl) AddMembersByDomain(ctx context.Context, id string, domain string) error {
+	query := `
+		INSERT INTO organization_members (organization_id, email, role)
+		SELECT $1, email, 'member'
+		FROM users
+		WHERE SPLIT_PART(LOWER(email), '@', 2) = $2
+		ON CONFLICT (organization_id, email) DO NOTHING;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, domain)
+	return err
+}
+
+// GetMember returns nil when the user is not a member
+func (r *OrganizationRepositoryImpl) GetMember(ctx context.Context, id string, email string) (*organization.Member, error) {
+	query := `
+		SELECT u.name, m.email, m.role, m.joined_at
+		FROM organization_members m
+		JOIN users u ON u.email = m.email
+		WHERE m.organization_id = $1 AND m.email = $2;
+	`
+
+	m := &organization.Member{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id, email).Scan(&m.Name, &m.Email, &m.Role, &m.JoinedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// GetMembers returns the owner first, then the admins, then everyone else by name
+func (r *OrganizationRepositoryImpl) GetMembers(ctx context.Context, id string) ([]*organization.Member, error) {
+	query := `
+		SELECT u.name, m.email, m.role, m.joined_at
+		FROM organization_members m
+		JOIN users u ON u.email = m.email
+		WHERE m.organization_id = $1
+		ORDER BY CASE m.role WHEN 'owner' THEN 0 WHEN 'admin' THEN 1 ELSE 2 END, u.name;
+	`
+
+	return r.queryMembers(ctx, query, id)
+}
+
+func (r *OrganizationRepositoryImpl) queryMembers(ctx context.Context, query string, args ...any) ([]*organization.Member, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*organization.Member{}
+	for rows.Next() {
+		m := &organization.Member{}
+		if err := rows.Scan(&m.Name, &m.Email, &m.Role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *OrganizationRepositoryImpl) UpdateMemberRole(ctx context.Context, id string, email string, role string) error {
+	query := `
+		UPDATE organization_members
+		SET role = $3
+		WHERE organization_id = $1 AND email = $2;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, email, role)
+	return err
+}
+
+// RemoveMember takes the user off the organization's teams too. It reports
+// false when the user was not a member.
+func (r *OrganizationRepositoryImpl) RemoveMember(ctx context.Context, id string, email string) (bool, error) {
+	query := `
+		DELETE FROM organization_members
+		WHERE organization_id = $1 AND email = $2;
+	`
+
+	return deleted(ctx, r.db, query, id, email)
+}
+
+// CreateTeam reports false when the organization already has a team by that name
+func (r *OrganizationRepositoryImpl) CreateTeam(ctx context.Context, t *organization.Team) (bool, error) {
+	query := `
+		INSERT INTO teams (id, organization_id, name)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, name) DO NOTHING
+		RETURNING created_at;
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, t.ID, t.OrganizationID, t.Name).Scan(&t.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+const teamColumns = `t.id, t.organization_id, t.name, t.created_at,
+			(SELECT COUNT(*) FROM team_members tm WHERE tm.team_id = t.id)`
+
+func scanTeam(row rowScanner, t *organization.Team) error {
+	return row.Scan(&t.ID, &t.OrganizationID, &t.Name, &t.CreatedAt, &t.Members)
+}
+
+// GetTeam returns nil when the organization has no such team
+func (r *OrganizationRepositoryImpl) GetTeam(ctx context.Context, id string, teamID string) (*organization.Team, error) {
+	query := `
+		SELECT ` + teamColumns + `
+		FROM teams t
+		WHERE t.organization_id = $1 AND t.id = $2;
+	`
+
+	t := &organization.Team{}
+	err := scanTeam(conn(ctx, r.db).QueryRowContext(ctx, query, id, teamID), t)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// GetTeams returns the teams of the organization by name
+func (r *OrganizationRepositoryImpl) GetTeams(ctx context.Context, id string) ([]*organization.Team, error) {
+	query := `
+		SELECT ` + teamColumns + `
+		FROM teams t
+		WHERE t.organization_id = $1
+		ORDER BY t.name;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []*organization.Team{}
+	for rows.Next() {
+		t := &organization.Team{}
+		if err := scanTeam(rows, t); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// DeleteTeam reports false when the organization has no such team
+func (r *OrganizationRepositoryImpl) DeleteTeam(ctx context.Context, id string, teamID string) (bool, error) {
+	query := `
+		DELETE FROM teams
+		WHERE organization_id = $1 AND id = $2;
+	`
+
+	return deleted(ctx, r.db, query, id, teamID)
+}
+
+// AddTeamMember does nothing when the member is already on the team
+func (r *OrganizationRepositoryImpl) AddTeamMember(ctx context.Context, id string, teamID string, email string) error {
+	query := `
+		INSERT INTO team_members (team_id, organization_id, email)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (team_id, email) DO NOTHING;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, teamID, id, email)
+	return err
+}
+
+// RemoveTeamMember reports false when the user was not on the team
+func (r *OrganizationRepositoryImpl) RemoveTeamMember(ctx context.Context, teamID string, email string) (bool, error) {
+	query := `
+		DELETE FROM team_members
+		WHERE team_id = $1 AND email = $2;
+	`
+
+	return deleted(ctx, r.db, query, teamID, email)
+}
+
+// GetTeamMembers returns the members of the team by name, with their role
+// in the organization
+func (r *OrganizationRepositoryImpl) GetTeamMembers(ctx context.Context, teamID string) ([]*organization.Member, error) {
+	query := `
+		SELECT u.name, tm.email, m.role, tm.joined_at
+		FROM team_members tm
+		JOIN organization_members m ON m.organization_id = tm.organization_id AND m.email = tm.email
+		JOIN users u ON u.email = tm.email
+		WHERE tm.team_id = $1
+		ORDER BY u.name;
+	`
+
+	return r.queryMembers(ctx, query, teamID)
+}
+
+// membersOnly narrows a query over users down to the members of the
+// organization, or of one of its teams
+func membersOnly(id string, teamID string) (string, []any) {
+	if teamID != "" {
+		return ` WHERE email IN (SELECT email FROM team_members WHERE team_id = $1)`, []any{teamID}
+	}
+	return ` WHERE email IN (SELECT email FROM organization_members WHERE organization_id = $1)`, []any{id}
+}
+
+// GetLeaderboard ranks the members the viewer may see by average performance
+func (r *OrganizationRepositoryImpl) GetLeaderboard(ctx context.Context, id string, teamID string, viewer string,
+	limit int) ([]*organization.LeaderboardEntry, error) {
+	filter, args := membersOnly(id, teamID)
+	query := `
+		SELECT RANK() OVER (ORDER BY avg_performance DESC), name, email, avg_performance, avg_speed, best_speed,
+			avg_accuracy, total_test
+		FROM users` + filter + ` AND ` + visibleTo("$2") + `
+		ORDER BY avg_performance DESC, name
+		LIMIT $3;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, append(args, viewer, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*organization.LeaderboardEntry{}
+	for rows.Next() {
+		e := &organization.LeaderboardEntry{}
+		err := rows.Scan(&e.Rank, &e.Name, &e.Email, &e.Performance, &e.AvgSpeed, &e.BestSpeed, &e.AvgAccuracy,
+			&e.TotalTest)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetDashboardTopData is the site-wide dashboard's aggregate over the members only
+func (r *OrganizationRepositoryImpl) GetDashboardTopData(ctx context.Context, id string, teamID string) (*user.DashboardTopData, error) {
+	filter, args := membersOnly(id, teamID)
+	return queryDashboardTopData(ctx, r.db, filter, args...)
+}
+
+// GetTopPerformers is the site-wide top performers over the members the
+// viewer may see
+func (r *OrganizationRepositoryImpl) GetTopPerformers(ctx context.Context, id string, teamID string,
+	viewer string) ([]*user.TopPerformer, error) {
+	filter, args := membersOnly(id, teamID)
+	return queryTopPerformers(ctx, r.db, filter+` AND `+visibleTo("$2"), append(args, viewer)...)
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+	"typing-speed/internals/core/organization"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrganization_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM organizations o WHERE o.id = ").
+		WithArgs("org-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewOrganizationRepository(db)
+	org, err := repo.GetOrganization(context.Background(), "org-1")
+
+	require.NoError(t, err)
+	assert.Nil(t, org)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrganizationsByEmail_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "domain", "domain_verified", "domain_token", "created_by",
+		"created_at", "members", "role"}).
+		AddRow("org-1", "Acme", "acme.io", true, "token-1", "boss@acme.io", time.Now(), 3, "admin").
+		AddRow("org-2", "Club", nil, false, nil, "x@mail.io", time.Now(), 2, "member")
+
+	mock.ExpectQuery("SELECT (.+) FROM organizations o JOIN organization_members m (.+) WHERE m.email = ").
+		WithArgs("a@acme.io").
+		WillReturnRows(rows)
+
+	repo := NewOrganizationRepository(db)
+	orgs, err := repo.GetOrganizationsByEmail(context.Background(), "a@acme.io")
+
+	require.NoError(t, err)
+	require.Len(t, orgs, 2)
+	assert.Equal(t, "acme.io", orgs[0].Domain)
+	assert.Equal(t, "admin", orgs[0].Role)
+	assert.True(t, orgs[0].Verified)
+	assert.Equal(t, "token-1", orgs[0].Token)
+	assert.Equal(t, "", orgs[1].Domain)
+	assert.Equal(t, 2, orgs[1].Members)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrganizationByDomain_Unverified(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM organizations o WHERE o.domain = \\$1 AND o.domain_verified").
+		WithArgs("acme.io").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewOrganizationRepository(db)
+	org, err := repo.GetOrganizationByDomain(context.Background(), "acme.io")
+
+	require.NoError(t, err)
+	assert.Nil(t, org)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddMembersByDomain(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO organization_members (.+) SELECT \\$1, email, 'member' FROM users "+
+		"WHERE SPLIT_PART\\(LOWER\\(email\\), '@', 2\\) = \\$2 ON CONFLICT").
+		WithArgs("org-1", "acme.io").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewOrganizationRepository(db)
+	err = repo.AddMembersByDomain(context.Background(), "org-1", "acme.io")
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTeam_Exists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("INSERT INTO teams (.+) ON CONFLICT (.+) DO NOTHING").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+
+	repo := NewOrganizationRepository(db)
+	created, err := repo.CreateTeam(context.Background(), &organization.Team{ID: "team-1", OrganizationID: "org-1",
+		Name: "Platform"})
+
+	require.NoError(t, err)
+	assert.False(t, created)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrganizationDashboardTopData_Team(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"avg_speed", "avg_accuracy", "total_test"}).
+		AddRow(72.8, 95.1, int64(40))
+
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE email IN \\(SELECT email FROM team_members WHERE team_id = ").
+		WithArgs("team-1").
+		WillReturnRows(rows)
+
+	repo := NewOrganizationRepository(db)
+	data, err := repo.GetDashboardTopData(context.Background(), "org-1", "team-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), data.TotalTest)
+	assert.Equal(t, 72, data.AverageSpeed)
+	assert.Equal(t, 95, data.AverageAccuracy)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrganizationLeaderboard_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"rank", "name", "email", "avg_performance", "avg_speed", "best_speed",
+		"avg_accuracy", "total_test"}).
+		AddRow(1, "Dev", "dev@acme.io", 90, 85, 110, 97, 40)
+
+	mock.ExpectQuery("SELECT RANK\\(\\) OVER (.+) FROM users WHERE email IN \\(SELECT email FROM organization_members (.+) LIMIT").
+		WithArgs("org-1", "dev@acme.io", 50).
+		WillReturnRows(rows)
+
+	repo := NewOrganizationRepository(db)
+	entries, err := repo.GetLeaderboard(context.Background(), "org-1", "", "dev@acme.io", 50)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "dev@acme.io", entries[0].Email)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/typing"
+)
+
+type PersonalBestRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewPersonalBestRepository(db *sql.DB) port.PersonalBestRepository {
+	return &PersonalBestRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *PersonalBestRepositoryImpl) GetPersonalBest(ctx context.Context, email string, mode string, language string) (*typing.PersonalBest, error) {
+	query := `
+		SELECT email, mode, language, wpm, accuracy, test_id, achieved_at
+		FROM personal_bests
+		WHERE email = $1 AND mode = $2 AND language = $3;
+	`
+
+	pb := &typing.PersonalBest{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, email, mode, language).Scan(
+		&pb.Email,
+		&pb.Mode,
+		&pb.Language,
+		&pb.WPM,
+		&pb.Accuracy,
+		&pb.TestID,
+		&pb.AchievedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // no personal best yet
+		}
+		return nil, err
+	}
+
+	return pb, nil
+}
+
+func (r *PersonalBestRepositoryImpl) GetPersonalBests(ctx context.Context, email string) ([]*typing.PersonalBest, error) {
+	query := `
+		SELECT email, mode, language, wpm, accuracy, test_id, achieved_at
+		FROM personal_bests
+		WHERE email = $1
+		ORDER BY mode, language;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pbs := []*typing.PersonalBest{}
+
+	for rows.Next() {
+		pb := &typing.PersonalBest{}
+		if err := rows.Scan(&pb.Email, &pb.Mode, &pb.Language, &pb.WPM, &pb.Accuracy, &pb.TestID, &pb.AchievedAt); err != nil {
+			return nil, err
+		}
+		pbs = append(pbs, pb)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return pbs, nil
+}
+
+// UpsertPersonalBest replaces the personal best of the mode and language and records the break in the history
+func (r *PersonalBestRepositoryImpl) UpsertPersonalBest(ctx context.Context, pb *typing.PersonalBest, previousWPM int) error {
+	query := `
+		INSERT INTO personal_bests (email, mode, language, wpm, accuracy, test_id, achieved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (email, mode, language) DO UPDATE
+		SET wpm = EXCLUDED.wpm,
+		    accuracy = EXCLUDED.accuracy,
+		    test_id = EXCLUDED.test_id,
+		    achieved_at = EXCLUDED.achieved_at;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, pb.Email, pb.Mode, pb.Language, pb.WPM, pb.Accuracy, pb.TestID, pb.AchievedAt)
+	if err != nil {
+		return err
+	}
+
+	historyQuery := `
+		INSERT INTO personal_best_history (email, mode, language, wpm, accuracy, previous_wpm, test_id, achieved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+	`
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, historyQuery, pb.Email, pb.Mode, pb.Language, pb.WPM, pb.Accuracy, previousWPM, pb.TestID, pb.AchievedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetPersonalBestHistory lists broken personal bests, newest first. Empty mode or language match all.
+func (r *PersonalBestRepositoryImpl) GetPersonalBestHistory(ctx context.Context, email string, mode string, language string) ([]*typing.PersonalBestRecord, error) {
+	query := `
+		SELECT email, mode, language, wpm, accuracy, test_id, achieved_at, previous_wpm
+		FROM personal_best_history
+		WHERE email = $1
+		  AND ($2 = '' OR mode = $2)
+		  AND ($3 = '' OR language = $3)
+		ORDER BY achieved_at DESC;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, email, mode, language)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*typing.PersonalBestRecord{}
+
+	for rows.Next() {
+		rec := &typing.PersonalBestRecord{}
+		if err := rows.Scan(
+			&rec.Email,
+			&rec.Mode,
+			&rec.Language,
+			&rec.WPM,
+			&rec.Accuracy,
+			&rec.TestID,
+			&rec.AchievedAt,
+			&rec.PreviousWPM,
+		); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+	"typing-speed/internals/core/typing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPersonalBest_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM personal_bests WHERE email = (.+) AND mode = (.+) AND language =").
+		WithArgs("test@test.com", "60s", "english").
+		WillReturnRows(sqlmock.NewRows([]string{"email", "mode", "language", "wpm", "accuracy", "test_id", "achieved_at"}))
+
+	repo := NewPersonalBestRepository(db)
+	pb, err := repo.GetPersonalBest(context.Background(), "test@test.com", "60s", "english")
+
+	require.NoError(t, err)
+	assert.Nil(t, pb)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertPersonalBest_WritesHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	pb := &typing.PersonalBest{
+		Email:      "test@test.com",
+		Mode:       "60s",
+		Language:   "english",
+		WPM:        92,
+		Accuracy:   97,
+		TestID:     "test-id",
+		AchievedAt: time.Now(),
+	}
+
+	mock.ExpectExec("INSERT INTO personal_bests (.+) ON CONFLICT").
+		WithArgs(pb.Email, pb.Mode, pb.Language, pb.WPM, pb.Accuracy, pb.TestID, pb.AchievedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO personal_best_history").
+		WithArgs(pb.Email, pb.Mode, pb.Language, pb.WPM, pb.Accuracy, 85, pb.TestID, pb.AchievedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := NewPersonalBestRepository(db)
+	err = repo.UpsertPersonalBest(context.Background(), pb, 85)
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
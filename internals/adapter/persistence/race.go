@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/race"
+)
+
+type RaceRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewRaceRepository(db *sql.DB) port.RaceRepository {
+	return &RaceRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *RaceRepositoryImpl) InsertRace(ctx context.Context, rc *race.Race) error {
+	query := `
+		INSERT INTO races (id, text, mode, language, rated, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, rc.ID, rc.Text, rc.Mode, rc.Language, rc.Rated, rc.StartedAt,
+		rc.FinishedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RaceRepositoryImpl) InsertRaceResults(ctx context.Context, raceID string, results []*race.Result) error {
+	query := `
+		INSERT INTO race_results (race_id, email, position, wpm, accuracy, duration, test_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+
+	for _, res := range results {
+		_, err := conn(ctx, r.db).ExecContext(ctx, query, raceID, res.Email, res.Position, res.WPM, res.Accuracy,
+			res.Duration, nullString(res.TestID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetRace returns the race with its results in finishing order, or nil when
+// there is no such race. Racers the viewer may not see are left anonymous.
+func (r *RaceRepositoryImpl) GetRace(ctx context.Context, raceID string, viewer string) (*race.Race, error) {
+	query := `
+		SELECT id, text, mode, language, rated, started_at, finished_at
+		FROM races
+		WHERE id = $1;
+	`
+
+	rc := &race.Race{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, raceID).Scan(&rc.ID, &rc.Text, &rc.Mode, &rc.Language, &rc.Rated,
+		&rc.StartedAt, &rc.FinishedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	resultsQuery := `
+		SELECT ` + visibleName + `, rr.position, rr.wpm, rr.accuracy, rr.duration,
+			CASE WHEN u.visible THEN COALESCE(rr.test_id::text, '') ELSE '' END
+		FROM race_results rr
+		JOIN ` + viewedUsers("$2") + ` u ON u.email = rr.email
+		WHERE rr.race_id = $1
+		ORDER BY rr.position = 0, rr.position, rr.wpm DESC;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, resultsQuery, raceID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rc.Results = []*race.Result{}
+	for rows.Next() {
+		res := &race.Result{}
+		if err := rows.Scan(&res.Name, &res.Position, &res.WPM, &res.Accuracy, &res.Duration, &res.TestID); err != nil {
+			return nil, err
+		}
+		rc.Results = append(rc.Results, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rc, nil
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+	"typing-speed/internals/core/race"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertRaceResults_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	results := []*race.Result{
+		{Email: "a@test.com", Position: 1, WPM: 90, Accuracy: 98, Duration: 21.5, TestID: "test-1"},
+		{Email: "b@test.com", WPM: 40, Accuracy: 90, Duration: 60},
+	}
+
+	mock.ExpectExec("INSERT INTO race_results").
+		WithArgs("race-id", "a@test.com", 1, 90, 98, 21.5, nullString("test-1")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO race_results").
+		WithArgs("race-id", "b@test.com", 0, 40, 90, 60.0, nullString("")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewRaceRepository(db)
+	err = repo.InsertRaceResults(context.Background(), "race-id", results)
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRace_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT id, text, mode, language, rated, started_at, finished_at FROM races WHERE id =").
+		WithArgs("race-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "text", "mode", "language", "rated", "started_at", "finished_at"}).
+			AddRow("race-id", "the quick fox", "30s", "english", true, now, now))
+	mock.ExpectQuery("SELECT (.+) FROM race_results rr JOIN (.+) u ON u.email = rr.email").
+		WithArgs("race-id", "alice@mail.com").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "position", "wpm", "accuracy", "duration", "test_id"}).
+			AddRow("Alice", 1, 90, 98, 21.5, "test-1").
+			AddRow("Bob", 0, 40, 90, 60.0, ""))
+
+	repo := NewRaceRepository(db)
+	data, err := repo.GetRace(context.Background(), "race-id", "alice@mail.com")
+
+	require.NoError(t, err)
+	assert.Equal(t, "the quick fox", data.Text)
+	assert.Equal(t, "30s", data.Mode)
+	assert.True(t, data.Rated)
+	require.Len(t, data.Results, 2)
+	assert.Equal(t, "Alice", data.Results[0].Name)
+	assert.Equal(t, 0, data.Results[1].Position)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRace_PrivateRacer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT id, text, mode, language, rated, started_at, finished_at FROM races WHERE id =").
+		WithArgs("race-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "text", "mode", "language", "rated", "started_at", "finished_at"}).
+			AddRow("race-id", "the quick fox", "30s", "english", true, now, now))
+	// the name and the test of a racer the viewer may not see come back hidden
+	mock.ExpectQuery("SELECT CASE WHEN u.visible THEN u.name ELSE 'Anonymous' END, (.+) "+
+		"CASE WHEN u.visible THEN COALESCE\\(rr.test_id::text, ''\\) ELSE '' END "+
+		"FROM race_results rr JOIN \\(SELECT email, name, \\(privacy = 'public' OR email = \\$2 (.+) AS visible FROM users\\) u").
+		WithArgs("race-id", "bob@mail.com").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "position", "wpm", "accuracy", "duration", "test_id"}).
+			AddRow("Anonymous", 1, 90, 98, 21.5, ""))
+
+	repo := NewRaceRepository(db)
+	data, err := repo.GetRace(context.Background(), "race-id", "bob@mail.com")
+
+	require.NoError(t, err)
+	require.Len(t, data.Results, 1)
+	assert.Equal(t, "Anonymous", data.Results[0].Name)
+	assert.Empty(t, data.Results[0].TestID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
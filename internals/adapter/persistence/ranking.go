@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/ranking"
+)
+
+type RankingRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewRankingRepository(db *sql.DB) port.RankingRepository {
+	return &RankingRepositoryImpl{
+		db: db,
+	}
+}
+
+const rankingColumns = `email, name, COALESCE(username, ''), avg_performance`
+
+func scanRanking(row rowScanner) (*ranking.Entry, error) {
+	e := &ranking.Entry{}
+	if err := row.Scan(&e.Email, &e.Name, &e.Username, &e.Performance); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// GetRankings returns every user with a public profile, in no order
+func (r *RankingRepositoryImpl) GetRankings(ctx context.Context) ([]*ranking.Entry, error) {
+	query := `SELECT ` + rankingColumns + ` FROM users WHERE privacy = 'public';`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*ranking.Entry{}
+	for rows.Next() {
+		e, err := scanRanking(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetRanking returns the user if their profile is public
+func (r *RankingRepositoryImpl) GetRanking(ctx context.Context, email string) (*ranking.Entry, error) {
+	query := `SELECT ` + rankingColumns + ` FROM users WHERE email = $1 AND privacy = 'public';`
+
+	e, err := scanRanking(conn(ctx, r.db).QueryRowContext(ctx, query, email))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
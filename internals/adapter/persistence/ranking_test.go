@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRanking(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRankingRepository(db)
+
+	rows := sqlmock.NewRows([]string{"email", "name", "username", "avg_performance"}).
+		AddRow("n@mail.com", "Navneet", "navneet", 7200)
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE email = \\$1 AND privacy = 'public'").
+		WithArgs("n@mail.com").
+		WillReturnRows(rows)
+
+	e, err := repo.GetRanking(context.Background(), "n@mail.com")
+	require.NoError(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, 7200, e.Performance)
+	assert.Equal(t, "navneet", e.Username)
+
+	// a private user is not ranked
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE email = \\$1 AND privacy = 'public'").
+		WithArgs("p@mail.com").
+		WillReturnRows(sqlmock.NewRows([]string{"email", "name", "username", "avg_performance"}))
+
+	e, err = repo.GetRanking(context.Background(), "p@mail.com")
+	require.NoError(t, err)
+	assert.Nil(t, e)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/rating"
+
+	"github.com/lib/pq"
+)
+
+type RatingRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewRatingRepository(db *sql.DB) port.RatingRepository {
+	return &RatingRepositoryImpl{
+		db: db,
+	}
+}
+
+func scanRating(row rowScanner, r *rating.Rating) error {
+	return row.Scan(&r.Email, &r.Rating, &r.Deviation, &r.Volatility, &r.Races, &r.UpdatedAt)
+}
+
+// GetRating returns nil when the user has never played a rated race
+func (r *RatingRepositoryImpl) GetRating(ctx context.Context, email string) (*rating.Rating, error) {
+	query := `
+		SELECT email, rating, deviation, volatility, races, updated_at
+		FROM user_ratings
+		WHERE email = $1;
+	`
+
+	data := &rating.Rating{}
+	err := scanRating(conn(ctx, r.db).QueryRowContext(ctx, query, email), data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetRatingsForUpdate locks the stored ratings of the users until the transaction ends
+func (r *RatingRepositoryImpl) GetRatingsForUpdate(ctx context.Context, emails []string) ([]*rating.Rating, error) {
+	query := `
+		SELECT email, rating, deviation, volatility, races, updated_at
+		FROM user_ratings
+		WHERE email = ANY($1)
+		ORDER BY email
+		FOR UPDATE;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(emails))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := []*rating.Rating{}
+	for rows.Next() {
+		data := &rating.Rating{}
+		if err := scanRating(rows, data); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+// SaveRating stores the rating a race left the user with and adds it to their history
+func (r *RatingRepositoryImpl) SaveRating(ctx context.Context, raceID string, data *rating.Rating, change float64) error {
+	query := `
+		INSERT INTO user_ratings (email, rating, deviation, volatility, races, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (email) DO UPDATE
+		SET rating = EXCLUDED.rating,
+		    deviation = EXCLUDED.deviation,
+		    volatility = EXCLUDED.volatility,
+		    races = EXCLUDED.races,
+		    updated_at = EXCLUDED.updated_at;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, data.Email, data.Rating, data.Deviation, data.Volatility, data.Races)
+	if err != nil {
+		return err
+	}
+
+	historyQuery := `
+		INSERT INTO rating_history (email, race_id, rating, deviation, change)
+		VALUES ($1, $2, $3, $4, $5);
+	`
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, historyQuery, data.Email, raceID, data.Rating, data.Deviation, change)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RatingRepositoryImpl) GetRatingHistory(ctx context.Context, email string, limit int) ([]*rating.Change, error) {
+	query := `
+		SELECT race_id, rating, deviation, change, created_at
+		FROM rating_history
+		WHERE email = $1
+		ORDER BY created_at DESC
+		LIMIT $2;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, email, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []*rating.Change{}
+	for rows.Next() {
+		c := &rating.Change{}
+		if err := rows.Scan(&c.RaceID, &c.Rating, &c.Deviation, &c.Change, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
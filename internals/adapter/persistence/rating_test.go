@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+	"typing-speed/internals/core/rating"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRatingsForUpdate_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	emails := []string{"a@test.com", "b@test.com"}
+	rows := sqlmock.NewRows([]string{"email", "rating", "deviation", "volatility", "races", "updated_at"}).
+		AddRow("a@test.com", 1612.5, 80.2, 0.059, 12, time.Now())
+
+	mock.ExpectQuery("SELECT (.+) FROM user_ratings WHERE email = ANY(.+) FOR UPDATE").
+		WithArgs(pq.Array(emails)).
+		WillReturnRows(rows)
+
+	repo := NewRatingRepository(db)
+	ratings, err := repo.GetRatingsForUpdate(context.Background(), emails)
+
+	require.NoError(t, err)
+	require.Len(t, ratings, 1)
+	assert.Equal(t, 1612.5, ratings[0].Rating)
+	assert.Equal(t, 12, ratings[0].Races)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveRating_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	r := &rating.Rating{Email: "a@test.com", Rating: 1520, Deviation: 300, Volatility: 0.06, Races: 1}
+
+	mock.ExpectExec("INSERT INTO user_ratings (.+) ON CONFLICT \\(email\\) DO UPDATE").
+		WithArgs("a@test.com", 1520.0, 300.0, 0.06, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO rating_history").
+		WithArgs("a@test.com", "race-id", 1520.0, 300.0, 20.0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := NewRatingRepository(db)
+	err = repo.SaveRating(context.Background(), "race-id", r, 20)
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
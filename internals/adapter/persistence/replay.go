@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/replay"
+)
+
+type ReplayRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewReplayRepository(db *sql.DB) port.ReplayRepository {
+	return &ReplayRepositoryImpl{
+		db: db,
+	}
+}
+
+const replayColumns = `t.id, t.mode, t.language, t.wpm,
+		       CASE WHEN t.total_words = 0 THEN 0 ELSE (t.typed_words - t.total_error) * 100 / t.total_words END,
+		       t.total_time, t.timeline, t.created_at`
+
+func scanReplay(row rowScanner, r *replay.Replay, extra ...any) error {
+	var timeline []byte
+	dest := append([]any{
+		&r.TestID,
+		&r.Mode,
+		&r.Language,
+		&r.WPM,
+		&r.Accuracy,
+		&r.TotalTime,
+		&timeline,
+		&r.CreatedAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+
+	var err error
+	r.Timeline, err = decodeTimeline(timeline)
+	return err
+}
+
+// queryReplay returns nil when no replay matches
+func (r *ReplayRepositoryImpl) queryReplay(ctx context.Context, query string, args ...any) (*replay.Replay, error) {
+	data := &replay.Replay{}
+	err := scanReplay(conn(ctx, r.db).QueryRowContext(ctx, query, args...), data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *ReplayRepositoryImpl) GetReplay(ctx context.Context, email string, testID string) (*replay.Replay, error) {
+	query := `
+		SELECT ` + replayColumns + `
+		FROM user_typing_data t
+		WHERE t.email = $1 AND t.id = $2 AND t.timeline IS NOT NULL;
+	`
+
+	return r.queryReplay(ctx, query, email, testID)
+}
+
+// GetPersonalBestReplay returns the replay of the test holding the personal best of mode and language
+func (r *ReplayRepositoryImpl) GetPersonalBestReplay(ctx context.Context, email string, mode string, language string) (*replay.Replay, error) {
+	query := `
+		SELECT ` + replayColumns + `
+		FROM personal_bests pb
+		JOIN user_typing_data t ON t.id = pb.test_id
+		WHERE pb.email = $1 AND pb.mode = $2 AND pb.language = $3 AND t.timeline IS NOT NULL;
+	`
+
+	return r.queryReplay(ctx, query, email, mode, language)
+}
+
+// SetShareToken shares the replay with token, or keeps the token it was already shared with.
+// It returns the replay's token, or an empty string when the user has no such replay.
+func (r *ReplayRepositoryImpl) SetShareToken(ctx context.Context, email string, testID string, token string) (string, error) {
+	query := `
+		UPDATE user_typing_data
+		SET share_token = COALESCE(share_token, $3)
+		WHERE email = $1 AND id = $2 AND timeline IS NOT NULL
+		RETURNING share_token;
+	`
+
+	var shared string
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, email, testID, token).Scan(&shared)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return shared, nil
+}
+
+// GetSharedReplay returns a shared replay with the name of its owner instead of their email
+func (r *ReplayRepositoryImpl) GetSharedReplay(ctx context.Context, token string) (*replay.Replay, error) {
+	query := `
+		SELECT ` + replayColumns + `, u.name
+		FROM user_typing_data t
+		JOIN users u ON u.email = t.email
+		WHERE t.share_token = $1 AND t.timeline IS NOT NULL;
+	`
+
+	data := &replay.Replay{}
+	err := scanReplay(conn(ctx, r.db).QueryRowContext(ctx, query, token), data, &data.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// encodeTimeline compresses a timeline into the uvarint deltas between seconds,
+// which is about a byte per second. Empty timelines are stored as NULL.
+func encodeTimeline(timeline []int) []byte {
+	if len(timeline) == 0 {
+		return nil
+	}
+
+	buf := make([]byte, 0, len(timeline))
+	prev := 0
+	for _, chars := range timeline {
+		buf = binary.AppendUvarint(buf, uint64(chars-prev))
+		prev = chars
+	}
+	return buf
+}
+
+func decodeTimeline(buf []byte) ([]int, error) {
+	timeline := []int{}
+	total := 0
+	for len(buf) > 0 {
+		delta, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return nil, errors.New("corrupt replay timeline")
+		}
+		total += int(delta)
+		timeline = append(timeline, total)
+		buf = buf[n:]
+	}
+	return timeline, nil
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimelineEncoding(t *testing.T) {
+	timeline := []int{0, 4, 9, 9, 15, 300, 301}
+
+	buf := encodeTimeline(timeline)
+	assert.Len(t, buf, 8) // one byte per second, two for the jump to 300
+
+	decoded, err := decodeTimeline(buf)
+	require.NoError(t, err)
+	assert.Equal(t, timeline, decoded)
+
+	assert.Nil(t, encodeTimeline(nil))
+
+	_, err = decodeTimeline([]byte{0x80})
+	assert.Error(t, err)
+}
+
+func TestGetReplay_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "mode", "language", "wpm", "accuracy", "total_time", "timeline", "created_at",
+	}).AddRow("test-id", "15s", "english", 72, 96, 15, encodeTimeline([]int{0, 6, 12}), time.Now())
+
+	mock.ExpectQuery("SELECT (.+) FROM user_typing_data t WHERE t.email = (.+) AND t.id = (.+) AND t.timeline IS NOT NULL").
+		WithArgs("test@test.com", "test-id").
+		WillReturnRows(rows)
+
+	repo := NewReplayRepository(db)
+	data, err := repo.GetReplay(context.Background(), "test@test.com", "test-id")
+
+	require.NoError(t, err)
+	assert.Equal(t, "15s", data.Mode)
+	assert.Equal(t, []int{0, 6, 12}, data.Timeline)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetShareToken_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("UPDATE user_typing_data SET share_token = COALESCE").
+		WithArgs("test@test.com", "test-id", "token").
+		WillReturnRows(sqlmock.NewRows([]string{"share_token"}))
+
+	repo := NewReplayRepository(db)
+	token, err := repo.SetShareToken(context.Background(), "test@test.com", "test-id", "token")
+
+	require.NoError(t, err)
+	assert.Equal(t, "", token)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/stats"
+)
+
+type StatsRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewStatsRepository(db *sql.DB) port.StatsRepository {
+	return &StatsRepositoryImpl{
+		db: db,
+	}
+}
+
+// GetProgress sums up the user's tests in every bucket of the range. The
+// buckets are read in the query's time zone; created_at holds UTC wall-clock
+// time, so it is read as UTC before it is moved to that zone. The series starts
+// MovingAverageBuckets-1 buckets early so the moving average of the first
+// bucket covers a full window, and those buckets are dropped at the end.
+func (r *StatsRepositoryImpl) GetProgress(ctx context.Context, q *stats.Query) ([]*stats.Point, error) {
+	query := `
+		WITH buckets AS (
+			SELECT generate_series($3::date::timestamp, $5::date::timestamp, ('1 ' || $6)::interval) AS bucket
+		), tests AS (
+			SELECT date_trunc($6, (created_at AT TIME ZONE 'UTC') AT TIME ZONE $7) AS bucket, wpm,
+				` + testAccuracy + ` AS accuracy
+			FROM user_typing_data
+			WHERE email = $1 AND ($2 = '' OR mode = $2)
+				AND created_at >= ($3::date::timestamp AT TIME ZONE $7) AT TIME ZONE 'UTC'
+				AND created_at < (($5::date::timestamp + ('1 ' || $6)::interval) AT TIME ZONE $7) AT TIME ZONE 'UTC'
+		), totals AS (
+			SELECT bucket, COUNT(*) AS tests, AVG(wpm) AS avg_wpm, MAX(wpm) AS best_wpm,
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY wpm) AS median_wpm, AVG(accuracy) AS avg_accuracy
+			FROM tests
+			GROUP BY bucket
+		), series AS (
+			SELECT b.bucket, t.tests, t.avg_wpm, t.best_wpm, t.median_wpm, t.avg_accuracy,
+				AVG(t.avg_wpm) OVER (ORDER BY b.bucket ROWS BETWEEN $8 PRECEDING AND CURRENT ROW) AS moving_wpm
+			FROM buckets b
+			LEFT JOIN totals t ON t.bucket = b.bucket
+		)
+		SELECT to_char(bucket, 'YYYY-MM-DD'), COALESCE(tests, 0),
+			COALESCE(ROUND(avg_wpm, 1), 0)::float8, COALESCE(best_wpm, 0),
+			COALESCE(ROUND(median_wpm::numeric, 1), 0)::float8, COALESCE(ROUND(avg_accuracy, 1), 0)::float8,
+			COALESCE(ROUND(moving_wpm, 1), 0)::float8
+		FROM series
+		WHERE bucket >= $4::date::timestamp
+		ORDER BY bucket;
+	`
+
+	warmup := stats.AddBuckets(q.Bucket, q.From, 1-stats.MovingAverageBuckets)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, q.Email, q.Mode, calendarDate(warmup), calendarDate(q.From), calendarDate(q.To),
+		q.Bucket, q.Location.String(), stats.MovingAverageBuckets-1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []*stats.Point{}
+	for rows.Next() {
+		p := &stats.Point{}
+		if err := rows.Scan(&p.Start, &p.Tests, &p.AverageWPM, &p.BestWPM, &p.MedianWPM, &p.AverageAccuracy,
+			&p.MovingAverageWPM); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// calendarDate writes the calendar date of t, which Postgres reads without a time zone
+func calendarDate(t time.Time) string {
+	return t.Format(stats.DateLayout)
+}
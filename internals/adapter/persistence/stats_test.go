@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+	"typing-speed/internals/core/stats"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProgress(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"start", "tests", "avg_wpm", "best_wpm", "median_wpm", "avg_accuracy", "moving_wpm"}).
+		AddRow("2026-09-28", 0, 0.0, 0, 0.0, 0.0, 61.5).
+		AddRow("2026-10-05", 4, 70.5, 82, 69.0, 96.3, 66.0)
+
+	// the series starts six weeks early to fill the first moving average, and
+	// created_at is read as UTC before it is moved to the user's zone
+	mock.ExpectQuery("WITH buckets AS (.+) date_trunc\\(\\$6, \\(created_at AT TIME ZONE 'UTC'\\) AT TIME ZONE \\$7\\) "+
+		"(.+) percentile_cont\\(0.5\\) (.+) ROWS BETWEEN \\$8 PRECEDING AND CURRENT ROW\\)(.+) "+
+		"WHERE bucket >= \\$4::date::timestamp").
+		WithArgs("a@mail.com", "60s", "2026-08-17", "2026-09-28", "2026-10-05", "week", "Asia/Kolkata", 6).
+		WillReturnRows(rows)
+
+	repo := NewStatsRepository(db)
+	points, err := repo.GetProgress(context.Background(), &stats.Query{
+		Email:    "a@mail.com",
+		Bucket:   stats.BucketWeek,
+		Mode:     "60s",
+		From:     time.Date(2026, 9, 28, 0, 0, 0, 0, loc),
+		To:       time.Date(2026, 10, 5, 0, 0, 0, 0, loc),
+		Location: loc,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	assert.Equal(t, 0, points[0].Tests)
+	assert.Equal(t, 61.5, points[0].MovingAverageWPM)
+	assert.Equal(t, 69.0, points[1].MedianWPM)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
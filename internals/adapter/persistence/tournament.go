@@ -0,0 +1,295 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/tournament"
+)
+
+type TournamentRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTournamentRepository(db *sql.DB) port.TournamentRepository {
+	return &TournamentRepositoryImpl{
+		db: db,
+	}
+}
+
+const tournamentColumns = `t.id, t.name, t.format, t.seeding, t.mode, t.best_of, t.max_entrants,
+	t.registration_opens_at, t.registration_closes_at, t.status, t.created_by, t.created_at, t.started_at, t.finished_at,
+	(SELECT COUNT(*) FROM tournament_entrants e WHERE e.tournament_id = t.id)`
+
+func scanTournament(row rowScanner, t *tournament.Tournament) error {
+	return row.Scan(
+		&t.ID,
+		&t.Name,
+		&t.Format,
+		&t.Seeding,
+		&t.Mode,
+		&t.BestOf,
+		&t.MaxEntrants,
+		&t.RegistrationOpensAt,
+		&t.RegistrationClosesAt,
+		&t.Status,
+		&t.CreatedBy,
+		&t.CreatedAt,
+		&t.StartedAt,
+		&t.FinishedAt,
+		&t.Entrants,
+	)
+}
+
+func (r *TournamentRepositoryImpl) CreateTournament(ctx context.Context, t *tournament.Tournament) error {
+	query := `
+		INSERT INTO tournaments (id, name, format, seeding, mode, best_of, max_entrants,
+			registration_opens_at, registration_closes_at, status, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING created_at;
+	`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, t.ID, t.Name, t.Format, t.Seeding, t.Mode, t.BestOf,
+		t.MaxEntrants, t.RegistrationOpensAt, t.RegistrationClosesAt, t.Status, t.CreatedBy).Scan(&t.CreatedAt)
+}
+
+// GetTournament returns nil when there is no such tournament
+func (r *TournamentRepositoryImpl) GetTournament(ctx context.Context, id string) (*tournament.Tournament, error) {
+	query := `
+		SELECT ` + tournamentColumns + `
+		FROM tournaments t
+		WHERE t.id = $1;
+	`
+
+	return r.getTournament(ctx, query, id)
+}
+
+// GetTournamentForUpdate locks the tournament until the transaction ends, so
+// registrations and match results are applied one at a time
+func (r *TournamentRepositoryImpl) GetTournamentForUpdate(ctx context.Context, id string) (*tournament.Tournament, error) {
+	query := `
+		SELECT ` + tournamentColumns + `
+		FROM tournaments t
+		WHERE t.id = $1
+		FOR UPDATE;
+	`
+
+	return r.getTournament(ctx, query, id)
+}
+
+func (r *TournamentRepositoryImpl) getTournament(ctx context.Context, query string, id string) (*tournament.Tournament, error) {
+	t := &tournament.Tournament{}
+	err := scanTournament(conn(ctx, r.db).QueryRowContext(ctx, query, id), t)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// GetTournaments returns the latest tournaments, newest first
+func (r *TournamentRepositoryImpl) GetTournaments(ctx context.Context, limit int) ([]*tournament.Tournament, error) {
+	query := `
+		SELECT ` + tournamentColumns + `
+		FROM tournaments t
+		ORDER BY t.created_at DESC
+		LIMIT $1;
+	`
+
+	return r.queryTournaments(ctx, query, limit)
+}
+
+func (r *TournamentRepositoryImpl) GetRunningTournaments(ctx context.Context) ([]*tournament.Tournament, error) {
+	query := `
+		SELECT ` + tournamentColumns + `
+		FROM tournaments t
+		WHERE t.status = $1;
+	`
+
+	return r.queryTournaments(ctx, query, tournament.StatusRunning)
+}
+
+func (r *TournamentRepositoryImpl) queryTournaments(ctx context.Context, query string, args ...any) ([]*tournament.Tournament, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tournaments := []*tournament.Tournament{}
+	for rows.Next() {
+		t := &tournament.Tournament{}
+		if err := scanTournament(rows, t); err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tournaments, nil
+}
+
+// UpdateTournamentStatus moves the tournament on, stamping the start or the finish with at
+func (r *TournamentRepositoryImpl) UpdateTournamentStatus(ctx context.Context, id string, status string, at time.Time) error {
+	query := `
+		UPDATE tournaments
+		SET status = $2,
+			started_at = CASE WHEN $2 = 'running' THEN $3 ELSE started_at END,
+			finished_at = CASE WHEN $2 = 'finished' THEN $3 ELSE finished_at END
+		WHERE id = $1;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, status, at)
+	return err
+}
+
+// AddEntrant registers the user, doing nothing when they already are
+func (r *TournamentRepositoryImpl) AddEntrant(ctx context.Context, id string, email string) error {
+	query := `
+		INSERT INTO tournament_entrants (tournament_id, email)
+		VALUES ($1, $2)
+		ON CONFLICT (tournament_id, email) DO NOTHING;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, email)
+	return err
+}
+
+// RemoveEntrant reports false when the user was not registered
+func (r *TournamentRepositoryImpl) RemoveEntrant(ctx context.Context, id string, email string) (bool, error) {
+	query := `
+		DELETE FROM tournament_entrants
+		WHERE tournament_id = $1 AND email = $2;
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, email)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// GetEntrants returns the entrants by seed, in order of registration before
+// seeding. The names of entrants the viewer may not see are hidden.
+func (r *TournamentRepositoryImpl) GetEntrants(ctx context.Context, id string, viewer string) ([]*tournament.Entrant, error) {
+	query := `
+		SELECT e.email, ` + visibleName + `, e.seed, e.seed_score
+		FROM tournament_entrants e
+		JOIN ` + viewedUsers("$2") + ` u ON u.email = e.email
+		WHERE e.tournament_id = $1
+		ORDER BY e.seed, e.registered_at;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, id, viewer)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entrants := []*tournament.Entrant{}
+	for rows.Next() {
+		e := &tournament.Entrant{}
+		if err := rows.Scan(&e.Email, &e.Name, &e.Seed, &e.SeedScore); err != nil {
+			return nil, err
+		}
+		entrants = append(entrants, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entrants, nil
+}
+
+func (r *TournamentRepositoryImpl) SetSeeds(ctx context.Context, id string, entrants []*tournament.Entrant) error {
+	query := `
+		UPDATE tournament_entrants
+		SET seed = $3, seed_score = $4
+		WHERE tournament_id = $1 AND email = $2;
+	`
+
+	for _, e := range entrants {
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, id, e.Email, e.Seed, e.SeedScore); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *TournamentRepositoryImpl) InsertMatches(ctx context.Context, id string, matches []*tournament.Match) error {
+	query := `
+		INSERT INTO tournament_matches (id, tournament_id, round, slot, player_a, player_b, winner, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+	`
+
+	for _, m := range matches {
+		_, err := conn(ctx, r.db).ExecContext(ctx, query, m.ID, id, m.Round, m.Slot, nullString(m.PlayerA),
+			nullString(m.PlayerB), nullString(m.Winner), m.Status)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetMatches returns the matches by round and slot
+func (r *TournamentRepositoryImpl) GetMatches(ctx context.Context, id string) ([]*tournament.Match, error) {
+	query := `
+		SELECT id, round, slot, COALESCE(player_a, ''), COALESCE(player_b, ''), COALESCE(winner, ''), status,
+			COALESCE(room_id::text, ''), COALESCE(race_id::text, ''), finished_at
+		FROM tournament_matches
+		WHERE tournament_id = $1
+		ORDER BY round, slot;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []*tournament.Match{}
+	for rows.Next() {
+		m := &tournament.Match{}
+		err := rows.Scan(&m.ID, &m.Round, &m.Slot, &m.PlayerA, &m.PlayerB, &m.Winner, &m.Status, &m.RoomID,
+			&m.RaceID, &m.FinishedAt)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (r *TournamentRepositoryImpl) UpdateMatch(ctx context.Context, m *tournament.Match) error {
+	query := `
+		UPDATE tournament_matches
+		SET player_a = $2, player_b = $3, winner = $4, status = $5, room_id = $6, race_id = $7, finished_at = $8
+		WHERE id = $1;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, m.ID, nullString(m.PlayerA), nullString(m.PlayerB),
+		nullString(m.Winner), m.Status, nullString(m.RoomID), nullString(m.RaceID), m.FinishedAt)
+	return err
+}
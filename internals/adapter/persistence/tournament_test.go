@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+	"typing-speed/internals/core/tournament"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTournament_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM tournaments t WHERE t.id = (.+)").
+		WithArgs("tournament-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewTournamentRepository(db)
+	data, err := repo.GetTournament(context.Background(), "tournament-id")
+
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveEntrant_NotRegistered(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM tournament_entrants").
+		WithArgs("tournament-id", "a@test.com").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewTournamentRepository(db)
+	removed, err := repo.RemoveEntrant(context.Background(), "tournament-id", "a@test.com")
+
+	require.NoError(t, err)
+	assert.False(t, removed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMatches_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	finished := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "round", "slot", "player_a", "player_b", "winner", "status", "room_id",
+		"race_id", "finished_at"}).
+		AddRow("match-1", 1, 0, "a@test.com", "", "a@test.com", tournament.MatchDone, "", "", finished).
+		AddRow("match-2", 2, 0, "a@test.com", "b@test.com", "", tournament.MatchLive, "room-id", "", nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM tournament_matches WHERE tournament_id = (.+) ORDER BY round, slot").
+		WithArgs("tournament-id").
+		WillReturnRows(rows)
+
+	repo := NewTournamentRepository(db)
+	matches, err := repo.GetMatches(context.Background(), "tournament-id")
+
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "", matches[0].PlayerB)
+	assert.NotNil(t, matches[0].FinishedAt)
+	assert.Equal(t, "room-id", matches[1].RoomID)
+	assert.Nil(t, matches[1].FinishedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEntrants_PrivateEntrant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// the name of an entrant the viewer may not see comes back hidden
+	mock.ExpectQuery("SELECT e.email, CASE WHEN u.visible THEN u.name ELSE 'Anonymous' END, (.+) "+
+		"FROM tournament_entrants e JOIN \\(SELECT email, name, \\(privacy = 'public' OR email = \\$2 (.+) AS visible FROM users\\) u").
+		WithArgs("t-1", "bob@mail.com").
+		WillReturnRows(sqlmock.NewRows([]string{"email", "name", "seed", "seed_score"}).
+			AddRow("alice@mail.com", "Anonymous", 1, 1650.0).
+			AddRow("bob@mail.com", "Bob", 2, 1500.0))
+
+	repo := NewTournamentRepository(db)
+	entrants, err := repo.GetEntrants(context.Background(), "t-1", "bob@mail.com")
+
+	require.NoError(t, err)
+	require.Len(t, entrants, 2)
+	assert.Equal(t, "Anonymous", entrants[0].Name)
+	assert.Equal(t, "alice@mail.com", entrants[0].Email)
+	assert.Equal(t, "Bob", entrants[1].Name)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
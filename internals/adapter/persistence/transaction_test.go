@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithinTransaction_Commit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(userColumnNames).AddRow(userRow("Navneet", "test@test.com")...)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE email = (.+) FOR UPDATE").
+		WithArgs("test@test.com").
+		WillReturnRows(rows)
+	mock.ExpectExec("UPDATE users").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := NewUserRepository(db)
+	tx := NewTransactor(db)
+
+	err = tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		u, err := repo.GetUserByEmailForUpdate(ctx, "test@test.com")
+		if err != nil {
+			return err
+		}
+		return repo.UpdateUser(ctx, u.Email, 60, 90, 50, 70)
+	})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTransaction_RollbackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE users").
+		WillReturnError(errors.New("update failed"))
+	mock.ExpectRollback()
+
+	repo := NewUserRepository(db)
+	tx := NewTransactor(db)
+
+	err = tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		return repo.UpdateUser(ctx, "test@test.com", 60, 90, 50, 70)
+	})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "update failed")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTransaction_NestedJoinsOuter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	tx := NewTransactor(db)
+
+	calls := 0
+	err = tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		return tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
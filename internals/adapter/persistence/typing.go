@@ -3,7 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"time"
 	"typing-speed/internals/adapter/port"
 	"typing-speed/internals/core/typing"
 )
@@ -18,6 +18,7 @@ func NewTestRepository(db *sql.DB) port.TypingRepository {
 	}
 }
 
+// InsertTestData stores the test and fills in its generated ID and creation time
 func (u *TestRepositoryImpl) InsertTestData(ctx context.Context, data *typing.TypingData) error {
 	query := `
 		INSERT INTO user_typing_data (
@@ -27,11 +28,17 @@ func (u *TestRepositoryImpl) InsertTestData(ctx context.Context, data *typing.Ty
 			typed_words,
 			total_time,
 			total_time_taken_by_user,
-			wpm
-		) VALUES ($1, $2, $3, $4, $5, $6, $7);
+			wpm,
+			mode,
+			language,
+			challenge_date,
+			timeline,
+			race_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at;
 	`
 
-	_, err := u.db.ExecContext(
+	err := conn(ctx, u.db).QueryRowContext(
 		ctx,
 		query,
 		data.Email,
@@ -41,7 +48,12 @@ func (u *TestRepositoryImpl) InsertTestData(ctx context.Context, data *typing.Ty
 		data.TotalTime,
 		data.TimeTakenByUser,
 		data.WPM,
-	)
+		data.Mode,
+		data.Language,
+		nullString(data.ChallengeDate),
+		encodeTimeline(data.Timeline),
+		nullString(data.RaceID),
+	).Scan(&data.ID, &data.CreatedAt)
 
 	if err != nil {
 		return err
@@ -50,59 +62,110 @@ func (u *TestRepositoryImpl) InsertTestData(ctx context.Context, data *typing.Ty
 	return nil
 }
 
-func (u *TestRepositoryImpl) GetRecentTestData(ctx context.Context, email string, month int) ([]*typing.TypingData, error) {
-	days := 30 * month
-
-	var query string
-
-	if month == -1 {
-		query = `
-			SELECT total_error, total_words, typed_words, total_time,
-			       total_time_taken_by_user, wpm, created_at
-			FROM user_typing_data
-			WHERE email = $1
-			ORDER BY created_at DESC
-		`
-	} else {
-		query = fmt.Sprintf(`
-			SELECT total_error, total_words, typed_words, total_time,
-			       total_time_taken_by_user, wpm, created_at
-			FROM user_typing_data
-			WHERE email = $1
-			  AND created_at >= NOW() - INTERVAL '%d days'
-			ORDER BY created_at DESC
-		`, days)
+// GetHistory returns the page of the user's tests after the cursor. Ties are
+// broken by the latest test first, then by ID, so every test has one place.
+func (u *TestRepositoryImpl) GetHistory(ctx context.Context, q *typing.HistoryQuery) ([]*typing.TypingData, error) {
+	query := `
+		SELECT id, total_error, total_words, typed_words, total_time,
+		       total_time_taken_by_user, wpm, mode, language, created_at
+		FROM user_typing_data
+		WHERE email = $1
+		  AND ($2 = '' OR mode = $2)
+		  AND ($3::timestamp IS NULL OR created_at >= $3)
+		  AND ($4::timestamp IS NULL OR created_at < $4)
+		  AND ` + testAccuracy + ` >= $5`
+	args := []any{q.Email, q.Mode, utcTime(q.From), utcTime(q.Until), q.MinAccuracy, q.Limit}
+
+	order := `created_at DESC, id DESC`
+	if q.Sort == typing.HistorySortWPM {
+		order = `wpm DESC, ` + order
 	}
 
-	rows, err := u.db.QueryContext(ctx, query, email)
+	// keyset pagination: every column sorts descending, so the next page is
+	// everything below the cursor
+	if c := q.Cursor; c != nil {
+		if q.Sort == typing.HistorySortWPM {
+			query += `
+		  AND (wpm, created_at, id) < ($7::int, $8::timestamp, $9::uuid)`
+			args = append(args, c.WPM, utcTime(&c.CreatedAt), c.ID)
+		} else {
+			query += `
+		  AND (created_at, id) < ($7::timestamp, $8::uuid)`
+			args = append(args, utcTime(&c.CreatedAt), c.ID)
+		}
+	}
+	query += `
+		ORDER BY ` + order + `
+		LIMIT $6;
+	`
+
+	rows, err := conn(ctx, u.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var records []*typing.TypingData
+	tests := []*typing.TypingData{}
+	for rows.Next() {
+		t := &typing.TypingData{Email: q.Email}
+		if err := rows.Scan(&t.ID, &t.TotalErrors, &t.TotalWords, &t.TypedWords, &t.TotalTime, &t.TimeTakenByUser,
+			&t.WPM, &t.Mode, &t.Language, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tests = append(tests, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tests, nil
+}
 
+// GetLatestTests returns the user's last tests, latest first
+func (u *TestRepositoryImpl) GetLatestTests(ctx context.Context, email string, limit int) ([]*typing.TypingData, error) {
+	query := `
+		SELECT id, total_error, total_words, typed_words, total_time,
+		       total_time_taken_by_user, wpm, mode, language, created_at
+		FROM user_typing_data
+		WHERE email = $1
+		ORDER BY created_at DESC
+		LIMIT $2;
+	`
+
+	rows, err := conn(ctx, u.db).QueryContext(ctx, query, email, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tests := []*typing.TypingData{}
 	for rows.Next() {
-		record := &typing.TypingData{}
-		if err := rows.Scan(
-			&record.TotalErrors,
-			&record.TotalWords,
-			&record.TypedWords,
-			&record.TotalTime,
-			&record.TimeTakenByUser,
-			&record.WPM,
-			&record.CreatedAt,
-		); err != nil {
+		t := &typing.TypingData{Email: email}
+		if err := rows.Scan(&t.ID, &t.TotalErrors, &t.TotalWords, &t.TypedWords, &t.TotalTime, &t.TimeTakenByUser,
+			&t.WPM, &t.Mode, &t.Language, &t.CreatedAt); err != nil {
 			return nil, err
 		}
-		records = append(records, record)
+		tests = append(tests, t)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return records, nil
+	return tests, nil
 }
 
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
 
+// utcTime binds t as the UTC wall-clock time created_at holds, since Postgres
+// drops the offset when it reads a timestamp. A nil t stays NULL.
+func utcTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
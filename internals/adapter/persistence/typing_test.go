@@ -3,8 +3,6 @@ package db
 import (
 	"context"
 	"errors"
-	"fmt"
-	"regexp"
 	"testing"
 	"time"
 	"typing-speed/internals/core/typing"
@@ -27,14 +25,18 @@ func TestInsertTestData_Success(t *testing.T) {
 		TotalTime:       20,
 		TimeTakenByUser: 15,
 		WPM:             10,
+		Mode:            "15s",
+		Language:        "english",
 	}
-	mock.ExpectExec("INSERT INTO user_typing_data").
+	mock.ExpectQuery("INSERT INTO user_typing_data").
 		WithArgs(data.Email, data.TotalErrors, data.TotalWords,
-			data.TypedWords, data.TotalTime, data.TimeTakenByUser, data.WPM).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+			data.TypedWords, data.TotalTime, data.TimeTakenByUser, data.WPM,
+			data.Mode, data.Language, nullString(data.ChallengeDate), encodeTimeline(data.Timeline), nullString(data.RaceID)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("test-id", time.Now()))
 	repo := NewTestRepository(db)
 	err = repo.InsertTestData(context.Background(), data)
 	require.NoError(t, err)
+	assert.Equal(t, "test-id", data.ID)
 	require.NoError(t, mock.ExpectationsWereMet())
 
 }
@@ -52,9 +54,11 @@ func TestInsertTestData_Error(t *testing.T) {
 		TotalTime:       20,
 		TimeTakenByUser: 15,
 		WPM:             10,
+		Mode:            "15s",
+		Language:        "english",
 	}
 
-	mock.ExpectExec("INSERT INTO user_typing_data").
+	mock.ExpectQuery("INSERT INTO user_typing_data").
 		WithArgs(
 			data.Email,
 			data.TotalErrors,
@@ -63,6 +67,11 @@ func TestInsertTestData_Error(t *testing.T) {
 			data.TotalTime,
 			data.TimeTakenByUser,
 			data.WPM,
+			data.Mode,
+			data.Language,
+			nullString(data.ChallengeDate),
+			encodeTimeline(data.Timeline),
+			nullString(data.RaceID),
 		).
 		WillReturnError(errors.New("insert failed"))
 
@@ -76,7 +85,7 @@ func TestInsertTestData_Error(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetRecentTest_Success(t *testing.T) {
+func TestGetHistory_ByDate(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 	defer db.Close()
@@ -84,95 +93,62 @@ func TestGetRecentTest_Success(t *testing.T) {
 	repo := NewTestRepository(db)
 
 	now := time.Now()
+	// the range starts at midnight in the user's zone and is bound as UTC
+	from := time.Date(2026, 10, 12, 0, 0, 0, 0, time.FixedZone("IST", 5*60*60+30*60))
 
 	rows := mock.NewRows([]string{
-		"total_error",
-		"total_words",
-		"typed_words",
-		"total_time",
-		"total_time_taken_by_user",
-		"wpm",
-		"created_at",
-	}).AddRow(1, 2, 3, 4, 5, 6, now)
-
-	query := `
-		SELECT total_error, total_words, typed_words, total_time,
-		       total_time_taken_by_user, wpm, created_at
-		FROM user_typing_data
-		WHERE email = $1
-		ORDER BY created_at DESC
-	`
-
-	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs("test@test.com").
+		"id", "total_error", "total_words", "typed_words", "total_time",
+		"total_time_taken_by_user", "wpm", "mode", "language", "created_at",
+	}).AddRow("test-1", 2, 10, 8, 60, 55, 80, "60s", "english", now)
+
+	mock.ExpectQuery("FROM user_typing_data WHERE email = \\$1 (.+) >= \\$5 ORDER BY created_at DESC, id DESC LIMIT \\$6").
+		WithArgs("test@test.com", "60s", time.Date(2026, 10, 11, 18, 30, 0, 0, time.UTC), nil, 90, 21).
 		WillReturnRows(rows)
 
-	data, err := repo.GetRecentTestData(
-		context.Background(),
-		"test@test.com",
-		-1,
-	)
+	data, err := repo.GetHistory(context.Background(), &typing.HistoryQuery{
+		Email:       "test@test.com",
+		Mode:        "60s",
+		From:        &from,
+		MinAccuracy: 90,
+		Sort:        typing.HistorySortDate,
+		Limit:       21,
+	})
 
 	require.NoError(t, err)
 	require.Len(t, data, 1)
 
-	assert.Equal(t, 1, data[0].TotalErrors)
-	assert.Equal(t, 2, data[0].TotalWords)
-	assert.Equal(t, 3, data[0].TypedWords)
-	assert.Equal(t, 6, data[0].WPM)
-	assert.WithinDuration(t, now, data[0].CreatedAt, time.Second)
+	assert.Equal(t, "test-1", data[0].ID)
+	assert.Equal(t, 80, data[0].WPM)
+	assert.Equal(t, "test@test.com", data[0].Email)
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
-func TestGetRecentTest_WithMonthFilter_Success(t *testing.T) {
+
+func TestGetHistory_ByWPMAfterCursor(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 	defer db.Close()
 
 	repo := NewTestRepository(db)
 
-	now := time.Now()
+	createdAt := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
 
-	rows := mock.NewRows([]string{
-		"total_error",
-		"total_words",
-		"typed_words",
-		"total_time",
-		"total_time_taken_by_user",
-		"wpm",
-		"created_at",
-	}).AddRow(2, 10, 8, 60, 55, 80, now)
-
-	// month = 1 → 30 days
-	days := 30
-
-	query := fmt.Sprintf(`
-		SELECT total_error, total_words, typed_words, total_time,
-		       total_time_taken_by_user, wpm, created_at
-		FROM user_typing_data
-		WHERE email = $1
-		  AND created_at >= NOW() - INTERVAL '%d days'
-		ORDER BY created_at DESC
-	`, days)
-
-	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs("test@test.com").
-		WillReturnRows(rows)
+	mock.ExpectQuery("AND \\(wpm, created_at, id\\) < \\(\\$7::int, \\$8::timestamp, \\$9::uuid\\) "+
+		"ORDER BY wpm DESC, created_at DESC, id DESC LIMIT \\$6").
+		WithArgs("test@test.com", "", nil, nil, 0, 21, 95, createdAt, "test-0").
+		WillReturnRows(mock.NewRows([]string{
+			"id", "total_error", "total_words", "typed_words", "total_time",
+			"total_time_taken_by_user", "wpm", "mode", "language", "created_at",
+		}))
 
-	data, err := repo.GetRecentTestData(
-		context.Background(),
-		"test@test.com",
-		1,
-	)
+	data, err := repo.GetHistory(context.Background(), &typing.HistoryQuery{
+		Email:  "test@test.com",
+		Sort:   typing.HistorySortWPM,
+		Limit:  21,
+		Cursor: &typing.HistoryCursor{WPM: 95, CreatedAt: createdAt, ID: "test-0"},
+	})
 
 	require.NoError(t, err)
-	require.Len(t, data, 1)
-
-	assert.Equal(t, 2, data[0].TotalErrors)
-	assert.Equal(t, 10, data[0].TotalWords)
-	assert.Equal(t, 8, data[0].TypedWords)
-	assert.Equal(t, 80, data[0].WPM)
-	assert.WithinDuration(t, now, data[0].CreatedAt, time.Second)
-
+	assert.Empty(t, data)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
@@ -19,32 +19,77 @@ func NewUserRepository(db *sql.DB) port.UserRepository {
 	}
 }
 
+// userColumns lists the users columns in the order scanUser reads them
+const userColumns = `id, name, email, password, created_at, avg_speed, avg_accuracy, total_test, level, last_test_time, streak,
+        best_speed, avg_performance, time_zone, longest_streak, xp, COALESCE(username, ''), privacy,
+        COALESCE(country, ''), COALESCE(region, '')`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner, u *user.User) error {
+	return row.Scan(
+		&u.ID,
+		&u.Name,
+		&u.Email,
+		&u.Password,
+		&u.CreatedAt,
+		&u.AvgSpeed,
+		&u.AvgAccuracy,
+		&u.TotalTest,
+		&u.Level,
+		&u.LastTestTime,
+		&u.Streak,
+		&u.BestSpeed,
+		&u.AvgPerformance,
+		&u.TimeZone,
+		&u.LongestStreak,
+		&u.XP,
+		&u.Username,
+		&u.Privacy,
+		&u.Country,
+		&u.Region,
+	)
+}
+
 func (r *UserRepositoryImpl) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
 	query := `
-		SELECT id, name, email, password, created_at, avg_speed, avg_accuracy, total_test, level, last_test_time, streak,
-        best_speed,avg_performance
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE email = $1;
 	`
 
-	user := &user.User{}
+	return r.getUser(ctx, query, email)
+}
 
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Name,
-		&user.Email,
-		&user.Password,
-		&user.CreatedAt,
-		&user.AvgSpeed,
-		&user.AvgAccuracy,
-		&user.TotalTest,
-		&user.Level,
-		&user.LastTestTime,
-		&user.Streak,
-		&user.BestSpeed,
-		&user.AvgPerformance,
-	)
+// GetUserByEmailForUpdate fetches the user and locks the row until the surrounding transaction ends
+func (r *UserRepositoryImpl) GetUserByEmailForUpdate(ctx context.Context, email string) (*user.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE email = $1
+		FOR UPDATE;
+	`
 
+	return r.getUser(ctx, query, email)
+}
+
+// GetUserByUsername returns nil when nobody has the username
+func (r *UserRepositoryImpl) GetUserByUsername(ctx context.Context, username string) (*user.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE username = $1;
+	`
+
+	return r.getUser(ctx, query, username)
+}
+
+func (r *UserRepositoryImpl) getUser(ctx context.Context, query string, key string) (*user.User, error) {
+	user := &user.User{}
+
+	err := scanUser(conn(ctx, r.db).QueryRowContext(ctx, query, key), user)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil // user not found
@@ -58,11 +103,12 @@ func (r *UserRepositoryImpl) GetUserByEmail(ctx context.Context, email string) (
 // CreateUser inserts a new user into the database (no return)
 func (r *UserRepositoryImpl) CreateUser(ctx context.Context, user *user.User) error {
 	query := `
-		INSERT INTO users (name, email, password)
-		VALUES ($1, $2, $3);
+		INSERT INTO users (name, email, password, username, country, region)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''));
 	`
 
-	_, err := r.db.ExecContext(ctx, query, user.Name, user.Email, user.Password)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, user.Name, user.Email, user.Password, user.Username,
+		user.Country, user.Region)
 	if err != nil {
 		return err
 	}
@@ -83,7 +129,7 @@ func (r *UserRepositoryImpl) UpdateUser(ctx context.Context, email string, speed
         WHERE email = $1;
     `
 
-	_, err := r.db.ExecContext(ctx, query, email, speed, accuracy,performance,time.Now(),bestSpeed)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, email, speed, accuracy,performance,time.Now(),bestSpeed)
 	if err != nil {
 		return err
 	}
@@ -91,10 +137,123 @@ func (r *UserRepositoryImpl) UpdateUser(ctx context.Context, email string, speed
 	return nil
 }
 
+// UpdateStreak stores the current and longest daily streak of the user
+func (r *UserRepositoryImpl) UpdateStreak(ctx context.Context, email string, streak int, longestStreak int) error {
+	query := `
+        UPDATE users
+        SET
+            streak = $2,
+            longest_streak = $3
+        WHERE email = $1;
+    `
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, email, streak, longestStreak)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateTimeZone stores the IANA time zone used for the user's calendar days
+func (r *UserRepositoryImpl) UpdateTimeZone(ctx context.Context, email string, timeZone string) error {
+	query := `UPDATE users SET time_zone = $2 WHERE email = $1;`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, email, timeZone)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateUsername reports false when another user already has the username
+func (r *UserRepositoryImpl) UpdateUsername(ctx context.Context, email string, username string) (bool, error) {
+	query := `
+		UPDATE users SET username = $2
+		WHERE email = $1
+			AND NOT EXISTS (SELECT 1 FROM users WHERE username = $2 AND email <> $1);
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, email, username)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// UpdatePrivacy stores who may see the user's profile and results
+func (r *UserRepositoryImpl) UpdatePrivacy(ctx context.Context, email string, privacy string) error {
+	query := `UPDATE users SET privacy = $2 WHERE email = $1;`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, email, privacy)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateCountry stores the country the user picked. The region was only
+// ever inferred, so it is dropped.
+func (r *UserRepositoryImpl) UpdateCountry(ctx context.Context, email string, country string) error {
+	query := `UPDATE users SET country = NULLIF($2, ''), region = NULL WHERE email = $1;`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, email, country)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateProgress stores the total XP of the user and the level it reaches
+func (r *UserRepositoryImpl) UpdateProgress(ctx context.Context, email string, xp int, level int) error {
+	query := `UPDATE users SET xp = $2, level = $3 WHERE email = $1;`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, email, xp, level)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTopPerformer only ranks users with a public profile
 func (u *UserRepositoryImpl) GetTopPerformer(ctx context.Context) ([]*user.TopPerformer, error) {
-	query := `SELECT name, avg_performance FROM users ORDER BY avg_performance DESC LIMIT 10`
+	return queryTopPerformers(ctx, u.db, ` WHERE privacy = 'public'`)
+}
+
+// visibleTo narrows users down to those whose privacy settings let the
+// viewer, bound to the numbered parameter, see them
+func visibleTo(param string) string {
+	return `(privacy = 'public' OR email = ` + param + ` OR (privacy = 'friends' AND email IN (
+			SELECT f.followee
+			FROM user_follows f
+			JOIN user_follows b ON b.follower = f.followee AND b.followee = f.follower
+			WHERE f.follower = ` + param + `)))`
+}
+
+// viewedUsers is the users table with whether the viewer in param may see
+// each user, for queries where other tables also have an email column
+func viewedUsers(param string) string {
+	return `(SELECT email, name, ` + visibleTo(param) + ` AS visible FROM users)`
+}
+
+// visibleName is the name of user u from viewedUsers, hidden unless the viewer may see them
+const visibleName = `CASE WHEN u.visible THEN u.name ELSE '` + user.AnonymousName + `' END`
 
-	rows, err := u.db.QueryContext(ctx, query)
+// queryTopPerformers returns the ten best users. The filter narrows the
+// users down, e.g. to the members of an organization.
+func queryTopPerformers(ctx context.Context, db *sql.DB, filter string, args ...any) ([]*user.TopPerformer, error) {
+	query := `SELECT name, COALESCE(username, ''), avg_performance FROM users` + filter + ` ORDER BY avg_performance DESC LIMIT 10`
+
+	rows, err := conn(ctx, db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +263,7 @@ func (u *UserRepositoryImpl) GetTopPerformer(ctx context.Context) ([]*user.TopPe
 
 	for rows.Next() {
 		p := &user.TopPerformer{}
-		if err := rows.Scan(&p.Name, &p.Performance); err != nil {
+		if err := rows.Scan(&p.Name, &p.Username, &p.Performance); err != nil {
 			return nil, err
 		}
 		performers = append(performers, p)
@@ -117,42 +276,27 @@ func (u *UserRepositoryImpl) GetTopPerformer(ctx context.Context) ([]*user.TopPe
 	return performers, nil
 }
 
-func (u *UserRepositoryImpl) GetAllUser(ctx context.Context) ([]*user.User, error) {
+// GetAllUser returns what anyone may see of the users with a public profile
+func (u *UserRepositoryImpl) GetAllUser(ctx context.Context) ([]*user.PublicUser, error) {
 	query := `
-        SELECT 
-            id, name, email, password, created_at, 
-            avg_speed, avg_accuracy, total_test, level, 
-            last_test_time, streak, best_speed, avg_performance
-        FROM users;
+        SELECT name, COALESCE(username, ''), level, avg_speed, avg_accuracy, best_speed, avg_performance, total_test
+        FROM users
+        WHERE privacy = 'public';
     `
 
-	rows, err := u.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, u.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []*user.User
+	var users []*user.PublicUser
 
 	for rows.Next() {
-		u := &user.User{}
-
-		err := rows.Scan(
-			&u.ID,
-			&u.Name,
-			&u.Email,
-			&u.Password,
-			&u.CreatedAt,
-			&u.AvgSpeed,
-			&u.AvgAccuracy,
-			&u.TotalTest,
-			&u.Level,
-			&u.LastTestTime,
-			&u.Streak,
-			&u.BestSpeed,
-			&u.AvgPerformance,
-		)
-		if err != nil {
+		u := &user.PublicUser{}
+
+		if err := rows.Scan(&u.Name, &u.Username, &u.Level, &u.AvgSpeed, &u.AvgAccuracy, &u.BestSpeed, &u.AvgPerformance,
+			&u.TotalTest); err != nil {
 			return nil, err
 		}
 
@@ -168,29 +312,35 @@ func (u *UserRepositoryImpl) GetAllUser(ctx context.Context) ([]*user.User, erro
 }
 
 func (u *UserRepositoryImpl) GetDashboardTopData(ctx context.Context) (*user.DashboardTopData, error) {
-    query := `
+	return queryDashboardTopData(ctx, u.db, "")
+}
+
+// queryDashboardTopData averages the speed and accuracy of the users and
+// sums up their tests. The filter narrows the users down, e.g. to the
+// members of an organization.
+func queryDashboardTopData(ctx context.Context, db *sql.DB, filter string, args ...any) (*user.DashboardTopData, error) {
+	query := `
         SELECT 
-            AVG(avg_speed) AS avg_speed,
-            AVG(avg_accuracy) AS avg_accuracy,
-            SUM(total_test) AS total_test
-        FROM users;
+            COALESCE(AVG(avg_speed), 0) AS avg_speed,
+            COALESCE(AVG(avg_accuracy), 0) AS avg_accuracy,
+            COALESCE(SUM(total_test), 0) AS total_test
+        FROM users` + filter + `;
     `
 
-    var avgSpeed float64
-    var avgAccuracy float64
-    var totalTest int64
+	var avgSpeed float64
+	var avgAccuracy float64
+	var totalTest int64
 
-    err := u.db.QueryRowContext(ctx, query).Scan(&avgSpeed, &avgAccuracy, &totalTest)
-    if err != nil {
-        return nil, err
-    }
+	err := conn(ctx, db).QueryRowContext(ctx, query, args...).Scan(&avgSpeed, &avgAccuracy, &totalTest)
+	if err != nil {
+		return nil, err
+	}
 
-    data := &user.DashboardTopData{
-        TotalTest:       totalTest,
-        AverageSpeed:    int(avgSpeed),
-        AverageAccuracy: int(avgAccuracy),
-    }
+	data := &user.DashboardTopData{
+		TotalTest:       totalTest,
+		AverageSpeed:    int(avgSpeed),
+		AverageAccuracy: int(avgAccuracy),
+	}
 
-    return data, nil
+	return data, nil
 }
-
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"testing"
 	"time"
@@ -13,21 +14,32 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// userColumnNames mirrors userColumns in user.go
+var userColumnNames = []string{
+	"id", "name", "email", "password", "created_at",
+	"avg_speed", "avg_accuracy", "total_test", "level",
+	"last_test_time", "streak", "best_speed", "avg_performance",
+	"time_zone", "longest_streak", "xp", "username", "privacy",
+	"country", "region",
+}
+
+func userRow(name, email string) []driver.Value {
+	return []driver.Value{
+		1, name, email, "hashed",
+		time.Now(), 50, 95, 10, 1,
+		time.Now(), 5, 70, 80,
+		"UTC", 7, 250, "", "public",
+		"", "",
+	}
+}
+
 func TestGetUserByEmail_UserFound(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewUserRepository(db)
-	rows := sqlmock.NewRows([]string{
-		"id", "name", "email", "password", "created_at",
-		"avg_speed", "avg_accuracy", "total_test", "level",
-		"last_test_time", "streak", "best_speed", "avg_performance",
-	}).AddRow(
-		1, "Navneet", "test@test.com", "hashed",
-		time.Now(), 50, 95, 10, 1,
-		time.Now(), 5, 70, 80,
-	)
+	rows := sqlmock.NewRows(userColumnNames).AddRow(userRow("Navneet", "test@test.com")...)
 	mock.ExpectQuery("SELECT (.+) FROM users WHERE email =").
 		WithArgs("test@test.com").
 		WillReturnRows(rows)
@@ -76,7 +88,7 @@ func TestCreateUser_Success(t *testing.T) {
 	}
 
 	mock.ExpectExec("INSERT INTO users").
-		WithArgs(u.Name, u.Email, u.Password).
+		WithArgs(u.Name, u.Email, u.Password, u.Username, u.Country, u.Region).
 		WillReturnResult(sqlmock.NewResult(1, 1)) // 1 row affected
 
 	err = repo.CreateUser(context.Background(), u)
@@ -100,7 +112,7 @@ func TestCreateUser_DBError(t *testing.T) {
 	dbErr := errors.New("duplicate key value violates unique constraint")
 
 	mock.ExpectExec("INSERT INTO users").
-		WithArgs(u.Name, u.Email, u.Password).
+		WithArgs(u.Name, u.Email, u.Password, u.Username, u.Country, u.Region).
 		WillReturnError(dbErr)
 
 	err = repo.CreateUser(context.Background(), u)
@@ -135,7 +147,7 @@ func TestCreateUser_ContextCancelled(t *testing.T) {
 }
 
 func TestGetTopPerformer_Success(t *testing.T) {
-	//	query := `SELECT name, avg_performance FROM users ORDER BY avg_performance DESC LIMIT 10`
+	//	query := `SELECT name, COALESCE(username, ''), avg_performance FROM users WHERE privacy = 'public' ORDER BY avg_performance DESC LIMIT 10`
 
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -143,14 +155,15 @@ func TestGetTopPerformer_Success(t *testing.T) {
 
 	repo := NewUserRepository(db)
 	rows := sqlmock.NewRows([]string{
-		"name", "avg_performance",
-	}).AddRow("navneet", 123)
+		"name", "username", "avg_performance",
+	}).AddRow("navneet", "navneet", 123)
 
-	mock.ExpectQuery("SELECT name, avg_performance FROM users ORDER BY avg_performance DESC LIMIT 10").WithArgs().WillReturnRows(rows)
+	mock.ExpectQuery("SELECT name, (.+) FROM users WHERE privacy = 'public' ORDER BY avg_performance DESC LIMIT 10").WithArgs().WillReturnRows(rows)
 	data, err := repo.GetTopPerformer(context.Background())
 	assert.NoError(t, err)
 	assert.NotNil(t, data)
 	assert.Equal(t, "navneet", data[0].Name)
+	assert.Equal(t, "navneet", data[0].Username)
 	assert.Equal(t, 123, data[0].Performance)
 	assert.NoError(t, mock.ExpectationsWereMet())
 
@@ -162,16 +175,11 @@ func TestGetAllUsers_UserFound(t *testing.T) {
 	defer db.Close()
 
 	repo := NewUserRepository(db)
-	rows := sqlmock.NewRows([]string{
-		"id", "name", "email", "password", "created_at",
-		"avg_speed", "avg_accuracy", "total_test", "level",
-		"last_test_time", "streak", "best_speed", "avg_performance",
-	}).AddRow(
-		1, "Navneet", "test@test.com", "hashed",
-		time.Now(), 50, 95, 10, 1,
-		time.Now(), 5, 70, 80,
-	)
-	mock.ExpectQuery("SELECT (.+) FROM users").
+	rows := sqlmock.NewRows([]string{"name", "username", "level", "avg_speed", "avg_accuracy", "best_speed",
+		"avg_performance", "total_test"}).AddRow("Navneet", "navneet", 3, 60, 95, 80, 57, 12)
+	// only the public columns are read, never the password or the email
+	mock.ExpectQuery("SELECT name, COALESCE\\(username, ''\\), level, avg_speed, avg_accuracy, best_speed, avg_performance, " +
+		"total_test FROM users WHERE privacy = 'public'").
 		WithArgs().
 		WillReturnRows(rows)
 
@@ -180,7 +188,8 @@ func TestGetAllUsers_UserFound(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, user)
 	assert.Equal(t, "Navneet", user[0].Name)
-	assert.Equal(t, "test@test.com", user[0].Email)
+	assert.Equal(t, "navneet", user[0].Username)
+	assert.Equal(t, 12, user[0].TotalTest)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 
@@ -193,11 +202,8 @@ func TestGetAllUser_NoUsers(t *testing.T) {
 
 	repo := NewUserRepository(db)
 
-	rows := sqlmock.NewRows([]string{
-		"id", "name", "email", "password", "created_at",
-		"avg_speed", "avg_accuracy", "total_test", "level",
-		"last_test_time", "streak", "best_speed", "avg_performance",
-	})
+	rows := sqlmock.NewRows([]string{"name", "username", "level", "avg_speed", "avg_accuracy", "best_speed",
+		"avg_performance", "total_test"})
 	// no AddRow → empty result set
 
 	mock.ExpectQuery("SELECT (.+) FROM users").
@@ -400,3 +406,20 @@ func TestUpdateUser_ContextCancelled(t *testing.T) {
 	// No DB call expected
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUpdateStreak_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec("UPDATE users SET streak = (.+), longest_streak = (.+) WHERE email =").
+		WithArgs("test@test.com", 3, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateStreak(context.Background(), "test@test.com", 3, 10)
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
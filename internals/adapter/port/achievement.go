@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"time"
+	"typing-speed/internals/core/achievement"
+)
+
+type AchievementRepository interface {
+	GetAchievementStats(ctx context.Context, email string) (*achievement.Stats, error)
+	GetUnlockedAchievements(ctx context.Context, email string) ([]*achievement.Unlocked, error)
+	InsertUnlockedAchievements(ctx context.Context, email string, codes []string, unlockedAt time.Time) error
+}
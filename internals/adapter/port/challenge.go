@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/challenge"
+)
+
+type ChallengeRepository interface {
+	GetChallengeLeaderboard(ctx context.Context, date string, limit int) ([]*challenge.LeaderboardEntry, error)
+	GetChallengeHistory(ctx context.Context, before string, limit int) ([]*challenge.Summary, error)
+}
@@ -0,0 +1,25 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/classroom"
+)
+
+type ClassRepository interface {
+	CreateClass(ctx context.Context, c *classroom.Class) error
+	GetClass(ctx context.Context, id string) (*classroom.Class, error)
+	GetClassByJoinCode(ctx context.Context, code string) (*classroom.Class, error)
+	GetClassesByEmail(ctx context.Context, email string) ([]*classroom.Class, error)
+	DeleteClass(ctx context.Context, id string) error
+	AddStudent(ctx context.Context, id string, email string) (bool, error)
+	GetStudent(ctx context.Context, id string, email string) (*classroom.Student, error)
+	GetStudents(ctx context.Context, id string) ([]*classroom.Student, error)
+	RemoveStudent(ctx context.Context, id string, email string) (bool, error)
+	CreateAssignment(ctx context.Context, a *classroom.Assignment) error
+	GetAssignment(ctx context.Context, id string, assignmentID string) (*classroom.Assignment, error)
+	GetAssignments(ctx context.Context, id string) ([]*classroom.Assignment, error)
+	GetCompletedAssignments(ctx context.Context, id string, email string) ([]string, error)
+	DeleteAssignment(ctx context.Context, id string, assignmentID string) (bool, error)
+	InsertSubmission(ctx context.Context, s *classroom.Submission) error
+	GetSubmissions(ctx context.Context, assignmentID string, email string) ([]*classroom.Submission, error)
+}
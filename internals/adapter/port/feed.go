@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/feed"
+)
+
+type FeedRepository interface {
+	InsertEvent(ctx context.Context, e *feed.Event) error
+	FanOut(ctx context.Context, e *feed.Event, limit int) ([]string, error)
+	TrimFeeds(ctx context.Context, emails []string, keep int) error
+	GetFeed(ctx context.Context, email string, before int64, limit int, fanOutLimit int) ([]*feed.Event, error)
+	Mute(ctx context.Context, email string, muted string) error
+	Unmute(ctx context.Context, email string, muted string) (bool, error)
+	GetMuted(ctx context.Context, email string) ([]*feed.Muted, error)
+}
@@ -0,0 +1,26 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/friend"
+)
+
+type FriendRepository interface {
+	Follow(ctx context.Context, follower string, followee string) error
+	Unfollow(ctx context.Context, follower string, followee string) (bool, error)
+	GetRelationship(ctx context.Context, email string, other string) (*friend.Relationship, error)
+	GetFollowers(ctx context.Context, email string) ([]*friend.Friend, error)
+	GetFollowing(ctx context.Context, email string) ([]*friend.Friend, error)
+	GetFriends(ctx context.Context, email string) ([]*friend.Friend, error)
+	CreateRequest(ctx context.Context, r *friend.Request) error
+	GetRequestForUpdate(ctx context.Context, id int64) (*friend.Request, error)
+	GetPendingRequest(ctx context.Context, from string, to string) (*friend.Request, error)
+	GetIncomingRequests(ctx context.Context, email string) ([]*friend.Request, error)
+	GetOutgoingRequests(ctx context.Context, email string) ([]*friend.Request, error)
+	UpdateRequestStatus(ctx context.Context, r *friend.Request) error
+	DeletePendingRequests(ctx context.Context, a string, b string) error
+	Block(ctx context.Context, blocker string, blocked string) error
+	Unblock(ctx context.Context, blocker string, blocked string) (bool, error)
+	GetBlocked(ctx context.Context, email string) ([]*friend.Friend, error)
+	GetFriendsLeaderboard(ctx context.Context, email string, limit int) ([]*friend.LeaderboardEntry, error)
+}
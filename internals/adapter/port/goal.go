@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/goal"
+)
+
+type GoalRepository interface {
+	CreateGoal(ctx context.Context, g *goal.Goal) error
+	GetGoals(ctx context.Context, email string) ([]*goal.Goal, error)
+	GetActiveGoals(ctx context.Context, email string) ([]*goal.Goal, error)
+	GetGoal(ctx context.Context, email string, id int64) (*goal.Goal, error)
+	UpdateGoal(ctx context.Context, g *goal.Goal) error
+	UpdateGoalProgress(ctx context.Context, g *goal.Goal) error
+	DeleteGoal(ctx context.Context, email string, id int64) (bool, error)
+}
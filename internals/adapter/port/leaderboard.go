@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/leaderboard"
+)
+
+type LeaderboardRepository interface {
+	GetLeaderboard(ctx context.Context, q *leaderboard.Query) ([]*leaderboard.Entry, error)
+	GetCountrySummaries(ctx context.Context) ([]*leaderboard.CountrySummary, error)
+	GetDistribution(ctx context.Context, metric string) ([]*leaderboard.Bucket, error)
+	GetUserSpeeds(ctx context.Context, email string, metric string) (map[string]int, error)
+}
@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/organization"
+	"typing-speed/internals/core/user"
+)
+
+// An empty teamID in the leaderboard and dashboard methods means the whole organization
+type OrganizationRepository interface {
+	CreateOrganization(ctx context.Context, o *organization.Organization) error
+	GetOrganization(ctx context.Context, id string) (*organization.Organization, error)
+	GetOrganizationByDomain(ctx context.Context, domain string) (*organization.Organization, error)
+	GetOrganizationsByEmail(ctx context.Context, email string) ([]*organization.Organization, error)
+	UpdateOrganization(ctx context.Context, o *organization.Organization) error
+	DeleteOrganization(ctx context.Context, id string) error
+	AddMember(ctx context.Context, id string, email string, role string) (bool, error)
+	AddMembersByDomain(ctx context.Context, id string, domain string) error
+	GetMember(ctx context.Context, id string, email string) (*organization.Member, error)
+	GetMembers(ctx context.Context, id string) ([]*organization.Member, error)
+	UpdateMemberRole(ctx context.Context, id string, email string, role string) error
+	RemoveMember(ctx context.Context, id string, email string) (bool, error)
+	CreateTeam(ctx context.Context, t *organization.Team) (bool, error)
+	GetTeam(ctx context.Context, id string, teamID string) (*organization.Team, error)
+	GetTeams(ctx context.Context, id string) ([]*organization.Team, error)
+	DeleteTeam(ctx context.Context, id string, teamID string) (bool, error)
+	AddTeamMember(ctx context.Context, id string, teamID string, email string) error
+	RemoveTeamMember(ctx context.Context, teamID string, email string) (bool, error)
+	GetTeamMembers(ctx context.Context, teamID string) ([]*organization.Member, error)
+	GetLeaderboard(ctx context.Context, id string, teamID string, viewer string, limit int) ([]*organization.LeaderboardEntry, error)
+	GetDashboardTopData(ctx context.Context, id string, teamID string) (*user.DashboardTopData, error)
+	GetTopPerformers(ctx context.Context, id string, teamID string, viewer string) ([]*user.TopPerformer, error)
+}
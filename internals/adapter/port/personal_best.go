@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/typing"
+)
+
+type PersonalBestRepository interface {
+	GetPersonalBest(ctx context.Context, email string, mode string, language string) (*typing.PersonalBest, error)
+	GetPersonalBests(ctx context.Context, email string) ([]*typing.PersonalBest, error)
+	UpsertPersonalBest(ctx context.Context, pb *typing.PersonalBest, previousWPM int) error
+	GetPersonalBestHistory(ctx context.Context, email string, mode string, language string) ([]*typing.PersonalBestRecord, error)
+}
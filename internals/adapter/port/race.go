@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/race"
+)
+
+type RaceRepository interface {
+	InsertRace(ctx context.Context, r *race.Race) error
+	InsertRaceResults(ctx context.Context, raceID string, results []*race.Result) error
+	GetRace(ctx context.Context, raceID string, viewer string) (*race.Race, error)
+}
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/ranking"
+)
+
+type RankingRepository interface {
+	GetRankings(ctx context.Context) ([]*ranking.Entry, error)
+	GetRanking(ctx context.Context, email string) (*ranking.Entry, error)
+}
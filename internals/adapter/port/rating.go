@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/rating"
+)
+
+type RatingRepository interface {
+	GetRating(ctx context.Context, email string) (*rating.Rating, error)
+	GetRatingsForUpdate(ctx context.Context, emails []string) ([]*rating.Rating, error)
+	SaveRating(ctx context.Context, raceID string, r *rating.Rating, change float64) error
+	GetRatingHistory(ctx context.Context, email string, limit int) ([]*rating.Change, error)
+}
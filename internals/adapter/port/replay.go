@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/replay"
+)
+
+type ReplayRepository interface {
+	GetReplay(ctx context.Context, email string, testID string) (*replay.Replay, error)
+	GetPersonalBestReplay(ctx context.Context, email string, mode string, language string) (*replay.Replay, error)
+	SetShareToken(ctx context.Context, email string, testID string, token string) (string, error)
+	GetSharedReplay(ctx context.Context, token string) (*replay.Replay, error)
+}
@@ -0,0 +1,10 @@
+package port
+
+import (
+	"context"
+	"typing-speed/internals/core/stats"
+)
+
+type StatsRepository interface {
+	GetProgress(ctx context.Context, q *stats.Query) ([]*stats.Point, error)
+}
@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+	"time"
+	"typing-speed/internals/core/tournament"
+)
+
+type TournamentRepository interface {
+	CreateTournament(ctx context.Context, t *tournament.Tournament) error
+	GetTournament(ctx context.Context, id string) (*tournament.Tournament, error)
+	GetTournamentForUpdate(ctx context.Context, id string) (*tournament.Tournament, error)
+	GetTournaments(ctx context.Context, limit int) ([]*tournament.Tournament, error)
+	GetRunningTournaments(ctx context.Context) ([]*tournament.Tournament, error)
+	UpdateTournamentStatus(ctx context.Context, id string, status string, at time.Time) error
+	AddEntrant(ctx context.Context, id string, email string) error
+	RemoveEntrant(ctx context.Context, id string, email string) (bool, error)
+	GetEntrants(ctx context.Context, id string, viewer string) ([]*tournament.Entrant, error)
+	SetSeeds(ctx context.Context, id string, entrants []*tournament.Entrant) error
+	InsertMatches(ctx context.Context, id string, matches []*tournament.Match) error
+	GetMatches(ctx context.Context, id string) ([]*tournament.Match, error)
+	UpdateMatch(ctx context.Context, m *tournament.Match) error
+}
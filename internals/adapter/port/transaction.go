@@ -0,0 +1,9 @@
+package port
+
+import "context"
+
+// Transactor runs a unit of work inside a single database transaction.
+// Repositories called with the ctx passed to fn take part in that transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
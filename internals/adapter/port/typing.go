@@ -7,5 +7,6 @@ import (
 
 type TypingRepository interface {
 	InsertTestData(ctx context.Context, user *typing.TypingData) error
-	GetRecentTestData(ctx context.Context, email string, month int) ([]*typing.TypingData, error)
+	GetHistory(ctx context.Context, q *typing.HistoryQuery) ([]*typing.TypingData, error)
+	GetLatestTests(ctx context.Context, email string, limit int) ([]*typing.TypingData, error)
 }
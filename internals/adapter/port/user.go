@@ -7,9 +7,17 @@ import (
 
 type UserRepository interface {
 	GetUserByEmail(ctx context.Context, email string) (*user.User, error)
+	GetUserByEmailForUpdate(ctx context.Context, email string) (*user.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*user.User, error)
 	CreateUser(ctx context.Context, user *user.User) error
 	UpdateUser(ctx context.Context, email string, speed, accuracy int,performance int,bestSpeed int) error
+	UpdateStreak(ctx context.Context, email string, streak int, longestStreak int) error
+	UpdateTimeZone(ctx context.Context, email string, timeZone string) error
+	UpdateUsername(ctx context.Context, email string, username string) (bool, error)
+	UpdatePrivacy(ctx context.Context, email string, privacy string) error
+	UpdateCountry(ctx context.Context, email string, country string) error
+	UpdateProgress(ctx context.Context, email string, xp int, level int) error
 	GetTopPerformer(ctx context.Context) ([]*user.TopPerformer, error)
-	GetAllUser(ctx context.Context) ([]*user.User, error)
+	GetAllUser(ctx context.Context) ([]*user.PublicUser, error)
 	GetDashboardTopData(ctx context.Context) (*user.DashboardTopData, error)
 }
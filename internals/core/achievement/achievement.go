@@ -0,0 +1,75 @@
+package achievement
+
+import (
+	"context"
+	"time"
+)
+
+type Metric string
+
+const (
+	MetricTotalTests   Metric = "total_tests"
+	MetricBestWPM      Metric = "best_wpm"
+	MetricStreak       Metric = "streak"
+	MetricLevel        Metric = "level"
+	MetricPerfectTests Metric = "perfect_tests"
+)
+
+// Definition is a badge and the rule that unlocks it: the metric has to reach Target
+type Definition struct {
+	Code        string
+	Name        string
+	Description string
+	Metric      Metric
+	Target      int
+}
+
+// Catalog lists every achievement a user can earn
+var Catalog = []Definition{
+	{Code: "first_test", Name: "First Steps", Description: "Complete your first test", Metric: MetricTotalTests, Target: 1},
+	{Code: "tests_100", Name: "Dedicated", Description: "Complete 100 tests", Metric: MetricTotalTests, Target: 100},
+	{Code: "tests_1000", Name: "Thousand Tests", Description: "Complete 1000 tests", Metric: MetricTotalTests, Target: 1000},
+	{Code: "wpm_50", Name: "Getting Fast", Description: "Reach 50 WPM", Metric: MetricBestWPM, Target: 50},
+	{Code: "wpm_100", Name: "First 100 WPM", Description: "Reach 100 WPM", Metric: MetricBestWPM, Target: 100},
+	{Code: "wpm_150", Name: "Lightning Fingers", Description: "Reach 150 WPM", Metric: MetricBestWPM, Target: 150},
+	{Code: "streak_7", Name: "Week Streak", Description: "Practice 7 days in a row", Metric: MetricStreak, Target: 7},
+	{Code: "streak_30", Name: "30-Day Streak", Description: "Practice 30 days in a row", Metric: MetricStreak, Target: 30},
+	{Code: "level_10", Name: "Level 10", Description: "Reach level 10", Metric: MetricLevel, Target: 10},
+	{Code: "perfect_60s", Name: "Flawless Minute", Description: "Finish a 60s test with perfect accuracy", Metric: MetricPerfectTests, Target: 1},
+}
+
+// Stats holds the current value of every metric for a user
+type Stats struct {
+	TotalTests    int
+	BestWPM       int
+	LongestStreak int
+	Level         int
+	PerfectTests  int // 60s tests finished with 100% accuracy
+}
+
+// Unlocked is an achievement a user has earned
+type Unlocked struct {
+	Code       string
+	UnlockedAt time.Time
+}
+
+// Achievement is an achievement as shown to a user, earned or not
+type Achievement struct {
+	Code        string     `json:"code"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Target      int        `json:"target"`
+	Progress    int        `json:"progress"`
+	Percent     int        `json:"percent"`
+	Earned      bool       `json:"earned"`
+	UnlockedAt  *time.Time `json:"unlockedAt,omitempty"`
+}
+
+type UserAchievements struct {
+	Earned []*Achievement `json:"earned"`
+	Locked []*Achievement `json:"locked"`
+}
+
+type AchievementService interface {
+	UserAchievements(ctx context.Context, email string) (*UserAchievements, error)
+}
@@ -0,0 +1,7 @@
+package achievement
+
+import "errors"
+
+var (
+	ErrGettingDataFromDB error = errors.New("error getting data from DB")
+)
@@ -0,0 +1,58 @@
+package achievement
+
+import "time"
+
+// Value returns the stat the metric is measured on
+func (s *Stats) Value(metric Metric) int {
+	switch metric {
+	case MetricTotalTests:
+		return s.TotalTests
+	case MetricBestWPM:
+		return s.BestWPM
+	case MetricStreak:
+		return s.LongestStreak
+	case MetricLevel:
+		return s.Level
+	case MetricPerfectTests:
+		return s.PerfectTests
+	}
+	return 0
+}
+
+// Evaluate returns the definitions whose rule is met by stats but that are not earned yet
+func Evaluate(defs []Definition, stats *Stats, earned map[string]bool) []Definition {
+	var unlocked []Definition
+	for _, def := range defs {
+		if earned[def.Code] {
+			continue
+		}
+		if stats.Value(def.Metric) >= def.Target {
+			unlocked = append(unlocked, def)
+		}
+	}
+	return unlocked
+}
+
+// NewAchievement builds the user facing view of a definition
+func NewAchievement(def Definition, stats *Stats, unlockedAt *time.Time) *Achievement {
+	progress := min(stats.Value(def.Metric), def.Target)
+	if unlockedAt != nil {
+		progress = def.Target
+	}
+
+	percent := 100
+	if def.Target > 0 {
+		percent = progress * 100 / def.Target
+	}
+
+	return &Achievement{
+		Code:        def.Code,
+		Name:        def.Name,
+		Description: def.Description,
+		Target:      def.Target,
+		Progress:    progress,
+		Percent:     percent,
+		Earned:      unlockedAt != nil,
+		UnlockedAt:  unlockedAt,
+	}
+}
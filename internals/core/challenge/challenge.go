@@ -0,0 +1,47 @@
+package challenge
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DateLayout = "2006-01-02"
+
+	LeaderboardLimit = 50
+	HistoryLimit     = 30
+
+	// SubmissionGrace keeps yesterday's challenge open for a while past
+	// midnight UTC, for tests started just before it
+	SubmissionGrace = 5 * time.Minute
+)
+
+// DailyChallenge is the passage everyone races on a given UTC day
+type DailyChallenge struct {
+	Date string `json:"date"`
+	Text string `json:"text"`
+}
+
+// LeaderboardEntry is a user's first attempt at a daily challenge
+type LeaderboardEntry struct {
+	Rank      int       `json:"rank"`
+	Name      string    `json:"name"`
+	WPM       int       `json:"wpm"`
+	Accuracy  int       `json:"accuracy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Summary describes a past daily challenge
+type Summary struct {
+	Date         string `json:"date"`
+	Text         string `json:"text"`
+	Participants int    `json:"participants"`
+	WinnerName   string `json:"winnerName"`
+	WinnerWPM    int    `json:"winnerWpm"`
+}
+
+type ChallengeService interface {
+	TodayChallenge(ctx context.Context) *DailyChallenge
+	Leaderboard(ctx context.Context, date string) ([]*LeaderboardEntry, error)
+	History(ctx context.Context, limit string) ([]*Summary, error)
+}
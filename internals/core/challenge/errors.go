@@ -0,0 +1,9 @@
+package challenge
+
+import "errors"
+
+var (
+	ErrInvalidDate       error = errors.New("invalid challenge date")
+	ErrInvalidLimit      error = errors.New("invalid limit")
+	ErrGettingDataFromDB error = errors.New("error getting data from DB")
+)
@@ -0,0 +1,66 @@
+package challenge
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+const challengeWords = 40
+
+var wordList = strings.Fields(`
+	the quick brown fox jumps over lazy dog time people year way day thing man world life hand part child eye woman
+	place work week case point government company number group problem fact be have do say get make go know take see
+	come think look want give use find tell ask seem feel try leave call good new first last long great little own
+	other old right big high different small large next early young important few public bad same able keyboard
+	practice speed accuracy rhythm finger letter word sentence paragraph focus steady calm quick bright river mountain
+	ocean forest city garden window morning evening light shadow story music travel friend family teacher student
+`)
+
+// DateKey returns the UTC calendar day of t, which identifies its challenge
+func DateKey(t time.Time) string {
+	return t.UTC().Format(DateLayout)
+}
+
+// Accepts reports whether a test of the challenge of date may still be
+// submitted at now: today's, or yesterday's within SubmissionGrace
+func Accepts(date string, now time.Time) bool {
+	return date == DateKey(now) || date == DateKey(now.Add(-SubmissionGrace))
+}
+
+// ParseDate validates a challenge date
+func ParseDate(date string) (time.Time, error) {
+	d, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	return d, nil
+}
+
+// GenerateText deterministically builds the passage for a date, so every
+// instance of the server serves the same text without storing it
+func GenerateText(date string) string {
+	h := fnv.New64a()
+	h.Write([]byte(date))
+	return TextFromSeed(int64(h.Sum64()), challengeWords)
+}
+
+// TextFromSeed builds a passage of n words, the same for the same seed
+func TextFromSeed(seed int64, n int) string {
+	r := rand.New(rand.NewSource(seed))
+
+	words := make([]string, n)
+	for i := range words {
+		words[i] = wordList[r.Intn(len(wordList))]
+	}
+	return strings.Join(words, " ")
+}
+
+// ForDate returns the challenge of the given date
+func ForDate(date string) *DailyChallenge {
+	return &DailyChallenge{
+		Date: date,
+		Text: GenerateText(date),
+	}
+}
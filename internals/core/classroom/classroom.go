@@ -0,0 +1,103 @@
+package classroom
+
+import (
+	"context"
+	"time"
+	"typing-speed/internals/core/typing"
+)
+
+const (
+	RoleTeacher = "teacher"
+	RoleStudent = "student"
+
+	JoinCodeLength = 8
+	MaxNameLength  = 100
+	MaxTextLength  = 5000
+)
+
+type Class struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Teacher     string    `json:"teacher"`
+	TeacherName string    `json:"teacherName"`
+	JoinCode    string    `json:"joinCode,omitempty"` // shown to the teacher only
+	Students    int       `json:"students"`
+	Role        string    `json:"role,omitempty"` // of the user asking
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type Student struct {
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+type Assignment struct {
+	ID          string    `json:"id"`
+	ClassID     string    `json:"classId"`
+	Title       string    `json:"title"`
+	Mode        string    `json:"mode"`
+	Text        string    `json:"text"`
+	MinAccuracy int       `json:"minAccuracy"`
+	DueAt       time.Time `json:"dueAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+
+	// for students, whether they have met the assignment yet
+	Completed *bool `json:"completed,omitempty"`
+}
+
+// Submission is a test a student took for an assignment
+type Submission struct {
+	AssignmentID string    `json:"assignmentId"`
+	Email        string    `json:"email"`
+	TestID       string    `json:"testId"`
+	WPM          int       `json:"wpm"`
+	Accuracy     int       `json:"accuracy"`
+	Passed       bool      `json:"passed"` // met the minimum accuracy
+	SubmittedAt  time.Time `json:"submittedAt"`
+}
+
+type SubmissionResult struct {
+	Submission *Submission        `json:"submission"`
+	Test       *typing.TestResult `json:"test"`
+}
+
+// ReportRow is how one student did on an assignment. Students who did not
+// submit anything are listed with no attempts.
+type ReportRow struct {
+	Name         string     `json:"name"`
+	Email        string     `json:"email"`
+	Attempts     int        `json:"attempts"`
+	Completed    bool       `json:"completed"`
+	BestWPM      int        `json:"bestWpm"`
+	BestAccuracy int        `json:"bestAccuracy"`
+	CompletedAt  *time.Time `json:"completedAt"`
+}
+
+type Report struct {
+	Assignment *Assignment  `json:"assignment"`
+	Students   int          `json:"students"`
+	Completed  int          `json:"completed"`
+	Rows       []*ReportRow `json:"rows"`
+}
+
+type ClassDetails struct {
+	Class       *Class        `json:"class"`
+	Students    []*Student    `json:"students,omitempty"` // shown to the teacher only
+	Assignments []*Assignment `json:"assignments"`
+}
+
+type ClassService interface {
+	CreateClass(ctx context.Context, email string, c *Class) (*Class, error)
+	Classes(ctx context.Context, email string) ([]*Class, error)
+	Class(ctx context.Context, email string, id string) (*ClassDetails, error)
+	DeleteClass(ctx context.Context, email string, id string) error
+	Join(ctx context.Context, email string, code string) (*Class, error)
+	RemoveStudent(ctx context.Context, email string, id string, student string) error
+	CreateAssignment(ctx context.Context, email string, id string, a *Assignment) (*Assignment, error)
+	Assignment(ctx context.Context, email string, id string, assignmentID string) (*Assignment, error)
+	DeleteAssignment(ctx context.Context, email string, id string, assignmentID string) error
+	Submit(ctx context.Context, email string, id string, assignmentID string, data *typing.TypingData) (*SubmissionResult, error)
+	Submissions(ctx context.Context, email string, id string, assignmentID string) ([]*Submission, error)
+	Report(ctx context.Context, email string, id string, assignmentID string) (*Report, error)
+}
@@ -0,0 +1,20 @@
+package classroom
+
+import "errors"
+
+var (
+	ErrInvalidClass       error = errors.New("invalid class")
+	ErrClassNotFound      error = errors.New("class not found")
+	ErrInvalidJoinCode    error = errors.New("invalid join code")
+	ErrTeacher            error = errors.New("teachers cannot join their own class")
+	ErrAlreadyEnrolled    error = errors.New("already enrolled in the class")
+	ErrNotEnrolled        error = errors.New("not enrolled in the class")
+	ErrNotTeacher         error = errors.New("only the teacher can do this")
+	ErrStudentNotFound    error = errors.New("student not found")
+	ErrInvalidAssignment  error = errors.New("invalid assignment")
+	ErrAssignmentNotFound error = errors.New("assignment not found")
+	ErrAssignmentClosed   error = errors.New("assignment is past its due date")
+	ErrModeMismatch       error = errors.New("test mode does not match the assignment")
+	ErrGettingDataFromDB  error = errors.New("error getting data from DB")
+	ErrSomethingWentWrong error = errors.New("something went wrong")
+)
@@ -0,0 +1,127 @@
+package classroom
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/csv"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"typing-speed/internals/core/typing"
+)
+
+// joinAlphabet leaves out characters that are easy to misread on a whiteboard
+const joinAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// NewJoinCode returns a random code students join a class with
+func NewJoinCode() (string, error) {
+	code := make([]byte, JoinCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(joinAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = joinAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// NormalizeJoinCode makes codes typed in lower case or with spaces match
+func NormalizeJoinCode(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
+
+// NormalizeAssignment trims the assignment and checks it can still be done
+func NormalizeAssignment(a *Assignment, now time.Time) error {
+	a.Title = strings.TrimSpace(a.Title)
+	a.Text = strings.TrimSpace(a.Text)
+
+	if a.Title == "" || len(a.Title) > MaxNameLength {
+		return ErrInvalidAssignment
+	}
+	if a.Text == "" || len(a.Text) > MaxTextLength {
+		return ErrInvalidAssignment
+	}
+	if !typing.ValidMode(a.Mode) {
+		return ErrInvalidAssignment
+	}
+	if a.MinAccuracy < 0 || a.MinAccuracy > 100 {
+		return ErrInvalidAssignment
+	}
+	if !a.DueAt.After(now) {
+		return ErrInvalidAssignment
+	}
+	return nil
+}
+
+// NewReport works out how each student did from their submissions
+func NewReport(a *Assignment, students []*Student, submissions []*Submission) *Report {
+	byEmail := map[string][]*Submission{}
+	for _, s := range submissions {
+		byEmail[s.Email] = append(byEmail[s.Email], s)
+	}
+
+	report := &Report{Assignment: a, Students: len(students), Rows: make([]*ReportRow, 0, len(students))}
+	for _, st := range students {
+		row := &ReportRow{Name: st.Name, Email: st.Email}
+		for _, s := range byEmail[st.Email] {
+			row.Attempts++
+			row.BestWPM = max(row.BestWPM, s.WPM)
+			row.BestAccuracy = max(row.BestAccuracy, s.Accuracy)
+			if s.Passed && (row.CompletedAt == nil || s.SubmittedAt.Before(*row.CompletedAt)) {
+				at := s.SubmittedAt
+				row.CompletedAt = &at
+			}
+		}
+		row.Completed = row.CompletedAt != nil
+		if row.Completed {
+			report.Completed++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report
+}
+
+// CSV writes the report out with one line per student
+func (r *Report) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{"name", "email", "attempts", "completed", "best_wpm", "best_accuracy", "completed_at"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, row := range r.Rows {
+		completedAt := ""
+		if row.CompletedAt != nil {
+			completedAt = row.CompletedAt.UTC().Format(time.RFC3339)
+		}
+		record := []string{
+			csvCell(row.Name),
+			csvCell(row.Email),
+			strconv.Itoa(row.Attempts),
+			strconv.FormatBool(row.Completed),
+			strconv.Itoa(row.BestWPM),
+			strconv.Itoa(row.BestAccuracy),
+			completedAt,
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvCell stops spreadsheets from running what a student typed as a formula
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
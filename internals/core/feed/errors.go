@@ -0,0 +1,13 @@
+package feed
+
+import "errors"
+
+var (
+	ErrSelf               error = errors.New("cannot do this to yourself")
+	ErrUserNotFound       error = errors.New("user not found")
+	ErrNotMuted           error = errors.New("user is not muted")
+	ErrInvalidCursor      error = errors.New("invalid cursor")
+	ErrInvalidLimit       error = errors.New("invalid limit")
+	ErrGettingDataFromDB  error = errors.New("error getting data from DB")
+	ErrSomethingWentWrong error = errors.New("something went wrong")
+)
@@ -0,0 +1,62 @@
+package feed
+
+import (
+	"context"
+	"time"
+)
+
+// Kinds of events that show up in the feeds of a user's followers
+const (
+	KindPersonalBest = "personal_best"
+	KindAchievement  = "achievement"
+	KindLevelUp      = "level_up"
+	KindRaceWin      = "race_win"
+)
+
+const (
+	PageSize    = 20
+	MaxPageSize = 50
+
+	// FanOutLimit caps how many followers get a copy of one event; the
+	// followers of actors with more pull their events when reading the feed.
+	// FeedLimit is how many items a feed keeps before dropping the oldest.
+	FanOutLimit = 5000
+	FeedLimit   = 500
+)
+
+// Event is a milestone of a user. Pages of a feed are cursored by its ID.
+type Event struct {
+	ID          int64     `json:"id"`
+	Actor       string    `json:"-"`
+	Name        string    `json:"name"`
+	Username    string    `json:"username,omitempty"`
+	Kind        string    `json:"kind"`
+	Mode        string    `json:"mode,omitempty"`
+	Language    string    `json:"language,omitempty"`
+	WPM         int       `json:"wpm,omitempty"`
+	Achievement string    `json:"achievement,omitempty"`
+	Level       int       `json:"level,omitempty"`
+	RaceID      string    `json:"raceId,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Page is a page of a feed, latest first. NextCursor is empty on the last page.
+type Page struct {
+	Items      []*Event `json:"items"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+}
+
+// Muted is a user whose events are kept out of the user's feed
+type Muted struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Since time.Time `json:"since"`
+}
+
+type FeedService interface {
+	Publish(ctx context.Context, events ...*Event) error
+	Feed(ctx context.Context, email string, cursor string, limit string) (*Page, error)
+	Mute(ctx context.Context, email string, target string) error
+	Unmute(ctx context.Context, email string, target string) error
+	Muted(ctx context.Context, email string) ([]*Muted, error)
+}
@@ -0,0 +1,73 @@
+package feed
+
+import (
+	"strconv"
+	"typing-speed/internals/core/race"
+	"typing-speed/internals/core/typing"
+)
+
+// TestEvents are the milestones the user reached with a test. A first result
+// in a mode is not a personal best worth announcing.
+func TestEvents(email string, result *typing.TestResult) []*Event {
+	events := []*Event{}
+
+	if pb := result.PersonalBest; pb != nil && pb.NewPersonalBest && pb.PreviousBestWPM > 0 {
+		events = append(events, &Event{Actor: email, Kind: KindPersonalBest, Mode: pb.Mode, Language: pb.Language,
+			WPM: pb.WPM})
+	}
+	for _, a := range result.Achievements {
+		events = append(events, &Event{Actor: email, Kind: KindAchievement, Achievement: a.Name})
+	}
+	if result.LeveledUp {
+		events = append(events, &Event{Actor: email, Kind: KindLevelUp, Level: result.Level})
+	}
+
+	return events
+}
+
+// RaceWinEvent is the win of the race, or nil when nobody beat another
+// person in it. Flagged racers are left out, so a flagged first place is
+// never announced.
+func RaceWinEvent(rc *race.Race) *Event {
+	racers := 0
+	var winner *race.Result
+	for _, res := range rc.Results {
+		if res.Bot || res.Flagged {
+			continue
+		}
+		racers++
+		if res.Position == 1 {
+			winner = res
+		}
+	}
+	if winner == nil || racers < 2 {
+		return nil
+	}
+
+	return &Event{Actor: winner.Email, Kind: KindRaceWin, Mode: rc.Mode, Language: rc.Language, WPM: winner.WPM,
+		RaceID: rc.ID}
+}
+
+// ParseCursor reads the cursor of a page, 0 for the first page
+func ParseCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
+
+// PageLimit reads the requested page size, PageSize when unset
+func PageLimit(limit string) (int, error) {
+	if limit == "" {
+		return PageSize, nil
+	}
+	l, err := strconv.Atoi(limit)
+	if err != nil || l <= 0 || l > MaxPageSize {
+		return 0, ErrInvalidLimit
+	}
+	return l, nil
+}
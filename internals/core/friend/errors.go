@@ -0,0 +1,18 @@
+package friend
+
+import "errors"
+
+var (
+	ErrSelf               error = errors.New("cannot do this to yourself")
+	ErrUserNotFound       error = errors.New("user not found")
+	ErrBlocked            error = errors.New("blocked")
+	ErrNotBlocked         error = errors.New("user is not blocked")
+	ErrNotFollowing       error = errors.New("not following the user")
+	ErrAlreadyFriends     error = errors.New("already friends")
+	ErrNotFriends         error = errors.New("not friends with the user")
+	ErrRequestNotFound    error = errors.New("friend request not found")
+	ErrRequestAnswered    error = errors.New("friend request already answered")
+	ErrPrivateProfile     error = errors.New("profile is private")
+	ErrGettingDataFromDB  error = errors.New("error getting data from DB")
+	ErrSomethingWentWrong error = errors.New("something went wrong")
+)
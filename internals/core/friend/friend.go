@@ -0,0 +1,107 @@
+package friend
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	RequestPending  = "pending"
+	RequestAccepted = "accepted"
+	RequestDeclined = "declined"
+
+	LeaderboardLimit = 100
+)
+
+// Friend is another user in one of the user's lists
+type Friend struct {
+	Name   string    `json:"name"`
+	Email  string    `json:"email"`
+	Mutual bool      `json:"mutual"` // they follow each other, which makes them friends
+	Since  time.Time `json:"since"`
+}
+
+// Relationship is how the user is connected to another user. Users who
+// follow each other are friends.
+type Relationship struct {
+	Following  bool `json:"following"`
+	FollowedBy bool `json:"followedBy"`
+	Friends    bool `json:"friends"`
+	Blocking   bool `json:"blocking"`
+	BlockedBy  bool `json:"-"` // nobody is told who blocked them
+}
+
+type Request struct {
+	ID          int64      `json:"id"`
+	FromEmail   string     `json:"fromEmail"`
+	FromName    string     `json:"fromName"`
+	ToEmail     string     `json:"toEmail"`
+	ToName      string     `json:"toName"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+}
+
+// Requests are the user's pending friend requests
+type Requests struct {
+	Incoming []*Request `json:"incoming"`
+	Outgoing []*Request `json:"outgoing"`
+}
+
+// LeaderboardEntry ranks the user among their friends
+type LeaderboardEntry struct {
+	Rank        int    `json:"rank"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	Performance int    `json:"performance"`
+	AvgSpeed    int    `json:"avgSpeed"`
+	BestSpeed   int    `json:"bestSpeed"`
+	AvgAccuracy int    `json:"avgAccuracy"`
+	TotalTest   int    `json:"totalTest"`
+	You         bool   `json:"you"`
+}
+
+type Stats struct {
+	Name           string `json:"name"`
+	Level          int    `json:"level"`
+	AvgSpeed       int    `json:"avgSpeed"`
+	BestSpeed      int    `json:"bestSpeed"`
+	AvgAccuracy    int    `json:"avgAccuracy"`
+	AvgPerformance int    `json:"avgPerformance"`
+	TotalTest      int    `json:"totalTest"`
+	Streak         int    `json:"streak"`
+	LongestStreak  int    `json:"longestStreak"`
+}
+
+// ModeComparison sets the personal bests of both users in a mode side by
+// side, 0 for one who has none
+type ModeComparison struct {
+	Mode     string `json:"mode"`
+	Language string `json:"language"`
+	You      int    `json:"you"`
+	Friend   int    `json:"friend"`
+}
+
+type Comparison struct {
+	You           *Stats            `json:"you"`
+	Friend        *Stats            `json:"friend"`
+	PersonalBests []*ModeComparison `json:"personalBests"`
+}
+
+type FriendService interface {
+	Follow(ctx context.Context, email string, target string) error
+	Unfollow(ctx context.Context, email string, target string) error
+	Followers(ctx context.Context, email string) ([]*Friend, error)
+	Following(ctx context.Context, email string) ([]*Friend, error)
+	Friends(ctx context.Context, email string) ([]*Friend, error)
+	Unfriend(ctx context.Context, email string, target string) error
+	Relationship(ctx context.Context, email string, target string) (*Relationship, error)
+	SendRequest(ctx context.Context, email string, target string) (*Request, error)
+	Requests(ctx context.Context, email string) (*Requests, error)
+	RespondToRequest(ctx context.Context, email string, id string, accept bool) (*Request, error)
+	Block(ctx context.Context, email string, target string) error
+	Unblock(ctx context.Context, email string, target string) error
+	Blocked(ctx context.Context, email string) ([]*Friend, error)
+	Leaderboard(ctx context.Context, email string) ([]*LeaderboardEntry, error)
+	Compare(ctx context.Context, email string, target string) (*Comparison, error)
+}
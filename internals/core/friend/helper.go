@@ -0,0 +1,62 @@
+package friend
+
+import (
+	"sort"
+	"time"
+	"typing-speed/internals/core/typing"
+	"typing-speed/internals/core/user"
+)
+
+// NewStats takes the stats of a user to compare, with the streak as of now
+func NewStats(u *user.User, now time.Time) *Stats {
+	return &Stats{
+		Name:           u.Name,
+		Level:          u.Level,
+		AvgSpeed:       u.AvgSpeed,
+		BestSpeed:      u.BestSpeed,
+		AvgAccuracy:    u.AvgAccuracy,
+		AvgPerformance: u.AvgPerformance,
+		TotalTest:      u.TotalTest,
+		Streak:         user.CurrentStreak(u.Streak, u.LastTestTime, now, user.Location(u.TimeZone)),
+		LongestStreak:  u.LongestStreak,
+	}
+}
+
+// ComparePersonalBests pairs up the personal bests of both users by mode and
+// language, covering every mode either of them has one in
+func ComparePersonalBests(yours []*typing.PersonalBest, theirs []*typing.PersonalBest) []*ModeComparison {
+	type key struct{ mode, language string }
+	byKey := map[key]*ModeComparison{}
+	at := func(pb *typing.PersonalBest) *ModeComparison {
+		k := key{pb.Mode, pb.Language}
+		if byKey[k] == nil {
+			byKey[k] = &ModeComparison{Mode: pb.Mode, Language: pb.Language}
+		}
+		return byKey[k]
+	}
+
+	for _, pb := range yours {
+		at(pb).You = pb.WPM
+	}
+	for _, pb := range theirs {
+		at(pb).Friend = pb.WPM
+	}
+
+	comparisons := make([]*ModeComparison, 0, len(byKey))
+	for _, c := range byKey {
+		comparisons = append(comparisons, c)
+	}
+	// in the order of typing.Modes, then by language
+	order := map[string]int{}
+	for i, mode := range typing.Modes {
+		order[mode] = i
+	}
+	sort.Slice(comparisons, func(i, j int) bool {
+		a, b := comparisons[i], comparisons[j]
+		if a.Mode != b.Mode {
+			return order[a.Mode] < order[b.Mode]
+		}
+		return a.Language < b.Language
+	})
+	return comparisons
+}
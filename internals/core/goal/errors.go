@@ -0,0 +1,10 @@
+package goal
+
+import "errors"
+
+var (
+	ErrInvalidGoal        error = errors.New("invalid goal")
+	ErrGoalNotFound       error = errors.New("goal not found")
+	ErrGettingDataFromDB  error = errors.New("error getting data from DB")
+	ErrSomethingWentWrong error = errors.New("something went wrong")
+)
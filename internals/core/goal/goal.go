@@ -0,0 +1,69 @@
+package goal
+
+import (
+	"context"
+	"time"
+)
+
+type Type string
+
+const (
+	TypeSpeed         Type = "speed"          // reach Target WPM
+	TypeAccuracy      Type = "accuracy"       // finish a test with Target % accuracy
+	TypeDailyPractice Type = "daily_practice" // practice Target minutes every day
+	TypeTestCount     Type = "test_count"     // complete Target tests
+)
+
+// The largest Target of each type
+const (
+	MaxSpeedTarget         = 300     // WPM
+	MaxAccuracyTarget      = 100     // %
+	MaxDailyPracticeTarget = 24 * 60 // minutes per day
+	MaxTestCountTarget     = 100000  // tests
+)
+
+type Goal struct {
+	ID          int64      `json:"id"`
+	Email       string     `json:"-"`
+	Type        Type       `json:"type"`
+	Target      int        `json:"target"`
+	Mode        string     `json:"mode,omitempty"` // limits speed, accuracy and test count goals to one mode
+	Deadline    *time.Time `json:"deadline,omitempty"`
+	Baseline    int        `json:"baseline"` // progress at the first evaluation, used to project completion
+	Progress    int        `json:"progress"` // seconds practiced on ProgressDate for daily practice goals
+	ProgressDay string     `json:"progressDay,omitempty"`
+	DaysMet     int        `json:"daysMet"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// TestInput is the part of a completed test goals are evaluated against
+type TestInput struct {
+	Mode     string
+	WPM      int
+	Accuracy int
+	Seconds  int // time the user spent typing
+}
+
+// Progress is a goal with how far along it is
+type Progress struct {
+	*Goal
+	Percent             int        `json:"percent"`
+	MetToday            bool       `json:"metToday,omitempty"`
+	Expired             bool       `json:"expired"`
+	OnTrack             bool       `json:"onTrack"`
+	ProjectedCompletion *time.Time `json:"projectedCompletion,omitempty"`
+}
+
+type GoalsProgress struct {
+	Active    []*Progress `json:"active"`
+	Completed []*Progress `json:"completed"`
+}
+
+type GoalService interface {
+	CreateGoal(ctx context.Context, email string, g *Goal) (*Goal, error)
+	Goals(ctx context.Context, email string) ([]*Goal, error)
+	UpdateGoal(ctx context.Context, email string, id string, g *Goal) (*Goal, error)
+	DeleteGoal(ctx context.Context, email string, id string) error
+	GoalsProgress(ctx context.Context, email string) (*GoalsProgress, error)
+}
@@ -0,0 +1,142 @@
+package goal
+
+import "time"
+
+const dayLayout = "2006-01-02"
+
+func ValidType(t Type) bool {
+	return MaxTarget(t) > 0
+}
+
+// MaxTarget is the largest target a goal of the type may have, 0 for an
+// unknown type
+func MaxTarget(t Type) int {
+	switch t {
+	case TypeSpeed:
+		return MaxSpeedTarget
+	case TypeAccuracy:
+		return MaxAccuracyTarget
+	case TypeDailyPractice:
+		return MaxDailyPracticeTarget
+	case TypeTestCount:
+		return MaxTestCountTarget
+	}
+	return 0
+}
+
+// Validate checks the user editable fields of a goal
+func (g *Goal) Validate() error {
+	if !ValidType(g.Type) || g.Target <= 0 || g.Target > MaxTarget(g.Type) {
+		return ErrInvalidGoal
+	}
+	return nil
+}
+
+// Evaluate applies a completed test to the goal and reports whether it changed.
+// Tests after the deadline do not count. now and loc decide the calendar day
+// for daily practice goals.
+func (g *Goal) Evaluate(in TestInput, now time.Time, loc *time.Location) bool {
+	if g.CompletedAt != nil || (g.Deadline != nil && now.After(*g.Deadline)) {
+		return false
+	}
+	if g.Mode != "" && g.Mode != in.Mode && g.Type != TypeDailyPractice {
+		return false
+	}
+
+	before := g.Progress
+	switch g.Type {
+	case TypeSpeed:
+		g.record(max(g.Progress, in.WPM))
+	case TypeAccuracy:
+		g.record(max(g.Progress, in.Accuracy))
+	case TypeTestCount:
+		g.Progress++
+	case TypeDailyPractice:
+		return g.practice(in.Seconds, now, loc)
+	}
+
+	if g.Progress >= g.Target {
+		g.CompletedAt = &now
+	}
+	return g.Progress != before
+}
+
+// record sets the progress, remembering the first value as the baseline
+func (g *Goal) record(value int) {
+	if g.Progress == 0 {
+		g.Baseline = value
+	}
+	g.Progress = value
+}
+
+// practice adds practice time to the current day, starting a new day when needed
+func (g *Goal) practice(seconds int, now time.Time, loc *time.Location) bool {
+	if seconds <= 0 {
+		return false
+	}
+
+	today := now.In(loc).Format(dayLayout)
+	if g.ProgressDay != today {
+		g.ProgressDay = today
+		g.Progress = 0
+	}
+
+	metBefore := g.Progress >= g.Target*60
+	g.Progress += seconds
+	if !metBefore && g.Progress >= g.Target*60 {
+		g.DaysMet++
+	}
+	return true
+}
+
+// NewProgress reports how far along the goal is at now
+func NewProgress(g *Goal, now time.Time, loc *time.Location) *Progress {
+	p := &Progress{Goal: g}
+
+	target := g.Target
+	current := g.Progress
+	if g.Type == TypeDailyPractice {
+		target *= 60
+		if g.ProgressDay != now.In(loc).Format(dayLayout) {
+			current = 0
+		}
+		p.MetToday = current >= target
+	}
+
+	p.Percent = min(current*100/target, 100)
+	if g.CompletedAt != nil {
+		p.Percent = 100
+		p.OnTrack = true
+		return p
+	}
+
+	p.Expired = g.Deadline != nil && now.After(*g.Deadline)
+	p.ProjectedCompletion = project(g, now)
+	p.OnTrack = !p.Expired && (g.Deadline == nil ||
+		(p.ProjectedCompletion != nil && !p.ProjectedCompletion.After(*g.Deadline)))
+	if g.Type == TypeDailyPractice {
+		p.OnTrack = p.MetToday
+	}
+
+	return p
+}
+
+// project extrapolates the progress made since the goal was created
+func project(g *Goal, now time.Time) *time.Time {
+	if g.Type == TypeDailyPractice {
+		return nil
+	}
+
+	gained := g.Progress - g.Baseline
+	elapsed := now.Sub(g.CreatedAt)
+	if g.Type == TypeTestCount {
+		gained = g.Progress
+	}
+	if gained <= 0 || elapsed <= 0 {
+		return nil
+	}
+
+	remaining := g.Target - g.Progress
+	eta := now.Add(time.Duration(float64(elapsed) * float64(remaining) / float64(gained)))
+	return &eta
+}
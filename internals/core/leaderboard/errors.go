@@ -0,0 +1,15 @@
+package leaderboard
+
+import "errors"
+
+var (
+	ErrInvalidWindow     error = errors.New("invalid window")
+	ErrInvalidMode       error = errors.New("invalid mode")
+	ErrInvalidSort       error = errors.New("invalid sort")
+	ErrInvalidCursor     error = errors.New("invalid cursor")
+	ErrInvalidLimit      error = errors.New("invalid limit")
+	ErrInvalidMetric     error = errors.New("invalid metric")
+	ErrInvalidCountry    error = errors.New("invalid country")
+	ErrInvalidRegion     error = errors.New("region needs a country")
+	ErrGettingDataFromDB error = errors.New("error getting data from DB")
+)
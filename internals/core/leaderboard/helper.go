@@ -0,0 +1,204 @@
+package leaderboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"typing-speed/internals/core/typing"
+	"typing-speed/internals/core/user"
+
+	"github.com/google/uuid"
+)
+
+// WindowStart returns when the window that now falls in began, nil for all time
+func WindowStart(window string, now time.Time) (*time.Time, error) {
+	y, m, d := now.UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	var start time.Time
+	switch window {
+	case WindowToday:
+		start = today
+	case WindowWeek:
+		// weeks start on Monday
+		start = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	case WindowMonth:
+		start = time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	case WindowAll:
+		return nil, nil
+	default:
+		return nil, ErrInvalidWindow
+	}
+	return &start, nil
+}
+
+func ValidSort(sort string) bool {
+	return sort == SortWPM || sort == SortPerformance || sort == SortAccuracy
+}
+
+// NewQuery checks the filter and fills in its defaults
+func NewQuery(f *Filter, now time.Time) (*Query, error) {
+	q := &Query{
+		Window:   f.Window,
+		Mode:     f.Mode,
+		Language: strings.ToLower(strings.TrimSpace(f.Language)),
+		Sort:     f.Sort,
+		Region:   strings.TrimSpace(f.Region),
+		Limit:    PageSize,
+	}
+	if q.Window == "" {
+		q.Window = WindowAll
+	}
+	if q.Mode == "" {
+		q.Mode = typing.Mode60s
+	}
+	if q.Language == "" {
+		q.Language = typing.DefaultLanguage
+	}
+	if q.Sort == "" {
+		q.Sort = SortWPM
+	}
+
+	since, err := WindowStart(q.Window, now)
+	if err != nil {
+		return nil, err
+	}
+	q.Since = since
+
+	if !typing.ValidMode(q.Mode) {
+		return nil, ErrInvalidMode
+	}
+	if !ValidSort(q.Sort) {
+		return nil, ErrInvalidSort
+	}
+
+	country, err := user.NormalizeCountry(f.Country)
+	if err != nil {
+		return nil, ErrInvalidCountry
+	}
+	q.Country = country
+	if q.Region != "" && q.Country == "" {
+		return nil, ErrInvalidRegion
+	}
+
+	if f.Limit != "" {
+		l, err := strconv.Atoi(f.Limit)
+		if err != nil || l <= 0 || l > MaxPageSize {
+			return nil, ErrInvalidLimit
+		}
+		q.Limit = l
+	}
+
+	if f.Cursor != "" {
+		c, err := DecodeCursor(f.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		q.Cursor = c
+	}
+
+	return q, nil
+}
+
+// SortValues returns the value the entry is ranked by and the one that
+// breaks ties
+func SortValues(sort string, e *Entry) (int, int) {
+	switch sort {
+	case SortAccuracy:
+		return e.Accuracy, e.WPM
+	case SortPerformance:
+		return e.Performance, e.WPM
+	default:
+		return e.WPM, e.Accuracy
+	}
+}
+
+// EncodeCursor makes the cursor of the page after the entry
+func EncodeCursor(sort string, e *Entry) string {
+	value, tiebreak := SortValues(sort, e)
+	raw := fmt.Sprintf("%d:%d:%d:%s", value, tiebreak, e.CreatedAt.UnixNano(), e.TestID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(cursor string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(parts[3]); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	value, err1 := strconv.Atoi(parts[0])
+	tiebreak, err2 := strconv.Atoi(parts[1])
+	nanos, err3 := strconv.ParseInt(parts[2], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{Value: value, Tiebreak: tiebreak, CreatedAt: time.Unix(0, nanos).UTC(), TestID: parts[3]}, nil
+}
+
+func ValidMetric(metric string) bool {
+	return metric == MetricBest || metric == MetricAverage
+}
+
+// NewDistribution builds the histograms of the modes from their buckets
+func NewDistribution(metric string, buckets []*Bucket, now time.Time) *Distribution {
+	d := &Distribution{Metric: metric, Modes: map[string]*Histogram{}, UpdatedAt: now}
+	for _, b := range buckets {
+		h := d.Modes[b.Mode]
+		if h == nil {
+			h = &Histogram{}
+			d.Modes[b.Mode] = h
+		}
+		h.WPM = append(h.WPM, b.WPM)
+		h.Users = append(h.Users, b.Users)
+		h.Total += b.Users
+	}
+
+	for _, h := range d.Modes {
+		sort.Sort(byWPM{h})
+	}
+	return d
+}
+
+type byWPM struct{ *Histogram }
+
+func (h byWPM) Len() int           { return len(h.WPM) }
+func (h byWPM) Less(i, j int) bool { return h.WPM[i] < h.WPM[j] }
+func (h byWPM) Swap(i, j int) {
+	h.WPM[i], h.WPM[j] = h.WPM[j], h.WPM[i]
+	h.Users[i], h.Users[j] = h.Users[j], h.Users[i]
+}
+
+// Standing places a speed in the histogram. Users as fast share a rank.
+// A speed newer than the histogram is counted in as one more user.
+func (h *Histogram) Standing(wpm int) (rank int, users int, percentile int) {
+	slower, same := 0, 0
+	for i, v := range h.WPM {
+		switch {
+		case v < wpm:
+			slower += h.Users[i]
+		case v == wpm:
+			same = h.Users[i]
+		}
+	}
+
+	users = h.Total
+	if same == 0 {
+		users++
+	}
+	rank = users - slower - max(same, 1) + 1
+	if users > 1 {
+		percentile = slower * 100 / (users - 1)
+	}
+	return rank, users, percentile
+}
@@ -0,0 +1,160 @@
+package leaderboard
+
+import (
+	"context"
+	"time"
+)
+
+// Windows a leaderboard covers, as calendar periods in UTC
+const (
+	WindowToday = "today"
+	WindowWeek  = "week"
+	WindowMonth = "month"
+	WindowAll   = "all"
+)
+
+// What users are ranked by. Each user is ranked by their one best test in
+// the window, the one with the highest value.
+const (
+	SortWPM         = "wpm"
+	SortPerformance = "performance" // speed times accuracy
+	SortAccuracy    = "accuracy"
+)
+
+const (
+	PageSize    = 50
+	MaxPageSize = 100
+)
+
+// Which speed of each user in a mode percentiles compare
+const (
+	MetricBest    = "best"    // their personal best
+	MetricAverage = "average" // their average over all their tests
+)
+
+// DistributionRefresh is how often the distribution of everyone's speed,
+// which ranks and percentiles are read from, is computed again
+const DistributionRefresh = 10 * time.Minute
+
+// Filter is a leaderboard request as it comes in. Empty fields take the
+// defaults: all time, 60s, English, by speed, every country.
+type Filter struct {
+	Window   string
+	Mode     string
+	Language string
+	Sort     string
+	Country  string
+	Region   string
+	Cursor   string
+	Limit    string
+}
+
+// Cursor is the position after the last entry of a page, in the order of
+// the leaderboard: best value first, then the second one, then the earliest
+// test
+type Cursor struct {
+	Value     int
+	Tiebreak  int
+	CreatedAt time.Time
+	TestID    string
+}
+
+// Query is a checked Filter. Since is nil for all time, and an empty
+// country or region is everywhere.
+type Query struct {
+	Window   string
+	Mode     string
+	Language string
+	Sort     string
+	Country  string
+	Region   string
+	Since    *time.Time
+	Cursor   *Cursor
+	Limit    int
+}
+
+// Entry is a user's best test in the window. Ties are broken by the other
+// of speed and accuracy, then by who got there first.
+type Entry struct {
+	Rank        int       `json:"rank"`
+	Name        string    `json:"name"`
+	Username    string    `json:"username,omitempty"`
+	Email       string    `json:"-"`
+	Country     string    `json:"country,omitempty"`
+	TestID      string    `json:"testId"`
+	WPM         int       `json:"wpm"`
+	Accuracy    int       `json:"accuracy"`
+	Performance int       `json:"performance"`
+	CreatedAt   time.Time `json:"createdAt"`
+	You         bool      `json:"you"`
+}
+
+type Page struct {
+	Window     string   `json:"window"`
+	Mode       string   `json:"mode"`
+	Language   string   `json:"language"`
+	Sort       string   `json:"sort"`
+	Country    string   `json:"country,omitempty"`
+	Region     string   `json:"region,omitempty"`
+	Entries    []*Entry `json:"entries"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+}
+
+// TopTypist is the fastest user of a country, by their best speed
+type TopTypist struct {
+	Name     string `json:"name"`
+	Username string `json:"username,omitempty"`
+	WPM      int    `json:"wpm"`
+}
+
+// CountrySummary sums up the users with a public profile in a country. The
+// average is over those who took a test.
+type CountrySummary struct {
+	Country    string     `json:"country"`
+	Users      int        `json:"users"`
+	AverageWPM int        `json:"averageWpm"`
+	TopTypist  *TopTypist `json:"topTypist"`
+}
+
+// Bucket is how many users have a speed in a mode
+type Bucket struct {
+	Mode  string
+	WPM   int
+	Users int
+}
+
+// Histogram counts the users of a mode by speed, slowest first
+type Histogram struct {
+	WPM   []int
+	Users []int
+	Total int
+}
+
+// Distribution is the speed of every user by mode, as of UpdatedAt
+type Distribution struct {
+	Metric    string
+	Modes     map[string]*Histogram
+	UpdatedAt time.Time
+}
+
+// Standing is where the user stands among everyone with a speed in a mode
+type Standing struct {
+	Mode       string `json:"mode"`
+	WPM        int    `json:"wpm"`
+	Rank       int    `json:"rank"`
+	Users      int    `json:"users"`
+	Percentile int    `json:"percentile"` // share of the users slower than the user
+}
+
+type Standings struct {
+	Metric    string      `json:"metric"`
+	Standings []*Standing `json:"standings"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+type LeaderboardService interface {
+	Leaderboard(ctx context.Context, email string, filter *Filter) (*Page, error)
+	Standings(ctx context.Context, email string, metric string) (*Standings, error)
+	Countries(ctx context.Context) ([]*CountrySummary, error)
+	RefreshDistributions(ctx context.Context) error
+}
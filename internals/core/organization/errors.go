@@ -0,0 +1,25 @@
+package organization
+
+import "errors"
+
+var (
+	ErrInvalidOrganization  error = errors.New("invalid organization")
+	ErrInvalidDomain        error = errors.New("invalid email domain")
+	ErrOrganizationNotFound error = errors.New("organization not found")
+	ErrNotMember            error = errors.New("not a member of the organization")
+	ErrForbidden            error = errors.New("not allowed for your role")
+	ErrAlreadyMember        error = errors.New("already a member")
+	ErrMemberNotFound       error = errors.New("member not found")
+	ErrInvalidRole          error = errors.New("invalid role")
+	ErrOwnerCannotLeave     error = errors.New("the owner cannot leave the organization")
+	ErrDomainTaken          error = errors.New("email domain already claimed by another organization")
+	ErrDomainMismatch       error = errors.New("email domain does not match the organization")
+	ErrDomainNotVerified    error = errors.New("email domain not verified")
+	ErrVerificationFailed   error = errors.New("no DNS TXT record verifies the domain")
+	ErrInvalidTeam          error = errors.New("invalid team")
+	ErrTeamNotFound         error = errors.New("team not found")
+	ErrTeamExists           error = errors.New("team already exists")
+	ErrUserNotFound         error = errors.New("user not found")
+	ErrGettingDataFromDB    error = errors.New("error getting data from DB")
+	ErrSomethingWentWrong   error = errors.New("something went wrong")
+)
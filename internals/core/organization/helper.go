@@ -0,0 +1,67 @@
+package organization
+
+import "strings"
+
+// publicDomains are email providers anyone can sign up with, so they cannot
+// be claimed by an organization
+var publicDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+	"yahoo.com":      true,
+	"outlook.com":    true,
+	"hotmail.com":    true,
+	"live.com":       true,
+	"msn.com":        true,
+	"icloud.com":     true,
+	"me.com":         true,
+	"aol.com":        true,
+	"proton.me":      true,
+	"protonmail.com": true,
+	"gmx.com":        true,
+	"yandex.com":     true,
+	"mail.com":       true,
+	"zoho.com":       true,
+}
+
+// EmailDomain returns the lower-cased part of the email after the @
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// NormalizeDomain lower-cases the domain and checks it can be claimed
+func NormalizeDomain(domain string) (string, error) {
+	domain = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(domain), "@")))
+	if domain == "" || !strings.Contains(domain, ".") || strings.ContainsAny(domain, "@ /") {
+		return "", ErrInvalidDomain
+	}
+	if publicDomains[domain] {
+		return "", ErrInvalidDomain
+	}
+	return domain, nil
+}
+
+// HasVerification reports whether one of the domain's TXT records carries the token
+func HasVerification(records []string, token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, r := range records {
+		if strings.TrimSpace(r) == VerificationPrefix+token {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidRole(role string) bool {
+	return role == RoleOwner || role == RoleAdmin || role == RoleMember
+}
+
+// CanManage reports whether the role may manage members and teams
+func CanManage(role string) bool {
+	return role == RoleOwner || role == RoleAdmin
+}
@@ -0,0 +1,99 @@
+package organization
+
+import (
+	"context"
+	"time"
+	"typing-speed/internals/core/user"
+)
+
+const (
+	RoleOwner  = "owner"  // one per organization, can do everything
+	RoleAdmin  = "admin"  // manages members and teams
+	RoleMember = "member" // sees the leaderboards and dashboards
+
+	MaxNameLength    = 100
+	LeaderboardLimit = 50
+
+	// VerificationPrefix starts the DNS TXT record, at the domain itself,
+	// that proves the organization owns its email domain
+	VerificationPrefix = "typing-speed-verification="
+)
+
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Domain    string    `json:"domain,omitempty"` // users with an email at this domain join once it is verified
+	Verified  bool      `json:"domainVerified"`
+	Token     string    `json:"domainToken,omitempty"` // for the owner and the admins only
+	CreatedBy string    `json:"-"`
+	Members   int       `json:"members"`
+	Role      string    `json:"role,omitempty"` // of the user asking
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Member struct {
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+type Team struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	Name           string    `json:"name"`
+	Members        int       `json:"members"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// Details is an organization as its members see it
+type Details struct {
+	Organization *Organization `json:"organization"`
+	Members      []*Member     `json:"members"`
+	Teams        []*Team       `json:"teams"`
+}
+
+type TeamDetails struct {
+	Team    *Team     `json:"team"`
+	Members []*Member `json:"members"`
+}
+
+type LeaderboardEntry struct {
+	Rank        int    `json:"rank"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	Performance int    `json:"performance"`
+	AvgSpeed    int    `json:"avgSpeed"`
+	BestSpeed   int    `json:"bestSpeed"`
+	AvgAccuracy int    `json:"avgAccuracy"`
+	TotalTest   int    `json:"totalTest"`
+	You         bool   `json:"you"`
+}
+
+// Dashboard sums up the tests of the members of an organization or team
+type Dashboard struct {
+	Members          int                    `json:"members"`
+	DashboardTopData *user.DashboardTopData `json:"dashboardTopData"`
+	TopPerformers    []*user.TopPerformer   `json:"topPerformers"`
+}
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, email string, o *Organization) (*Organization, error)
+	Organizations(ctx context.Context, email string) ([]*Organization, error)
+	Organization(ctx context.Context, email string, id string) (*Details, error)
+	UpdateOrganization(ctx context.Context, email string, id string, o *Organization) (*Organization, error)
+	DeleteOrganization(ctx context.Context, email string, id string) error
+	VerifyDomain(ctx context.Context, email string, id string) (*Organization, error)
+	Join(ctx context.Context, email string, id string) error
+	Leave(ctx context.Context, email string, id string) error
+	AddMember(ctx context.Context, email string, id string, member string, role string) error
+	UpdateMemberRole(ctx context.Context, email string, id string, member string, role string) error
+	RemoveMember(ctx context.Context, email string, id string, member string) error
+	CreateTeam(ctx context.Context, email string, id string, t *Team) (*Team, error)
+	Team(ctx context.Context, email string, id string, teamID string) (*TeamDetails, error)
+	DeleteTeam(ctx context.Context, email string, id string, teamID string) error
+	AddTeamMember(ctx context.Context, email string, id string, teamID string, member string) error
+	RemoveTeamMember(ctx context.Context, email string, id string, teamID string, member string) error
+	Leaderboard(ctx context.Context, email string, id string, teamID string) ([]*LeaderboardEntry, error)
+	Dashboard(ctx context.Context, email string, id string, teamID string) (*Dashboard, error)
+}
@@ -0,0 +1,9 @@
+package profile
+
+import "errors"
+
+var (
+	ErrProfileNotFound   error = errors.New("profile not found")
+	ErrPrivateProfile    error = errors.New("profile is private")
+	ErrGettingDataFromDB error = errors.New("error getting data from DB")
+)
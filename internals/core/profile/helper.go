@@ -0,0 +1,41 @@
+package profile
+
+import (
+	"time"
+	"typing-speed/internals/core/typing"
+	"typing-speed/internals/core/user"
+)
+
+// NewProfile puts together the profile of a user, with the streak as of now
+func NewProfile(u *user.User, bests []*typing.PersonalBest, tests []*typing.TypingData, now time.Time) *Profile {
+	recent := make([]*RecentTest, 0, len(tests))
+	for _, t := range tests {
+		recent = append(recent, &RecentTest{
+			ID:        t.ID,
+			Mode:      t.Mode,
+			Language:  t.Language,
+			WPM:       t.WPM,
+			Accuracy:  typing.Accuracy(t),
+			CreatedAt: t.CreatedAt,
+		})
+	}
+
+	return &Profile{
+		Username:       u.Username,
+		Name:           u.Name,
+		Privacy:        u.Privacy,
+		Country:        u.Country,
+		Level:          u.Level,
+		XP:             u.XP,
+		Streak:         user.CurrentStreak(u.Streak, u.LastTestTime, now, user.Location(u.TimeZone)),
+		LongestStreak:  u.LongestStreak,
+		AvgSpeed:       u.AvgSpeed,
+		BestSpeed:      u.BestSpeed,
+		AvgAccuracy:    u.AvgAccuracy,
+		AvgPerformance: u.AvgPerformance,
+		TotalTest:      u.TotalTest,
+		PersonalBests:  bests,
+		RecentTests:    recent,
+		JoinedAt:       u.CreatedAt,
+	}
+}
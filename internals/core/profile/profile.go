@@ -0,0 +1,45 @@
+package profile
+
+import (
+	"context"
+	"time"
+	"typing-speed/internals/core/typing"
+)
+
+const RecentTestsLimit = 10
+
+// RecentTest is a test shown on a profile, without the details only its
+// owner needs
+type RecentTest struct {
+	ID        string    `json:"id"`
+	Mode      string    `json:"mode"`
+	Language  string    `json:"language"`
+	WPM       int       `json:"wpm"`
+	Accuracy  int       `json:"accuracy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Profile is what other users see of a user, found by their username
+type Profile struct {
+	Username       string                 `json:"username"`
+	Name           string                 `json:"name"`
+	Privacy        string                 `json:"privacy"`
+	Country        string                 `json:"country,omitempty"`
+	Level          int                    `json:"level"`
+	XP             int                    `json:"xp"`
+	Streak         int                    `json:"streak"`
+	LongestStreak  int                    `json:"longestStreak"`
+	AvgSpeed       int                    `json:"avgSpeed"`
+	BestSpeed      int                    `json:"bestSpeed"`
+	AvgAccuracy    int                    `json:"avgAccuracy"`
+	AvgPerformance int                    `json:"avgPerformance"`
+	TotalTest      int                    `json:"totalTest"`
+	PersonalBests  []*typing.PersonalBest `json:"personalBests"`
+	RecentTests    []*RecentTest          `json:"recentTests"`
+	JoinedAt       time.Time              `json:"joinedAt"`
+	You            bool                   `json:"you"`
+}
+
+type ProfileService interface {
+	Profile(ctx context.Context, email string, username string) (*Profile, error)
+}
@@ -0,0 +1,102 @@
+package progress
+
+import (
+	"math"
+	"typing-speed/internals/core/typing"
+)
+
+const (
+	maxStreakBonusDays = 10
+	streakBonusPercent = 5
+	pbBonusPercent     = 50
+)
+
+// modeMultiplier rewards longer, harder modes a little more per second
+var modeMultiplier = map[string]float64{
+	typing.Mode15s:    1.0,
+	typing.Mode30s:    1.0,
+	typing.Mode60s:    1.1,
+	typing.Mode120s:   1.2,
+	typing.ModeCustom: 1.0,
+}
+
+// NewLevelCurve builds a curve, using the defaults for non-positive values
+func NewLevelCurve(baseXP int, exponent float64) LevelCurve {
+	if baseXP <= 0 {
+		baseXP = DefaultBaseXP
+	}
+	if exponent <= 0 {
+		exponent = DefaultExponent
+	}
+	return LevelCurve{BaseXP: baseXP, Exponent: exponent}
+}
+
+// LevelCost returns the XP needed to go from level to level+1
+func (c LevelCurve) LevelCost(level int) int {
+	c = NewLevelCurve(c.BaseXP, c.Exponent)
+	return int(math.Round(float64(c.BaseXP) * math.Pow(float64(level+1), c.Exponent)))
+}
+
+// XPForLevel returns the total XP needed to reach level
+func (c LevelCurve) XPForLevel(level int) int {
+	total := 0
+	for l := 0; l < level; l++ {
+		total += c.LevelCost(l)
+	}
+	return total
+}
+
+// LevelForXP returns the level reached with xp
+func (c LevelCurve) LevelForXP(xp int) int {
+	level := 0
+	for xp >= c.LevelCost(level) {
+		xp -= c.LevelCost(level)
+		level++
+	}
+	return level
+}
+
+// Progress returns the level and the progress towards the next one for xp
+func (c LevelCurve) Progress(xp int) *Progress {
+	level := c.LevelForXP(xp)
+	levelXP := xp - c.XPForLevel(level)
+	next := c.LevelCost(level)
+
+	return &Progress{
+		Level:       level,
+		XP:          xp,
+		LevelXP:     levelXP,
+		NextLevelXP: next,
+		Percent:     levelXP * 100 / next,
+	}
+}
+
+// CalculateXP rewards a completed test. The base scales with the test
+// duration, the mode and the accuracy; personal bests and streaks add bonuses.
+// Timed modes count their own length whatever the client sent, and custom
+// tests count at most MaxCustomDuration.
+func CalculateXP(in XPInput) XPGain {
+	multiplier, ok := modeMultiplier[in.Mode]
+	if !ok {
+		multiplier = 1.0
+	}
+
+	duration := typing.ModeDuration(in.Mode)
+	if duration == 0 {
+		duration = min(max(in.Duration, 0), typing.MaxCustomDuration)
+	}
+
+	accuracy := min(max(in.Accuracy, 0), 100)
+	base := int(math.Round(float64(duration) / 2 * multiplier * float64(accuracy) / 100))
+
+	gain := XPGain{Base: base}
+	if in.PersonalBest {
+		gain.PersonalBestBonus = base * pbBonusPercent / 100
+	}
+	if in.Streak > 1 {
+		gain.StreakBonus = base * min(in.Streak, maxStreakBonusDays) * streakBonusPercent / 100
+	}
+	gain.Total = gain.Base + gain.PersonalBestBonus + gain.StreakBonus
+
+	return gain
+}
@@ -0,0 +1,40 @@
+package progress
+
+const (
+	DefaultBaseXP   = 100
+	DefaultExponent = 1.5
+)
+
+// LevelCurve describes how much XP each level costs.
+// Going from level n to n+1 needs BaseXP * (n+1)^Exponent XP.
+// The zero value behaves like the default curve.
+type LevelCurve struct {
+	BaseXP   int
+	Exponent float64
+}
+
+// Progress is the user's position on the level curve
+type Progress struct {
+	Level       int `json:"level"`
+	XP          int `json:"xp"`
+	LevelXP     int `json:"levelXp"`     // XP earned since reaching the current level
+	NextLevelXP int `json:"nextLevelXp"` // XP needed to go from the current level to the next
+	Percent     int `json:"percent"`
+}
+
+// XPInput is everything a completed test is rewarded for
+type XPInput struct {
+	Duration     int // test length in seconds
+	Accuracy     int
+	Mode         string
+	PersonalBest bool
+	Streak       int
+}
+
+// XPGain breaks down the XP earned by one test
+type XPGain struct {
+	Base              int `json:"base"`
+	PersonalBestBonus int `json:"personalBestBonus"`
+	StreakBonus       int `json:"streakBonus"`
+	Total             int `json:"total"`
+}
@@ -0,0 +1,131 @@
+package race
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+const (
+	MinBotWPM          = 10
+	MaxBotWPM          = 250
+	DefaultBotWPM      = 60
+	MinBotAccuracy     = 50
+	DefaultBotAccuracy = 95
+
+	hesitationChance = 0.05 // of a bot pausing for a few keystrokes before a word
+)
+
+// Bot is a computer-controlled racer. The same seed always types the same race.
+type Bot struct {
+	WPM      int   `json:"wpm"`
+	Accuracy int   `json:"accuracy"`       // percent of keystrokes that are correct
+	Seed     int64 `json:"seed,omitempty"` // random when left out
+}
+
+// Normalize fills in the unset fields with the defaults and validates the rest
+func (b *Bot) Normalize() error {
+	if b.WPM == 0 {
+		b.WPM = DefaultBotWPM
+	}
+	if b.Accuracy == 0 {
+		b.Accuracy = DefaultBotAccuracy
+	}
+
+	if b.WPM < MinBotWPM || b.WPM > MaxBotWPM {
+		return ErrInvalidBot
+	}
+	if b.Accuracy < MinBotAccuracy || b.Accuracy > 100 {
+		return ErrInvalidBot
+	}
+	return nil
+}
+
+// Name is what the other racers see the bot as
+func (b Bot) Name() string {
+	return fmt.Sprintf("Bot (%d WPM)", b.WPM)
+}
+
+// Pace is a bot's race worked out ahead of time: when each character of the
+// text is typed and how many mistakes were made by then
+type Pace struct {
+	at     []time.Duration
+	errors []int
+}
+
+// NewPace plans how the bot types the text. Every keystroke takes the bot's
+// average time give or take some jitter, the bot speeds up and slows down
+// from word to word, and now and then hesitates before a word. A wrong
+// keystroke costs the time to type and correct it. The plan only depends
+// on the bot, the text and the seed.
+func NewPace(bot Bot, text string, seed int64) *Pace {
+	r := rand.New(rand.NewSource(seed))
+	miss := float64(100-bot.Accuracy) / 100
+
+	// the mistakes and the hesitations come out of the time of a
+	// keystroke, so the bot still types at its WPM on average
+	perChar := float64(time.Minute) / float64(bot.WPM*5)
+	mistakes := 2 * miss / (1 - miss)
+	hesitations := 0.0
+	if len(text) > 0 {
+		hesitations = float64(strings.Count(text, " ")) * hesitationChance * 4 / float64(len(text))
+	}
+	keystroke := perChar / (1 + mistakes + hesitations)
+
+	p := &Pace{at: make([]time.Duration, 0, len(text)), errors: make([]int, 0, len(text))}
+	var elapsed float64
+	errors := 0
+	word := wordSpeed(r)
+
+	for i := range len(text) {
+		// every word is typed at a speed of its own
+		if i > 0 && text[i-1] == ' ' {
+			word = wordSpeed(r)
+			if r.Float64() < hesitationChance {
+				elapsed += keystroke * (2 + 4*r.Float64())
+			}
+		}
+		for r.Float64() < miss {
+			errors++
+			elapsed += 2 * keystroke * word * jitter(r)
+		}
+		elapsed += keystroke * word * jitter(r)
+
+		p.at = append(p.at, time.Duration(elapsed))
+		p.errors = append(p.errors, errors)
+	}
+	return p
+}
+
+// At is how far the bot has got after elapsed time into the race
+func (p *Pace) At(elapsed time.Duration) (typed int, errors int) {
+	// the plan is sorted by time, so search for the first character not yet typed
+	lo, hi := 0, len(p.at)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if p.at[mid] <= elapsed {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo == 0 {
+		return 0, 0
+	}
+	return lo, p.errors[lo-1]
+}
+
+// wordSpeed scales the keystrokes of a word, keeping the average at one
+func wordSpeed(r *rand.Rand) float64 {
+	return clamp(1+r.NormFloat64()*0.1, 0.7, 1.3)
+}
+
+// jitter scales a single keystroke
+func jitter(r *rand.Rand) float64 {
+	return clamp(1+r.NormFloat64()*0.25, 0.4, 1.6)
+}
+
+func clamp(x, lo, hi float64) float64 {
+	return min(max(x, lo), hi)
+}
@@ -0,0 +1,22 @@
+package race
+
+import "errors"
+
+var (
+	ErrRoomNotFound          error = errors.New("race room not found")
+	ErrRoomFull              error = errors.New("race room is full")
+	ErrRaceStarted           error = errors.New("race already started")
+	ErrNotInvited            error = errors.New("not a player in this match")
+	ErrKicked                error = errors.New("removed from the race room by the host")
+	ErrNotHost               error = errors.New("only the host can do this")
+	ErrInvalidSettings       error = errors.New("invalid room settings")
+	ErrParticipantMissing    error = errors.New("no such participant in the room")
+	ErrInvalidBot            error = errors.New("invalid bot")
+	ErrInvalidSpectatorToken error = errors.New("invalid spectator token")
+	ErrTooManySpectators     error = errors.New("too many spectators")
+	ErrNotEnoughPlayers      error = errors.New("not enough players to start")
+	ErrAlreadyQueued         error = errors.New("already waiting for a quick race")
+	ErrRaceNotFound          error = errors.New("race not found")
+	ErrGettingDataFromDB     error = errors.New("error getting data from DB")
+	ErrSomethingWentWrong    error = errors.New("something went wrong")
+)
@@ -0,0 +1,211 @@
+package race
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+	mrand "math/rand"
+	"strings"
+	"time"
+	"typing-speed/internals/core/challenge"
+	"typing-speed/internals/core/typing"
+)
+
+// inviteAlphabet leaves out characters that are easy to misread
+const (
+	inviteAlphabet   = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	InviteCodeLength = 6
+)
+
+// Languages lists the languages races can be typed in
+var Languages = []string{typing.DefaultLanguage}
+
+// DefaultSettings are the settings of quick races and of new private rooms
+func DefaultSettings() Settings {
+	return Settings{
+		Mode:       typing.ModeCustom,
+		Duration:   DefaultDuration,
+		Language:   typing.DefaultLanguage,
+		MaxPlayers: MaxPlayers,
+		Rounds:     1,
+	}
+}
+
+// Normalize fills in the unset settings with the defaults and validates the rest
+func (s *Settings) Normalize() error {
+	defaults := DefaultSettings()
+	if s.Mode == "" {
+		s.Mode = defaults.Mode
+	}
+	if s.Duration == 0 {
+		s.Duration = defaults.Duration
+	}
+	if s.Language == "" {
+		s.Language = defaults.Language
+	}
+	if s.MaxPlayers == 0 {
+		s.MaxPlayers = defaults.MaxPlayers
+	}
+	if s.Rounds == 0 {
+		s.Rounds = defaults.Rounds
+	}
+	s.Language = strings.ToLower(s.Language)
+
+	if !typing.ValidMode(s.Mode) || !validLanguage(s.Language) {
+		return ErrInvalidSettings
+	}
+	if s.Duration < MinDuration || s.Duration > MaxDuration {
+		return ErrInvalidSettings
+	}
+	if s.MaxPlayers < MinPlayers || s.MaxPlayers > MaxPlayers {
+		return ErrInvalidSettings
+	}
+	// best of N needs an odd N to always have a winner
+	if s.Rounds < 1 || s.Rounds > MaxRounds || s.Rounds%2 == 0 {
+		return ErrInvalidSettings
+	}
+	return nil
+}
+
+func validLanguage(language string) bool {
+	for _, l := range Languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// Timed reports whether the race lasts a fixed time instead of until the end of the text
+func (s Settings) Timed() bool {
+	return s.Mode != typing.ModeCustom
+}
+
+// TimeLimit is how long a race with these settings lasts at most
+func (s Settings) TimeLimit() time.Duration {
+	if s.Timed() {
+		return time.Duration(typing.ModeDuration(s.Mode)) * time.Second
+	}
+	return time.Duration(s.Duration) * time.Second
+}
+
+// GenerateText builds the passage of a race. Timed races get enough words
+// that nobody runs out before the time does.
+func GenerateText(seed int64, settings Settings) string {
+	words := RaceWords
+	if settings.Timed() {
+		// four words a second is well beyond the fastest typists
+		words = max(words, typing.ModeDuration(settings.Mode)*4)
+	}
+
+	text := challenge.TextFromSeed(seed, words)
+	if settings.Punctuation {
+		text = punctuate(text, seed)
+	}
+	return text
+}
+
+// punctuate turns the words into sentences with commas, the same for the same seed
+func punctuate(text string, seed int64) string {
+	r := mrand.New(mrand.NewSource(seed))
+	words := strings.Fields(text)
+
+	sentenceStart := true
+	for i, w := range words {
+		if sentenceStart {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+			sentenceStart = false
+		}
+		if i == len(words)-1 {
+			words[i] += "."
+			break
+		}
+		switch n := r.Intn(10); {
+		case n == 0:
+			words[i] += "."
+			sentenceStart = true
+		case n == 1:
+			words[i] += ","
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// NewInviteCode returns a short random code to join a private room
+func NewInviteCode() (string, error) {
+	code := make([]byte, InviteCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(inviteAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = inviteAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// NewSpectatorToken returns a random token that lets anyone holding it watch a room
+func NewSpectatorToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Points is what a finishing place is worth in a series: one per racer beaten, plus one
+func Points(position int, racers int) int {
+	if position == 0 {
+		return 0
+	}
+	return racers - position + 1
+}
+
+// MaxTyped is how many characters can be typed in elapsed time at wpm
+func MaxTyped(wpm int, elapsed time.Duration) int {
+	if elapsed <= 0 {
+		return 0
+	}
+	return int(float64(wpm) * 5 * elapsed.Minutes())
+}
+
+// WPM is the speed of typing chars characters in elapsed time, a word being five characters
+func WPM(chars int, elapsed time.Duration) int {
+	if elapsed <= 0 {
+		return 0
+	}
+	return int(float64(chars) / 5 / elapsed.Minutes())
+}
+
+// Accuracy is the share of keystrokes that were correct
+func Accuracy(typed int, errors int) int {
+	if typed+errors == 0 {
+		return 0
+	}
+	return typed * 100 / (typed + errors)
+}
+
+// Score is the outcome of a against b for rating: 1 for a win, 0.5 for a draw and 0 for a loss.
+// Finishers beat everyone who did not finish, who are ranked by how far they got.
+func Score(a, b *Result) float64 {
+	switch {
+	case a.Position > 0 && b.Position > 0:
+		return compare(b.Position, a.Position)
+	case a.Position > 0:
+		return 1
+	case b.Position > 0:
+		return 0
+	}
+	return compare(a.Typed, b.Typed)
+}
+
+// compare scores x against y, the higher value winning
+func compare(x, y int) float64 {
+	switch {
+	case x > y:
+		return 1
+	case x < y:
+		return 0
+	}
+	return 0.5
+}
@@ -0,0 +1,178 @@
+package race
+
+import (
+	"context"
+	"time"
+	"typing-speed/internals/core/rating"
+)
+
+const (
+	StatusLobby     = "lobby"
+	StatusCountdown = "countdown"
+	StatusRacing    = "racing"
+	StatusFinished  = "finished"
+
+	MinPlayers       = 2
+	MaxPlayers       = 8
+	QuickRacePlayers = 4
+	MaxSpectators    = 100
+	RaceWords        = 30
+	MaxRounds        = 9
+
+	DefaultDuration = 180 // seconds, the time limit of races to the end of the text
+	MinDuration     = 10
+	MaxDuration     = 600
+
+	// MaxWPM is faster than anyone sustains. Progress beyond it is capped
+	// and the racer is kept out of tests and ratings.
+	MaxWPM = 250
+)
+
+// messages a participant sends to the room
+const (
+	MsgStart        = "start"
+	MsgProgress     = "progress"
+	MsgLeave        = "leave"
+	MsgSettings     = "settings"     // host only, in the lobby
+	MsgKick         = "kick"         // host only
+	MsgTransferHost = "transferHost" // host only
+	MsgAddBot       = "addBot"       // host only, in the lobby. Bots are removed by kicking them.
+)
+
+// events the room sends to its participants
+const (
+	EventQueued    = "queued"    // waiting in the quick race queue
+	EventState     = "state"     // someone joined or left the lobby
+	EventCountdown = "countdown" // the host started the countdown
+	EventStart     = "start"
+	EventProgress  = "progress"
+	EventFinish    = "finish"  // a participant crossed the line
+	EventResults   = "results" // a round is over
+	EventSeriesEnd = "seriesEnd"
+	EventKicked    = "kicked"
+	EventError     = "error"
+)
+
+// Settings are chosen by the host of a private room
+type Settings struct {
+	Mode        string `json:"mode"`     // a timed mode, or custom to race to the end of the text
+	Duration    int    `json:"duration"` // seconds, the time limit of custom races
+	Language    string `json:"language"`
+	Punctuation bool   `json:"punctuation"`
+	MaxPlayers  int    `json:"maxPlayers"`
+	Rounds      int    `json:"rounds"` // best of N
+}
+
+// Participant is a racer as the other racers see them
+type Participant struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	Host       bool    `json:"host"`
+	Bot        bool    `json:"bot,omitempty"`
+	Typed      int     `json:"typed"`  // characters of the text typed correctly
+	Errors     int     `json:"errors"` // mistyped characters
+	WPM        int     `json:"wpm"`
+	Accuracy   int     `json:"accuracy"`
+	Position   int     `json:"position,omitempty"`   // finishing place, 0 until finished
+	FinishTime float64 `json:"finishTime,omitempty"` // seconds from the start
+	Points     int     `json:"points"`               // over all the rounds of the series
+	Connected  bool    `json:"connected"`
+	Left       bool    `json:"left,omitempty"`
+}
+
+// Room is a snapshot of a race room
+type Room struct {
+	ID             string         `json:"id"`
+	InviteCode     string         `json:"inviteCode,omitempty"`     // private rooms only
+	SpectatorToken string         `json:"spectatorToken,omitempty"` // private rooms only, never sent to spectators
+	RaceID         string         `json:"raceId,omitempty"`         // the current round
+	Status         string         `json:"status"`
+	Rated          bool           `json:"rated"`
+	Settings       Settings       `json:"settings"`
+	Round          int            `json:"round"`
+	Spectators     int            `json:"spectators"`
+	Text           string         `json:"text,omitempty"`     // revealed when the countdown starts
+	StartsAt       *time.Time     `json:"startsAt,omitempty"` // when the countdown ends
+	StartedAt      *time.Time     `json:"startedAt,omitempty"`
+	Participants   []*Participant `json:"participants"`
+}
+
+// ClientMessage is what a participant sends over the socket
+type ClientMessage struct {
+	Type     string    `json:"type"`
+	Typed    int       `json:"typed"`
+	Errors   int       `json:"errors"`
+	Target   int       `json:"target"` // the participant id to kick or make host
+	Settings *Settings `json:"settings"`
+	Bot      *Bot      `json:"bot"`
+}
+
+// Event is what the room sends over the socket
+type Event struct {
+	Type  string `json:"type"`
+	You   int    `json:"you,omitempty"` // the participant id of the receiver
+	Room  *Room  `json:"room,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Result is a participant's recorded outcome of a race
+type Result struct {
+	Email    string  `json:"-"`
+	Name     string  `json:"name"`
+	Bot      bool    `json:"-"`        // bots are left out of what is recorded
+	Position int     `json:"position"` // 0 when the participant did not finish
+	WPM      int     `json:"wpm"`
+	Accuracy int     `json:"accuracy"`
+	Duration float64 `json:"duration"`
+	Typed    int     `json:"-"`
+	Errors   int     `json:"-"`
+	Flagged  bool    `json:"-"` // claimed progress faster than MaxWPM
+	TestID   string  `json:"testId,omitempty"`
+}
+
+// Race is a recorded race
+type Race struct {
+	ID         string    `json:"id"`
+	Text       string    `json:"text"`
+	Mode       string    `json:"mode"`
+	Language   string    `json:"language"`
+	Rated      bool      `json:"rated"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Results    []*Result `json:"results"`
+}
+
+// Session is a participant's connection to a room. Events is closed once
+// the participant is out of the room or has connected again elsewhere.
+// A disconnected participant keeps their seat for a while to reconnect.
+type Session interface {
+	Events() <-chan *Event
+	Send(msg ClientMessage)
+	Leave()
+	Disconnect()
+}
+
+// MatchResult is how a match room ended. Placings lists the players best
+// first by series points; those who never showed up are left out.
+type MatchResult struct {
+	RoomID   string
+	RaceID   string // the last race of the series, empty when nobody raced
+	Placings []string
+}
+
+// Spectator is a read-only view of a room. Events is closed once the room closes.
+type Spectator interface {
+	Events() <-chan *Event
+	Close()
+}
+
+type RaceService interface {
+	CreateRoom(ctx context.Context, email string, settings *Settings) (*Room, error)
+	JoinRoom(ctx context.Context, room string, email string) (Session, error) // by id or invite code
+	Spectate(ctx context.Context, roomID string, token string) (Spectator, error)
+	CreateMatchRoom(ctx context.Context, players []string, settings *Settings, done func(*MatchResult)) (*Room, error)
+	Race(ctx context.Context, raceID string, email string) (*Race, error)
+	QuickRace(ctx context.Context, email string) (Session, error)
+	Rating(ctx context.Context, email string) (*rating.Rating, error)
+	RatingHistory(ctx context.Context, email string) ([]*rating.Change, error)
+}
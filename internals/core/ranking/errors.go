@@ -0,0 +1,9 @@
+package ranking
+
+import "errors"
+
+var (
+	ErrNotRanked         error = errors.New("user is not ranked")
+	ErrInvalidLimit      error = errors.New("invalid limit")
+	ErrGettingDataFromDB error = errors.New("error getting data from DB")
+)
@@ -0,0 +1,51 @@
+package ranking
+
+import (
+	"context"
+	"time"
+)
+
+// TopSize is how many users the top performers list holds
+const TopSize = 10
+
+// How many users on each side of the user an around-me query returns
+const (
+	AroundSize    = 5
+	MaxAroundSize = 25
+)
+
+// ReconcileInterval is how often the ranking is loaded again from the
+// database, which corrects any update it missed
+const ReconcileInterval = 5 * time.Minute
+
+// Entry is a user with a public profile, ranked by their average performance
+type Entry struct {
+	Rank        int    `json:"rank"`
+	Email       string `json:"-"`
+	Name        string `json:"name"`
+	Username    string `json:"username,omitempty"`
+	Performance int    `json:"performance"`
+	You         bool   `json:"you,omitempty"`
+}
+
+// Position is where the user stands among everyone ranked
+type Position struct {
+	Rank        int `json:"rank"`
+	Users       int `json:"users"`
+	Performance int `json:"performance"`
+}
+
+// Around is the users ranked right above and below the user
+type Around struct {
+	Rank    int      `json:"rank"`
+	Users   int      `json:"users"`
+	Entries []*Entry `json:"entries"`
+}
+
+type RankingService interface {
+	Top(ctx context.Context, n int) ([]*Entry, error)
+	Rank(ctx context.Context, email string) (*Position, error)
+	Around(ctx context.Context, email string, limit string) (*Around, error)
+	Update(ctx context.Context, email string) error
+	Reconcile(ctx context.Context) error
+}
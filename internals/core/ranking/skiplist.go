@@ -0,0 +1,185 @@
+package ranking
+
+import "math/rand"
+
+const (
+	maxLevel    = 32
+	probability = 0.25
+)
+
+type node struct {
+	entry Entry
+	next  []*node
+	// span is how many entries the link at each level skips over, so the
+	// rank of a node is the sum of the spans on the way to it
+	span []int
+}
+
+// SkipList keeps entries best first, with ties broken by email. Inserting,
+// removing, finding the rank of an entry and finding the entry at a rank take
+// O(log n) on average. It is not safe for concurrent use.
+type SkipList struct {
+	head   *node
+	level  int
+	length int
+	nodes  map[string]*node
+}
+
+func NewSkipList() *SkipList {
+	return &SkipList{
+		head:  &node{next: make([]*node, maxLevel), span: make([]int, maxLevel)},
+		level: 1,
+		nodes: map[string]*node{},
+	}
+}
+
+func (s *SkipList) Len() int {
+	return s.length
+}
+
+// ahead tells whether the entry ranks above the one with the performance and email
+func ahead(e *Entry, performance int, email string) bool {
+	if e.Performance != performance {
+		return e.Performance > performance
+	}
+	return e.Email < email
+}
+
+func randomLevel() int {
+	level := 1
+	for level < maxLevel && rand.Float64() < probability {
+		level++
+	}
+	return level
+}
+
+// Set ranks the entry, moving it if the user is already ranked
+func (s *SkipList) Set(e Entry) {
+	if n, ok := s.nodes[e.Email]; ok {
+		if n.entry.Performance == e.Performance {
+			n.entry = e
+			return
+		}
+		s.Remove(e.Email)
+	}
+
+	update := make([]*node, maxLevel)
+	rank := make([]int, maxLevel)
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.next[i] != nil && ahead(&x.next[i].entry, e.Performance, e.Email) {
+			rank[i] += x.span[i]
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+
+	level := randomLevel()
+	if level > s.level {
+		for i := s.level; i < level; i++ {
+			update[i] = s.head
+			update[i].span[i] = s.length
+		}
+		s.level = level
+	}
+
+	n := &node{entry: e, next: make([]*node, level), span: make([]int, level)}
+	for i := 0; i < level; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+		n.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	for i := level; i < s.level; i++ {
+		update[i].span[i]++
+	}
+
+	s.length++
+	s.nodes[e.Email] = n
+}
+
+// Remove drops the user from the ranking, and tells whether they were in it
+func (s *SkipList) Remove(email string) bool {
+	target, ok := s.nodes[email]
+	if !ok {
+		return false
+	}
+
+	update := make([]*node, maxLevel)
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i] != target &&
+			ahead(&x.next[i].entry, target.entry.Performance, target.entry.Email) {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+
+	for i := 0; i < s.level; i++ {
+		if update[i].next[i] == target {
+			update[i].span[i] += target.span[i] - 1
+			update[i].next[i] = target.next[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+
+	s.length--
+	delete(s.nodes, email)
+	return true
+}
+
+// Rank returns the rank of the user, counted from 1, and their entry
+func (s *SkipList) Rank(email string) (int, Entry, bool) {
+	target, ok := s.nodes[email]
+	if !ok {
+		return 0, Entry{}, false
+	}
+
+	rank := 0
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && (x.next[i] == target ||
+			ahead(&x.next[i].entry, target.entry.Performance, target.entry.Email)) {
+			rank += x.span[i]
+			x = x.next[i]
+		}
+		if x == target {
+			break
+		}
+	}
+	return rank, target.entry, true
+}
+
+// Range returns up to n entries from the rank on, counted from 1
+func (s *SkipList) Range(rank int, n int) []*Entry {
+	entries := []*Entry{}
+	if rank < 1 || rank > s.length || n < 1 {
+		return entries
+	}
+
+	traversed := 0
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && traversed+x.span[i] <= rank {
+			traversed += x.span[i]
+			x = x.next[i]
+		}
+		if traversed == rank {
+			break
+		}
+	}
+
+	for ; x != nil && len(entries) < n; x = x.next[0] {
+		e := x.entry
+		e.Rank = rank + len(entries)
+		entries = append(entries, &e)
+	}
+	return entries
+}
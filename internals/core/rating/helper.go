@@ -0,0 +1,99 @@
+package rating
+
+import "math"
+
+// glicko2Scale converts between the Glicko and Glicko-2 scales
+const (
+	glicko2Scale = 173.7178
+	convergence  = 0.000001
+)
+
+// New returns the rating of a player who has never raced
+func New(email string) *Rating {
+	return &Rating{
+		Email:      email,
+		Rating:     DefaultRating,
+		Deviation:  DefaultDeviation,
+		Volatility: DefaultVolatility,
+	}
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expected(mu, muJ, phiJ float64) float64 {
+	return 1 / (1 + math.Exp(-g(phiJ)*(mu-muJ)))
+}
+
+// Update applies one rating period of outcomes to r, following Glickman's
+// description of Glicko-2. A period without games only widens the deviation.
+func Update(r *Rating, outcomes []Outcome) *Rating {
+	mu := (r.Rating - DefaultRating) / glicko2Scale
+	phi := r.Deviation / glicko2Scale
+	sigma := r.Volatility
+
+	updated := *r
+	if len(outcomes) == 0 {
+		updated.Deviation = math.Sqrt(phi*phi+sigma*sigma) * glicko2Scale
+		return &updated
+	}
+
+	var vInv, deltaSum float64
+	for _, o := range outcomes {
+		muJ := (o.Opponent.Rating - DefaultRating) / glicko2Scale
+		phiJ := o.Opponent.Deviation / glicko2Scale
+		e := expected(mu, muJ, phiJ)
+		vInv += g(phiJ) * g(phiJ) * e * (1 - e)
+		deltaSum += g(phiJ) * (o.Score - e)
+	}
+	v := 1 / vInv
+	delta := v * deltaSum
+
+	sigma = volatility(phi, sigma, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * deltaSum
+
+	updated.Rating = mu*glicko2Scale + DefaultRating
+	updated.Deviation = phi * glicko2Scale
+	updated.Volatility = sigma
+	return &updated
+}
+
+// volatility finds the new volatility with the Illinois algorithm
+func volatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(Tau*Tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*Tau) < 0 {
+			k++
+		}
+		B = a - k*Tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
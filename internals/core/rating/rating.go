@@ -0,0 +1,40 @@
+package rating
+
+import "time"
+
+// Glicko-2 defaults for a player who has never raced
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+
+	// Tau constrains how fast volatility changes, 0.3 to 1.2 being sensible
+	Tau = 0.5
+
+	HistoryLimit = 50
+)
+
+// Rating is a player's Glicko-2 skill estimate
+type Rating struct {
+	Email      string    `json:"-"`
+	Rating     float64   `json:"rating"`
+	Deviation  float64   `json:"deviation"`
+	Volatility float64   `json:"volatility"`
+	Races      int       `json:"races"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// Change is one entry of a player's rating history
+type Change struct {
+	RaceID    string    `json:"raceId"`
+	Rating    float64   `json:"rating"`
+	Deviation float64   `json:"deviation"`
+	Change    float64   `json:"change"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Outcome is a game against one opponent. Score is 1 for a win, 0.5 for a draw and 0 for a loss.
+type Outcome struct {
+	Opponent *Rating
+	Score    float64
+}
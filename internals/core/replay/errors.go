@@ -0,0 +1,10 @@
+package replay
+
+import "errors"
+
+var (
+	ErrReplayNotFound     error = errors.New("replay not found")
+	ErrInvalidMode        error = errors.New("invalid test mode")
+	ErrGettingDataFromDB  error = errors.New("error getting data from DB")
+	ErrSomethingWentWrong error = errors.New("something went wrong")
+)
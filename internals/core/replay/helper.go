@@ -0,0 +1,39 @@
+package replay
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"regexp"
+)
+
+var testIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ValidTestID reports whether id can be a test id, so malformed ids never reach the database
+func ValidTestID(id string) bool {
+	return testIDPattern.MatchString(id)
+}
+
+// ValidTimeline reports whether timeline is a plausible replay of a test lasting seconds.
+// Counts are cumulative, so they can never go down.
+func ValidTimeline(timeline []int, seconds int) bool {
+	if len(timeline) > MaxTimelineLength || len(timeline) > seconds+1 {
+		return false
+	}
+	prev := 0
+	for _, chars := range timeline {
+		if chars < prev {
+			return false
+		}
+		prev = chars
+	}
+	return true
+}
+
+// NewShareToken returns an unguessable token for a public replay link
+func NewShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
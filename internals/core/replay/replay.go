@@ -0,0 +1,36 @@
+package replay
+
+import (
+	"context"
+	"time"
+)
+
+// MaxTimelineLength caps a timeline at one hour of per-second samples
+const MaxTimelineLength = 3600
+
+// Replay is the pacing of a test, used to race a ghost of it.
+// Timeline[i] is the number of characters typed after i seconds.
+type Replay struct {
+	TestID    string    `json:"testId"`
+	Name      string    `json:"name,omitempty"` // only set on shared replays
+	Mode      string    `json:"mode"`
+	Language  string    `json:"language"`
+	WPM       int       `json:"wpm"`
+	Accuracy  int       `json:"accuracy"`
+	TotalTime int       `json:"totalTime"`
+	Timeline  []int     `json:"timeline"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Share is the public token of a shared replay
+type Share struct {
+	TestID string `json:"testId"`
+	Token  string `json:"token"`
+}
+
+type ReplayService interface {
+	Replay(ctx context.Context, email string, testID string) (*Replay, error)
+	PersonalBestReplay(ctx context.Context, email string, mode string, language string) (*Replay, error)
+	ShareReplay(ctx context.Context, email string, testID string) (*Share, error)
+	SharedReplay(ctx context.Context, token string) (*Replay, error)
+}
@@ -0,0 +1,12 @@
+package stats
+
+import "errors"
+
+var (
+	ErrInvalidBucket     error = errors.New("invalid bucket")
+	ErrInvalidMode       error = errors.New("invalid mode")
+	ErrInvalidDate       error = errors.New("invalid date")
+	ErrInvalidRange      error = errors.New("invalid date range")
+	ErrUserNotFound      error = errors.New("user not found")
+	ErrGettingDataFromDB error = errors.New("error getting data from DB")
+)
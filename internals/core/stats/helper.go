@@ -0,0 +1,76 @@
+package stats
+
+import (
+	"time"
+	"typing-speed/internals/core/typing"
+)
+
+func ValidBucket(bucket string) bool {
+	return bucket == BucketDay || bucket == BucketWeek || bucket == BucketMonth
+}
+
+// BucketStart is the start of the bucket holding t, in t's location
+func BucketStart(bucket string, t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	switch bucket {
+	case BucketWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// AddBuckets moves the start of a bucket n buckets on, or back when n is
+// negative
+func AddBuckets(bucket string, t time.Time, n int) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return t.AddDate(0, 0, 7*n)
+	case BucketMonth:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+// NewQuery checks the filter and fills in its defaults. Dates are read, and
+// today is taken, in loc.
+func NewQuery(email string, f *Filter, loc *time.Location, now time.Time) (*Query, error) {
+	q := &Query{Email: email, Bucket: f.Bucket, Mode: f.Mode, Location: loc}
+	if q.Bucket == "" {
+		q.Bucket = BucketDay
+	}
+	if !ValidBucket(q.Bucket) {
+		return nil, ErrInvalidBucket
+	}
+	if q.Mode != "" && !typing.ValidMode(q.Mode) {
+		return nil, ErrInvalidMode
+	}
+
+	to := now.In(loc)
+	if f.To != "" {
+		t, err := time.ParseInLocation(DateLayout, f.To, loc)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		to = t
+	}
+	q.To = BucketStart(q.Bucket, to)
+
+	q.From = AddBuckets(q.Bucket, q.To, 1-DefaultBuckets)
+	if f.From != "" {
+		t, err := time.ParseInLocation(DateLayout, f.From, loc)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		q.From = BucketStart(q.Bucket, t)
+	}
+
+	if q.From.After(q.To) || !AddBuckets(q.Bucket, q.From, MaxBuckets).After(q.To) {
+		return nil, ErrInvalidRange
+	}
+	return q, nil
+}
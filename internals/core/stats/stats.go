@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"context"
+	"time"
+)
+
+// Buckets the tests are grouped into, as calendar periods in the user's time
+// zone
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week" // starts on Monday
+	BucketMonth = "month"
+)
+
+// DateLayout is how the dates of a range are written
+const DateLayout = "2006-01-02"
+
+const (
+	// DefaultBuckets is how many buckets a range without a start covers
+	DefaultBuckets = 30
+	MaxBuckets     = 366
+
+	// MovingAverageBuckets is how many buckets the moving average spans,
+	// ending at the bucket it is shown on
+	MovingAverageBuckets = 7
+)
+
+// Filter is a stats request as it comes in. Empty fields take the defaults:
+// daily buckets, every mode, the last DefaultBuckets buckets up to today.
+type Filter struct {
+	From   string
+	To     string
+	Bucket string
+	Mode   string
+}
+
+// Query is a checked Filter. From and To are the starts of the first and the
+// last bucket, at midnight in Location.
+type Query struct {
+	Email    string
+	Bucket   string
+	Mode     string
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+}
+
+// Point is the tests of one bucket. Buckets without tests are kept, with
+// zeros, so the series has no gaps.
+type Point struct {
+	Start           string  `json:"start"`
+	Tests           int     `json:"tests"`
+	AverageWPM      float64 `json:"averageWpm"`
+	BestWPM         int     `json:"bestWpm"`
+	MedianWPM       float64 `json:"medianWpm"`
+	AverageAccuracy float64 `json:"averageAccuracy"`
+	// MovingAverageWPM averages the average speed of this bucket and the
+	// ones before it that have tests
+	MovingAverageWPM float64 `json:"movingAverageWpm"`
+}
+
+type Series struct {
+	Bucket   string   `json:"bucket"`
+	Mode     string   `json:"mode,omitempty"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	TimeZone string   `json:"timeZone"`
+	Points   []*Point `json:"points"`
+}
+
+type StatsService interface {
+	Progress(ctx context.Context, email string, filter *Filter) (*Series, error)
+}
@@ -0,0 +1,16 @@
+package tournament
+
+import "errors"
+
+var (
+	ErrInvalidTournament  error = errors.New("invalid tournament")
+	ErrTournamentNotFound error = errors.New("tournament not found")
+	ErrNotOrganizer       error = errors.New("only the organizer can do this")
+	ErrRegistrationClosed error = errors.New("registration is closed")
+	ErrRegistrationOpen   error = errors.New("registration is still open")
+	ErrTournamentFull     error = errors.New("tournament is full")
+	ErrNotRegistered      error = errors.New("not registered for the tournament")
+	ErrNotEnoughEntrants  error = errors.New("not enough entrants to start")
+	ErrGettingDataFromDB  error = errors.New("error getting data from DB")
+	ErrSomethingWentWrong error = errors.New("something went wrong")
+)
@@ -0,0 +1,291 @@
+package tournament
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"typing-speed/internals/core/race"
+	"typing-speed/internals/core/typing"
+)
+
+const MaxNameLength = 100
+
+// Validate fills in the defaults of a new tournament and checks the rest
+func (t *Tournament) Validate(now time.Time) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Format == "" {
+		t.Format = FormatSingleElimination
+	}
+	if t.Seeding == "" {
+		t.Seeding = SeedingRating
+	}
+	if t.Mode == "" {
+		t.Mode = typing.ModeCustom
+	}
+	if t.BestOf == 0 {
+		t.BestOf = 1
+	}
+	if t.MaxEntrants == 0 {
+		t.MaxEntrants = MaxEntrants
+	}
+	if t.RegistrationOpensAt.IsZero() {
+		t.RegistrationOpensAt = now
+	}
+
+	if t.Name == "" || len(t.Name) > MaxNameLength {
+		return ErrInvalidTournament
+	}
+	if t.Format != FormatSingleElimination && t.Format != FormatRoundRobin {
+		return ErrInvalidTournament
+	}
+	if t.Seeding != SeedingRating && t.Seeding != SeedingPersonalBest {
+		return ErrInvalidTournament
+	}
+	if !typing.ValidMode(t.Mode) {
+		return ErrInvalidTournament
+	}
+	// an odd number of races always has a winner
+	if t.BestOf < 1 || t.BestOf > race.MaxRounds || t.BestOf%2 == 0 {
+		return ErrInvalidTournament
+	}
+	if t.MaxEntrants < MinEntrants || t.MaxEntrants > MaxEntrants {
+		return ErrInvalidTournament
+	}
+	if !t.RegistrationClosesAt.After(t.RegistrationOpensAt) || !t.RegistrationClosesAt.After(now) {
+		return ErrInvalidTournament
+	}
+	return nil
+}
+
+// RegistrationOpen reports whether users can register or withdraw at now
+func (t *Tournament) RegistrationOpen(now time.Time) bool {
+	return t.Status == StatusRegistration && !now.Before(t.RegistrationOpensAt) && now.Before(t.RegistrationClosesAt)
+}
+
+// SeedOrder lists the seeds of a bracket of size players in slot order, so
+// that the top seeds can only meet in the last rounds: 1 8 4 5 2 7 3 6 for eight.
+// size is a power of two.
+func SeedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// SingleElimination draws the whole bracket for entrants ordered by seed.
+// When the field is not a power of two the top seeds get a bye, and their
+// first round matches are already decided.
+func SingleElimination(entrants []*Entrant) []*Match {
+	size := 1
+	for size < len(entrants) {
+		size *= 2
+	}
+	order := SeedOrder(size)
+
+	player := func(seed int) string {
+		if seed > len(entrants) {
+			return ""
+		}
+		return entrants[seed-1].Email
+	}
+
+	matches := []*Match{}
+	for round, slots := 1, size/2; slots >= 1; round, slots = round+1, slots/2 {
+		for slot := 0; slot < slots; slot++ {
+			m := &Match{Round: round, Slot: slot, Status: MatchPending}
+			if round == 1 {
+				m.PlayerA = player(order[slot*2])
+				m.PlayerB = player(order[slot*2+1])
+			}
+			matches = append(matches, m)
+		}
+	}
+
+	for _, m := range matches {
+		if m.Round == 1 && (m.PlayerA == "") != (m.PlayerB == "") {
+			m.Winner = m.PlayerA + m.PlayerB
+			m.Status = MatchDone
+			Advance(matches, m)
+		}
+	}
+	return matches
+}
+
+// RoundRobin pairs every entrant with every other once, by the circle
+// method. With an odd field one entrant sits out each round.
+func RoundRobin(entrants []*Entrant) []*Match {
+	players := make([]string, 0, len(entrants)+1)
+	for _, e := range entrants {
+		players = append(players, e.Email)
+	}
+	if len(players)%2 == 1 {
+		players = append(players, "")
+	}
+
+	n := len(players)
+	matches := []*Match{}
+	for round := 1; round < n; round++ {
+		slot := 0
+		for i := 0; i < n/2; i++ {
+			a, b := players[i], players[n-1-i]
+			if a == "" || b == "" {
+				continue
+			}
+			matches = append(matches, &Match{Round: round, Slot: slot, PlayerA: a, PlayerB: b, Status: MatchPending})
+			slot++
+		}
+		// the first player stays put and the rest rotate
+		players = append([]string{players[0], players[n-1]}, players[1:n-1]...)
+	}
+	return matches
+}
+
+// Advance moves the winner of an elimination match into the next round and
+// returns that match, or nil after the final
+func Advance(matches []*Match, m *Match) *Match {
+	for _, next := range matches {
+		if next.Round == m.Round+1 && next.Slot == m.Slot/2 {
+			if m.Slot%2 == 0 {
+				next.PlayerA = m.Winner
+			} else {
+				next.PlayerB = m.Winner
+			}
+			return next
+		}
+	}
+	return nil
+}
+
+// Decide picks the winner of a match from the placings of its race room.
+// When neither player raced an elimination match goes to the better seed,
+// and a round robin match has no winner.
+func Decide(format string, m *Match, placings []string, seeds map[string]int) string {
+	for _, email := range placings {
+		if email == m.PlayerA || email == m.PlayerB {
+			return email
+		}
+	}
+	if format == FormatRoundRobin {
+		return ""
+	}
+	if seeds[m.PlayerB] < seeds[m.PlayerA] {
+		return m.PlayerB
+	}
+	return m.PlayerA
+}
+
+// Ready returns the matches that can be raced now: both players are known
+// and, in a round robin, the earlier rounds are over
+func Ready(format string, matches []*Match) []*Match {
+	current := 0
+	for _, m := range matches {
+		if m.Status != MatchDone && (current == 0 || m.Round < current) {
+			current = m.Round
+		}
+	}
+
+	ready := []*Match{}
+	for _, m := range matches {
+		if m.Status != MatchPending || m.PlayerA == "" || m.PlayerB == "" {
+			continue
+		}
+		if format == FormatRoundRobin && m.Round != current {
+			continue
+		}
+		ready = append(ready, m)
+	}
+	return ready
+}
+
+// Finished reports whether every match has been decided
+func Finished(matches []*Match) bool {
+	for _, m := range matches {
+		if m.Status != MatchDone {
+			return false
+		}
+	}
+	return true
+}
+
+// Link fills in the entrants of the matches for the bracket
+func Link(matches []*Match, entrants []*Entrant) {
+	byEmail := make(map[string]*Entrant, len(entrants))
+	for _, e := range entrants {
+		byEmail[e.Email] = e
+	}
+	for _, m := range matches {
+		m.A = byEmail[m.PlayerA]
+		m.B = byEmail[m.PlayerB]
+		if w := byEmail[m.Winner]; w != nil {
+			m.WinnerName = w.Name
+		}
+	}
+}
+
+// Rank orders the entrants. A round robin ranks by wins. An elimination
+// bracket ranks by the round a player went out in, the champion first.
+// Players level on that share a place, listed by seed.
+func Rank(format string, entrants []*Entrant, matches []*Match) []*Standing {
+	wins := map[string]int{}
+	losses := map[string]int{}
+	reached := map[string]int{} // the last round played, one more for the champion
+
+	for _, m := range matches {
+		if m.Status != MatchDone {
+			continue
+		}
+		reached[m.PlayerA] = max(reached[m.PlayerA], m.Round)
+		reached[m.PlayerB] = max(reached[m.PlayerB], m.Round)
+		// a bye is neither won nor lost
+		if m.PlayerA == "" || m.PlayerB == "" || m.Winner == "" {
+			continue
+		}
+		wins[m.Winner]++
+		if m.Winner == m.PlayerA {
+			losses[m.PlayerB]++
+		} else {
+			losses[m.PlayerA]++
+		}
+	}
+
+	score := func(email string) int {
+		if format == FormatRoundRobin {
+			return wins[email]
+		}
+		// a player still in the bracket is ahead of everyone knocked out
+		if losses[email] == 0 {
+			return reached[email] + 1
+		}
+		return reached[email]
+	}
+
+	sorted := append([]*Entrant{}, entrants...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := score(sorted[i].Email), score(sorted[j].Email)
+		if a != b {
+			return a > b
+		}
+		return sorted[i].Seed < sorted[j].Seed
+	})
+
+	standings := make([]*Standing, 0, len(sorted))
+	for i, e := range sorted {
+		place := i + 1
+		if i > 0 && score(e.Email) == score(sorted[i-1].Email) {
+			place = standings[i-1].Place
+		}
+		standings = append(standings, &Standing{
+			Place:  place,
+			Name:   e.Name,
+			Seed:   e.Seed,
+			Wins:   wins[e.Email],
+			Losses: losses[e.Email],
+		})
+	}
+	return standings
+}
@@ -0,0 +1,101 @@
+package tournament
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	FormatSingleElimination = "single_elimination"
+	FormatRoundRobin        = "round_robin"
+
+	SeedingRating       = "rating"        // by quick race rating
+	SeedingPersonalBest = "personal_best" // by personal best WPM in the tournament mode
+
+	StatusRegistration = "registration"
+	StatusRunning      = "running"
+	StatusFinished     = "finished"
+
+	MinEntrants = 2
+	MaxEntrants = 64
+)
+
+// match statuses
+const (
+	MatchPending = "pending" // waiting for its players or its round
+	MatchLive    = "live"    // its race room is open
+	MatchDone    = "done"
+)
+
+type Tournament struct {
+	ID                   string     `json:"id"`
+	Name                 string     `json:"name"`
+	Format               string     `json:"format"`
+	Seeding              string     `json:"seeding"`
+	Mode                 string     `json:"mode"`   // of the races, and of the personal bests seeded by
+	BestOf               int        `json:"bestOf"` // races per match
+	MaxEntrants          int        `json:"maxEntrants"`
+	RegistrationOpensAt  time.Time  `json:"registrationOpensAt"`
+	RegistrationClosesAt time.Time  `json:"registrationClosesAt"`
+	Status               string     `json:"status"`
+	CreatedBy            string     `json:"-"`
+	Entrants             int        `json:"entrants"`
+	CreatedAt            time.Time  `json:"createdAt"`
+	StartedAt            *time.Time `json:"startedAt,omitempty"`
+	FinishedAt           *time.Time `json:"finishedAt,omitempty"`
+}
+
+type Entrant struct {
+	Email     string  `json:"-"`
+	Name      string  `json:"name"`
+	Seed      int     `json:"seed"`      // 0 until the tournament starts
+	SeedScore float64 `json:"seedScore"` // the rating or WPM the seed comes from
+}
+
+type Match struct {
+	ID         string     `json:"id"`
+	Round      int        `json:"round"` // from 1
+	Slot       int        `json:"slot"`  // position within the round, from 0
+	PlayerA    string     `json:"-"`     // empty until decided, or for a bye
+	PlayerB    string     `json:"-"`
+	Winner     string     `json:"-"` // empty for a round robin match nobody showed up to
+	Status     string     `json:"status"`
+	RoomID     string     `json:"roomId,omitempty"` // the race room the players join
+	RaceID     string     `json:"raceId,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	A          *Entrant `json:"playerA"`
+	B          *Entrant `json:"playerB"`
+	WinnerName string   `json:"winner,omitempty"`
+}
+
+type Bracket struct {
+	Tournament *Tournament `json:"tournament"`
+	Entrants   []*Entrant  `json:"entrants"`
+	Matches    []*Match    `json:"matches"` // by round and slot
+}
+
+type Standing struct {
+	Place  int    `json:"place"` // shared by tied players
+	Name   string `json:"name"`
+	Seed   int    `json:"seed"`
+	Wins   int    `json:"wins"`
+	Losses int    `json:"losses"`
+}
+
+type Standings struct {
+	Tournament *Tournament `json:"tournament"`
+	Final      bool        `json:"final"`
+	Standings  []*Standing `json:"standings"`
+}
+
+type TournamentService interface {
+	CreateTournament(ctx context.Context, email string, t *Tournament) (*Tournament, error)
+	Tournaments(ctx context.Context) ([]*Tournament, error)
+	Register(ctx context.Context, id string, email string) error
+	Withdraw(ctx context.Context, id string, email string) error
+	Start(ctx context.Context, id string, email string) (*Bracket, error)
+	Bracket(ctx context.Context, id string, email string) (*Bracket, error)
+	Standings(ctx context.Context, id string, email string) (*Standings, error)
+	Resume(ctx context.Context) error
+}
@@ -3,13 +3,27 @@ package typing
 import "errors"
 
 var (
-	ErrInvalidUser        error = errors.New("invalid user id")
-	ErrInvalidWPM         error = errors.New("invalid wpm")
-	ErrInvalidTypedWords  error = errors.New("invalid typed words")
-	ErrInvalidTotalWords  error = errors.New("invalid total words")
-	ErrSomethingWentWrong error = errors.New("something went wrong")
-	ErrinvalidTotalErrors error = errors.New("invalid total errors count")
-	ErrInsertingData      error = errors.New("error inserting data to DB")
-	ErrUpdatingTotalTest  error = errors.New("error in updating test count")
-	ErrGettingDataFromDB  error = errors.New("error getting data from DB")
+	ErrInvalidUser          error = errors.New("invalid user id")
+	ErrInvalidWPM           error = errors.New("invalid wpm")
+	ErrInvalidTypedWords    error = errors.New("invalid typed words")
+	ErrInvalidTotalWords    error = errors.New("invalid total words")
+	ErrSomethingWentWrong   error = errors.New("something went wrong")
+	ErrinvalidTotalErrors   error = errors.New("invalid total errors count")
+	ErrInsertingData        error = errors.New("error inserting data to DB")
+	ErrUpdatingTotalTest    error = errors.New("error in updating test count")
+	ErrGettingDataFromDB    error = errors.New("error getting data from DB")
+	ErrUpdatingStreak       error = errors.New("error updating streak")
+	ErrInvalidMode          error = errors.New("invalid test mode")
+	ErrModeDurationMismatch error = errors.New("test duration does not match its mode")
+	ErrUpdatingProgress     error = errors.New("error updating xp")
+	ErrUpdatingAchievements error = errors.New("error updating achievements")
+	ErrUpdatingPersonalBest error = errors.New("error updating personal best")
+	ErrUpdatingGoals        error = errors.New("error updating goals")
+	ErrInvalidTimeline      error = errors.New("invalid replay timeline")
+	ErrInvalidSort          error = errors.New("invalid sort")
+	ErrInvalidDate          error = errors.New("invalid date")
+	ErrInvalidRange         error = errors.New("invalid date range")
+	ErrInvalidAccuracy      error = errors.New("invalid minimum accuracy")
+	ErrInvalidCursor        error = errors.New("invalid cursor")
+	ErrInvalidLimit         error = errors.New("invalid limit")
 )
@@ -1,9 +1,187 @@
 package typing
 
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"typing-speed/internals/core/replay"
+
+	"github.com/google/uuid"
+)
+
 // func TypingDataValid(data *TypingData)error{
 // 	if data.UserId==""{
 // 		return ErrInvalidUser
 // 	}
 // 	return nil
 	
-// }
\ No newline at end of file
+// }
+
+// Accuracy returns the percentage of correctly typed words in the test
+func Accuracy(data *TypingData) int {
+	if data.TotalWords == 0 {
+		return 0
+	}
+	return ((data.TypedWords - data.TotalErrors) * 100) / (data.TotalWords)
+}
+
+// NormalizeTestData fills in the mode and language of a submitted test.
+// Tests without a mode are classified by their duration, and a timed mode
+// must match the duration. The time the user took cannot exceed the test.
+func NormalizeTestData(data *TypingData) error {
+	if !replay.ValidTimeline(data.Timeline, max(data.TotalTime, data.TimeTakenByUser)) {
+		return ErrInvalidTimeline
+	}
+	if data.Mode == "" {
+		data.Mode = ModeFromDuration(data.TotalTime)
+	}
+	if !ValidMode(data.Mode) {
+		return ErrInvalidMode
+	}
+	if duration := ModeDuration(data.Mode); duration > 0 && duration != data.TotalTime {
+		return ErrModeDurationMismatch
+	}
+	data.TimeTakenByUser = min(max(data.TimeTakenByUser, 0), max(data.TotalTime, 0))
+
+	data.Language = strings.ToLower(strings.TrimSpace(data.Language))
+	if data.Language == "" {
+		data.Language = DefaultLanguage
+	}
+	return nil
+}
+
+// ModeFromDuration maps a test duration in seconds to its timed mode
+func ModeFromDuration(seconds int) string {
+	for _, mode := range Modes {
+		if mode != ModeCustom && ModeDuration(mode) == seconds {
+			return mode
+		}
+	}
+	return ModeCustom
+}
+
+// ModeDuration returns the length in seconds of a timed mode, or 0 for custom tests
+func ModeDuration(mode string) int {
+	switch mode {
+	case Mode15s:
+		return 15
+	case Mode30s:
+		return 30
+	case Mode60s:
+		return 60
+	case Mode120s:
+		return 120
+	}
+	return 0
+}
+
+func ValidMode(mode string) bool {
+	for _, m := range Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Beaten reports whether a test with wpm and accuracy is better than the personal best.
+// Speed decides, accuracy breaks ties.
+func (pb *PersonalBest) Beaten(wpm int, accuracy int) bool {
+	if pb == nil {
+		return true
+	}
+	if wpm != pb.WPM {
+		return wpm > pb.WPM
+	}
+	return accuracy > pb.Accuracy
+}
+
+// NewHistoryQuery checks the filter and fills in its defaults. Dates are
+// read in loc.
+func NewHistoryQuery(email string, f *HistoryFilter, loc *time.Location) (*HistoryQuery, error) {
+	q := &HistoryQuery{Email: email, Mode: f.Mode, Sort: f.Sort, Limit: HistoryPageSize}
+	if q.Sort == "" {
+		q.Sort = HistorySortDate
+	}
+	if q.Sort != HistorySortDate && q.Sort != HistorySortWPM {
+		return nil, ErrInvalidSort
+	}
+	if q.Mode != "" && !ValidMode(q.Mode) {
+		return nil, ErrInvalidMode
+	}
+
+	if f.From != "" {
+		from, err := time.ParseInLocation(HistoryDateLayout, f.From, loc)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		q.From = &from
+	}
+	if f.To != "" {
+		to, err := time.ParseInLocation(HistoryDateLayout, f.To, loc)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		until := to.AddDate(0, 0, 1)
+		q.Until = &until
+	}
+	if q.From != nil && q.Until != nil && !q.From.Before(*q.Until) {
+		return nil, ErrInvalidRange
+	}
+
+	if f.MinAccuracy != "" {
+		a, err := strconv.Atoi(f.MinAccuracy)
+		if err != nil || a < 0 || a > 100 {
+			return nil, ErrInvalidAccuracy
+		}
+		q.MinAccuracy = a
+	}
+
+	if f.Limit != "" {
+		l, err := strconv.Atoi(f.Limit)
+		if err != nil || l <= 0 || l > MaxHistoryPageSize {
+			return nil, ErrInvalidLimit
+		}
+		q.Limit = l
+	}
+
+	if f.Cursor != "" {
+		c, err := DecodeHistoryCursor(f.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		q.Cursor = c
+	}
+	return q, nil
+}
+
+// EncodeHistoryCursor points after the test. The speed is only needed when
+// sorting by it, but is always kept so a cursor reads the same either way.
+func EncodeHistoryCursor(t *TypingData) string {
+	raw := fmt.Sprintf("%d:%d:%s", t.WPM, t.CreatedAt.UnixNano(), t.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeHistoryCursor(cursor string) (*HistoryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(parts[2]); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	wpm, err1 := strconv.Atoi(parts[0])
+	nanos, err2 := strconv.ParseInt(parts[1], 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &HistoryCursor{WPM: wpm, CreatedAt: time.Unix(0, nanos).UTC(), ID: parts[2]}, nil
+}
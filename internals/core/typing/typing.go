@@ -3,21 +3,145 @@ package typing
 import (
 	"context"
 	"time"
+	"typing-speed/internals/core/achievement"
+	"typing-speed/internals/core/goal"
+)
+
+const (
+	Mode15s    = "15s"
+	Mode30s    = "30s"
+	Mode60s    = "60s"
+	Mode120s   = "120s"
+	ModeCustom = "custom"
+
+	// MaxCustomDuration is the most seconds a custom test counts for
+	MaxCustomDuration = 600
+
+	DefaultLanguage = "english"
+)
+
+var Modes = []string{Mode15s, Mode30s, Mode60s, Mode120s, ModeCustom}
+
+// Orders of the test history, each latest first among equals
+const (
+	HistorySortDate = "date"
+	HistorySortWPM  = "wpm"
+)
+
+const (
+	HistoryPageSize    = 20
+	MaxHistoryPageSize = 100
+
+	// HistoryDateLayout is how the dates of a history range are written
+	HistoryDateLayout = "2006-01-02"
 )
 
 type TypingData struct {
+	ID              string    `json:"id"`
 	Email           string    `json:"email"`
+	Mode            string    `json:"mode"`
+	Language        string    `json:"language"`
 	WPM             int       `json:"wpm"`
 	TotalErrors     int       `json:"totalErrors"`
 	TotalWords      int       `json:"totalWords"`
 	TypedWords      int       `json:"typedWords"`
-	TotalTime       int       `json:"totalTime"`       // total time of test in second
-	TimeTakenByUser int       `json:"timeTakenByUser"` // total time spend by user
+	TotalTime       int       `json:"totalTime"`               // total time of test in second
+	TimeTakenByUser int       `json:"timeTakenByUser"`         // total time spend by user
+	ChallengeDate   string    `json:"challengeDate,omitempty"` // set when the test raced the daily challenge
+	Timeline        []int     `json:"timeline,omitempty"`      // characters typed after each second, for replays
+	RaceID          string    `json:"raceId,omitempty"`        // set when the test was a multiplayer race
 	CreatedAt       time.Time `json:"createdAt"`
 }
 
+// PersonalBest is the user's fastest test in a mode and language
+type PersonalBest struct {
+	Email      string    `json:"-"`
+	Mode       string    `json:"mode"`
+	Language   string    `json:"language"`
+	WPM        int       `json:"wpm"`
+	Accuracy   int       `json:"accuracy"`
+	TestID     string    `json:"testId"`
+	AchievedAt time.Time `json:"achievedAt"`
+}
+
+// PersonalBestRecord is one entry of the history of broken personal bests
+type PersonalBestRecord struct {
+	PersonalBest
+	PreviousWPM int `json:"previousWpm"`
+}
+
+// PersonalBestResult tells the user whether the submitted test set a new personal best
+type PersonalBestResult struct {
+	NewPersonalBest bool   `json:"newPersonalBest"`
+	Mode            string `json:"mode"`
+	Language        string `json:"language"`
+	WPM             int    `json:"wpm"`
+	PreviousBestWPM int    `json:"previousBestWpm"`
+	Improvement     int    `json:"improvement"`
+}
+
+// TestResult is returned to the user after a test is submitted
+type TestResult struct {
+	TestID    string `json:"testId"`
+	XPGained  int    `json:"xpGained"`
+	XP        int    `json:"xp"`
+	Level     int    `json:"level"`
+	LeveledUp bool   `json:"leveledUp"`
+
+	PersonalBest *PersonalBestResult `json:"personalBest"`
+
+	Achievements   []*achievement.Achievement `json:"achievements"`
+	CompletedGoals []*goal.Goal               `json:"completedGoals"`
+}
+
+// HistoryFilter is a test history request as it comes in. Empty fields take
+// the defaults: every mode, all time, any accuracy, latest first. The dates
+// are days in the user's time zone, both included.
+type HistoryFilter struct {
+	Mode        string
+	From        string
+	To          string
+	MinAccuracy string
+	Sort        string
+	Cursor      string
+	Limit       string
+}
+
+// HistoryCursor is the position after the last test of a page
+type HistoryCursor struct {
+	WPM       int
+	CreatedAt time.Time
+	ID        string
+}
+
+// HistoryQuery is a checked HistoryFilter. From and Until are nil when the
+// range is open; Until is excluded.
+type HistoryQuery struct {
+	Email       string
+	Mode        string
+	From        *time.Time
+	Until       *time.Time
+	MinAccuracy int
+	Sort        string
+	Cursor      *HistoryCursor
+	Limit       int
+}
+
+// HistoryTest is a test of the history, with its accuracy worked out
+type HistoryTest struct {
+	*TypingData
+	Accuracy int `json:"accuracy"`
+}
+
+type HistoryPage struct {
+	Tests      []*HistoryTest `json:"tests"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
 type TypingService interface {
-	AddTestData(ctx context.Context, data *TypingData, email string) error
-	RecentTestForProfile(ctx context.Context, email string, month string) ([]*TypingData, error)
+	AddTestData(ctx context.Context, data *TypingData, email string) (*TestResult, error)
+	History(ctx context.Context, email string, filter *HistoryFilter) (*HistoryPage, error)
 	SendTypingSentence(ctx context.Context) string
+	PersonalBests(ctx context.Context, email string) ([]*PersonalBest, error)
+	PersonalBestHistory(ctx context.Context, email string, mode string, language string) ([]*PersonalBestRecord, error)
 }
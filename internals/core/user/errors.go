@@ -10,6 +10,12 @@ var (
 	ErrUnexpectedSigningMethod error = errors.New("unexpected token signin method")
 	ErrInvalidRefreshToken     error = errors.New("invalid refresh token")
 	ErrGettingDataFromDB       error = errors.New("error getting data from DB")
+	ErrInvalidTimeZone         error = errors.New("invalid time zone")
+	ErrInvalidUsername         error = errors.New("invalid username")
+	ErrReservedUsername        error = errors.New("username is not allowed")
+	ErrUsernameTaken           error = errors.New("username already taken")
+	ErrInvalidPrivacy          error = errors.New("invalid privacy setting")
+	ErrInvalidCountry          error = errors.New("invalid country")
 )
 
 type ErrorStruct struct {
@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -119,3 +120,145 @@ func Decrypt(ciphertextB64 string, key []byte) (string, error) {
 
 	return string(plaintext), nil
 }
+
+// Location resolves the user's IANA time zone, falling back to UTC
+func Location(timeZone string) *time.Location {
+	if timeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// daysBetween counts the calendar days from a to b as seen in loc
+func daysBetween(a, b time.Time, loc *time.Location) int {
+	ay, am, ad := a.In(loc).Date()
+	by, bm, bd := b.In(loc).Date()
+	from := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	to := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// NextStreak returns the daily streak after a test completed at now.
+// A test on the day after the last one extends the streak, a test on the
+// same day keeps it and anything later starts a new streak.
+func NextStreak(streak int, lastTest *time.Time, now time.Time, loc *time.Location) int {
+	if lastTest == nil || streak == 0 {
+		return 1
+	}
+
+	days := daysBetween(*lastTest, now, loc)
+	switch {
+	case days <= 0:
+		return streak
+	case days == 1:
+		return streak + 1
+	default:
+		return 1
+	}
+}
+
+// CurrentStreak returns the stored streak, or 0 once a full day has been missed
+func CurrentStreak(streak int, lastTest *time.Time, now time.Time, loc *time.Location) int {
+	if lastTest == nil || daysBetween(*lastTest, now, loc) > 1 {
+		return 0
+	}
+	return streak
+}
+
+// reservedUsernames would clash with routes or impersonate staff
+var reservedUsernames = map[string]bool{
+	"admin": true, "administrator": true, "api": true, "anonymous": true, "help": true, "login": true,
+	"logout": true, "me": true, "mod": true, "moderator": true, "null": true, "profile": true, "profiles": true,
+	"register": true, "root": true, "settings": true, "signup": true, "staff": true, "support": true,
+	"system": true, "typing": true, "undefined": true,
+}
+
+// profanity is matched anywhere in a username after undoing common
+// letter substitutions
+var profanity = []string{
+	"fuck", "shit", "cunt", "bitch", "bastard", "asshole", "dick", "cock", "pussy", "whore", "slut",
+	"nigger", "nigga", "faggot", "retard", "rape", "nazi",
+}
+
+var leetspeak = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "8", "b", "_", "")
+
+// NormalizeUsername lowercases the username and checks it is 3 to 20
+// letters, digits or underscores starting with a letter, and neither
+// reserved nor profane
+func NormalizeUsername(username string) (string, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if len(username) < MinUsernameLength || len(username) > MaxUsernameLength {
+		return "", ErrInvalidUsername
+	}
+
+	for i, r := range username {
+		switch {
+		case r >= 'a' && r <= 'z':
+		case (r >= '0' && r <= '9' || r == '_') && i > 0:
+		default:
+			return "", ErrInvalidUsername
+		}
+	}
+
+	if reservedUsernames[username] {
+		return "", ErrReservedUsername
+	}
+
+	plain := leetspeak.Replace(username)
+	for _, word := range profanity {
+		if strings.Contains(plain, word) {
+			return "", ErrReservedUsername
+		}
+	}
+
+	return username, nil
+}
+
+// countries are the ISO 3166-1 alpha-2 codes
+var countries = strings.Fields(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ
+	CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR
+	GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO
+	JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR
+	MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO
+	RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO TR TT TV
+	TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW
+`)
+
+// NormalizeCountry uppercases an ISO 3166-1 alpha-2 code and checks it is
+// assigned. An empty code clears the country.
+func NormalizeCountry(country string) (string, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		return "", nil
+	}
+	for _, c := range countries {
+		if c == country {
+			return country, nil
+		}
+	}
+	return "", ErrInvalidCountry
+}
+
+func ValidPrivacy(privacy string) bool {
+	return privacy == PrivacyPublic || privacy == PrivacyFriends || privacy == PrivacyPrivate
+}
+
+// CanView reports whether a viewer may see the data of a user with the given
+// privacy setting
+func CanView(privacy string, self, friends bool) bool {
+	switch {
+	case self:
+		return true
+	case privacy == PrivacyFriends:
+		return friends
+	case privacy == PrivacyPrivate:
+		return false
+	default:
+		return true
+	}
+}
@@ -3,15 +3,30 @@ package user
 import (
 	"context"
 	"time"
+	"typing-speed/internals/core/progress"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Privacy settings decide who may see a user's profile and find them on
+// leaderboards
+const (
+	PrivacyPublic  = "public"  // everyone
+	PrivacyFriends = "friends" // the user's friends only
+	PrivacyPrivate = "private" // nobody but the user
+
+	MinUsernameLength = 3
+	MaxUsernameLength = 20
+
+	// AnonymousName stands in for users whose profile the viewer may not see
+	AnonymousName = "Anonymous"
+)
+
 type User struct {
 	ID             int64      `db:"id" json:"-"`
 	Name           string     `db:"name" json:"name"`
 	Email          string     `db:"email" json:"email"`
-	Password       string     `db:"password" json:"password"`
+	Password       string     `db:"password" json:"-"`
 	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
 	AvgSpeed       int        `db:"avg_speed" json:"avgSpeed"`
 	AvgAccuracy    int        `db:"avg_accuracy" json:"avgAccuracy"`
@@ -21,13 +36,49 @@ type User struct {
 	Streak         int        `db:"streak" json:"streak"`
 	BestSpeed      int        `db:"best_speed" json:"bestSpeed"`
 	AvgPerformance int        `db:"avg_performance" json:"avgPerformance"`
+	TimeZone       string     `db:"time_zone" json:"timeZone"`
+	LongestStreak  int        `db:"longest_streak" json:"longestStreak"`
+	XP             int        `db:"xp" json:"xp"`
+	Username       string     `db:"username" json:"username"`
+	Privacy        string     `db:"privacy" json:"privacy"`
+	Country        string     `db:"country" json:"country"`
+	Region         string     `db:"region" json:"region,omitempty"`
+	// IP is where the user signed up from, which their country is inferred
+	// from when they do not give one
+	IP string `db:"-" json:"-"`
+
+	Progress *progress.Progress `db:"-" json:"progress,omitempty"`
+}
+
+// PublicUser is what anyone may see of a user with a public profile
+type PublicUser struct {
+	Name           string `json:"name"`
+	Username       string `json:"username,omitempty"`
+	Level          int    `json:"level"`
+	AvgSpeed       int    `json:"avgSpeed"`
+	AvgAccuracy    int    `json:"avgAccuracy"`
+	BestSpeed      int    `json:"bestSpeed"`
+	AvgPerformance int    `json:"avgPerformance"`
+	TotalTest      int    `json:"totalTest"`
 }
 
 type TopPerformer struct {
 	Name        string `json:"name"`
+	Username    string `json:"username,omitempty"`
 	Performance int    `json:"performance"`
 }
 
+// RegisterUser is the sign-up request. It is kept apart from User so the
+// password is never written back out.
+type RegisterUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Username string `json:"username"`
+	Country  string `json:"country"`
+	Region   string `json:"region"`
+}
+
 type LoginUser struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -56,7 +107,7 @@ type DashboardTopData struct {
 }
 
 type DashboardData struct {
-	User             []*User           `json:"user"`
+	User             []*PublicUser     `json:"user"`
 	DashboardTopData *DashboardTopData `json:"dashboardTopData"`
 }
 
@@ -67,4 +118,8 @@ type UserService interface {
 	UserByEmail(ctx context.Context, email string) (*User, error)
 	TopPerformer(ctx context.Context) ([]*TopPerformer, error)
 	GetDataForDashboard(ctx context.Context) (*DashboardData, error)
+	UpdateTimeZone(ctx context.Context, email string, timeZone string) error
+	UpdateUsername(ctx context.Context, email string, username string) error
+	UpdatePrivacy(ctx context.Context, email string, privacy string) error
+	UpdateCountry(ctx context.Context, email string, country string) error
 }
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"time"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) AchievementsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.achievementUseCase.UserAchievements(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "achievements fetched successfully", start, logsData, data)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"time"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) DailyChallengeHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	data := h.challengeUseCase.TodayChallenge(c.Request.Context())
+
+	h.respondSuccess(c, "daily challenge fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) ChallengeLeaderboardHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	date := c.Query("date")
+
+	data, err := h.challengeUseCase.Leaderboard(c.Request.Context(), date)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "challenge leaderboard fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) ChallengeHistoryHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	limit := c.Query("limit")
+
+	data, err := h.challengeUseCase.History(c.Request.Context(), limit)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "challenge history fetched successfully", start, logsData, data)
+}
@@ -0,0 +1,339 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+	"typing-speed/internals/core/classroom"
+	"typing-speed/internals/core/typing"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateClassHandler makes the user the teacher of a new class
+func (h *Handler) CreateClassHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var req classroom.Class
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	data, err := h.classUseCase.CreateClass(c.Request.Context(), email, &req)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "class created successfully", start, logsData, data)
+}
+
+// ClassesHandler lists the classes the user teaches or is enrolled in
+func (h *Handler) ClassesHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.classUseCase.Classes(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "classes fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) JoinClassHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	data, err := h.classUseCase.Join(c.Request.Context(), email, req.Code)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "class joined successfully", start, logsData, data)
+}
+
+func (h *Handler) ClassHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	data, err := h.classUseCase.Class(c.Request.Context(), email, id)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "class fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) DeleteClassHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	if err := h.classUseCase.DeleteClass(c.Request.Context(), email, id); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "class deleted successfully", start, logsData, nil)
+}
+
+// RemoveStudentHandler takes a student out of the class, or lets a student leave
+func (h *Handler) RemoveStudentHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	student := c.Param("student")
+
+	if err := h.classUseCase.RemoveStudent(c.Request.Context(), email, id, student); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "student removed successfully", start, logsData, nil)
+}
+
+func (h *Handler) CreateAssignmentHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	var req classroom.Assignment
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	data, err := h.classUseCase.CreateAssignment(c.Request.Context(), email, id, &req)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "assignment created successfully", start, logsData, data)
+}
+
+func (h *Handler) AssignmentHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	assignmentID := c.Param("assignment")
+
+	data, err := h.classUseCase.Assignment(c.Request.Context(), email, id, assignmentID)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "assignment fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) DeleteAssignmentHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	assignmentID := c.Param("assignment")
+
+	if err := h.classUseCase.DeleteAssignment(c.Request.Context(), email, id, assignmentID); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "assignment deleted successfully", start, logsData, nil)
+}
+
+// SubmitAssignmentHandler records a test the student took for the assignment
+func (h *Handler) SubmitAssignmentHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	assignmentID := c.Param("assignment")
+
+	var req typing.TypingData
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	data, err := h.classUseCase.Submit(c.Request.Context(), email, id, assignmentID, &req)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "assignment submitted successfully", start, logsData, data)
+}
+
+func (h *Handler) AssignmentSubmissionsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	assignmentID := c.Param("assignment")
+
+	data, err := h.classUseCase.Submissions(c.Request.Context(), email, id, assignmentID)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "submissions fetched successfully", start, logsData, data)
+}
+
+// AssignmentReportHandler shows the teacher how each student did on the
+// assignment, as CSV with ?format=csv
+func (h *Handler) AssignmentReportHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	assignmentID := c.Param("assignment")
+
+	data, err := h.classUseCase.Report(c.Request.Context(), email, id, assignmentID)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		h.respondSuccess(c, "report fetched successfully", start, logsData, data)
+		return
+	}
+
+	csv, err := data.CSV()
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, "something went wrong", err, start, logsData)
+		return
+	}
+
+	h.respondFile(c, "report exported successfully", "assignment-"+data.Assignment.ID+".csv", "text/csv", start,
+		logsData, csv)
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"time"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) FeedHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	cursor := c.Query("cursor")
+	limit := c.Query("limit")
+
+	data, err := h.feedUseCase.Feed(c.Request.Context(), email, cursor, limit)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "feed fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) MuteHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	target := c.Param("target")
+
+	if err := h.feedUseCase.Mute(c.Request.Context(), email, target); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "muted successfully", start, logsData, nil)
+}
+
+func (h *Handler) UnmuteHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	target := c.Param("target")
+
+	if err := h.feedUseCase.Unmute(c.Request.Context(), email, target); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "unmuted successfully", start, logsData, nil)
+}
+
+func (h *Handler) MutedHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.feedUseCase.Muted(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "muted users fetched successfully", start, logsData, data)
+}
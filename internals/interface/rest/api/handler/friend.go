@@ -0,0 +1,378 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) FollowHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	target := c.Param("target")
+
+	if err := h.friendUseCase.Follow(c.Request.Context(), email, target); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "followed successfully", start, logsData, nil)
+}
+
+func (h *Handler) UnfollowHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	target := c.Param("target")
+
+	if err := h.friendUseCase.Unfollow(c.Request.Context(), email, target); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "unfollowed successfully", start, logsData, nil)
+}
+
+func (h *Handler) FollowersHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.friendUseCase.Followers(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "followers fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) FollowingHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.friendUseCase.Following(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "following fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) FriendsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.friendUseCase.Friends(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "friends fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) UnfriendHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	target := c.Param("target")
+
+	if err := h.friendUseCase.Unfriend(c.Request.Context(), email, target); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "unfriended successfully", start, logsData, nil)
+}
+
+func (h *Handler) RelationshipHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	target := c.Param("target")
+
+	data, err := h.friendUseCase.Relationship(c.Request.Context(), email, target)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "relationship fetched successfully", start, logsData, data)
+}
+
+// SendFriendRequestHandler asks the user with the given email to be friends
+func (h *Handler) SendFriendRequestHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	data, err := h.friendUseCase.SendRequest(c.Request.Context(), email, req.Email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "friend request sent successfully", start, logsData, data)
+}
+
+func (h *Handler) FriendRequestsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.friendUseCase.Requests(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "friend requests fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) AcceptFriendRequestHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	data, err := h.friendUseCase.RespondToRequest(c.Request.Context(), email, id, true)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "friend request accepted successfully", start, logsData, data)
+}
+
+func (h *Handler) DeclineFriendRequestHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	data, err := h.friendUseCase.RespondToRequest(c.Request.Context(), email, id, false)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "friend request declined successfully", start, logsData, data)
+}
+
+func (h *Handler) BlockHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	target := c.Param("target")
+
+	if err := h.friendUseCase.Block(c.Request.Context(), email, target); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "blocked successfully", start, logsData, nil)
+}
+
+func (h *Handler) UnblockHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	target := c.Param("target")
+
+	if err := h.friendUseCase.Unblock(c.Request.Context(), email, target); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "unblocked successfully", start, logsData, nil)
+}
+
+func (h *Handler) BlockedHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.friendUseCase.Blocked(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "blocked users fetched successfully", start, logsData, data)
+}
+
+// FriendsLeaderboardHandler ranks the user among their friends
+func (h *Handler) FriendsLeaderboardHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.friendUseCase.Leaderboard(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "friends leaderboard fetched successfully", start, logsData, data)
+}
+
+// CompareFriendHandler sets the user's stats beside a friend's
+func (h *Handler) CompareFriendHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	target := c.Param("target")
+
+	data, err := h.friendUseCase.Compare(c.Request.Context(), email, target)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "comparison fetched successfully", start, logsData, data)
+}
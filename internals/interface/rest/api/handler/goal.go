@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+	"typing-speed/internals/core/goal"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) CreateGoalHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var goalData goal.Goal
+	if err := c.ShouldBindJSON(&goalData); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = goalData
+
+	data, err := h.goalUseCase.CreateGoal(c.Request.Context(), email, &goalData)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "goal created successfully", start, logsData, data)
+}
+
+func (h *Handler) GoalsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.goalUseCase.Goals(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "goals fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) UpdateGoalHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	var goalData goal.Goal
+	if err := c.ShouldBindJSON(&goalData); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = goalData
+
+	data, err := h.goalUseCase.UpdateGoal(c.Request.Context(), email, id, &goalData)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "goal updated successfully", start, logsData, data)
+}
+
+func (h *Handler) DeleteGoalHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	if err := h.goalUseCase.DeleteGoal(c.Request.Context(), email, id); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "goal deleted successfully", start, logsData, nil)
+}
+
+func (h *Handler) GoalsProgressHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.goalUseCase.GoalsProgress(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "goal progress fetched successfully", start, logsData, data)
+}
@@ -4,6 +4,19 @@ import (
 	"errors"
 	"net/http"
 	"time"
+	"typing-speed/internals/core/challenge"
+	"typing-speed/internals/core/classroom"
+	"typing-speed/internals/core/feed"
+	"typing-speed/internals/core/friend"
+	"typing-speed/internals/core/goal"
+	"typing-speed/internals/core/leaderboard"
+	"typing-speed/internals/core/organization"
+	"typing-speed/internals/core/profile"
+	"typing-speed/internals/core/race"
+	"typing-speed/internals/core/ranking"
+	"typing-speed/internals/core/replay"
+	"typing-speed/internals/core/stats"
+	"typing-speed/internals/core/tournament"
 	"typing-speed/internals/core/typing"
 	"typing-speed/internals/core/user"
 	"typing-speed/pkg/logs"
@@ -43,6 +56,20 @@ func (h *Handler) respondSuccess(c *gin.Context,msg string,start time.Time,logsD
 	})
 }
 
+// respondFile sends data as a file to download
+func (h *Handler) respondFile(c *gin.Context, msg string, filename string, contentType string, start time.Time,
+	logsData *logs.LogEntry, data []byte) {
+	logsData.Level = LogLevelInfo
+	logsData.Msg = msg
+	logsData.Status = http.StatusOK
+	logsData.Latency = logs.Duration(time.Since(start))
+
+	h.logsChan <- *logsData
+
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
+
 // recoverPanic response in case of panic
 func (h *Handler) recoverPanic(c *gin.Context, start time.Time, logsData *logs.LogEntry) {
 	if r := recover(); r != nil {
@@ -73,6 +100,382 @@ func (h *Handler) handleServiceError(c *gin.Context,err error,logsData *logs.Log
 		status = http.StatusBadRequest
 		message = "user cannot be empty"
 
+	case errors.Is(err, typing.ErrInvalidMode):
+		status = http.StatusBadRequest
+		message = "invalid test mode"
+
+	case errors.Is(err, typing.ErrModeDurationMismatch):
+		status = http.StatusBadRequest
+		message = "test duration does not match its mode"
+
+	case errors.Is(err, typing.ErrInvalidSort):
+		status = http.StatusBadRequest
+		message = "sort must be date or wpm"
+
+	case errors.Is(err, typing.ErrInvalidDate):
+		status = http.StatusBadRequest
+		message = "dates must be written as YYYY-MM-DD"
+
+	case errors.Is(err, typing.ErrInvalidRange):
+		status = http.StatusBadRequest
+		message = "from must not be after to"
+
+	case errors.Is(err, typing.ErrInvalidAccuracy):
+		status = http.StatusBadRequest
+		message = "minimum accuracy must be between 0 and 100"
+
+	case errors.Is(err, typing.ErrInvalidCursor):
+		status = http.StatusBadRequest
+		message = "invalid cursor"
+
+	case errors.Is(err, typing.ErrInvalidLimit):
+		status = http.StatusBadRequest
+		message = "invalid limit"
+
+	case errors.Is(err, challenge.ErrInvalidDate):
+		status = http.StatusBadRequest
+		message = "invalid challenge date"
+
+	case errors.Is(err, challenge.ErrInvalidLimit):
+		status = http.StatusBadRequest
+		message = "invalid limit"
+
+	case errors.Is(err, goal.ErrInvalidGoal):
+		status = http.StatusBadRequest
+		message = "invalid goal"
+
+	case errors.Is(err, goal.ErrGoalNotFound):
+		status = http.StatusNotFound
+		message = "goal not found"
+
+	case errors.Is(err, typing.ErrInvalidTimeline):
+		status = http.StatusBadRequest
+		message = "invalid replay timeline"
+
+	case errors.Is(err, replay.ErrInvalidMode):
+		status = http.StatusBadRequest
+		message = "invalid test mode"
+
+	case errors.Is(err, replay.ErrReplayNotFound):
+		status = http.StatusNotFound
+		message = "replay not found"
+
+	case errors.Is(err, race.ErrRoomNotFound):
+		status = http.StatusNotFound
+		message = "race room not found"
+
+	case errors.Is(err, race.ErrRaceNotFound):
+		status = http.StatusNotFound
+		message = "race not found"
+
+	case errors.Is(err, race.ErrRoomFull):
+		status = http.StatusConflict
+		message = "race room is full"
+
+	case errors.Is(err, race.ErrRaceStarted):
+		status = http.StatusConflict
+		message = "race already started"
+
+	case errors.Is(err, race.ErrNotInvited):
+		status = http.StatusForbidden
+		message = "not a player in this match"
+
+	case errors.Is(err, race.ErrKicked):
+		status = http.StatusForbidden
+		message = "removed from the race room by the host"
+
+	case errors.Is(err, race.ErrInvalidSettings):
+		status = http.StatusBadRequest
+		message = "invalid room settings"
+
+	case errors.Is(err, race.ErrInvalidSpectatorToken):
+		status = http.StatusForbidden
+		message = "invalid spectator token"
+
+	case errors.Is(err, race.ErrTooManySpectators):
+		status = http.StatusServiceUnavailable
+		message = "too many spectators"
+
+	case errors.Is(err, race.ErrAlreadyQueued):
+		status = http.StatusConflict
+		message = "already waiting for a quick race"
+
+	case errors.Is(err, tournament.ErrInvalidTournament):
+		status = http.StatusBadRequest
+		message = "invalid tournament"
+
+	case errors.Is(err, tournament.ErrTournamentNotFound):
+		status = http.StatusNotFound
+		message = "tournament not found"
+
+	case errors.Is(err, tournament.ErrNotOrganizer):
+		status = http.StatusForbidden
+		message = "only the organizer can do this"
+
+	case errors.Is(err, tournament.ErrRegistrationClosed):
+		status = http.StatusConflict
+		message = "registration is closed"
+
+	case errors.Is(err, tournament.ErrRegistrationOpen):
+		status = http.StatusConflict
+		message = "registration is still open"
+
+	case errors.Is(err, tournament.ErrTournamentFull):
+		status = http.StatusConflict
+		message = "tournament is full"
+
+	case errors.Is(err, tournament.ErrNotRegistered):
+		status = http.StatusNotFound
+		message = "not registered for the tournament"
+
+	case errors.Is(err, tournament.ErrNotEnoughEntrants):
+		status = http.StatusConflict
+		message = "not enough entrants to start"
+
+	case errors.Is(err, friend.ErrSelf):
+		status = http.StatusBadRequest
+		message = "cannot do this to yourself"
+
+	case errors.Is(err, friend.ErrUserNotFound):
+		status = http.StatusNotFound
+		message = "user not found"
+
+	case errors.Is(err, friend.ErrBlocked):
+		status = http.StatusForbidden
+		message = "blocked"
+
+	case errors.Is(err, friend.ErrNotBlocked):
+		status = http.StatusNotFound
+		message = "user is not blocked"
+
+	case errors.Is(err, friend.ErrNotFollowing):
+		status = http.StatusNotFound
+		message = "not following the user"
+
+	case errors.Is(err, friend.ErrAlreadyFriends):
+		status = http.StatusConflict
+		message = "already friends"
+
+	case errors.Is(err, friend.ErrNotFriends):
+		status = http.StatusForbidden
+		message = "not friends with the user"
+
+	case errors.Is(err, friend.ErrRequestNotFound):
+		status = http.StatusNotFound
+		message = "friend request not found"
+
+	case errors.Is(err, friend.ErrRequestAnswered):
+		status = http.StatusConflict
+		message = "friend request already answered"
+
+	case errors.Is(err, friend.ErrPrivateProfile):
+		status = http.StatusForbidden
+		message = "profile is private"
+
+	case errors.Is(err, feed.ErrSelf):
+		status = http.StatusBadRequest
+		message = "cannot do this to yourself"
+
+	case errors.Is(err, feed.ErrUserNotFound):
+		status = http.StatusNotFound
+		message = "user not found"
+
+	case errors.Is(err, feed.ErrNotMuted):
+		status = http.StatusNotFound
+		message = "user is not muted"
+
+	case errors.Is(err, feed.ErrInvalidCursor):
+		status = http.StatusBadRequest
+		message = "invalid cursor"
+
+	case errors.Is(err, feed.ErrInvalidLimit):
+		status = http.StatusBadRequest
+		message = "invalid limit"
+
+	case errors.Is(err, leaderboard.ErrInvalidWindow):
+		status = http.StatusBadRequest
+		message = "window must be today, week, month or all"
+
+	case errors.Is(err, leaderboard.ErrInvalidMode):
+		status = http.StatusBadRequest
+		message = "invalid mode"
+
+	case errors.Is(err, leaderboard.ErrInvalidSort):
+		status = http.StatusBadRequest
+		message = "sort must be wpm, performance or accuracy"
+
+	case errors.Is(err, leaderboard.ErrInvalidCursor):
+		status = http.StatusBadRequest
+		message = "invalid cursor"
+
+	case errors.Is(err, leaderboard.ErrInvalidLimit):
+		status = http.StatusBadRequest
+		message = "invalid limit"
+
+	case errors.Is(err, leaderboard.ErrInvalidMetric):
+		status = http.StatusBadRequest
+		message = "invalid metric"
+
+	case errors.Is(err, leaderboard.ErrInvalidCountry):
+		status = http.StatusBadRequest
+		message = "country must be an ISO 3166 alpha-2 code"
+
+	case errors.Is(err, leaderboard.ErrInvalidRegion):
+		status = http.StatusBadRequest
+		message = "region needs a country"
+
+	case errors.Is(err, stats.ErrInvalidBucket):
+		status = http.StatusBadRequest
+		message = "bucket must be day, week or month"
+
+	case errors.Is(err, stats.ErrInvalidMode):
+		status = http.StatusBadRequest
+		message = "invalid mode"
+
+	case errors.Is(err, stats.ErrInvalidDate):
+		status = http.StatusBadRequest
+		message = "dates must be written as YYYY-MM-DD"
+
+	case errors.Is(err, stats.ErrInvalidRange):
+		status = http.StatusBadRequest
+		message = "from must not be after to, and the range must be at most 366 buckets"
+
+	case errors.Is(err, stats.ErrUserNotFound):
+		status = http.StatusNotFound
+		message = "user not found"
+
+	case errors.Is(err, ranking.ErrNotRanked):
+		status = http.StatusNotFound
+		message = "user is not ranked"
+
+	case errors.Is(err, ranking.ErrInvalidLimit):
+		status = http.StatusBadRequest
+		message = "invalid limit"
+
+	case errors.Is(err, profile.ErrProfileNotFound):
+		status = http.StatusNotFound
+		message = "profile not found"
+
+	case errors.Is(err, profile.ErrPrivateProfile):
+		status = http.StatusForbidden
+		message = "profile is private"
+
+	case errors.Is(err, organization.ErrInvalidOrganization):
+		status = http.StatusBadRequest
+		message = "invalid organization"
+
+	case errors.Is(err, organization.ErrInvalidDomain):
+		status = http.StatusBadRequest
+		message = "invalid email domain"
+
+	case errors.Is(err, organization.ErrDomainTaken):
+		status = http.StatusConflict
+		message = "email domain already claimed by another organization"
+
+	case errors.Is(err, organization.ErrDomainMismatch):
+		status = http.StatusForbidden
+		message = "email domain does not match the organization"
+
+	case errors.Is(err, organization.ErrDomainNotVerified):
+		status = http.StatusForbidden
+		message = "email domain not verified"
+
+	case errors.Is(err, organization.ErrVerificationFailed):
+		status = http.StatusUnprocessableEntity
+		message = "no DNS TXT record verifies the domain"
+
+	case errors.Is(err, organization.ErrOrganizationNotFound):
+		status = http.StatusNotFound
+		message = "organization not found"
+
+	case errors.Is(err, organization.ErrNotMember):
+		status = http.StatusForbidden
+		message = "not a member of the organization"
+
+	case errors.Is(err, organization.ErrForbidden):
+		status = http.StatusForbidden
+		message = "not allowed for your role"
+
+	case errors.Is(err, organization.ErrAlreadyMember):
+		status = http.StatusConflict
+		message = "already a member"
+
+	case errors.Is(err, organization.ErrMemberNotFound):
+		status = http.StatusNotFound
+		message = "member not found"
+
+	case errors.Is(err, organization.ErrInvalidRole):
+		status = http.StatusBadRequest
+		message = "invalid role"
+
+	case errors.Is(err, organization.ErrOwnerCannotLeave):
+		status = http.StatusBadRequest
+		message = "the owner cannot leave the organization"
+
+	case errors.Is(err, organization.ErrInvalidTeam):
+		status = http.StatusBadRequest
+		message = "invalid team"
+
+	case errors.Is(err, organization.ErrTeamNotFound):
+		status = http.StatusNotFound
+		message = "team not found"
+
+	case errors.Is(err, organization.ErrTeamExists):
+		status = http.StatusConflict
+		message = "team already exists"
+
+	case errors.Is(err, organization.ErrUserNotFound):
+		status = http.StatusNotFound
+		message = "user not found"
+
+	case errors.Is(err, classroom.ErrInvalidClass):
+		status = http.StatusBadRequest
+		message = "invalid class"
+
+	case errors.Is(err, classroom.ErrClassNotFound):
+		status = http.StatusNotFound
+		message = "class not found"
+
+	case errors.Is(err, classroom.ErrInvalidJoinCode):
+		status = http.StatusNotFound
+		message = "invalid join code"
+
+	case errors.Is(err, classroom.ErrTeacher):
+		status = http.StatusBadRequest
+		message = "teachers cannot join their own class"
+
+	case errors.Is(err, classroom.ErrAlreadyEnrolled):
+		status = http.StatusConflict
+		message = "already enrolled in the class"
+
+	case errors.Is(err, classroom.ErrNotEnrolled):
+		status = http.StatusForbidden
+		message = "not enrolled in the class"
+
+	case errors.Is(err, classroom.ErrNotTeacher):
+		status = http.StatusForbidden
+		message = "only the teacher can do this"
+
+	case errors.Is(err, classroom.ErrStudentNotFound):
+		status = http.StatusNotFound
+		message = "student not found"
+
+	case errors.Is(err, classroom.ErrInvalidAssignment):
+		status = http.StatusBadRequest
+		message = "invalid assignment"
+
+	case errors.Is(err, classroom.ErrAssignmentNotFound):
+		status = http.StatusNotFound
+		message = "assignment not found"
+
+	case errors.Is(err, classroom.ErrAssignmentClosed):
+		status = http.StatusForbidden
+		message = "assignment is past its due date"
+
+	case errors.Is(err, classroom.ErrModeMismatch):
+		status = http.StatusBadRequest
+		message = "test mode does not match the assignment"
+
 	case errors.Is(err, user.ErrUserNotFound):
 		status = http.StatusNotFound
 		message = "user not found"
@@ -88,6 +491,30 @@ func (h *Handler) handleServiceError(c *gin.Context,err error,logsData *logs.Log
 	case errors.Is(err, user.ErrUserAlreadyRegistered):
 		status = http.StatusBadRequest
 		message = "user already registered"
+
+	case errors.Is(err, user.ErrInvalidTimeZone):
+		status = http.StatusBadRequest
+		message = "invalid time zone"
+
+	case errors.Is(err, user.ErrInvalidUsername):
+		status = http.StatusBadRequest
+		message = "username must be 3 to 20 letters, digits or underscores and start with a letter"
+
+	case errors.Is(err, user.ErrReservedUsername):
+		status = http.StatusBadRequest
+		message = "username is not allowed"
+
+	case errors.Is(err, user.ErrUsernameTaken):
+		status = http.StatusConflict
+		message = "username already taken"
+
+	case errors.Is(err, user.ErrInvalidPrivacy):
+		status = http.StatusBadRequest
+		message = "privacy must be public, friends or private"
+
+	case errors.Is(err, user.ErrInvalidCountry):
+		status = http.StatusBadRequest
+		message = "country must be an ISO 3166 alpha-2 code"
 	}
 
 	logsData.Status = status
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"time"
+	"typing-speed/internals/core/leaderboard"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) LeaderboardHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	filter := &leaderboard.Filter{
+		Window:   c.Query("window"),
+		Mode:     c.Query("mode"),
+		Language: c.Query("language"),
+		Sort:     c.Query("sort"),
+		Country:  c.Query("country"),
+		Region:   c.Query("region"),
+		Cursor:   c.Query("cursor"),
+		Limit:    c.Query("limit"),
+	}
+
+	logsData.RequestData = filter
+
+	data, err := h.leaderboardUseCase.Leaderboard(c.Request.Context(), email, filter)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "leaderboard fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) StandingsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	metric := c.Query("metric")
+
+	data, err := h.leaderboardUseCase.Standings(c.Request.Context(), email, metric)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "standings fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) CountriesHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	data, err := h.leaderboardUseCase.Countries(c.Request.Context())
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "countries fetched successfully", start, logsData, data)
+}
@@ -0,0 +1,529 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+	"typing-speed/internals/core/organization"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) CreateOrganizationHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var req organization.Organization
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	data, err := h.organizationUseCase.CreateOrganization(c.Request.Context(), email, &req)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "organization created successfully", start, logsData, data)
+}
+
+// OrganizationsHandler lists the organizations the user is a member of
+func (h *Handler) OrganizationsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.organizationUseCase.Organizations(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "organizations fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) OrganizationHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	data, err := h.organizationUseCase.Organization(c.Request.Context(), email, id)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "organization fetched successfully", start, logsData, data)
+}
+
+// UpdateOrganizationHandler renames the organization and sets its email domain
+func (h *Handler) UpdateOrganizationHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	var req organization.Organization
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	data, err := h.organizationUseCase.UpdateOrganization(c.Request.Context(), email, id, &req)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "organization updated successfully", start, logsData, data)
+}
+
+func (h *Handler) DeleteOrganizationHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	if err := h.organizationUseCase.DeleteOrganization(c.Request.Context(), email, id); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "organization deleted successfully", start, logsData, nil)
+}
+
+// VerifyOrganizationDomainHandler checks the TXT record of the organization's email domain
+func (h *Handler) VerifyOrganizationDomainHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	data, err := h.organizationUseCase.VerifyDomain(c.Request.Context(), email, id)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "domain verified successfully", start, logsData, data)
+}
+
+// JoinOrganizationHandler joins an organization that verified the user's email domain
+func (h *Handler) JoinOrganizationHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	if err := h.organizationUseCase.Join(c.Request.Context(), email, id); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "organization joined successfully", start, logsData, nil)
+}
+
+func (h *Handler) LeaveOrganizationHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	if err := h.organizationUseCase.Leave(c.Request.Context(), email, id); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "organization left successfully", start, logsData, nil)
+}
+
+func (h *Handler) AddOrganizationMemberHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	var req struct {
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	if err := h.organizationUseCase.AddMember(c.Request.Context(), email, id, req.Email, req.Role); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "member added successfully", start, logsData, nil)
+}
+
+func (h *Handler) UpdateOrganizationMemberHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	member := c.Param("member")
+
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	if err := h.organizationUseCase.UpdateMemberRole(c.Request.Context(), email, id, member, req.Role); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "member updated successfully", start, logsData, nil)
+}
+
+func (h *Handler) RemoveOrganizationMemberHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	member := c.Param("member")
+
+	if err := h.organizationUseCase.RemoveMember(c.Request.Context(), email, id, member); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "member removed successfully", start, logsData, nil)
+}
+
+func (h *Handler) OrganizationLeaderboardHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	data, err := h.organizationUseCase.Leaderboard(c.Request.Context(), email, id, "")
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "leaderboard fetched successfully", start, logsData, data)
+}
+
+// OrganizationDashboardHandler is the dashboard over the members of the organization
+func (h *Handler) OrganizationDashboardHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	data, err := h.organizationUseCase.Dashboard(c.Request.Context(), email, id, "")
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "dashboard data fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) CreateTeamHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	var req organization.Team
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	data, err := h.organizationUseCase.CreateTeam(c.Request.Context(), email, id, &req)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "team created successfully", start, logsData, data)
+}
+
+func (h *Handler) TeamHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	teamID := c.Param("team")
+
+	data, err := h.organizationUseCase.Team(c.Request.Context(), email, id, teamID)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "team fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) DeleteTeamHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	teamID := c.Param("team")
+
+	if err := h.organizationUseCase.DeleteTeam(c.Request.Context(), email, id, teamID); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "team deleted successfully", start, logsData, nil)
+}
+
+func (h *Handler) AddTeamMemberHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	teamID := c.Param("team")
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	if err := h.organizationUseCase.AddTeamMember(c.Request.Context(), email, id, teamID, req.Email); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "team member added successfully", start, logsData, nil)
+}
+
+func (h *Handler) RemoveTeamMemberHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	teamID := c.Param("team")
+	member := c.Param("member")
+
+	if err := h.organizationUseCase.RemoveTeamMember(c.Request.Context(), email, id, teamID, member); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "team member removed successfully", start, logsData, nil)
+}
+
+func (h *Handler) TeamLeaderboardHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	teamID := c.Param("team")
+
+	data, err := h.organizationUseCase.Leaderboard(c.Request.Context(), email, id, teamID)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "leaderboard fetched successfully", start, logsData, data)
+}
+
+// TeamDashboardHandler is the dashboard over the members of the team
+func (h *Handler) TeamDashboardHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+	teamID := c.Param("team")
+
+	data, err := h.organizationUseCase.Dashboard(c.Request.Context(), email, id, teamID)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "dashboard data fetched successfully", start, logsData, data)
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"time"
+	"typing-speed/internals/core/race"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const spectatorHeartbeat = 15 * time.Second
+
+// CreateRaceRoomHandler opens a private room. The settings are optional, an
+// empty body gets the defaults.
+func (h *Handler) CreateRaceRoomHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var settings *race.Settings
+	if c.Request.ContentLength != 0 {
+		settings = &race.Settings{}
+		if err := c.ShouldBindJSON(settings); err != nil {
+			h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+			return
+		}
+		logsData.RequestData = settings
+	}
+
+	data, err := h.raceUseCase.CreateRoom(c.Request.Context(), email, settings)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "race room created successfully", start, logsData, data)
+}
+
+// RaceRoomSocketHandler joins the user to a race room, by id or invite code,
+// over a WebSocket. Connecting again resumes the user's seat.
+func (h *Handler) RaceRoomSocketHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	roomID := c.Param("id")
+
+	session, err := h.raceUseCase.JoinRoom(c.Request.Context(), roomID, email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.serveRaceSession(c, session, start, logsData)
+}
+
+// QuickRaceSocketHandler queues the user for a rated race over a WebSocket
+func (h *Handler) QuickRaceSocketHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	session, err := h.raceUseCase.QuickRace(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.serveRaceSession(c, session, start, logsData)
+}
+
+// serveRaceSession bridges the session's events and the user's messages
+// over a WebSocket until either side goes away. A dropped socket only
+// disconnects the session, so the user can reconnect to their seat.
+func (h *Handler) serveRaceSession(c *gin.Context, session race.Session, start time.Time, logsData *logs.LogEntry) {
+	defer session.Disconnect()
+
+	server := websocket.Server{
+		// the socket is authenticated by the access token, not by cookies,
+		// so a page on another origin gains nothing by opening it
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			go func() {
+				for {
+					var msg race.ClientMessage
+					if err := websocket.JSON.Receive(ws, &msg); err != nil {
+						session.Disconnect()
+						return
+					}
+					session.Send(msg)
+				}
+			}()
+
+			for event := range session.Events() {
+				if err := websocket.JSON.Send(ws, event); err != nil {
+					session.Disconnect()
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+
+	// the connection is hijacked, so only the log is left to write
+	logsData.Level = LogLevelInfo
+	logsData.Msg = "race connection closed"
+	logsData.Status = http.StatusSwitchingProtocols
+	logsData.Latency = logs.Duration(time.Since(start))
+	h.logsChan <- *logsData
+}
+
+// SpectateRaceHandler streams a race room as Server-Sent Events to anyone
+// holding its spectator token
+func (h *Handler) SpectateRaceHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	roomID := c.Param("id")
+	token := c.Query("token")
+
+	spectator, err := h.raceUseCase.Spectate(c.Request.Context(), roomID, token)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+	defer spectator.Close()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	// comments keep idle proxies from dropping the stream in a quiet lobby
+	heartbeat := time.NewTicker(spectatorHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-spectator.Events():
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+
+	logsData.Level = LogLevelInfo
+	logsData.Msg = "spectator stream closed"
+	logsData.Status = http.StatusOK
+	logsData.Latency = logs.Duration(time.Since(start))
+	h.logsChan <- *logsData
+}
+
+func (h *Handler) RaceHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	raceID := c.Param("id")
+
+	data, err := h.raceUseCase.Race(c.Request.Context(), raceID, email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "race fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) RatingHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.raceUseCase.Rating(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "rating fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) RatingHistoryHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.raceUseCase.RatingHistory(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "rating history fetched successfully", start, logsData, data)
+}
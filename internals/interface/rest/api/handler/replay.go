@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"time"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) ReplayHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	testID := c.Param("id")
+
+	data, err := h.replayUseCase.Replay(c.Request.Context(), email, testID)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "replay fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) PersonalBestReplayHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	mode := c.Query("mode")
+	language := c.Query("language")
+
+	data, err := h.replayUseCase.PersonalBestReplay(c.Request.Context(), email, mode, language)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "personal best replay fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) ShareReplayHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	testID := c.Param("id")
+
+	data, err := h.replayUseCase.ShareReplay(c.Request.Context(), email, testID)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "replay shared successfully", start, logsData, data)
+}
+
+// SharedReplayHandler serves public replay links, so it needs no login
+func (h *Handler) SharedReplayHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	token := c.Param("token")
+
+	data, err := h.replayUseCase.SharedReplay(c.Request.Context(), token)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "replay fetched successfully", start, logsData, data)
+}
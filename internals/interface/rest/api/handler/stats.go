@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"time"
+	"typing-speed/internals/core/stats"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) ProgressStatsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	filter := &stats.Filter{
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+		Bucket: c.Query("bucket"),
+		Mode:   c.Query("mode"),
+	}
+
+	logsData.RequestData = filter
+
+	data, err := h.statsUseCase.Progress(c.Request.Context(), email, filter)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "progress stats fetched successfully", start, logsData, data)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+	"typing-speed/internals/core/tournament"
+	"typing-speed/pkg/logs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) CreateTournamentHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var tournamentData tournament.Tournament
+	if err := c.ShouldBindJSON(&tournamentData); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = tournamentData
+
+	data, err := h.tournamentUseCase.CreateTournament(c.Request.Context(), email, &tournamentData)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "tournament created successfully", start, logsData, data)
+}
+
+func (h *Handler) TournamentsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	data, err := h.tournamentUseCase.Tournaments(c.Request.Context())
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "tournaments fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) RegisterTournamentHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	if err := h.tournamentUseCase.Register(c.Request.Context(), id, email); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "registered successfully", start, logsData, nil)
+}
+
+func (h *Handler) WithdrawTournamentHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	if err := h.tournamentUseCase.Withdraw(c.Request.Context(), id, email); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "withdrawn successfully", start, logsData, nil)
+}
+
+func (h *Handler) StartTournamentHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	data, err := h.tournamentUseCase.Start(c.Request.Context(), id, email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "tournament started successfully", start, logsData, data)
+}
+
+func (h *Handler) TournamentBracketHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	data, err := h.tournamentUseCase.Bracket(c.Request.Context(), id, email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "bracket fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) TournamentStandingsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	id := c.Param("id")
+
+	data, err := h.tournamentUseCase.Standings(c.Request.Context(), id, email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "standings fetched successfully", start, logsData, data)
+}
@@ -4,6 +4,20 @@ import (
 	"context"
 	"net/http"
 	"time"
+	"typing-speed/internals/core/achievement"
+	"typing-speed/internals/core/challenge"
+	"typing-speed/internals/core/classroom"
+	"typing-speed/internals/core/feed"
+	"typing-speed/internals/core/friend"
+	"typing-speed/internals/core/goal"
+	"typing-speed/internals/core/leaderboard"
+	"typing-speed/internals/core/organization"
+	"typing-speed/internals/core/profile"
+	"typing-speed/internals/core/race"
+	"typing-speed/internals/core/ranking"
+	"typing-speed/internals/core/replay"
+	"typing-speed/internals/core/stats"
+	"typing-speed/internals/core/tournament"
 	"typing-speed/internals/core/typing"
 	"typing-speed/internals/core/user"
 	"typing-speed/pkg/logs"
@@ -12,16 +26,49 @@ import (
 )
 
 type Handler struct {
-	typingUseCase typing.TypingService
-	userUseCase   user.UserService
-	logsChan      chan logs.LogEntry
+	typingUseCase       typing.TypingService
+	userUseCase         user.UserService
+	achievementUseCase  achievement.AchievementService
+	challengeUseCase    challenge.ChallengeService
+	goalUseCase         goal.GoalService
+	replayUseCase       replay.ReplayService
+	raceUseCase         race.RaceService
+	tournamentUseCase   tournament.TournamentService
+	friendUseCase       friend.FriendService
+	organizationUseCase organization.OrganizationService
+	classUseCase        classroom.ClassService
+	profileUseCase      profile.ProfileService
+	feedUseCase         feed.FeedService
+	leaderboardUseCase  leaderboard.LeaderboardService
+	rankingUseCase      ranking.RankingService
+	statsUseCase        stats.StatsService
+	logsChan            chan logs.LogEntry
 }
 
-func NewHandler(ty typing.TypingService, auth user.UserService, ch chan logs.LogEntry) Handler {
+func NewHandler(ty typing.TypingService, auth user.UserService, ach achievement.AchievementService,
+	chal challenge.ChallengeService, gl goal.GoalService, rep replay.ReplayService, rc race.RaceService,
+	tr tournament.TournamentService, fr friend.FriendService, org organization.OrganizationService,
+	cl classroom.ClassService, pr profile.ProfileService, fd feed.FeedService,
+	lb leaderboard.LeaderboardService, rk ranking.RankingService, st stats.StatsService,
+	ch chan logs.LogEntry) Handler {
 	return Handler{
-		typingUseCase: ty,
-		logsChan:      ch,
-		userUseCase:   auth,
+		typingUseCase:       ty,
+		logsChan:            ch,
+		userUseCase:         auth,
+		achievementUseCase:  ach,
+		challengeUseCase:    chal,
+		goalUseCase:         gl,
+		replayUseCase:       rep,
+		raceUseCase:         rc,
+		tournamentUseCase:   tr,
+		friendUseCase:       fr,
+		organizationUseCase: org,
+		classUseCase:        cl,
+		profileUseCase:      pr,
+		feedUseCase:         fd,
+		leaderboardUseCase:  lb,
+		rankingUseCase:      rk,
+		statsUseCase:        st,
 	}
 }
 
@@ -50,16 +97,19 @@ func (h *Handler) TypingDataHandler(c *gin.Context) {
 		return
 	}
 
-	err := h.typingUseCase.AddTestData(c.Request.Context(), &userData, email)
+	// race results are only submitted by the race rooms themselves
+	userData.RaceID = ""
+
+	result, err := h.typingUseCase.AddTestData(c.Request.Context(), &userData, email)
 	if err != nil {
 		h.handleServiceError(c, err, logsData, start)
 		return
 	}
 
-	h.respondSuccess(c, "user typing data saved successfully", start, logsData, nil)
+	h.respondSuccess(c, "user typing data saved successfully", start, logsData, result)
 }
 
-func (h *Handler) RecentTestDashboardHandler(c *gin.Context) {
+func (h *Handler) TestHistoryHandler(c *gin.Context) {
 	start := time.Now()
 
 	logsData := &logs.LogEntry{
@@ -71,20 +121,27 @@ func (h *Handler) RecentTestDashboardHandler(c *gin.Context) {
 	defer h.recoverPanic(c, start, logsData)
 
 	email := c.GetString("email")
-	month := c.Query("month")
+	filter := &typing.HistoryFilter{
+		Mode:        c.Query("mode"),
+		From:        c.Query("from"),
+		To:          c.Query("to"),
+		MinAccuracy: c.Query("minAccuracy"),
+		Sort:        c.Query("sort"),
+		Cursor:      c.Query("cursor"),
+		Limit:       c.Query("limit"),
+	}
+
+	logsData.RequestData = filter
 
-	data, err := h.typingUseCase.RecentTestForProfile(c.Request.Context(), email, month)
+	data, err := h.typingUseCase.History(c.Request.Context(), email, filter)
 	if err != nil {
 		h.handleServiceError(c, err, logsData, start)
 		return
 	}
 
-	if data == nil {
-		data = []*typing.TypingData{}
-	}
-
-	h.respondSuccess(c, "recent test fetched successfully", start, logsData, data)
+	h.respondSuccess(c, "test history fetched successfully", start, logsData, data)
 }
+
 func (h *Handler) SendWordsToType(c *gin.Context) {
 	start := time.Now()
 
@@ -100,3 +157,49 @@ func (h *Handler) SendWordsToType(c *gin.Context) {
 
 	h.respondSuccess(c, "typing data fetched successfully", start, logsData, data)
 }
+
+func (h *Handler) PersonalBestsHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.typingUseCase.PersonalBests(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "personal bests fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) PersonalBestHistoryHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	mode := c.Query("mode")
+	language := c.Query("language")
+
+	data, err := h.typingUseCase.PersonalBestHistory(c.Request.Context(), email, mode, language)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "personal best history fetched successfully", start, logsData, data)
+}
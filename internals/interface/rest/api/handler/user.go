@@ -21,16 +21,24 @@ func (h *Handler) RegisterUser(c *gin.Context) {
 
 	defer h.recoverPanic(c, start, logsData)
 
-	var userData user.User
-	if err := c.ShouldBindJSON(&userData); err != nil {
-		logsData.RequestData = userData
+	var req user.RegisterUser
+	if err := c.ShouldBindJSON(&req); err != nil {
 		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
 		return
 	}
 
+	userData := &user.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+		Username: req.Username,
+		Country:  req.Country,
+		Region:   req.Region,
+		IP:       c.ClientIP(),
+	}
 	logsData.RequestData = userData
 
-	if err := h.userUseCase.RegisterUser(c.Request.Context(), &userData); err != nil {
+	if err := h.userUseCase.RegisterUser(c.Request.Context(), userData); err != nil {
 		h.handleServiceError(c, err, logsData, start)
 		return
 	}
@@ -160,6 +168,51 @@ func (h *Handler) TopPerformerHandler(c *gin.Context) {
 	h.respondSuccess(c, "top performer fetched successfully", start, logsData, data)
 }
 
+func (h *Handler) TopPerformerRankHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	data, err := h.rankingUseCase.Rank(c.Request.Context(), email)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "rank fetched successfully", start, logsData, data)
+}
+
+func (h *Handler) TopPerformerAroundHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+	limit := c.Query("limit")
+
+	data, err := h.rankingUseCase.Around(c.Request.Context(), email, limit)
+	if err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "ranking fetched successfully", start, logsData, data)
+}
+
 func (h *Handler) DataForDashboardHandler(c *gin.Context) {
 	start := time.Now()
 
@@ -183,3 +236,127 @@ func (h *Handler) DataForDashboardHandler(c *gin.Context) {
 
 	h.respondSuccess(c, "user data fetched successfully", start, logsData, data)
 }
+
+func (h *Handler) UpdateTimeZoneHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var req struct {
+		TimeZone string `json:"timeZone"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	if err := h.userUseCase.UpdateTimeZone(c.Request.Context(), email, req.TimeZone); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "time zone updated successfully", start, logsData, nil)
+}
+
+func (h *Handler) UpdateUsernameHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var req struct {
+		Username string `json:"username"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	if err := h.userUseCase.UpdateUsername(c.Request.Context(), email, req.Username); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "username updated successfully", start, logsData, nil)
+}
+
+func (h *Handler) UpdatePrivacyHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var req struct {
+		Privacy string `json:"privacy"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	if err := h.userUseCase.UpdatePrivacy(c.Request.Context(), email, req.Privacy); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "privacy updated successfully", start, logsData, nil)
+}
+
+func (h *Handler) UpdateCountryHandler(c *gin.Context) {
+	start := time.Now()
+
+	logsData := &logs.LogEntry{
+		Time:   start,
+		Method: c.Request.Method,
+		Path:   c.FullPath(),
+	}
+
+	defer h.recoverPanic(c, start, logsData)
+
+	email := c.GetString("email")
+
+	var req struct {
+		Country string `json:"country"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid request body", err, start, logsData)
+		return
+	}
+
+	logsData.RequestData = req
+
+	if err := h.userUseCase.UpdateCountry(c.Request.Context(), email, req.Country); err != nil {
+		h.handleServiceError(c, err, logsData, start)
+		return
+	}
+
+	h.respondSuccess(c, "country updated successfully", start, logsData, nil)
+}
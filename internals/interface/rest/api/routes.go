@@ -42,6 +42,11 @@ func SetUpRoutes(handler handler.Handler) *gin.Engine {
 	auth.POST("/signup", handler.RegisterUser)
 	auth.POST("/signin", handler.LoginUser)
 
+	// shared replays are public links
+	app.GET("/replays/shared/:token", handler.SharedReplayHandler)
+	// spectators hold a token instead of an account
+	app.GET("/races/:id/spectate", handler.SpectateRaceHandler)
+
 	protected := app.Group("/")
 	protected.Use(middleware.AuthMiddleware())
 
@@ -49,11 +54,105 @@ func SetUpRoutes(handler handler.Handler) *gin.Engine {
 	api.POST("/typing", handler.TypingDataHandler)
 	api.GET("/userData", handler.UserByEmailHandler)
 	api.GET("/topPerformer", handler.TopPerformerHandler)
+	api.GET("/topPerformer/me", handler.TopPerformerRankHandler)
+	api.GET("/topPerformer/around", handler.TopPerformerAroundHandler)
+	api.GET("/leaderboard", handler.LeaderboardHandler)
+	api.GET("/leaderboard/me", handler.StandingsHandler)
+	api.GET("/leaderboard/countries", handler.CountriesHandler)
 	api.GET("/allUser", handler.DataForDashboardHandler)
 	api.GET("/typingWord", handler.SendWordsToType)
+	api.PUT("/timeZone", handler.UpdateTimeZoneHandler)
+	api.PUT("/username", handler.UpdateUsernameHandler)
+	api.PUT("/privacy", handler.UpdatePrivacyHandler)
+	api.PUT("/country", handler.UpdateCountryHandler)
+	api.GET("/profiles/:username", handler.ProfileHandler)
+	api.GET("/achievements", handler.AchievementsHandler)
+	api.GET("/dailyChallenge", handler.DailyChallengeHandler)
+	api.GET("/dailyChallenge/leaderboard", handler.ChallengeLeaderboardHandler)
+	api.GET("/dailyChallenge/history", handler.ChallengeHistoryHandler)
+	api.GET("/personalBests", handler.PersonalBestsHandler)
+	api.GET("/personalBests/history", handler.PersonalBestHistoryHandler)
+	api.POST("/goals", handler.CreateGoalHandler)
+	api.GET("/goals", handler.GoalsHandler)
+	api.GET("/goals/progress", handler.GoalsProgressHandler)
+	api.PUT("/goals/:id", handler.UpdateGoalHandler)
+	api.DELETE("/goals/:id", handler.DeleteGoalHandler)
+	api.GET("/replays/:id", handler.ReplayHandler)
+	api.POST("/replays/:id/share", handler.ShareReplayHandler)
+	api.GET("/personalBests/replay", handler.PersonalBestReplayHandler)
+	api.POST("/races", handler.CreateRaceRoomHandler)
+	api.GET("/races/:id", handler.RaceHandler)
+	api.GET("/ratings", handler.RatingHandler)
+	api.GET("/ratings/history", handler.RatingHistoryHandler)
+	api.POST("/tournaments", handler.CreateTournamentHandler)
+	api.GET("/tournaments", handler.TournamentsHandler)
+	api.POST("/tournaments/:id/register", handler.RegisterTournamentHandler)
+	api.DELETE("/tournaments/:id/register", handler.WithdrawTournamentHandler)
+	api.POST("/tournaments/:id/start", handler.StartTournamentHandler)
+	api.GET("/tournaments/:id", handler.TournamentBracketHandler)
+	api.GET("/tournaments/:id/standings", handler.TournamentStandingsHandler)
+	api.GET("/followers", handler.FollowersHandler)
+	api.GET("/following", handler.FollowingHandler)
+	api.POST("/following/:target", handler.FollowHandler)
+	api.DELETE("/following/:target", handler.UnfollowHandler)
+	api.GET("/relationships/:target", handler.RelationshipHandler)
+	api.GET("/friends", handler.FriendsHandler)
+	api.GET("/friends/leaderboard", handler.FriendsLeaderboardHandler)
+	api.GET("/friends/requests", handler.FriendRequestsHandler)
+	api.POST("/friends/requests", handler.SendFriendRequestHandler)
+	api.POST("/friends/requests/:id/accept", handler.AcceptFriendRequestHandler)
+	api.POST("/friends/requests/:id/decline", handler.DeclineFriendRequestHandler)
+	api.GET("/friends/:target/compare", handler.CompareFriendHandler)
+	api.DELETE("/friends/:target", handler.UnfriendHandler)
+	api.GET("/blocks", handler.BlockedHandler)
+	api.POST("/blocks/:target", handler.BlockHandler)
+	api.DELETE("/blocks/:target", handler.UnblockHandler)
+	api.GET("/feed", handler.FeedHandler)
+	api.GET("/feed/mutes", handler.MutedHandler)
+	api.POST("/feed/mutes/:target", handler.MuteHandler)
+	api.DELETE("/feed/mutes/:target", handler.UnmuteHandler)
+	api.POST("/organizations", handler.CreateOrganizationHandler)
+	api.GET("/organizations", handler.OrganizationsHandler)
+	api.GET("/organizations/:id", handler.OrganizationHandler)
+	api.PUT("/organizations/:id", handler.UpdateOrganizationHandler)
+	api.DELETE("/organizations/:id", handler.DeleteOrganizationHandler)
+	api.POST("/organizations/:id/domain/verify", handler.VerifyOrganizationDomainHandler)
+	api.POST("/organizations/:id/join", handler.JoinOrganizationHandler)
+	api.POST("/organizations/:id/leave", handler.LeaveOrganizationHandler)
+	api.POST("/organizations/:id/members", handler.AddOrganizationMemberHandler)
+	api.PUT("/organizations/:id/members/:member", handler.UpdateOrganizationMemberHandler)
+	api.DELETE("/organizations/:id/members/:member", handler.RemoveOrganizationMemberHandler)
+	api.GET("/organizations/:id/leaderboard", handler.OrganizationLeaderboardHandler)
+	api.GET("/organizations/:id/dashboard", handler.OrganizationDashboardHandler)
+	api.POST("/organizations/:id/teams", handler.CreateTeamHandler)
+	api.GET("/organizations/:id/teams/:team", handler.TeamHandler)
+	api.DELETE("/organizations/:id/teams/:team", handler.DeleteTeamHandler)
+	api.POST("/organizations/:id/teams/:team/members", handler.AddTeamMemberHandler)
+	api.DELETE("/organizations/:id/teams/:team/members/:member", handler.RemoveTeamMemberHandler)
+	api.GET("/organizations/:id/teams/:team/leaderboard", handler.TeamLeaderboardHandler)
+	api.GET("/organizations/:id/teams/:team/dashboard", handler.TeamDashboardHandler)
+	api.POST("/classes", handler.CreateClassHandler)
+	api.GET("/classes", handler.ClassesHandler)
+	api.POST("/classes/join", handler.JoinClassHandler)
+	api.GET("/classes/:id", handler.ClassHandler)
+	api.DELETE("/classes/:id", handler.DeleteClassHandler)
+	api.DELETE("/classes/:id/students/:student", handler.RemoveStudentHandler)
+	api.POST("/classes/:id/assignments", handler.CreateAssignmentHandler)
+	api.GET("/classes/:id/assignments/:assignment", handler.AssignmentHandler)
+	api.DELETE("/classes/:id/assignments/:assignment", handler.DeleteAssignmentHandler)
+	api.POST("/classes/:id/assignments/:assignment/submissions", handler.SubmitAssignmentHandler)
+	api.GET("/classes/:id/assignments/:assignment/submissions", handler.AssignmentSubmissionsHandler)
+	api.GET("/classes/:id/assignments/:assignment/report", handler.AssignmentReportHandler)
+
+	// browsers cannot send the Authorization header on a WebSocket handshake
+	ws := app.Group("/ws")
+	ws.Use(middleware.WebSocketAuthMiddleware())
+	ws.GET("/races/:id", handler.RaceRoomSocketHandler)
+	ws.GET("/quickRace", handler.QuickRaceSocketHandler)
 
 	dashboard := protected.Group("/dashboard")
-	dashboard.GET("/recentTest", handler.RecentTestDashboardHandler)
+	dashboard.GET("/tests", handler.TestHistoryHandler)
+	dashboard.GET("/stats", handler.ProgressStatsHandler)
 
 	return app
 }
@@ -0,0 +1,50 @@
+package achievement
+
+import (
+	"context"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/achievement"
+)
+
+type AchievementServiceImpl struct {
+	achievementSvc port.AchievementRepository
+}
+
+func NewAchievementService(svc port.AchievementRepository) achievement.AchievementService {
+	return &AchievementServiceImpl{
+		achievementSvc: svc,
+	}
+}
+
+// UserAchievements lists the earned achievements and the progress made towards the locked ones
+func (a *AchievementServiceImpl) UserAchievements(ctx context.Context, email string) (*achievement.UserAchievements, error) {
+	stats, err := a.achievementSvc.GetAchievementStats(ctx, email)
+	if err != nil {
+		return nil, achievement.ErrGettingDataFromDB
+	}
+
+	unlocked, err := a.achievementSvc.GetUnlockedAchievements(ctx, email)
+	if err != nil {
+		return nil, achievement.ErrGettingDataFromDB
+	}
+
+	unlockedAt := make(map[string]time.Time, len(unlocked))
+	for _, u := range unlocked {
+		unlockedAt[u.Code] = u.UnlockedAt
+	}
+
+	response := &achievement.UserAchievements{
+		Earned: []*achievement.Achievement{},
+		Locked: []*achievement.Achievement{},
+	}
+	for _, def := range achievement.Catalog {
+		if at, ok := unlockedAt[def.Code]; ok {
+			response.Earned = append(response.Earned, achievement.NewAchievement(def, stats, &at))
+			continue
+		}
+		response.Locked = append(response.Locked, achievement.NewAchievement(def, stats, nil))
+	}
+
+	return response, nil
+}
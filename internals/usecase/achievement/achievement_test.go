@@ -0,0 +1,85 @@
+package achievement
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"typing-speed/internals/core/achievement"
+)
+
+type FakeAchievementRepo struct {
+	StatsFn    func(ctx context.Context, email string) (*achievement.Stats, error)
+	UnlockedFn func(ctx context.Context, email string) ([]*achievement.Unlocked, error)
+}
+
+func (f *FakeAchievementRepo) GetAchievementStats(ctx context.Context, email string) (*achievement.Stats, error) {
+	if f.StatsFn != nil {
+		return f.StatsFn(ctx, email)
+	}
+	return &achievement.Stats{}, nil
+}
+
+func (f *FakeAchievementRepo) GetUnlockedAchievements(ctx context.Context, email string) ([]*achievement.Unlocked, error) {
+	if f.UnlockedFn != nil {
+		return f.UnlockedFn(ctx, email)
+	}
+	return nil, nil
+}
+
+func (f *FakeAchievementRepo) InsertUnlockedAchievements(ctx context.Context, email string, codes []string, unlockedAt time.Time) error {
+	return nil
+}
+
+func TestUserAchievements(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("db error", func(t *testing.T) {
+		service := NewAchievementService(&FakeAchievementRepo{
+			StatsFn: func(ctx context.Context, email string) (*achievement.Stats, error) {
+				return nil, errors.New("db error")
+			},
+		})
+
+		_, err := service.UserAchievements(ctx, "test@mail.com")
+		if err != achievement.ErrGettingDataFromDB {
+			t.Fatalf("expected %v, got %v", achievement.ErrGettingDataFromDB, err)
+		}
+	})
+
+	t.Run("earned and locked with progress", func(t *testing.T) {
+		service := NewAchievementService(&FakeAchievementRepo{
+			StatsFn: func(ctx context.Context, email string) (*achievement.Stats, error) {
+				return &achievement.Stats{TotalTests: 250, BestWPM: 75, LongestStreak: 3}, nil
+			},
+			UnlockedFn: func(ctx context.Context, email string) ([]*achievement.Unlocked, error) {
+				return []*achievement.Unlocked{
+					{Code: "first_test", UnlockedAt: time.Now()},
+					{Code: "tests_100", UnlockedAt: time.Now()},
+					{Code: "wpm_50", UnlockedAt: time.Now()},
+				}, nil
+			},
+		})
+
+		data, err := service.UserAchievements(ctx, "test@mail.com")
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if len(data.Earned) != 3 || len(data.Locked) != len(achievement.Catalog)-3 {
+			t.Fatalf("expected 3 earned, got %d earned %d locked", len(data.Earned), len(data.Locked))
+		}
+
+		for _, a := range data.Locked {
+			switch a.Code {
+			case "tests_1000":
+				if a.Progress != 250 || a.Percent != 25 {
+					t.Fatalf("expected 250/1000 (25%%), got %d (%d%%)", a.Progress, a.Percent)
+				}
+			case "wpm_100":
+				if a.Progress != 75 || a.Percent != 75 {
+					t.Fatalf("expected 75/100 (75%%), got %d (%d%%)", a.Progress, a.Percent)
+				}
+			}
+		}
+	})
+}
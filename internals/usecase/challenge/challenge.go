@@ -0,0 +1,61 @@
+package challenge
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/challenge"
+)
+
+type ChallengeServiceImpl struct {
+	challengeSvc port.ChallengeRepository
+}
+
+func NewChallengeService(svc port.ChallengeRepository) challenge.ChallengeService {
+	return &ChallengeServiceImpl{
+		challengeSvc: svc,
+	}
+}
+
+func (c *ChallengeServiceImpl) TodayChallenge(ctx context.Context) *challenge.DailyChallenge {
+	return challenge.ForDate(challenge.DateKey(time.Now()))
+}
+
+// Leaderboard ranks the first attempts at the challenge of date, today's when date is empty
+func (c *ChallengeServiceImpl) Leaderboard(ctx context.Context, date string) ([]*challenge.LeaderboardEntry, error) {
+	if date == "" {
+		date = challenge.DateKey(time.Now())
+	}
+	if _, err := challenge.ParseDate(date); err != nil {
+		return nil, err
+	}
+
+	data, err := c.challengeSvc.GetChallengeLeaderboard(ctx, date, challenge.LeaderboardLimit)
+	if err != nil {
+		return nil, challenge.ErrGettingDataFromDB
+	}
+	return data, nil
+}
+
+// History lists past challenges with their participation and winner
+func (c *ChallengeServiceImpl) History(ctx context.Context, limit string) ([]*challenge.Summary, error) {
+	l := challenge.HistoryLimit
+	if limit != "" {
+		var err error
+		l, err = strconv.Atoi(limit)
+		if err != nil || l <= 0 || l > challenge.HistoryLimit {
+			return nil, challenge.ErrInvalidLimit
+		}
+	}
+
+	data, err := c.challengeSvc.GetChallengeHistory(ctx, challenge.DateKey(time.Now()), l)
+	if err != nil {
+		return nil, challenge.ErrGettingDataFromDB
+	}
+
+	for _, s := range data {
+		s.Text = challenge.GenerateText(s.Date)
+	}
+	return data, nil
+}
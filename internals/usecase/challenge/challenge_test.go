@@ -0,0 +1,114 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"typing-speed/internals/core/challenge"
+)
+
+type FakeChallengeRepo struct {
+	LeaderboardFn func(ctx context.Context, date string, limit int) ([]*challenge.LeaderboardEntry, error)
+	HistoryFn     func(ctx context.Context, before string, limit int) ([]*challenge.Summary, error)
+}
+
+func (f *FakeChallengeRepo) GetChallengeLeaderboard(ctx context.Context, date string, limit int) ([]*challenge.LeaderboardEntry, error) {
+	if f.LeaderboardFn != nil {
+		return f.LeaderboardFn(ctx, date, limit)
+	}
+	return nil, nil
+}
+
+func (f *FakeChallengeRepo) GetChallengeHistory(ctx context.Context, before string, limit int) ([]*challenge.Summary, error) {
+	if f.HistoryFn != nil {
+		return f.HistoryFn(ctx, before, limit)
+	}
+	return nil, nil
+}
+
+func TestTodayChallenge(t *testing.T) {
+	service := NewChallengeService(&FakeChallengeRepo{})
+
+	first := service.TodayChallenge(context.Background())
+	second := service.TodayChallenge(context.Background())
+
+	if first.Date != time.Now().UTC().Format(challenge.DateLayout) {
+		t.Fatalf("expected today's UTC date, got %s", first.Date)
+	}
+	if first.Text == "" || first.Text != second.Text {
+		t.Fatalf("expected the same non-empty text for the same day")
+	}
+	if challenge.GenerateText("2026-01-01") == challenge.GenerateText("2026-01-02") {
+		t.Fatalf("expected different days to get different texts")
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		date          string
+		repo          *FakeChallengeRepo
+		expectedDate  string
+		expectedError error
+	}{
+		{name: "invalid date", date: "19-10-2026", repo: &FakeChallengeRepo{}, expectedError: challenge.ErrInvalidDate},
+		{
+			name: "db error",
+			date: "2026-10-01",
+			repo: &FakeChallengeRepo{
+				LeaderboardFn: func(ctx context.Context, date string, limit int) ([]*challenge.LeaderboardEntry, error) {
+					return nil, errors.New("db error")
+				},
+			},
+			expectedError: challenge.ErrGettingDataFromDB,
+		},
+		{name: "defaults to today", date: "", repo: &FakeChallengeRepo{}, expectedDate: challenge.DateKey(time.Now())},
+		{name: "past date", date: "2026-10-01", repo: &FakeChallengeRepo{}, expectedDate: "2026-10-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDate string
+			if tt.repo.LeaderboardFn == nil {
+				tt.repo.LeaderboardFn = func(ctx context.Context, date string, limit int) ([]*challenge.LeaderboardEntry, error) {
+					gotDate = date
+					return []*challenge.LeaderboardEntry{}, nil
+				}
+			}
+			service := NewChallengeService(tt.repo)
+
+			_, err := service.Leaderboard(ctx, tt.date)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectedError == nil && gotDate != tt.expectedDate {
+				t.Fatalf("expected date %s, got %s", tt.expectedDate, gotDate)
+			}
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+
+	service := NewChallengeService(&FakeChallengeRepo{
+		HistoryFn: func(ctx context.Context, before string, limit int) ([]*challenge.Summary, error) {
+			return []*challenge.Summary{{Date: "2026-10-01", Participants: 3}}, nil
+		},
+	})
+
+	if _, err := service.History(ctx, "abc"); err != challenge.ErrInvalidLimit {
+		t.Fatalf("expected %v, got %v", challenge.ErrInvalidLimit, err)
+	}
+
+	data, err := service.History(ctx, "")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if data[0].Text != challenge.GenerateText("2026-10-01") {
+		t.Fatalf("expected history to carry the challenge text")
+	}
+}
@@ -0,0 +1,413 @@
+package classroom
+
+import (
+	"context"
+	"strings"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/classroom"
+	"typing-speed/internals/core/typing"
+
+	"github.com/google/uuid"
+)
+
+// joinCodeAttempts is how many codes are tried before giving up on a clash
+const joinCodeAttempts = 3
+
+type ClassServiceImpl struct {
+	classSvc  port.ClassRepository
+	typingSvc typing.TypingService
+}
+
+func NewClassService(classes port.ClassRepository, typingSvc typing.TypingService) classroom.ClassService {
+	return &ClassServiceImpl{
+		classSvc:  classes,
+		typingSvc: typingSvc,
+	}
+}
+
+// class looks up the class and the user's role in it: the teacher, or one
+// of the students
+func (s *ClassServiceImpl) class(ctx context.Context, email string, id string) (*classroom.Class, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, classroom.ErrClassNotFound
+	}
+
+	c, err := s.classSvc.GetClass(ctx, id)
+	if err != nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+	if c == nil {
+		return nil, classroom.ErrClassNotFound
+	}
+
+	if c.Teacher == email {
+		c.Role = classroom.RoleTeacher
+		return c, nil
+	}
+
+	student, err := s.classSvc.GetStudent(ctx, id, email)
+	if err != nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+	if student == nil {
+		return nil, classroom.ErrNotEnrolled
+	}
+
+	// only the teacher hands out the code
+	c.Role = classroom.RoleStudent
+	c.JoinCode = ""
+	return c, nil
+}
+
+// teacher is class for the teacher only
+func (s *ClassServiceImpl) teacher(ctx context.Context, email string, id string) (*classroom.Class, error) {
+	c, err := s.class(ctx, email, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Role != classroom.RoleTeacher {
+		return nil, classroom.ErrNotTeacher
+	}
+	return c, nil
+}
+
+func (s *ClassServiceImpl) assignment(ctx context.Context, id string, assignmentID string) (*classroom.Assignment, error) {
+	if _, err := uuid.Parse(assignmentID); err != nil {
+		return nil, classroom.ErrAssignmentNotFound
+	}
+
+	a, err := s.classSvc.GetAssignment(ctx, id, assignmentID)
+	if err != nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+	if a == nil {
+		return nil, classroom.ErrAssignmentNotFound
+	}
+	return a, nil
+}
+
+// CreateClass makes the user the teacher of a new class with a fresh join code
+func (s *ClassServiceImpl) CreateClass(ctx context.Context, email string, c *classroom.Class) (*classroom.Class, error) {
+	name := strings.TrimSpace(c.Name)
+	if name == "" || len(name) > classroom.MaxNameLength {
+		return nil, classroom.ErrInvalidClass
+	}
+
+	code, err := s.newJoinCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	class := &classroom.Class{ID: uuid.NewString(), Name: name, Teacher: email, JoinCode: code}
+	if err := s.classSvc.CreateClass(ctx, class); err != nil {
+		return nil, classroom.ErrSomethingWentWrong
+	}
+
+	created, err := s.classSvc.GetClass(ctx, class.ID)
+	if err != nil || created == nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+	created.Role = classroom.RoleTeacher
+	return created, nil
+}
+
+// newJoinCode draws codes until one is not taken by another class
+func (s *ClassServiceImpl) newJoinCode(ctx context.Context) (string, error) {
+	for range joinCodeAttempts {
+		code, err := classroom.NewJoinCode()
+		if err != nil {
+			return "", classroom.ErrSomethingWentWrong
+		}
+
+		existing, err := s.classSvc.GetClassByJoinCode(ctx, code)
+		if err != nil {
+			return "", classroom.ErrGettingDataFromDB
+		}
+		if existing == nil {
+			return code, nil
+		}
+	}
+	return "", classroom.ErrSomethingWentWrong
+}
+
+// Classes returns the classes the user teaches or is enrolled in
+func (s *ClassServiceImpl) Classes(ctx context.Context, email string) ([]*classroom.Class, error) {
+	classes, err := s.classSvc.GetClassesByEmail(ctx, email)
+	if err != nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+	for _, c := range classes {
+		if c.Role != classroom.RoleTeacher {
+			c.JoinCode = ""
+		}
+	}
+	return classes, nil
+}
+
+// Class shows the teacher the students and the assignments, and a student
+// the assignments with the ones they completed marked
+func (s *ClassServiceImpl) Class(ctx context.Context, email string, id string) (*classroom.ClassDetails, error) {
+	c, err := s.class(ctx, email, id)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.classSvc.GetAssignments(ctx, id)
+	if err != nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+	details := &classroom.ClassDetails{Class: c, Assignments: assignments}
+
+	if c.Role == classroom.RoleTeacher {
+		students, err := s.classSvc.GetStudents(ctx, id)
+		if err != nil {
+			return nil, classroom.ErrGettingDataFromDB
+		}
+		details.Students = students
+		return details, nil
+	}
+
+	completed, err := s.classSvc.GetCompletedAssignments(ctx, id, email)
+	if err != nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+	done := map[string]bool{}
+	for _, assignmentID := range completed {
+		done[assignmentID] = true
+	}
+	for _, a := range assignments {
+		completed := done[a.ID]
+		a.Completed = &completed
+	}
+	return details, nil
+}
+
+func (s *ClassServiceImpl) DeleteClass(ctx context.Context, email string, id string) error {
+	if _, err := s.teacher(ctx, email, id); err != nil {
+		return err
+	}
+
+	if err := s.classSvc.DeleteClass(ctx, id); err != nil {
+		return classroom.ErrSomethingWentWrong
+	}
+	return nil
+}
+
+// Join enrolls the user in the class with the join code
+func (s *ClassServiceImpl) Join(ctx context.Context, email string, code string) (*classroom.Class, error) {
+	code = classroom.NormalizeJoinCode(code)
+	if len(code) != classroom.JoinCodeLength {
+		return nil, classroom.ErrInvalidJoinCode
+	}
+
+	c, err := s.classSvc.GetClassByJoinCode(ctx, code)
+	if err != nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+	if c == nil {
+		return nil, classroom.ErrInvalidJoinCode
+	}
+	if c.Teacher == email {
+		return nil, classroom.ErrTeacher
+	}
+
+	added, err := s.classSvc.AddStudent(ctx, c.ID, email)
+	if err != nil {
+		return nil, classroom.ErrSomethingWentWrong
+	}
+	if !added {
+		return nil, classroom.ErrAlreadyEnrolled
+	}
+
+	c.Students++
+	c.Role = classroom.RoleStudent
+	c.JoinCode = ""
+	return c, nil
+}
+
+// RemoveStudent is for the teacher, and for students leaving the class
+func (s *ClassServiceImpl) RemoveStudent(ctx context.Context, email string, id string, student string) error {
+	c, err := s.class(ctx, email, id)
+	if err != nil {
+		return err
+	}
+	if c.Role != classroom.RoleTeacher && student != email {
+		return classroom.ErrNotTeacher
+	}
+
+	removed, err := s.classSvc.RemoveStudent(ctx, id, student)
+	if err != nil {
+		return classroom.ErrSomethingWentWrong
+	}
+	if !removed {
+		return classroom.ErrStudentNotFound
+	}
+	return nil
+}
+
+func (s *ClassServiceImpl) CreateAssignment(ctx context.Context, email string, id string,
+	a *classroom.Assignment) (*classroom.Assignment, error) {
+	if _, err := s.teacher(ctx, email, id); err != nil {
+		return nil, err
+	}
+
+	assignment := &classroom.Assignment{
+		ID:          uuid.NewString(),
+		ClassID:     id,
+		Title:       a.Title,
+		Mode:        a.Mode,
+		Text:        a.Text,
+		MinAccuracy: a.MinAccuracy,
+		DueAt:       a.DueAt,
+	}
+	if err := classroom.NormalizeAssignment(assignment, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.classSvc.CreateAssignment(ctx, assignment); err != nil {
+		return nil, classroom.ErrSomethingWentWrong
+	}
+	return assignment, nil
+}
+
+func (s *ClassServiceImpl) Assignment(ctx context.Context, email string, id string,
+	assignmentID string) (*classroom.Assignment, error) {
+	c, err := s.class(ctx, email, id)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.assignment(ctx, id, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Role == classroom.RoleStudent {
+		submissions, err := s.classSvc.GetSubmissions(ctx, assignmentID, email)
+		if err != nil {
+			return nil, classroom.ErrGettingDataFromDB
+		}
+		completed := false
+		for _, sub := range submissions {
+			completed = completed || sub.Passed
+		}
+		a.Completed = &completed
+	}
+	return a, nil
+}
+
+func (s *ClassServiceImpl) DeleteAssignment(ctx context.Context, email string, id string, assignmentID string) error {
+	if _, err := s.teacher(ctx, email, id); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(assignmentID); err != nil {
+		return classroom.ErrAssignmentNotFound
+	}
+
+	removed, err := s.classSvc.DeleteAssignment(ctx, id, assignmentID)
+	if err != nil {
+		return classroom.ErrSomethingWentWrong
+	}
+	if !removed {
+		return classroom.ErrAssignmentNotFound
+	}
+	return nil
+}
+
+// Submit records a test the student took for the assignment. It counts as
+// a regular test too, and it passes when it meets the minimum accuracy.
+func (s *ClassServiceImpl) Submit(ctx context.Context, email string, id string, assignmentID string,
+	data *typing.TypingData) (*classroom.SubmissionResult, error) {
+	c, err := s.class(ctx, email, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Role != classroom.RoleStudent {
+		return nil, classroom.ErrNotEnrolled
+	}
+
+	a, err := s.assignment(ctx, id, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(a.DueAt) {
+		return nil, classroom.ErrAssignmentClosed
+	}
+
+	if data.Mode == "" {
+		data.Mode = a.Mode
+	}
+	if data.Mode != a.Mode {
+		return nil, classroom.ErrModeMismatch
+	}
+	// an assignment is neither a race nor the daily challenge
+	data.RaceID = ""
+	data.ChallengeDate = ""
+
+	// the test commits on its own first, so its feed events and ranking only
+	// ever follow a saved test, and a failed submission still keeps it
+	test, err := s.typingSvc.AddTestData(ctx, data, email)
+	if err != nil {
+		return nil, err
+	}
+
+	accuracy := typing.Accuracy(data)
+	submission := &classroom.Submission{
+		AssignmentID: a.ID,
+		Email:        email,
+		TestID:       test.TestID,
+		WPM:          data.WPM,
+		Accuracy:     accuracy,
+		Passed:       accuracy >= a.MinAccuracy,
+	}
+	if err := s.classSvc.InsertSubmission(ctx, submission); err != nil {
+		return nil, classroom.ErrSomethingWentWrong
+	}
+
+	return &classroom.SubmissionResult{Submission: submission, Test: test}, nil
+}
+
+// Submissions shows the teacher every submission for the assignment, and a
+// student their own
+func (s *ClassServiceImpl) Submissions(ctx context.Context, email string, id string,
+	assignmentID string) ([]*classroom.Submission, error) {
+	c, err := s.class(ctx, email, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.assignment(ctx, id, assignmentID); err != nil {
+		return nil, err
+	}
+
+	of := email
+	if c.Role == classroom.RoleTeacher {
+		of = ""
+	}
+	submissions, err := s.classSvc.GetSubmissions(ctx, assignmentID, of)
+	if err != nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+	return submissions, nil
+}
+
+// Report shows the teacher how every student in the class did on the assignment
+func (s *ClassServiceImpl) Report(ctx context.Context, email string, id string, assignmentID string) (*classroom.Report, error) {
+	if _, err := s.teacher(ctx, email, id); err != nil {
+		return nil, err
+	}
+	a, err := s.assignment(ctx, id, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.classSvc.GetStudents(ctx, id)
+	if err != nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+	submissions, err := s.classSvc.GetSubmissions(ctx, assignmentID, "")
+	if err != nil {
+		return nil, classroom.ErrGettingDataFromDB
+	}
+
+	return classroom.NewReport(a, students, submissions), nil
+}
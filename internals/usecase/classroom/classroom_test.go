@@ -0,0 +1,329 @@
+package classroom
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/classroom"
+	"typing-speed/internals/core/typing"
+
+	"github.com/google/uuid"
+)
+
+// FakeClassRepo keeps classes, students, assignments and submissions in memory
+type FakeClassRepo struct {
+	port.ClassRepository
+	classes     map[string]*classroom.Class
+	students    map[string][]*classroom.Student
+	assignments map[string]*classroom.Assignment
+	submissions []*classroom.Submission
+	submitErr   error
+}
+
+func newFakeClassRepo() *FakeClassRepo {
+	return &FakeClassRepo{
+		classes:     map[string]*classroom.Class{},
+		students:    map[string][]*classroom.Student{},
+		assignments: map[string]*classroom.Assignment{},
+	}
+}
+
+func (f *FakeClassRepo) CreateClass(ctx context.Context, c *classroom.Class) error {
+	copied := *c
+	f.classes[c.ID] = &copied
+	return nil
+}
+
+func (f *FakeClassRepo) GetClass(ctx context.Context, id string) (*classroom.Class, error) {
+	c, ok := f.classes[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *c
+	copied.Students = len(f.students[id])
+	return &copied, nil
+}
+
+func (f *FakeClassRepo) GetClassByJoinCode(ctx context.Context, code string) (*classroom.Class, error) {
+	for id, c := range f.classes {
+		if c.JoinCode == code {
+			return f.GetClass(ctx, id)
+		}
+	}
+	return nil, nil
+}
+
+func (f *FakeClassRepo) AddStudent(ctx context.Context, id string, email string) (bool, error) {
+	if s, _ := f.GetStudent(ctx, id, email); s != nil {
+		return false, nil
+	}
+	f.students[id] = append(f.students[id], &classroom.Student{Name: strings.Split(email, "@")[0], Email: email})
+	return true, nil
+}
+
+func (f *FakeClassRepo) GetStudent(ctx context.Context, id string, email string) (*classroom.Student, error) {
+	for _, s := range f.students[id] {
+		if s.Email == email {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *FakeClassRepo) GetStudents(ctx context.Context, id string) ([]*classroom.Student, error) {
+	return f.students[id], nil
+}
+
+func (f *FakeClassRepo) RemoveStudent(ctx context.Context, id string, email string) (bool, error) {
+	for i, s := range f.students[id] {
+		if s.Email == email {
+			f.students[id] = append(f.students[id][:i], f.students[id][i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *FakeClassRepo) CreateAssignment(ctx context.Context, a *classroom.Assignment) error {
+	copied := *a
+	f.assignments[a.ID] = &copied
+	return nil
+}
+
+func (f *FakeClassRepo) GetAssignment(ctx context.Context, id string, assignmentID string) (*classroom.Assignment, error) {
+	a, ok := f.assignments[assignmentID]
+	if !ok || a.ClassID != id {
+		return nil, nil
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (f *FakeClassRepo) GetAssignments(ctx context.Context, id string) ([]*classroom.Assignment, error) {
+	assignments := []*classroom.Assignment{}
+	for _, a := range f.assignments {
+		if a.ClassID == id {
+			copied := *a
+			assignments = append(assignments, &copied)
+		}
+	}
+	return assignments, nil
+}
+
+func (f *FakeClassRepo) GetCompletedAssignments(ctx context.Context, id string, email string) ([]string, error) {
+	ids := []string{}
+	for _, s := range f.submissions {
+		if s.Email == email && s.Passed && f.assignments[s.AssignmentID].ClassID == id {
+			ids = append(ids, s.AssignmentID)
+		}
+	}
+	return ids, nil
+}
+
+func (f *FakeClassRepo) InsertSubmission(ctx context.Context, s *classroom.Submission) error {
+	if f.submitErr != nil {
+		return f.submitErr
+	}
+	s.SubmittedAt = time.Now()
+	f.submissions = append(f.submissions, s)
+	return nil
+}
+
+func (f *FakeClassRepo) GetSubmissions(ctx context.Context, assignmentID string, email string) ([]*classroom.Submission, error) {
+	submissions := []*classroom.Submission{}
+	for _, s := range f.submissions {
+		if s.AssignmentID == assignmentID && (email == "" || s.Email == email) {
+			submissions = append(submissions, s)
+		}
+	}
+	return submissions, nil
+}
+
+// FakeTypingService saves every test it is given
+type FakeTypingService struct {
+	typing.TypingService
+	tests []*typing.TypingData
+}
+
+func (f *FakeTypingService) AddTestData(ctx context.Context, data *typing.TypingData, email string) (*typing.TestResult, error) {
+	if err := typing.NormalizeTestData(data); err != nil {
+		return nil, err
+	}
+	data.ID = uuid.NewString()
+	f.tests = append(f.tests, data)
+	return &typing.TestResult{TestID: data.ID}, nil
+}
+
+func newTestService() (classroom.ClassService, *FakeClassRepo, *FakeTypingService) {
+	repo := newFakeClassRepo()
+	tests := &FakeTypingService{}
+	return NewClassService(repo, tests), repo, tests
+}
+
+func TestJoinClass(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	if _, err := svc.CreateClass(ctx, "teacher@school.io", &classroom.Class{Name: " "}); err != classroom.ErrInvalidClass {
+		t.Fatalf("expected %v, got %v", classroom.ErrInvalidClass, err)
+	}
+	class, err := svc.CreateClass(ctx, "teacher@school.io", &classroom.Class{Name: "Year 7"})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(class.JoinCode) != classroom.JoinCodeLength || class.Role != classroom.RoleTeacher {
+		t.Fatalf("unexpected class %+v", class)
+	}
+
+	tests := []struct {
+		name          string
+		email         string
+		code          string
+		expectedError error
+	}{
+		{name: "wrong code", email: "amy@school.io", code: "AAAAAAAA", expectedError: classroom.ErrInvalidJoinCode},
+		{name: "teacher", email: "teacher@school.io", code: class.JoinCode, expectedError: classroom.ErrTeacher},
+		{name: "success", email: "amy@school.io", code: " " + strings.ToLower(class.JoinCode) + " "},
+		{name: "twice", email: "amy@school.io", code: class.JoinCode, expectedError: classroom.ErrAlreadyEnrolled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joined, err := svc.Join(ctx, tt.email, tt.code)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if err == nil && joined.JoinCode != "" {
+				t.Fatalf("expected the join code to be hidden from students")
+			}
+		})
+	}
+
+	details, err := svc.Class(ctx, "amy@school.io", class.ID)
+	if err != nil || details.Class.JoinCode != "" || details.Students != nil {
+		t.Fatalf("expected students not to see the code or the roster, got %+v, %v", details, err)
+	}
+	if _, err := svc.Class(ctx, "bob@school.io", class.ID); err != classroom.ErrNotEnrolled {
+		t.Fatalf("expected %v, got %v", classroom.ErrNotEnrolled, err)
+	}
+}
+
+func TestSubmitAssignment(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, tests := newTestService()
+
+	class, _ := svc.CreateClass(ctx, "teacher@school.io", &classroom.Class{Name: "Year 7"})
+	svc.Join(ctx, "amy@school.io", class.JoinCode)
+
+	due := time.Now().Add(24 * time.Hour)
+	assignment := &classroom.Assignment{Title: "Home row", Mode: typing.Mode30s, Text: "asdf jkl;", MinAccuracy: 90,
+		DueAt: due}
+	if _, err := svc.CreateAssignment(ctx, "amy@school.io", class.ID, assignment); err != classroom.ErrNotTeacher {
+		t.Fatalf("expected %v, got %v", classroom.ErrNotTeacher, err)
+	}
+	late := *assignment
+	late.DueAt = time.Now().Add(-time.Hour)
+	if _, err := svc.CreateAssignment(ctx, "teacher@school.io", class.ID, &late); err != classroom.ErrInvalidAssignment {
+		t.Fatalf("expected %v, got %v", classroom.ErrInvalidAssignment, err)
+	}
+	created, err := svc.CreateAssignment(ctx, "teacher@school.io", class.ID, assignment)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	test := func(mode string, errors int) *typing.TypingData {
+		return &typing.TypingData{Mode: mode, WPM: 40, TotalWords: 100, TypedWords: 100, TotalErrors: errors,
+			TotalTime: 30, TimeTakenByUser: 30}
+	}
+
+	if _, err := svc.Submit(ctx, "teacher@school.io", class.ID, created.ID, test("", 0)); err != classroom.ErrNotEnrolled {
+		t.Fatalf("expected %v, got %v", classroom.ErrNotEnrolled, err)
+	}
+	if _, err := svc.Submit(ctx, "amy@school.io", class.ID, created.ID, test(typing.Mode60s, 0)); err != classroom.ErrModeMismatch {
+		t.Fatalf("expected %v, got %v", classroom.ErrModeMismatch, err)
+	}
+
+	// too many mistakes is an attempt but does not complete the assignment
+	result, err := svc.Submit(ctx, "amy@school.io", class.ID, created.ID, test("", 20))
+	if err != nil || result.Submission.Passed || result.Submission.Accuracy != 80 {
+		t.Fatalf("expected a failed attempt, got %+v, %v", result, err)
+	}
+	got, _ := svc.Assignment(ctx, "amy@school.io", class.ID, created.ID)
+	if got.Completed == nil || *got.Completed {
+		t.Fatalf("expected the assignment not to be completed yet")
+	}
+
+	result, err = svc.Submit(ctx, "amy@school.io", class.ID, created.ID, test("", 5))
+	if err != nil || !result.Submission.Passed || result.Submission.TestID != result.Test.TestID {
+		t.Fatalf("expected a passing submission linked to its test, got %+v, %v", result, err)
+	}
+	if len(tests.tests) != 2 || tests.tests[1].Mode != typing.Mode30s {
+		t.Fatalf("expected both attempts to be saved as 30s tests, got %d", len(tests.tests))
+	}
+
+	// the test is kept when the submission cannot be saved
+	repo.submitErr = errors.New("connection reset")
+	if _, err := svc.Submit(ctx, "amy@school.io", class.ID, created.ID, test("", 0)); err != classroom.ErrSomethingWentWrong {
+		t.Fatalf("expected %v, got %v", classroom.ErrSomethingWentWrong, err)
+	}
+	if len(tests.tests) != 3 || len(repo.submissions) != 2 {
+		t.Fatalf("expected the test to be saved without a submission, got %d tests", len(tests.tests))
+	}
+	repo.submitErr = nil
+
+	// the due date has passed
+	repo.assignments[created.ID].DueAt = time.Now().Add(-time.Minute)
+	if _, err := svc.Submit(ctx, "amy@school.io", class.ID, created.ID, test("", 0)); err != classroom.ErrAssignmentClosed {
+		t.Fatalf("expected %v, got %v", classroom.ErrAssignmentClosed, err)
+	}
+}
+
+func TestAssignmentReport(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newTestService()
+
+	class, _ := svc.CreateClass(ctx, "teacher@school.io", &classroom.Class{Name: "Year 7"})
+	svc.Join(ctx, "amy@school.io", class.JoinCode)
+	svc.Join(ctx, "=bob@school.io", class.JoinCode)
+	assignment, _ := svc.CreateAssignment(ctx, "teacher@school.io", class.ID, &classroom.Assignment{Title: "Home row",
+		Mode: typing.Mode30s, Text: "asdf jkl;", MinAccuracy: 90, DueAt: time.Now().Add(time.Hour)})
+
+	first := time.Now().Add(-time.Minute)
+	repo.submissions = []*classroom.Submission{
+		{AssignmentID: assignment.ID, Email: "amy@school.io", WPM: 55, Accuracy: 85},
+		{AssignmentID: assignment.ID, Email: "amy@school.io", WPM: 45, Accuracy: 96, Passed: true, SubmittedAt: first},
+		{AssignmentID: assignment.ID, Email: "amy@school.io", WPM: 50, Accuracy: 92, Passed: true,
+			SubmittedAt: time.Now()},
+	}
+
+	if _, err := svc.Report(ctx, "amy@school.io", class.ID, assignment.ID); err != classroom.ErrNotTeacher {
+		t.Fatalf("expected %v, got %v", classroom.ErrNotTeacher, err)
+	}
+	report, err := svc.Report(ctx, "teacher@school.io", class.ID, assignment.ID)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if report.Students != 2 || report.Completed != 1 || len(report.Rows) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	amy, bob := report.Rows[0], report.Rows[1]
+	if amy.Attempts != 3 || !amy.Completed || amy.BestWPM != 55 || amy.BestAccuracy != 96 || !amy.CompletedAt.Equal(first) {
+		t.Fatalf("unexpected row %+v", amy)
+	}
+	if bob.Attempts != 0 || bob.Completed || bob.CompletedAt != nil {
+		t.Fatalf("expected students who did nothing to be listed, got %+v", bob)
+	}
+
+	csv, err := report.CSV()
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "amy,amy@school.io,3,true,55,96,") ||
+		lines[2] != "'=bob,'=bob@school.io,0,false,0,0," {
+		t.Fatalf("unexpected csv %q", csv)
+	}
+}
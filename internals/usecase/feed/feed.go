@@ -0,0 +1,113 @@
+package feed
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/feed"
+)
+
+type FeedServiceImpl struct {
+	feedSvc port.FeedRepository
+	userSvc port.UserRepository
+	txSvc   port.Transactor
+}
+
+func NewFeedService(feeds port.FeedRepository, users port.UserRepository, tx port.Transactor) feed.FeedService {
+	return &FeedServiceImpl{
+		feedSvc: feeds,
+		userSvc: users,
+		txSvc:   tx,
+	}
+}
+
+// Publish stores the events and copies each into the feeds of its actor's
+// followers, trimming the feeds it went into to their cap
+func (s *FeedServiceImpl) Publish(ctx context.Context, events ...*feed.Event) error {
+	for _, e := range events {
+		err := s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.feedSvc.InsertEvent(ctx, e); err != nil {
+				return err
+			}
+			followers, err := s.feedSvc.FanOut(ctx, e, feed.FanOutLimit)
+			if err != nil {
+				return err
+			}
+			if len(followers) == 0 {
+				return nil
+			}
+			return s.feedSvc.TrimFeeds(ctx, followers, feed.FeedLimit)
+		})
+		if err != nil {
+			return feed.ErrSomethingWentWrong
+		}
+	}
+	return nil
+}
+
+// Feed returns a page of the milestones of the people the user follows,
+// latest first. The cursor is the NextCursor of the page before.
+func (s *FeedServiceImpl) Feed(ctx context.Context, email string, cursor string, limit string) (*feed.Page, error) {
+	before, err := feed.ParseCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	l, err := feed.PageLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// one extra item tells whether there is another page
+	items, err := s.feedSvc.GetFeed(ctx, email, before, l+1, feed.FanOutLimit)
+	if err != nil {
+		return nil, feed.ErrGettingDataFromDB
+	}
+
+	page := &feed.Page{Items: items}
+	if len(items) > l {
+		page.Items = items[:l]
+		page.NextCursor = strconv.FormatInt(page.Items[l-1].ID, 10)
+	}
+	return page, nil
+}
+
+// Mute keeps another user's events out of the user's feed without unfollowing them
+func (s *FeedServiceImpl) Mute(ctx context.Context, email string, target string) error {
+	if strings.EqualFold(email, target) {
+		return feed.ErrSelf
+	}
+
+	userData, err := s.userSvc.GetUserByEmail(ctx, target)
+	if err != nil {
+		return feed.ErrGettingDataFromDB
+	}
+	if userData == nil {
+		return feed.ErrUserNotFound
+	}
+
+	if err := s.feedSvc.Mute(ctx, email, target); err != nil {
+		return feed.ErrSomethingWentWrong
+	}
+	return nil
+}
+
+// Unmute brings back the other user's events
+func (s *FeedServiceImpl) Unmute(ctx context.Context, email string, target string) error {
+	removed, err := s.feedSvc.Unmute(ctx, email, target)
+	if err != nil {
+		return feed.ErrSomethingWentWrong
+	}
+	if !removed {
+		return feed.ErrNotMuted
+	}
+	return nil
+}
+
+func (s *FeedServiceImpl) Muted(ctx context.Context, email string) ([]*feed.Muted, error) {
+	data, err := s.feedSvc.GetMuted(ctx, email)
+	if err != nil {
+		return nil, feed.ErrGettingDataFromDB
+	}
+	return data, nil
+}
@@ -0,0 +1,181 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/feed"
+	"typing-speed/internals/core/user"
+)
+
+// FakeFeedRepo serves a feed of items with IDs counting down from the latest
+type FakeFeedRepo struct {
+	port.FeedRepository
+	items     []*feed.Event
+	published []*feed.Event
+	followers []string
+	trimmed   [][]string
+	muted     map[string]bool
+	fanOutErr error
+}
+
+func (f *FakeFeedRepo) InsertEvent(ctx context.Context, e *feed.Event) error {
+	e.ID = int64(len(f.published) + 1)
+	f.published = append(f.published, e)
+	return nil
+}
+
+func (f *FakeFeedRepo) FanOut(ctx context.Context, e *feed.Event, limit int) ([]string, error) {
+	if limit != feed.FanOutLimit {
+		return nil, errors.New("unexpected fan-out limit")
+	}
+	return f.followers, f.fanOutErr
+}
+
+func (f *FakeFeedRepo) TrimFeeds(ctx context.Context, emails []string, keep int) error {
+	f.trimmed = append(f.trimmed, emails)
+	return nil
+}
+
+func (f *FakeFeedRepo) GetFeed(ctx context.Context, email string, before int64, limit int,
+	fanOutLimit int) ([]*feed.Event, error) {
+	if fanOutLimit != feed.FanOutLimit {
+		return nil, errors.New("unexpected fan-out limit")
+	}
+	page := []*feed.Event{}
+	for _, e := range f.items {
+		if (before == 0 || e.ID < before) && len(page) < limit {
+			page = append(page, e)
+		}
+	}
+	return page, nil
+}
+
+func (f *FakeFeedRepo) Mute(ctx context.Context, email string, muted string) error {
+	f.muted[muted] = true
+	return nil
+}
+
+func (f *FakeFeedRepo) Unmute(ctx context.Context, email string, muted string) (bool, error) {
+	found := f.muted[muted]
+	delete(f.muted, muted)
+	return found, nil
+}
+
+type FakeUserRepo struct {
+	port.UserRepository
+}
+
+func (f *FakeUserRepo) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	if email == "b@mail.com" {
+		return &user.User{Email: email}, nil
+	}
+	return nil, nil
+}
+
+type FakeTransactor struct{}
+
+func (f *FakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestFeed(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeFeedRepo{}
+	for id := int64(5); id > 0; id-- {
+		repo.items = append(repo.items, &feed.Event{ID: id, Kind: feed.KindLevelUp})
+	}
+	svc := NewFeedService(repo, &FakeUserRepo{}, &FakeTransactor{})
+
+	for _, tt := range []struct{ cursor, limit string }{{"abc", ""}, {"-1", ""}} {
+		if _, err := svc.Feed(ctx, "a@mail.com", tt.cursor, tt.limit); err != feed.ErrInvalidCursor {
+			t.Fatalf("expected %v, got %v", feed.ErrInvalidCursor, err)
+		}
+	}
+	if _, err := svc.Feed(ctx, "a@mail.com", "", "51"); err != feed.ErrInvalidLimit {
+		t.Fatalf("expected %v, got %v", feed.ErrInvalidLimit, err)
+	}
+
+	// pages of two: 5 4, 3 2, 1
+	cursor := ""
+	var pages [][]int64
+	for {
+		page, err := svc.Feed(ctx, "a@mail.com", cursor, "2")
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		ids := []int64{}
+		for _, e := range page.Items {
+			ids = append(ids, e.ID)
+		}
+		pages = append(pages, ids)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(pages) != 3 || pages[1][0] != 3 || pages[1][1] != 2 || len(pages[2]) != 1 || pages[2][0] != 1 {
+		t.Fatalf("unexpected pages %v", pages)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeFeedRepo{followers: []string{"b@mail.com", "c@mail.com"}}
+	svc := NewFeedService(repo, &FakeUserRepo{}, &FakeTransactor{})
+
+	err := svc.Publish(ctx, &feed.Event{Actor: "a@mail.com", Kind: feed.KindLevelUp, Level: 3},
+		&feed.Event{Actor: "a@mail.com", Kind: feed.KindAchievement, Achievement: "Speedster"})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(repo.published) != 2 || len(repo.trimmed) != 2 {
+		t.Fatalf("expected both events fanned out and trimmed, got %d and %d", len(repo.published), len(repo.trimmed))
+	}
+	// only the feeds the event went into are trimmed
+	if len(repo.trimmed[0]) != 2 || repo.trimmed[0][0] != "b@mail.com" {
+		t.Fatalf("expected the followers fanned out to to be trimmed, got %v", repo.trimmed[0])
+	}
+
+	repo.followers = nil
+	if err := svc.Publish(ctx, &feed.Event{Actor: "a@mail.com"}); err != nil || len(repo.trimmed) != 2 {
+		t.Fatalf("expected nothing to trim without followers, got %v, %d", err, len(repo.trimmed))
+	}
+
+	repo.fanOutErr = errors.New("db error")
+	if err := svc.Publish(ctx, &feed.Event{Actor: "a@mail.com"}); err != feed.ErrSomethingWentWrong {
+		t.Fatalf("expected %v, got %v", feed.ErrSomethingWentWrong, err)
+	}
+}
+
+func TestMute(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeFeedRepo{muted: map[string]bool{}}
+	svc := NewFeedService(repo, &FakeUserRepo{}, &FakeTransactor{})
+
+	tests := []struct {
+		name          string
+		target        string
+		expectedError error
+	}{
+		{name: "self", target: "A@mail.com", expectedError: feed.ErrSelf},
+		{name: "unknown user", target: "x@mail.com", expectedError: feed.ErrUserNotFound},
+		{name: "success", target: "b@mail.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.Mute(ctx, "a@mail.com", tt.target); err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+
+	if err := svc.Unmute(ctx, "a@mail.com", "b@mail.com"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if err := svc.Unmute(ctx, "a@mail.com", "b@mail.com"); err != feed.ErrNotMuted {
+		t.Fatalf("expected %v, got %v", feed.ErrNotMuted, err)
+	}
+}
@@ -0,0 +1,316 @@
+package friend
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/friend"
+	"typing-speed/internals/core/user"
+)
+
+type FriendServiceImpl struct {
+	friendSvc       port.FriendRepository
+	userSvc         port.UserRepository
+	personalBestSvc port.PersonalBestRepository
+	txSvc           port.Transactor
+}
+
+func NewFriendService(friends port.FriendRepository, users port.UserRepository, pbs port.PersonalBestRepository,
+	tx port.Transactor) friend.FriendService {
+	return &FriendServiceImpl{
+		friendSvc:       friends,
+		userSvc:         users,
+		personalBestSvc: pbs,
+		txSvc:           tx,
+	}
+}
+
+// relationship looks up how the user is connected to another existing user
+func (s *FriendServiceImpl) relationship(ctx context.Context, email string, target string) (*friend.Relationship, error) {
+	if strings.EqualFold(email, target) {
+		return nil, friend.ErrSelf
+	}
+
+	userData, err := s.userSvc.GetUserByEmail(ctx, target)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	if userData == nil {
+		return nil, friend.ErrUserNotFound
+	}
+
+	rel, err := s.friendSvc.GetRelationship(ctx, email, target)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	rel.Friends = rel.Following && rel.FollowedBy
+	return rel, nil
+}
+
+// Follow is one way. Following someone who follows back makes them friends.
+func (s *FriendServiceImpl) Follow(ctx context.Context, email string, target string) error {
+	rel, err := s.relationship(ctx, email, target)
+	if err != nil {
+		return err
+	}
+	if rel.Blocking || rel.BlockedBy {
+		return friend.ErrBlocked
+	}
+
+	if err := s.friendSvc.Follow(ctx, email, target); err != nil {
+		return friend.ErrSomethingWentWrong
+	}
+	return nil
+}
+
+func (s *FriendServiceImpl) Unfollow(ctx context.Context, email string, target string) error {
+	removed, err := s.friendSvc.Unfollow(ctx, email, target)
+	if err != nil {
+		return friend.ErrSomethingWentWrong
+	}
+	if !removed {
+		return friend.ErrNotFollowing
+	}
+	return nil
+}
+
+func (s *FriendServiceImpl) Followers(ctx context.Context, email string) ([]*friend.Friend, error) {
+	data, err := s.friendSvc.GetFollowers(ctx, email)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	return data, nil
+}
+
+func (s *FriendServiceImpl) Following(ctx context.Context, email string) ([]*friend.Friend, error) {
+	data, err := s.friendSvc.GetFollowing(ctx, email)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	return data, nil
+}
+
+func (s *FriendServiceImpl) Friends(ctx context.Context, email string) ([]*friend.Friend, error) {
+	data, err := s.friendSvc.GetFriends(ctx, email)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	return data, nil
+}
+
+// Unfriend drops the follows both ways
+func (s *FriendServiceImpl) Unfriend(ctx context.Context, email string, target string) error {
+	return s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		rel, err := s.friendSvc.GetRelationship(ctx, email, target)
+		if err != nil {
+			return friend.ErrGettingDataFromDB
+		}
+		if !rel.Following || !rel.FollowedBy {
+			return friend.ErrNotFriends
+		}
+
+		if _, err := s.friendSvc.Unfollow(ctx, email, target); err != nil {
+			return friend.ErrSomethingWentWrong
+		}
+		if _, err := s.friendSvc.Unfollow(ctx, target, email); err != nil {
+			return friend.ErrSomethingWentWrong
+		}
+		return nil
+	})
+}
+
+func (s *FriendServiceImpl) Relationship(ctx context.Context, email string, target string) (*friend.Relationship, error) {
+	return s.relationship(ctx, email, target)
+}
+
+// SendRequest asks another user to be friends. Asking someone who already
+// asked the user accepts their request instead, and asking twice returns
+// the open request.
+func (s *FriendServiceImpl) SendRequest(ctx context.Context, email string, target string) (*friend.Request, error) {
+	rel, err := s.relationship(ctx, email, target)
+	if err != nil {
+		return nil, err
+	}
+	if rel.Blocking || rel.BlockedBy {
+		return nil, friend.ErrBlocked
+	}
+	if rel.Friends {
+		return nil, friend.ErrAlreadyFriends
+	}
+
+	received, err := s.friendSvc.GetPendingRequest(ctx, target, email)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	if received != nil {
+		return s.RespondToRequest(ctx, email, strconv.FormatInt(received.ID, 10), true)
+	}
+
+	sent, err := s.friendSvc.GetPendingRequest(ctx, email, target)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	if sent != nil {
+		return sent, nil
+	}
+
+	req := &friend.Request{FromEmail: email, ToEmail: target, Status: friend.RequestPending}
+	if err := s.friendSvc.CreateRequest(ctx, req); err != nil {
+		return nil, friend.ErrSomethingWentWrong
+	}
+	return req, nil
+}
+
+func (s *FriendServiceImpl) Requests(ctx context.Context, email string) (*friend.Requests, error) {
+	incoming, err := s.friendSvc.GetIncomingRequests(ctx, email)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	outgoing, err := s.friendSvc.GetOutgoingRequests(ctx, email)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+
+	return &friend.Requests{Incoming: incoming, Outgoing: outgoing}, nil
+}
+
+// RespondToRequest accepts or declines a request sent to the user.
+// Accepting makes them follow each other.
+func (s *FriendServiceImpl) RespondToRequest(ctx context.Context, email string, id string, accept bool) (*friend.Request, error) {
+	requestID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || requestID <= 0 {
+		return nil, friend.ErrRequestNotFound
+	}
+
+	var req *friend.Request
+	err = s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		req, err = s.friendSvc.GetRequestForUpdate(ctx, requestID)
+		if err != nil {
+			return friend.ErrGettingDataFromDB
+		}
+		if req == nil || req.ToEmail != email {
+			return friend.ErrRequestNotFound
+		}
+		if req.Status != friend.RequestPending {
+			return friend.ErrRequestAnswered
+		}
+
+		now := time.Now()
+		req.Status = friend.RequestDeclined
+		req.RespondedAt = &now
+		if accept {
+			req.Status = friend.RequestAccepted
+			if err := s.friendSvc.Follow(ctx, req.FromEmail, req.ToEmail); err != nil {
+				return friend.ErrSomethingWentWrong
+			}
+			if err := s.friendSvc.Follow(ctx, req.ToEmail, req.FromEmail); err != nil {
+				return friend.ErrSomethingWentWrong
+			}
+		}
+
+		if err := s.friendSvc.UpdateRequestStatus(ctx, req); err != nil {
+			return friend.ErrSomethingWentWrong
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// Block cuts the users off from each other: the follows both ways and the
+// open requests between them are dropped, and neither can follow or ask
+// the other until the block is lifted
+func (s *FriendServiceImpl) Block(ctx context.Context, email string, target string) error {
+	if _, err := s.relationship(ctx, email, target); err != nil {
+		return err
+	}
+
+	return s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.friendSvc.Block(ctx, email, target); err != nil {
+			return friend.ErrSomethingWentWrong
+		}
+		if _, err := s.friendSvc.Unfollow(ctx, email, target); err != nil {
+			return friend.ErrSomethingWentWrong
+		}
+		if _, err := s.friendSvc.Unfollow(ctx, target, email); err != nil {
+			return friend.ErrSomethingWentWrong
+		}
+		if err := s.friendSvc.DeletePendingRequests(ctx, email, target); err != nil {
+			return friend.ErrSomethingWentWrong
+		}
+		return nil
+	})
+}
+
+func (s *FriendServiceImpl) Unblock(ctx context.Context, email string, target string) error {
+	removed, err := s.friendSvc.Unblock(ctx, email, target)
+	if err != nil {
+		return friend.ErrSomethingWentWrong
+	}
+	if !removed {
+		return friend.ErrNotBlocked
+	}
+	return nil
+}
+
+func (s *FriendServiceImpl) Blocked(ctx context.Context, email string) ([]*friend.Friend, error) {
+	data, err := s.friendSvc.GetBlocked(ctx, email)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	return data, nil
+}
+
+// Leaderboard ranks the user among their friends
+func (s *FriendServiceImpl) Leaderboard(ctx context.Context, email string) ([]*friend.LeaderboardEntry, error) {
+	data, err := s.friendSvc.GetFriendsLeaderboard(ctx, email, friend.LeaderboardLimit)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	return data, nil
+}
+
+// Compare sets the user's stats and personal bests beside a friend's
+func (s *FriendServiceImpl) Compare(ctx context.Context, email string, target string) (*friend.Comparison, error) {
+	rel, err := s.relationship(ctx, email, target)
+	if err != nil {
+		return nil, err
+	}
+	if !rel.Friends {
+		return nil, friend.ErrNotFriends
+	}
+
+	you, err := s.userSvc.GetUserByEmail(ctx, email)
+	if err != nil || you == nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	them, err := s.userSvc.GetUserByEmail(ctx, target)
+	if err != nil || them == nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	if !user.CanView(them.Privacy, false, true) {
+		return nil, friend.ErrPrivateProfile
+	}
+
+	yourBests, err := s.personalBestSvc.GetPersonalBests(ctx, email)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+	theirBests, err := s.personalBestSvc.GetPersonalBests(ctx, target)
+	if err != nil {
+		return nil, friend.ErrGettingDataFromDB
+	}
+
+	now := time.Now()
+	return &friend.Comparison{
+		You:           friend.NewStats(you, now),
+		Friend:        friend.NewStats(them, now),
+		PersonalBests: friend.ComparePersonalBests(yourBests, theirBests),
+	}, nil
+}
@@ -0,0 +1,300 @@
+package friend
+
+import (
+	"context"
+	"testing"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/friend"
+	"typing-speed/internals/core/typing"
+	"typing-speed/internals/core/user"
+)
+
+type pair struct{ a, b string }
+
+// FakeFriendRepo keeps follows, blocks and requests in memory
+type FakeFriendRepo struct {
+	port.FriendRepository
+	follows  map[pair]bool
+	blocks   map[pair]bool
+	requests []*friend.Request
+}
+
+func newFakeFriendRepo() *FakeFriendRepo {
+	return &FakeFriendRepo{follows: map[pair]bool{}, blocks: map[pair]bool{}}
+}
+
+func (f *FakeFriendRepo) Follow(ctx context.Context, follower string, followee string) error {
+	f.follows[pair{follower, followee}] = true
+	return nil
+}
+
+func (f *FakeFriendRepo) Unfollow(ctx context.Context, follower string, followee string) (bool, error) {
+	found := f.follows[pair{follower, followee}]
+	delete(f.follows, pair{follower, followee})
+	return found, nil
+}
+
+func (f *FakeFriendRepo) GetRelationship(ctx context.Context, email string, other string) (*friend.Relationship, error) {
+	return &friend.Relationship{
+		Following:  f.follows[pair{email, other}],
+		FollowedBy: f.follows[pair{other, email}],
+		Blocking:   f.blocks[pair{email, other}],
+		BlockedBy:  f.blocks[pair{other, email}],
+	}, nil
+}
+
+func (f *FakeFriendRepo) CreateRequest(ctx context.Context, r *friend.Request) error {
+	r.ID = int64(len(f.requests) + 1)
+	copied := *r
+	f.requests = append(f.requests, &copied)
+	return nil
+}
+
+func (f *FakeFriendRepo) GetRequestForUpdate(ctx context.Context, id int64) (*friend.Request, error) {
+	for _, r := range f.requests {
+		if r.ID == id {
+			copied := *r
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *FakeFriendRepo) GetPendingRequest(ctx context.Context, from string, to string) (*friend.Request, error) {
+	for _, r := range f.requests {
+		if r.FromEmail == from && r.ToEmail == to && r.Status == friend.RequestPending {
+			copied := *r
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *FakeFriendRepo) UpdateRequestStatus(ctx context.Context, r *friend.Request) error {
+	for _, stored := range f.requests {
+		if stored.ID == r.ID {
+			stored.Status = r.Status
+		}
+	}
+	return nil
+}
+
+func (f *FakeFriendRepo) DeletePendingRequests(ctx context.Context, a string, b string) error {
+	kept := []*friend.Request{}
+	for _, r := range f.requests {
+		between := (r.FromEmail == a && r.ToEmail == b) || (r.FromEmail == b && r.ToEmail == a)
+		if !between || r.Status != friend.RequestPending {
+			kept = append(kept, r)
+		}
+	}
+	f.requests = kept
+	return nil
+}
+
+func (f *FakeFriendRepo) Block(ctx context.Context, blocker string, blocked string) error {
+	f.blocks[pair{blocker, blocked}] = true
+	return nil
+}
+
+func (f *FakeFriendRepo) Unblock(ctx context.Context, blocker string, blocked string) (bool, error) {
+	found := f.blocks[pair{blocker, blocked}]
+	delete(f.blocks, pair{blocker, blocked})
+	return found, nil
+}
+
+type FakeUserRepo struct {
+	port.UserRepository
+	users map[string]*user.User
+}
+
+func (f *FakeUserRepo) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	return f.users[email], nil
+}
+
+type FakePersonalBestRepo struct {
+	port.PersonalBestRepository
+	bests map[string][]*typing.PersonalBest
+}
+
+func (f *FakePersonalBestRepo) GetPersonalBests(ctx context.Context, email string) ([]*typing.PersonalBest, error) {
+	return f.bests[email], nil
+}
+
+type FakeTransactor struct{}
+
+func (f *FakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newTestService() (friend.FriendService, *FakeFriendRepo) {
+	repo := newFakeFriendRepo()
+	users := &FakeUserRepo{users: map[string]*user.User{
+		"a@mail.com": {Name: "a", Email: "a@mail.com", AvgSpeed: 70, BestSpeed: 90},
+		"b@mail.com": {Name: "b", Email: "b@mail.com", AvgSpeed: 80, BestSpeed: 85},
+		"c@mail.com": {Name: "c", Email: "c@mail.com", Privacy: user.PrivacyPrivate},
+	}}
+	pbs := &FakePersonalBestRepo{bests: map[string][]*typing.PersonalBest{
+		"a@mail.com": {
+			{Mode: typing.Mode60s, Language: typing.DefaultLanguage, WPM: 88},
+			{Mode: typing.Mode15s, Language: typing.DefaultLanguage, WPM: 95},
+		},
+		"b@mail.com": {{Mode: typing.Mode60s, Language: typing.DefaultLanguage, WPM: 84}},
+	}}
+	return NewFriendService(repo, users, pbs, &FakeTransactor{}), repo
+}
+
+func TestFollow(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService()
+
+	tests := []struct {
+		name          string
+		target        string
+		expectedError error
+	}{
+		{name: "self", target: "A@mail.com", expectedError: friend.ErrSelf},
+		{name: "unknown user", target: "x@mail.com", expectedError: friend.ErrUserNotFound},
+		{name: "success", target: "b@mail.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.Follow(ctx, "a@mail.com", tt.target); err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+
+	// following back makes them friends
+	if err := svc.Follow(ctx, "b@mail.com", "a@mail.com"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	rel, err := svc.Relationship(ctx, "a@mail.com", "b@mail.com")
+	if err != nil || !rel.Friends {
+		t.Fatalf("expected mutual follows to be friends, got %+v, %v", rel, err)
+	}
+
+	if err := svc.Unfollow(ctx, "a@mail.com", "c@mail.com"); err != friend.ErrNotFollowing {
+		t.Fatalf("expected %v, got %v", friend.ErrNotFollowing, err)
+	}
+}
+
+func TestFriendRequests(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService()
+
+	req, err := svc.SendRequest(ctx, "a@mail.com", "b@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	// asking twice gives back the open request
+	again, _ := svc.SendRequest(ctx, "a@mail.com", "b@mail.com")
+	if again.ID != req.ID || len(repo.requests) != 1 {
+		t.Fatalf("expected the open request back, got %+v", again)
+	}
+
+	if _, err := svc.RespondToRequest(ctx, "c@mail.com", "1", true); err != friend.ErrRequestNotFound {
+		t.Fatalf("expected only the addressee to answer, got %v", err)
+	}
+	if _, err := svc.RespondToRequest(ctx, "b@mail.com", "abc", true); err != friend.ErrRequestNotFound {
+		t.Fatalf("expected %v, got %v", friend.ErrRequestNotFound, err)
+	}
+
+	accepted, err := svc.RespondToRequest(ctx, "b@mail.com", "1", true)
+	if err != nil || accepted.Status != friend.RequestAccepted {
+		t.Fatalf("expected the request to be accepted, got %+v, %v", accepted, err)
+	}
+	if !repo.follows[pair{"a@mail.com", "b@mail.com"}] || !repo.follows[pair{"b@mail.com", "a@mail.com"}] {
+		t.Fatalf("expected accepting to make them follow each other")
+	}
+	if _, err := svc.RespondToRequest(ctx, "b@mail.com", "1", false); err != friend.ErrRequestAnswered {
+		t.Fatalf("expected %v, got %v", friend.ErrRequestAnswered, err)
+	}
+	if _, err := svc.SendRequest(ctx, "b@mail.com", "a@mail.com"); err != friend.ErrAlreadyFriends {
+		t.Fatalf("expected %v, got %v", friend.ErrAlreadyFriends, err)
+	}
+
+	// asking someone who already asked accepts their request
+	svc.SendRequest(ctx, "c@mail.com", "a@mail.com")
+	crossed, err := svc.SendRequest(ctx, "a@mail.com", "c@mail.com")
+	if err != nil || crossed.Status != friend.RequestAccepted || crossed.FromEmail != "c@mail.com" {
+		t.Fatalf("expected the crossed request to be accepted, got %+v, %v", crossed, err)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService()
+
+	svc.Follow(ctx, "a@mail.com", "b@mail.com")
+	svc.Follow(ctx, "b@mail.com", "a@mail.com")
+	svc.SendRequest(ctx, "c@mail.com", "a@mail.com")
+
+	if err := svc.Block(ctx, "a@mail.com", "b@mail.com"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if err := svc.Block(ctx, "a@mail.com", "c@mail.com"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(repo.follows) != 0 || len(repo.requests) != 0 {
+		t.Fatalf("expected the follows and requests to be dropped, got %v and %d requests", repo.follows, len(repo.requests))
+	}
+
+	// the blocked user cannot reach the blocker, and the blocker cannot reach them
+	if err := svc.Follow(ctx, "b@mail.com", "a@mail.com"); err != friend.ErrBlocked {
+		t.Fatalf("expected %v, got %v", friend.ErrBlocked, err)
+	}
+	if _, err := svc.SendRequest(ctx, "a@mail.com", "c@mail.com"); err != friend.ErrBlocked {
+		t.Fatalf("expected %v, got %v", friend.ErrBlocked, err)
+	}
+	rel, _ := svc.Relationship(ctx, "b@mail.com", "a@mail.com")
+	if !rel.BlockedBy || rel.Blocking {
+		t.Fatalf("unexpected relationship %+v", rel)
+	}
+
+	if err := svc.Unblock(ctx, "a@mail.com", "b@mail.com"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if err := svc.Unblock(ctx, "a@mail.com", "b@mail.com"); err != friend.ErrNotBlocked {
+		t.Fatalf("expected %v, got %v", friend.ErrNotBlocked, err)
+	}
+	if err := svc.Follow(ctx, "b@mail.com", "a@mail.com"); err != nil {
+		t.Fatalf("expected success after unblocking, got %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService()
+
+	if _, err := svc.Compare(ctx, "a@mail.com", "b@mail.com"); err != friend.ErrNotFriends {
+		t.Fatalf("expected %v, got %v", friend.ErrNotFriends, err)
+	}
+
+	svc.Follow(ctx, "a@mail.com", "b@mail.com")
+	svc.Follow(ctx, "b@mail.com", "a@mail.com")
+
+	data, err := svc.Compare(ctx, "a@mail.com", "b@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if data.You.Name != "a" || data.Friend.Name != "b" || data.Friend.AvgSpeed != 80 {
+		t.Fatalf("unexpected stats %+v and %+v", data.You, data.Friend)
+	}
+	// modes only one of them has a personal best in are still listed, in mode order
+	if len(data.PersonalBests) != 2 {
+		t.Fatalf("expected 2 modes, got %d", len(data.PersonalBests))
+	}
+	first, second := data.PersonalBests[0], data.PersonalBests[1]
+	if first.Mode != typing.Mode15s || first.You != 95 || first.Friend != 0 ||
+		second.Mode != typing.Mode60s || second.You != 88 || second.Friend != 84 {
+		t.Fatalf("unexpected personal bests %+v %+v", first, second)
+	}
+
+	// private users are not compared, not even with friends
+	svc.Follow(ctx, "a@mail.com", "c@mail.com")
+	svc.Follow(ctx, "c@mail.com", "a@mail.com")
+	if _, err := svc.Compare(ctx, "a@mail.com", "c@mail.com"); err != friend.ErrPrivateProfile {
+		t.Fatalf("expected %v, got %v", friend.ErrPrivateProfile, err)
+	}
+}
@@ -0,0 +1,177 @@
+package goal
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/goal"
+	"typing-speed/internals/core/typing"
+	"typing-speed/internals/core/user"
+)
+
+type GoalServiceImpl struct {
+	goalSvc port.GoalRepository
+	userSvc port.UserRepository
+}
+
+func NewGoalService(svc port.GoalRepository, users port.UserRepository) goal.GoalService {
+	return &GoalServiceImpl{
+		goalSvc: svc,
+		userSvc: users,
+	}
+}
+
+// validate checks the goal fields a user sets
+func validate(g *goal.Goal) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	if g.Mode != "" && !typing.ValidMode(g.Mode) {
+		return goal.ErrInvalidGoal
+	}
+	return nil
+}
+
+func parseID(id string) (int64, error) {
+	goalID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || goalID <= 0 {
+		return 0, goal.ErrGoalNotFound
+	}
+	return goalID, nil
+}
+
+func (s *GoalServiceImpl) CreateGoal(ctx context.Context, email string, g *goal.Goal) (*goal.Goal, error) {
+	if err := validate(g); err != nil {
+		return nil, err
+	}
+	if g.Deadline != nil && !g.Deadline.After(time.Now()) {
+		return nil, goal.ErrInvalidGoal
+	}
+
+	newGoal := &goal.Goal{
+		Email:    email,
+		Type:     g.Type,
+		Target:   g.Target,
+		Mode:     g.Mode,
+		Deadline: g.Deadline,
+	}
+	if err := s.goalSvc.CreateGoal(ctx, newGoal); err != nil {
+		return nil, goal.ErrSomethingWentWrong
+	}
+
+	return newGoal, nil
+}
+
+func (s *GoalServiceImpl) Goals(ctx context.Context, email string) ([]*goal.Goal, error) {
+	data, err := s.goalSvc.GetGoals(ctx, email)
+	if err != nil {
+		return nil, goal.ErrGettingDataFromDB
+	}
+	return data, nil
+}
+
+// UpdateGoal changes the target, mode and deadline of a goal. The type of a
+// goal is fixed. Progress made in another mode does not carry over.
+func (s *GoalServiceImpl) UpdateGoal(ctx context.Context, email string, id string, g *goal.Goal) (*goal.Goal, error) {
+	goalID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.goalSvc.GetGoal(ctx, email, goalID)
+	if err != nil {
+		return nil, goal.ErrGettingDataFromDB
+	}
+	if existing == nil {
+		return nil, goal.ErrGoalNotFound
+	}
+
+	if g.Type == "" {
+		g.Type = existing.Type
+	}
+	if g.Type != existing.Type {
+		return nil, goal.ErrInvalidGoal
+	}
+	if err := validate(g); err != nil {
+		return nil, err
+	}
+	if g.Deadline != nil && !g.Deadline.After(time.Now()) {
+		return nil, goal.ErrInvalidGoal
+	}
+
+	// daily practice counts every mode, so only the other goals start over
+	if g.Mode != existing.Mode && existing.Type != goal.TypeDailyPractice {
+		existing.Progress = 0
+		existing.Baseline = 0
+	}
+	existing.Target = g.Target
+	existing.Mode = g.Mode
+	existing.Deadline = g.Deadline
+
+	// a changed target can complete a goal or reopen it
+	if existing.Type != goal.TypeDailyPractice {
+		if existing.Progress >= existing.Target && existing.CompletedAt == nil {
+			now := time.Now()
+			existing.CompletedAt = &now
+		} else if existing.Progress < existing.Target {
+			existing.CompletedAt = nil
+		}
+	}
+
+	if err := s.goalSvc.UpdateGoal(ctx, existing); err != nil {
+		return nil, goal.ErrSomethingWentWrong
+	}
+
+	return existing, nil
+}
+
+func (s *GoalServiceImpl) DeleteGoal(ctx context.Context, email string, id string) error {
+	goalID, err := parseID(id)
+	if err != nil {
+		return err
+	}
+
+	found, err := s.goalSvc.DeleteGoal(ctx, email, goalID)
+	if err != nil {
+		return goal.ErrSomethingWentWrong
+	}
+	if !found {
+		return goal.ErrGoalNotFound
+	}
+	return nil
+}
+
+// GoalsProgress reports the progress, projection and completion of every goal
+func (s *GoalServiceImpl) GoalsProgress(ctx context.Context, email string) (*goal.GoalsProgress, error) {
+	goals, err := s.goalSvc.GetGoals(ctx, email)
+	if err != nil {
+		return nil, goal.ErrGettingDataFromDB
+	}
+
+	userData, err := s.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, goal.ErrGettingDataFromDB
+	}
+
+	loc := time.UTC
+	if userData != nil {
+		loc = user.Location(userData.TimeZone)
+	}
+
+	now := time.Now()
+	response := &goal.GoalsProgress{
+		Active:    []*goal.Progress{},
+		Completed: []*goal.Progress{},
+	}
+	for _, g := range goals {
+		p := goal.NewProgress(g, now, loc)
+		if g.CompletedAt != nil {
+			response.Completed = append(response.Completed, p)
+			continue
+		}
+		response.Active = append(response.Active, p)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,236 @@
+package goal
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+	"typing-speed/internals/core/goal"
+)
+
+type FakeGoalRepo struct {
+	CreateFn     func(ctx context.Context, g *goal.Goal) error
+	GetGoalsFn   func(ctx context.Context, email string) ([]*goal.Goal, error)
+	GetGoalFn    func(ctx context.Context, email string, id int64) (*goal.Goal, error)
+	UpdateGoalFn func(ctx context.Context, g *goal.Goal) error
+	DeleteFn     func(ctx context.Context, email string, id int64) (bool, error)
+}
+
+func (f *FakeGoalRepo) CreateGoal(ctx context.Context, g *goal.Goal) error {
+	if f.CreateFn != nil {
+		return f.CreateFn(ctx, g)
+	}
+	return nil
+}
+
+func (f *FakeGoalRepo) GetGoals(ctx context.Context, email string) ([]*goal.Goal, error) {
+	if f.GetGoalsFn != nil {
+		return f.GetGoalsFn(ctx, email)
+	}
+	return nil, nil
+}
+
+func (f *FakeGoalRepo) GetActiveGoals(ctx context.Context, email string) ([]*goal.Goal, error) {
+	return f.GetGoals(ctx, email)
+}
+
+func (f *FakeGoalRepo) GetGoal(ctx context.Context, email string, id int64) (*goal.Goal, error) {
+	if f.GetGoalFn != nil {
+		return f.GetGoalFn(ctx, email, id)
+	}
+	return nil, nil
+}
+
+func (f *FakeGoalRepo) UpdateGoal(ctx context.Context, g *goal.Goal) error {
+	if f.UpdateGoalFn != nil {
+		return f.UpdateGoalFn(ctx, g)
+	}
+	return nil
+}
+
+func (f *FakeGoalRepo) UpdateGoalProgress(ctx context.Context, g *goal.Goal) error {
+	return nil
+}
+
+func (f *FakeGoalRepo) DeleteGoal(ctx context.Context, email string, id int64) (bool, error) {
+	if f.DeleteFn != nil {
+		return f.DeleteFn(ctx, email, id)
+	}
+	return false, nil
+}
+
+func TestCreateGoal(t *testing.T) {
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		input         *goal.Goal
+		repo          *FakeGoalRepo
+		expectedError error
+	}{
+		{name: "unknown type", input: &goal.Goal{Type: "typing", Target: 10}, repo: &FakeGoalRepo{}, expectedError: goal.ErrInvalidGoal},
+		{name: "zero target", input: &goal.Goal{Type: goal.TypeSpeed}, repo: &FakeGoalRepo{}, expectedError: goal.ErrInvalidGoal},
+		{name: "accuracy above 100", input: &goal.Goal{Type: goal.TypeAccuracy, Target: 101}, repo: &FakeGoalRepo{}, expectedError: goal.ErrInvalidGoal},
+		{name: "practice past a day", input: &goal.Goal{Type: goal.TypeDailyPractice, Target: 24*60 + 1}, repo: &FakeGoalRepo{}, expectedError: goal.ErrInvalidGoal},
+		{name: "overflowing practice", input: &goal.Goal{Type: goal.TypeDailyPractice, Target: math.MaxInt / 30}, repo: &FakeGoalRepo{}, expectedError: goal.ErrInvalidGoal},
+		{name: "speed above the maximum", input: &goal.Goal{Type: goal.TypeSpeed, Target: goal.MaxSpeedTarget + 1}, repo: &FakeGoalRepo{}, expectedError: goal.ErrInvalidGoal},
+		{name: "test count above the maximum", input: &goal.Goal{Type: goal.TypeTestCount, Target: goal.MaxTestCountTarget + 1}, repo: &FakeGoalRepo{}, expectedError: goal.ErrInvalidGoal},
+		{name: "unknown mode", input: &goal.Goal{Type: goal.TypeSpeed, Target: 80, Mode: "5s"}, repo: &FakeGoalRepo{}, expectedError: goal.ErrInvalidGoal},
+		{name: "deadline in the past", input: &goal.Goal{Type: goal.TypeSpeed, Target: 80, Deadline: &past}, repo: &FakeGoalRepo{}, expectedError: goal.ErrInvalidGoal},
+		{
+			name:  "db error",
+			input: &goal.Goal{Type: goal.TypeSpeed, Target: 80},
+			repo: &FakeGoalRepo{
+				CreateFn: func(ctx context.Context, g *goal.Goal) error {
+					return errors.New("db error")
+				},
+			},
+			expectedError: goal.ErrSomethingWentWrong,
+		},
+		{name: "success", input: &goal.Goal{Type: goal.TypeSpeed, Target: 80, Mode: "60s", Deadline: &future, Progress: 79}, repo: &FakeGoalRepo{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewGoalService(tt.repo, nil)
+
+			data, err := service.CreateGoal(ctx, "test@mail.com", tt.input)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if err == nil && (data.Email != "test@mail.com" || data.Progress != 0) {
+				t.Fatalf("expected a fresh goal owned by the user, got %+v", data)
+			}
+		})
+	}
+}
+
+func TestUpdateGoal(t *testing.T) {
+	ctx := context.Background()
+
+	existing := func() (*goal.Goal, error) {
+		return &goal.Goal{ID: 7, Type: goal.TypeTestCount, Target: 50, Progress: 20}, nil
+	}
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name             string
+		id               string
+		input            *goal.Goal
+		repo             *FakeGoalRepo
+		expectedError    error
+		completed        bool
+		expectedProgress int
+	}{
+		{name: "invalid id", id: "abc", input: &goal.Goal{Target: 10}, repo: &FakeGoalRepo{}, expectedError: goal.ErrGoalNotFound},
+		{name: "missing goal", id: "7", input: &goal.Goal{Target: 10}, repo: &FakeGoalRepo{}, expectedError: goal.ErrGoalNotFound},
+		{
+			name:  "type cannot change",
+			id:    "7",
+			input: &goal.Goal{Type: goal.TypeSpeed, Target: 10},
+			repo: &FakeGoalRepo{GetGoalFn: func(ctx context.Context, email string, id int64) (*goal.Goal, error) {
+				return existing()
+			}},
+			expectedError: goal.ErrInvalidGoal,
+		},
+		{
+			name:  "deadline in the past",
+			id:    "7",
+			input: &goal.Goal{Target: 100, Deadline: &past},
+			repo: &FakeGoalRepo{GetGoalFn: func(ctx context.Context, email string, id int64) (*goal.Goal, error) {
+				return existing()
+			}},
+			expectedError: goal.ErrInvalidGoal,
+		},
+		{
+			name:  "lower target completes the goal",
+			id:    "7",
+			input: &goal.Goal{Target: 10},
+			repo: &FakeGoalRepo{GetGoalFn: func(ctx context.Context, email string, id int64) (*goal.Goal, error) {
+				return existing()
+			}},
+			completed:        true,
+			expectedProgress: 20,
+		},
+		{
+			name:  "higher target keeps it open",
+			id:    "7",
+			input: &goal.Goal{Target: 100},
+			repo: &FakeGoalRepo{GetGoalFn: func(ctx context.Context, email string, id int64) (*goal.Goal, error) {
+				return existing()
+			}},
+			expectedProgress: 20,
+		},
+		{
+			name:  "new mode starts over",
+			id:    "7",
+			input: &goal.Goal{Target: 10, Mode: "60s"},
+			repo: &FakeGoalRepo{GetGoalFn: func(ctx context.Context, email string, id int64) (*goal.Goal, error) {
+				return existing()
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewGoalService(tt.repo, nil)
+
+			data, err := service.UpdateGoal(ctx, "test@mail.com", tt.id, tt.input)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if err == nil && (data.CompletedAt != nil) != tt.completed {
+				t.Fatalf("expected completed %v, got %+v", tt.completed, data)
+			}
+			if err == nil && data.Progress != tt.expectedProgress {
+				t.Fatalf("expected progress %v, got %+v", tt.expectedProgress, data)
+			}
+		})
+	}
+}
+
+func TestDeleteGoal(t *testing.T) {
+	ctx := context.Background()
+
+	service := NewGoalService(&FakeGoalRepo{
+		DeleteFn: func(ctx context.Context, email string, id int64) (bool, error) {
+			return id == 3, nil
+		},
+	}, nil)
+
+	if err := service.DeleteGoal(ctx, "test@mail.com", "4"); err != goal.ErrGoalNotFound {
+		t.Fatalf("expected %v, got %v", goal.ErrGoalNotFound, err)
+	}
+	if err := service.DeleteGoal(ctx, "test@mail.com", "3"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+}
+
+func TestGoalProgressProjection(t *testing.T) {
+	now := time.Now()
+	deadline := now.Add(20 * 24 * time.Hour)
+
+	// 10 WPM gained in 10 days, 10 more to go: done in about 10 more days
+	g := &goal.Goal{
+		Type:      goal.TypeSpeed,
+		Target:    80,
+		Baseline:  60,
+		Progress:  70,
+		Deadline:  &deadline,
+		CreatedAt: now.Add(-10 * 24 * time.Hour),
+	}
+
+	p := goal.NewProgress(g, now, time.UTC)
+	if p.Percent != 87 {
+		t.Fatalf("expected 87%%, got %d", p.Percent)
+	}
+	if p.ProjectedCompletion == nil || p.ProjectedCompletion.Sub(now).Round(time.Hour) != 10*24*time.Hour {
+		t.Fatalf("expected completion in 10 days, got %v", p.ProjectedCompletion)
+	}
+	if !p.OnTrack || p.Expired {
+		t.Fatalf("expected goal on track, got %+v", p)
+	}
+}
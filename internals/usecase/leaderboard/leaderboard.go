@@ -0,0 +1,141 @@
+package leaderboard
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/leaderboard"
+)
+
+type LeaderboardServiceImpl struct {
+	leaderboardSvc port.LeaderboardRepository
+
+	// distributions of everyone's speed by metric, so that a standing is not
+	// a scan of every user
+	mu            sync.RWMutex
+	distributions map[string]*leaderboard.Distribution
+}
+
+func NewLeaderboardService(leaderboards port.LeaderboardRepository) leaderboard.LeaderboardService {
+	return &LeaderboardServiceImpl{
+		leaderboardSvc: leaderboards,
+		distributions:  map[string]*leaderboard.Distribution{},
+	}
+}
+
+// Leaderboard returns a page of the ranking the filter asks for. The cursor
+// is the NextCursor of the page before.
+func (s *LeaderboardServiceImpl) Leaderboard(ctx context.Context, email string, filter *leaderboard.Filter) (*leaderboard.Page, error) {
+	q, err := leaderboard.NewQuery(filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	limit := q.Limit
+
+	// one extra entry tells whether there is another page
+	q.Limit++
+	entries, err := s.leaderboardSvc.GetLeaderboard(ctx, q)
+	if err != nil {
+		return nil, leaderboard.ErrGettingDataFromDB
+	}
+
+	page := &leaderboard.Page{Window: q.Window, Mode: q.Mode, Language: q.Language, Sort: q.Sort,
+		Country: q.Country, Region: q.Region, Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = leaderboard.EncodeCursor(q.Sort, page.Entries[limit-1])
+	}
+	for _, e := range page.Entries {
+		e.You = e.Email == email
+	}
+	return page, nil
+}
+
+// Countries sums up every country with users in it
+func (s *LeaderboardServiceImpl) Countries(ctx context.Context) ([]*leaderboard.CountrySummary, error) {
+	summaries, err := s.leaderboardSvc.GetCountrySummaries(ctx)
+	if err != nil {
+		return nil, leaderboard.ErrGettingDataFromDB
+	}
+	return summaries, nil
+}
+
+// Standings places the user among everyone in each mode they took a test in,
+// by their best or average speed
+func (s *LeaderboardServiceImpl) Standings(ctx context.Context, email string, metric string) (*leaderboard.Standings, error) {
+	if metric == "" {
+		metric = leaderboard.MetricBest
+	}
+	if !leaderboard.ValidMetric(metric) {
+		return nil, leaderboard.ErrInvalidMetric
+	}
+
+	d, err := s.distribution(ctx, metric)
+	if err != nil {
+		return nil, leaderboard.ErrGettingDataFromDB
+	}
+
+	speeds, err := s.leaderboardSvc.GetUserSpeeds(ctx, email, metric)
+	if err != nil {
+		return nil, leaderboard.ErrGettingDataFromDB
+	}
+
+	standings := &leaderboard.Standings{Metric: metric, Standings: []*leaderboard.Standing{}, UpdatedAt: d.UpdatedAt}
+	for mode, wpm := range speeds {
+		h := d.Modes[mode]
+		if h == nil {
+			h = &leaderboard.Histogram{}
+		}
+		rank, users, percentile := h.Standing(wpm)
+		standings.Standings = append(standings.Standings, &leaderboard.Standing{
+			Mode:       mode,
+			WPM:        wpm,
+			Rank:       rank,
+			Users:      users,
+			Percentile: percentile,
+		})
+	}
+	sort.Slice(standings.Standings, func(i, j int) bool {
+		return standings.Standings[i].Mode < standings.Standings[j].Mode
+	})
+	return standings, nil
+}
+
+// RefreshDistributions computes the distributions of every metric again
+func (s *LeaderboardServiceImpl) RefreshDistributions(ctx context.Context) error {
+	for _, metric := range []string{leaderboard.MetricBest, leaderboard.MetricAverage} {
+		if _, err := s.refresh(ctx, metric); err != nil {
+			log.Println("error refreshing the", metric, "distribution:", err)
+			return leaderboard.ErrGettingDataFromDB
+		}
+	}
+	return nil
+}
+
+// distribution returns the cached distribution of the metric, computing it
+// the first time it is asked for
+func (s *LeaderboardServiceImpl) distribution(ctx context.Context, metric string) (*leaderboard.Distribution, error) {
+	s.mu.RLock()
+	d := s.distributions[metric]
+	s.mu.RUnlock()
+	if d != nil {
+		return d, nil
+	}
+	return s.refresh(ctx, metric)
+}
+
+func (s *LeaderboardServiceImpl) refresh(ctx context.Context, metric string) (*leaderboard.Distribution, error) {
+	buckets, err := s.leaderboardSvc.GetDistribution(ctx, metric)
+	if err != nil {
+		return nil, err
+	}
+
+	d := leaderboard.NewDistribution(metric, buckets, time.Now())
+	s.mu.Lock()
+	s.distributions[metric] = d
+	s.mu.Unlock()
+	return d, nil
+}
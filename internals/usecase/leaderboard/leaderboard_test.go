@@ -0,0 +1,171 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/leaderboard"
+	"typing-speed/internals/core/typing"
+)
+
+// FakeLeaderboardRepo serves entries already in leaderboard order
+type FakeLeaderboardRepo struct {
+	port.LeaderboardRepository
+	entries []*leaderboard.Entry
+	queries []*leaderboard.Query
+}
+
+func (f *FakeLeaderboardRepo) GetLeaderboard(ctx context.Context, q *leaderboard.Query) ([]*leaderboard.Entry, error) {
+	copied := *q
+	f.queries = append(f.queries, &copied)
+
+	page := []*leaderboard.Entry{}
+	past := q.Cursor == nil
+	for _, e := range f.entries {
+		if past && len(page) < q.Limit {
+			page = append(page, e)
+		}
+		if q.Cursor != nil && e.TestID == q.Cursor.TestID {
+			past = true
+		}
+	}
+	return page, nil
+}
+
+func TestLeaderboardFilter(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLeaderboardService(&FakeLeaderboardRepo{})
+
+	tests := []struct {
+		name          string
+		filter        *leaderboard.Filter
+		expectedError error
+	}{
+		{name: "window", filter: &leaderboard.Filter{Window: "year"}, expectedError: leaderboard.ErrInvalidWindow},
+		{name: "mode", filter: &leaderboard.Filter{Mode: "marathon"}, expectedError: leaderboard.ErrInvalidMode},
+		{name: "sort", filter: &leaderboard.Filter{Sort: "name"}, expectedError: leaderboard.ErrInvalidSort},
+		{name: "limit", filter: &leaderboard.Filter{Limit: "101"}, expectedError: leaderboard.ErrInvalidLimit},
+		{name: "cursor", filter: &leaderboard.Filter{Cursor: "not-a-cursor"}, expectedError: leaderboard.ErrInvalidCursor},
+		{name: "country", filter: &leaderboard.Filter{Country: "XX"}, expectedError: leaderboard.ErrInvalidCountry},
+		{name: "region", filter: &leaderboard.Filter{Region: "Berlin"}, expectedError: leaderboard.ErrInvalidRegion},
+		{name: "country and region", filter: &leaderboard.Filter{Country: "de", Region: "Berlin"}},
+		{name: "defaults", filter: &leaderboard.Filter{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Leaderboard(ctx, "a@mail.com", tt.filter)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestLeaderboardPages(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	repo := &FakeLeaderboardRepo{entries: []*leaderboard.Entry{
+		{Rank: 1, Email: "a@mail.com", TestID: "00000000-0000-0000-0000-000000000001", WPM: 120, Accuracy: 97, CreatedAt: now},
+		{Rank: 2, Email: "b@mail.com", TestID: "00000000-0000-0000-0000-000000000002", WPM: 110, Accuracy: 99, CreatedAt: now},
+		{Rank: 3, Email: "c@mail.com", TestID: "00000000-0000-0000-0000-000000000003", WPM: 110, Accuracy: 95, CreatedAt: now},
+	}}
+	svc := NewLeaderboardService(repo)
+
+	first, err := svc.Leaderboard(ctx, "b@mail.com", &leaderboard.Filter{Window: leaderboard.WindowWeek, Limit: "2"})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(first.Entries) != 2 || first.NextCursor == "" || !first.Entries[1].You || first.Entries[0].You {
+		t.Fatalf("unexpected first page %+v", first)
+	}
+	if first.Mode != typing.Mode60s || first.Language != typing.DefaultLanguage || first.Sort != leaderboard.SortWPM {
+		t.Fatalf("expected the defaults, got %+v", first)
+	}
+
+	q := repo.queries[0]
+	if q.Limit != 3 || q.Since == nil || q.Since.Weekday() != time.Monday {
+		t.Fatalf("expected one extra entry since Monday, got %+v", q)
+	}
+
+	second, err := svc.Leaderboard(ctx, "b@mail.com", &leaderboard.Filter{Window: leaderboard.WindowWeek, Limit: "2",
+		Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(second.Entries) != 1 || second.Entries[0].Rank != 3 || second.NextCursor != "" {
+		t.Fatalf("unexpected second page %+v", second)
+	}
+
+	c := repo.queries[1].Cursor
+	if c == nil || c.Value != 110 || c.Tiebreak != 99 || c.TestID != repo.entries[1].TestID ||
+		!c.CreatedAt.Equal(now) {
+		t.Fatalf("expected the cursor after the second entry, got %+v", c)
+	}
+}
+
+// FakeDistributionRepo counts the scans of the distribution
+type FakeDistributionRepo struct {
+	port.LeaderboardRepository
+	buckets []*leaderboard.Bucket
+	speeds  map[string]int
+	scans   int
+}
+
+func (f *FakeDistributionRepo) GetDistribution(ctx context.Context, metric string) ([]*leaderboard.Bucket, error) {
+	f.scans++
+	return f.buckets, nil
+}
+
+func (f *FakeDistributionRepo) GetUserSpeeds(ctx context.Context, email string, metric string) (map[string]int, error) {
+	return f.speeds, nil
+}
+
+func TestStandings(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeDistributionRepo{
+		buckets: []*leaderboard.Bucket{
+			{Mode: "60s", WPM: 100, Users: 1},
+			{Mode: "60s", WPM: 40, Users: 6},
+			{Mode: "60s", WPM: 70, Users: 3},
+		},
+		speeds: map[string]int{"60s": 70, "15s": 50},
+	}
+	svc := NewLeaderboardService(repo)
+
+	if _, err := svc.Standings(ctx, "a@mail.com", "median"); err != leaderboard.ErrInvalidMetric {
+		t.Fatalf("expected %v, got %v", leaderboard.ErrInvalidMetric, err)
+	}
+
+	got, err := svc.Standings(ctx, "a@mail.com", "")
+	if err != nil {
+		t.Fatalf("expected %v, got %v", nil, err)
+	}
+	if got.Metric != leaderboard.MetricBest || len(got.Standings) != 2 {
+		t.Fatalf("expected %v, got %v", 2, len(got.Standings))
+	}
+
+	// no one else took a 15s test
+	if s := got.Standings[0]; s.Mode != "15s" || s.Rank != 1 || s.Users != 1 || s.Percentile != 0 {
+		t.Fatalf("expected %v, got %+v", "rank 1 of 1", s)
+	}
+	// 6 of the other 9 users are slower; the 3 as fast share the rank
+	if s := got.Standings[1]; s.Rank != 2 || s.Users != 10 || s.Percentile != 66 {
+		t.Fatalf("expected %v, got %+v", "rank 2 of 10 at 66%", s)
+	}
+
+	if _, err := svc.Standings(ctx, "b@mail.com", leaderboard.MetricBest); err != nil {
+		t.Fatalf("expected %v, got %v", nil, err)
+	}
+	if repo.scans != 1 {
+		t.Fatalf("expected %v, got %v", 1, repo.scans)
+	}
+
+	if err := svc.RefreshDistributions(ctx); err != nil {
+		t.Fatalf("expected %v, got %v", nil, err)
+	}
+	if repo.scans != 3 {
+		t.Fatalf("expected %v, got %v", 3, repo.scans)
+	}
+}
@@ -0,0 +1,595 @@
+package organization
+
+import (
+	"context"
+	"net"
+	"strings"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/organization"
+
+	"github.com/google/uuid"
+)
+
+type OrganizationServiceImpl struct {
+	orgSvc    port.OrganizationRepository
+	userSvc   port.UserRepository
+	txSvc     port.Transactor
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+}
+
+func NewOrganizationService(orgs port.OrganizationRepository, users port.UserRepository,
+	tx port.Transactor) organization.OrganizationService {
+	return &OrganizationServiceImpl{
+		orgSvc:    orgs,
+		userSvc:   users,
+		txSvc:     tx,
+		lookupTXT: net.DefaultResolver.LookupTXT,
+	}
+}
+
+// member looks up the organization and the user's membership of it
+func (s *OrganizationServiceImpl) member(ctx context.Context, email string, id string) (*organization.Organization,
+	*organization.Member, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil, organization.ErrOrganizationNotFound
+	}
+
+	org, err := s.orgSvc.GetOrganization(ctx, id)
+	if err != nil {
+		return nil, nil, organization.ErrGettingDataFromDB
+	}
+	if org == nil {
+		return nil, nil, organization.ErrOrganizationNotFound
+	}
+
+	m, err := s.orgSvc.GetMember(ctx, id, email)
+	if err != nil {
+		return nil, nil, organization.ErrGettingDataFromDB
+	}
+	if m == nil {
+		return nil, nil, organization.ErrNotMember
+	}
+
+	org.Role = m.Role
+	if !organization.CanManage(m.Role) {
+		org.Token = ""
+	}
+	return org, m, nil
+}
+
+// manager is member for the owner and the admins only
+func (s *OrganizationServiceImpl) manager(ctx context.Context, email string, id string) (*organization.Organization,
+	*organization.Member, error) {
+	org, m, err := s.member(ctx, email, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !organization.CanManage(m.Role) {
+		return nil, nil, organization.ErrForbidden
+	}
+	return org, m, nil
+}
+
+func (s *OrganizationServiceImpl) team(ctx context.Context, id string, teamID string) (*organization.Team, error) {
+	if _, err := uuid.Parse(teamID); err != nil {
+		return nil, organization.ErrTeamNotFound
+	}
+
+	t, err := s.orgSvc.GetTeam(ctx, id, teamID)
+	if err != nil {
+		return nil, organization.ErrGettingDataFromDB
+	}
+	if t == nil {
+		return nil, organization.ErrTeamNotFound
+	}
+	return t, nil
+}
+
+// claimDomain checks the email domain may be claimed for the organization:
+// no other organization may have verified it. The claim counts only once
+// VerifyDomain finds the TXT record, since an email address proves nothing.
+func (s *OrganizationServiceImpl) claimDomain(ctx context.Context, id string, domain string) (string, error) {
+	domain, err := organization.NormalizeDomain(domain)
+	if err != nil {
+		return "", err
+	}
+
+	owner, err := s.orgSvc.GetOrganizationByDomain(ctx, domain)
+	if err != nil {
+		return "", organization.ErrGettingDataFromDB
+	}
+	if owner != nil && owner.ID != id {
+		return "", organization.ErrDomainTaken
+	}
+	return domain, nil
+}
+
+func validName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	return name, name != "" && len(name) <= organization.MaxNameLength
+}
+
+// CreateOrganization makes the user its owner. A claimed email domain
+// starts unverified, with the token for its TXT record.
+func (s *OrganizationServiceImpl) CreateOrganization(ctx context.Context, email string,
+	o *organization.Organization) (*organization.Organization, error) {
+	name, ok := validName(o.Name)
+	if !ok {
+		return nil, organization.ErrInvalidOrganization
+	}
+
+	org := &organization.Organization{ID: uuid.NewString(), Name: name, CreatedBy: email}
+	if o.Domain != "" {
+		domain, err := s.claimDomain(ctx, org.ID, o.Domain)
+		if err != nil {
+			return nil, err
+		}
+		org.Domain = domain
+		org.Token = uuid.NewString()
+	}
+
+	var created *organization.Organization
+	err := s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.orgSvc.CreateOrganization(ctx, org); err != nil {
+			return organization.ErrSomethingWentWrong
+		}
+		if _, err := s.orgSvc.AddMember(ctx, org.ID, email, organization.RoleOwner); err != nil {
+			return organization.ErrSomethingWentWrong
+		}
+
+		var err error
+		created, err = s.orgSvc.GetOrganization(ctx, org.ID)
+		if err != nil || created == nil {
+			return organization.ErrGettingDataFromDB
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created.Role = organization.RoleOwner
+	return created, nil
+}
+
+func (s *OrganizationServiceImpl) Organizations(ctx context.Context, email string) ([]*organization.Organization, error) {
+	data, err := s.orgSvc.GetOrganizationsByEmail(ctx, email)
+	if err != nil {
+		return nil, organization.ErrGettingDataFromDB
+	}
+	for _, o := range data {
+		if !organization.CanManage(o.Role) {
+			o.Token = ""
+		}
+	}
+	return data, nil
+}
+
+func (s *OrganizationServiceImpl) Organization(ctx context.Context, email string, id string) (*organization.Details, error) {
+	org, _, err := s.member(ctx, email, id)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.orgSvc.GetMembers(ctx, id)
+	if err != nil {
+		return nil, organization.ErrGettingDataFromDB
+	}
+	teams, err := s.orgSvc.GetTeams(ctx, id)
+	if err != nil {
+		return nil, organization.ErrGettingDataFromDB
+	}
+
+	return &organization.Details{Organization: org, Members: members, Teams: teams}, nil
+}
+
+// UpdateOrganization renames the organization and sets its email domain.
+// Leaving the domain out stops auto-join; claiming a new one has to be
+// verified again before anyone at it joins.
+func (s *OrganizationServiceImpl) UpdateOrganization(ctx context.Context, email string, id string,
+	o *organization.Organization) (*organization.Organization, error) {
+	org, m, err := s.manager(ctx, email, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name, ok := validName(o.Name)
+	if !ok {
+		return nil, organization.ErrInvalidOrganization
+	}
+	org.Name = name
+
+	if o.Domain == "" {
+		org.Domain, org.Token, org.Verified = "", "", false
+	} else if domain, _ := organization.NormalizeDomain(o.Domain); domain != org.Domain {
+		domain, err := s.claimDomain(ctx, id, o.Domain)
+		if err != nil {
+			return nil, err
+		}
+		org.Domain, org.Token, org.Verified = domain, uuid.NewString(), false
+	}
+
+	if err := s.orgSvc.UpdateOrganization(ctx, org); err != nil {
+		return nil, organization.ErrSomethingWentWrong
+	}
+
+	updated, err := s.orgSvc.GetOrganization(ctx, id)
+	if err != nil || updated == nil {
+		return nil, organization.ErrGettingDataFromDB
+	}
+
+	updated.Role = m.Role
+	return updated, nil
+}
+
+// VerifyDomain looks for the organization's token in the TXT records of its
+// email domain. Once found, everyone already signed up with it joins as a
+// member, and so does everyone who signs up with it later.
+func (s *OrganizationServiceImpl) VerifyDomain(ctx context.Context, email string, id string) (*organization.Organization, error) {
+	org, m, err := s.manager(ctx, email, id)
+	if err != nil {
+		return nil, err
+	}
+	if org.Domain == "" {
+		return nil, organization.ErrInvalidDomain
+	}
+	if org.Verified {
+		return org, nil
+	}
+
+	records, err := s.lookupTXT(ctx, org.Domain)
+	if err != nil || !organization.HasVerification(records, org.Token) {
+		return nil, organization.ErrVerificationFailed
+	}
+
+	if _, err := s.claimDomain(ctx, id, org.Domain); err != nil {
+		return nil, err
+	}
+
+	org.Verified = true
+	err = s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.orgSvc.UpdateOrganization(ctx, org); err != nil {
+			return organization.ErrSomethingWentWrong
+		}
+		if err := s.orgSvc.AddMembersByDomain(ctx, id, org.Domain); err != nil {
+			return organization.ErrSomethingWentWrong
+		}
+
+		updated, err := s.orgSvc.GetOrganization(ctx, id)
+		if err != nil || updated == nil {
+			return organization.ErrGettingDataFromDB
+		}
+		org = updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	org.Role = m.Role
+	return org, nil
+}
+
+// DeleteOrganization is for the owner only
+func (s *OrganizationServiceImpl) DeleteOrganization(ctx context.Context, email string, id string) error {
+	_, m, err := s.member(ctx, email, id)
+	if err != nil {
+		return err
+	}
+	if m.Role != organization.RoleOwner {
+		return organization.ErrForbidden
+	}
+
+	if err := s.orgSvc.DeleteOrganization(ctx, id); err != nil {
+		return organization.ErrSomethingWentWrong
+	}
+	return nil
+}
+
+// Join lets a user whose email is at the organization's verified domain
+// join on their own
+func (s *OrganizationServiceImpl) Join(ctx context.Context, email string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return organization.ErrOrganizationNotFound
+	}
+
+	org, err := s.orgSvc.GetOrganization(ctx, id)
+	if err != nil {
+		return organization.ErrGettingDataFromDB
+	}
+	if org == nil {
+		return organization.ErrOrganizationNotFound
+	}
+	if org.Domain == "" || org.Domain != organization.EmailDomain(email) {
+		return organization.ErrDomainMismatch
+	}
+	if !org.Verified {
+		return organization.ErrDomainNotVerified
+	}
+
+	added, err := s.orgSvc.AddMember(ctx, id, email, organization.RoleMember)
+	if err != nil {
+		return organization.ErrSomethingWentWrong
+	}
+	if !added {
+		return organization.ErrAlreadyMember
+	}
+	return nil
+}
+
+// Leave is for everyone but the owner, who has to hand the organization
+// over or delete it
+func (s *OrganizationServiceImpl) Leave(ctx context.Context, email string, id string) error {
+	_, m, err := s.member(ctx, email, id)
+	if err != nil {
+		return err
+	}
+	if m.Role == organization.RoleOwner {
+		return organization.ErrOwnerCannotLeave
+	}
+
+	if _, err := s.orgSvc.RemoveMember(ctx, id, email); err != nil {
+		return organization.ErrSomethingWentWrong
+	}
+	return nil
+}
+
+// AddMember adds another user as a member or, for the owner, as an admin
+func (s *OrganizationServiceImpl) AddMember(ctx context.Context, email string, id string, member string,
+	role string) error {
+	_, m, err := s.manager(ctx, email, id)
+	if err != nil {
+		return err
+	}
+
+	if role == "" {
+		role = organization.RoleMember
+	}
+	if role != organization.RoleMember && role != organization.RoleAdmin {
+		return organization.ErrInvalidRole
+	}
+	if role == organization.RoleAdmin && m.Role != organization.RoleOwner {
+		return organization.ErrForbidden
+	}
+
+	userData, err := s.userSvc.GetUserByEmail(ctx, member)
+	if err != nil {
+		return organization.ErrGettingDataFromDB
+	}
+	if userData == nil {
+		return organization.ErrUserNotFound
+	}
+
+	added, err := s.orgSvc.AddMember(ctx, id, userData.Email, role)
+	if err != nil {
+		return organization.ErrSomethingWentWrong
+	}
+	if !added {
+		return organization.ErrAlreadyMember
+	}
+	return nil
+}
+
+// UpdateMemberRole is for the owner only. Making someone else the owner
+// hands the organization over, and the old owner stays on as an admin.
+func (s *OrganizationServiceImpl) UpdateMemberRole(ctx context.Context, email string, id string, member string,
+	role string) error {
+	_, m, err := s.member(ctx, email, id)
+	if err != nil {
+		return err
+	}
+	if m.Role != organization.RoleOwner {
+		return organization.ErrForbidden
+	}
+	if !organization.ValidRole(role) || member == email {
+		return organization.ErrInvalidRole
+	}
+
+	target, err := s.orgSvc.GetMember(ctx, id, member)
+	if err != nil {
+		return organization.ErrGettingDataFromDB
+	}
+	if target == nil {
+		return organization.ErrMemberNotFound
+	}
+
+	return s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.orgSvc.UpdateMemberRole(ctx, id, member, role); err != nil {
+			return organization.ErrSomethingWentWrong
+		}
+		if role == organization.RoleOwner {
+			if err := s.orgSvc.UpdateMemberRole(ctx, id, email, organization.RoleAdmin); err != nil {
+				return organization.ErrSomethingWentWrong
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveMember takes a member out of the organization and its teams. Admins
+// can only remove members; the owner can remove anyone but themselves.
+func (s *OrganizationServiceImpl) RemoveMember(ctx context.Context, email string, id string, member string) error {
+	if member == email {
+		return s.Leave(ctx, email, id)
+	}
+
+	_, m, err := s.manager(ctx, email, id)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.orgSvc.GetMember(ctx, id, member)
+	if err != nil {
+		return organization.ErrGettingDataFromDB
+	}
+	if target == nil {
+		return organization.ErrMemberNotFound
+	}
+	if target.Role == organization.RoleOwner || (target.Role == organization.RoleAdmin && m.Role != organization.RoleOwner) {
+		return organization.ErrForbidden
+	}
+
+	if _, err := s.orgSvc.RemoveMember(ctx, id, member); err != nil {
+		return organization.ErrSomethingWentWrong
+	}
+	return nil
+}
+
+func (s *OrganizationServiceImpl) CreateTeam(ctx context.Context, email string, id string,
+	t *organization.Team) (*organization.Team, error) {
+	if _, _, err := s.manager(ctx, email, id); err != nil {
+		return nil, err
+	}
+
+	name, ok := validName(t.Name)
+	if !ok {
+		return nil, organization.ErrInvalidTeam
+	}
+
+	team := &organization.Team{ID: uuid.NewString(), OrganizationID: id, Name: name}
+	created, err := s.orgSvc.CreateTeam(ctx, team)
+	if err != nil {
+		return nil, organization.ErrSomethingWentWrong
+	}
+	if !created {
+		return nil, organization.ErrTeamExists
+	}
+	return team, nil
+}
+
+func (s *OrganizationServiceImpl) Team(ctx context.Context, email string, id string,
+	teamID string) (*organization.TeamDetails, error) {
+	if _, _, err := s.member(ctx, email, id); err != nil {
+		return nil, err
+	}
+
+	t, err := s.team(ctx, id, teamID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.orgSvc.GetTeamMembers(ctx, teamID)
+	if err != nil {
+		return nil, organization.ErrGettingDataFromDB
+	}
+
+	return &organization.TeamDetails{Team: t, Members: members}, nil
+}
+
+func (s *OrganizationServiceImpl) DeleteTeam(ctx context.Context, email string, id string, teamID string) error {
+	if _, _, err := s.manager(ctx, email, id); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(teamID); err != nil {
+		return organization.ErrTeamNotFound
+	}
+
+	removed, err := s.orgSvc.DeleteTeam(ctx, id, teamID)
+	if err != nil {
+		return organization.ErrSomethingWentWrong
+	}
+	if !removed {
+		return organization.ErrTeamNotFound
+	}
+	return nil
+}
+
+// AddTeamMember puts a member of the organization on one of its teams
+func (s *OrganizationServiceImpl) AddTeamMember(ctx context.Context, email string, id string, teamID string,
+	member string) error {
+	if _, _, err := s.manager(ctx, email, id); err != nil {
+		return err
+	}
+	if _, err := s.team(ctx, id, teamID); err != nil {
+		return err
+	}
+
+	target, err := s.orgSvc.GetMember(ctx, id, member)
+	if err != nil {
+		return organization.ErrGettingDataFromDB
+	}
+	if target == nil {
+		return organization.ErrMemberNotFound
+	}
+
+	if err := s.orgSvc.AddTeamMember(ctx, id, teamID, member); err != nil {
+		return organization.ErrSomethingWentWrong
+	}
+	return nil
+}
+
+// RemoveTeamMember is for the owner and the admins, and for members taking
+// themselves off a team
+func (s *OrganizationServiceImpl) RemoveTeamMember(ctx context.Context, email string, id string, teamID string,
+	member string) error {
+	_, m, err := s.member(ctx, email, id)
+	if err != nil {
+		return err
+	}
+	if member != email && !organization.CanManage(m.Role) {
+		return organization.ErrForbidden
+	}
+	if _, err := s.team(ctx, id, teamID); err != nil {
+		return err
+	}
+
+	removed, err := s.orgSvc.RemoveTeamMember(ctx, teamID, member)
+	if err != nil {
+		return organization.ErrSomethingWentWrong
+	}
+	if !removed {
+		return organization.ErrMemberNotFound
+	}
+	return nil
+}
+
+// Leaderboard ranks the members of the organization, or of one of its
+// teams when teamID is set
+func (s *OrganizationServiceImpl) Leaderboard(ctx context.Context, email string, id string,
+	teamID string) ([]*organization.LeaderboardEntry, error) {
+	if _, _, err := s.member(ctx, email, id); err != nil {
+		return nil, err
+	}
+	if teamID != "" {
+		if _, err := s.team(ctx, id, teamID); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := s.orgSvc.GetLeaderboard(ctx, id, teamID, email, organization.LeaderboardLimit)
+	if err != nil {
+		return nil, organization.ErrGettingDataFromDB
+	}
+	for _, e := range entries {
+		e.You = e.Email == email
+	}
+	return entries, nil
+}
+
+// Dashboard is the site-wide dashboard over the members of the
+// organization, or of one of its teams when teamID is set
+func (s *OrganizationServiceImpl) Dashboard(ctx context.Context, email string, id string,
+	teamID string) (*organization.Dashboard, error) {
+	org, _, err := s.member(ctx, email, id)
+	if err != nil {
+		return nil, err
+	}
+	members := org.Members
+	if teamID != "" {
+		t, err := s.team(ctx, id, teamID)
+		if err != nil {
+			return nil, err
+		}
+		members = t.Members
+	}
+
+	topData, err := s.orgSvc.GetDashboardTopData(ctx, id, teamID)
+	if err != nil {
+		return nil, organization.ErrGettingDataFromDB
+	}
+	performers, err := s.orgSvc.GetTopPerformers(ctx, id, teamID, email)
+	if err != nil {
+		return nil, organization.ErrGettingDataFromDB
+	}
+
+	return &organization.Dashboard{Members: members, DashboardTopData: topData, TopPerformers: performers}, nil
+}
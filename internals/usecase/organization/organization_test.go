@@ -0,0 +1,441 @@
+package organization
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/organization"
+	"typing-speed/internals/core/user"
+)
+
+type key struct{ org, email string }
+
+// FakeOrganizationRepo keeps organizations, members and teams in memory
+type FakeOrganizationRepo struct {
+	port.OrganizationRepository
+	users   map[string]*user.User
+	orgs    map[string]*organization.Organization
+	members map[key]string
+	teams   map[string]*organization.Team
+	onTeam  map[key]bool
+}
+
+func (f *FakeOrganizationRepo) count(id string) int {
+	n := 0
+	for k := range f.members {
+		if k.org == id {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *FakeOrganizationRepo) CreateOrganization(ctx context.Context, o *organization.Organization) error {
+	copied := *o
+	f.orgs[o.ID] = &copied
+	return nil
+}
+
+func (f *FakeOrganizationRepo) GetOrganization(ctx context.Context, id string) (*organization.Organization, error) {
+	o, ok := f.orgs[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *o
+	copied.Members = f.count(id)
+	return &copied, nil
+}
+
+func (f *FakeOrganizationRepo) GetOrganizationByDomain(ctx context.Context, domain string) (*organization.Organization, error) {
+	for _, o := range f.orgs {
+		if o.Domain == domain && o.Verified {
+			return f.GetOrganization(ctx, o.ID)
+		}
+	}
+	return nil, nil
+}
+
+func (f *FakeOrganizationRepo) UpdateOrganization(ctx context.Context, o *organization.Organization) error {
+	f.orgs[o.ID].Name = o.Name
+	f.orgs[o.ID].Domain = o.Domain
+	f.orgs[o.ID].Token = o.Token
+	f.orgs[o.ID].Verified = o.Verified
+	return nil
+}
+
+func (f *FakeOrganizationRepo) AddMember(ctx context.Context, id string, email string, role string) (bool, error) {
+	if _, ok := f.members[key{id, email}]; ok {
+		return false, nil
+	}
+	f.members[key{id, email}] = role
+	return true, nil
+}
+
+func (f *FakeOrganizationRepo) AddMembersByDomain(ctx context.Context, id string, domain string) error {
+	for email := range f.users {
+		if organization.EmailDomain(email) == domain {
+			f.AddMember(ctx, id, email, organization.RoleMember)
+		}
+	}
+	return nil
+}
+
+func (f *FakeOrganizationRepo) GetMember(ctx context.Context, id string, email string) (*organization.Member, error) {
+	role, ok := f.members[key{id, email}]
+	if !ok {
+		return nil, nil
+	}
+	return &organization.Member{Email: email, Role: role}, nil
+}
+
+func (f *FakeOrganizationRepo) UpdateMemberRole(ctx context.Context, id string, email string, role string) error {
+	f.members[key{id, email}] = role
+	return nil
+}
+
+func (f *FakeOrganizationRepo) RemoveMember(ctx context.Context, id string, email string) (bool, error) {
+	_, ok := f.members[key{id, email}]
+	delete(f.members, key{id, email})
+	for k := range f.onTeam {
+		if k.email == email && f.teams[k.org].OrganizationID == id {
+			delete(f.onTeam, k)
+		}
+	}
+	return ok, nil
+}
+
+func (f *FakeOrganizationRepo) CreateTeam(ctx context.Context, t *organization.Team) (bool, error) {
+	for _, existing := range f.teams {
+		if existing.OrganizationID == t.OrganizationID && existing.Name == t.Name {
+			return false, nil
+		}
+	}
+	copied := *t
+	f.teams[t.ID] = &copied
+	return true, nil
+}
+
+func (f *FakeOrganizationRepo) GetTeam(ctx context.Context, id string, teamID string) (*organization.Team, error) {
+	t, ok := f.teams[teamID]
+	if !ok || t.OrganizationID != id {
+		return nil, nil
+	}
+	copied := *t
+	for k := range f.onTeam {
+		if k.org == teamID {
+			copied.Members++
+		}
+	}
+	return &copied, nil
+}
+
+func (f *FakeOrganizationRepo) AddTeamMember(ctx context.Context, id string, teamID string, email string) error {
+	f.onTeam[key{teamID, email}] = true
+	return nil
+}
+
+func (f *FakeOrganizationRepo) RemoveTeamMember(ctx context.Context, teamID string, email string) (bool, error) {
+	ok := f.onTeam[key{teamID, email}]
+	delete(f.onTeam, key{teamID, email})
+	return ok, nil
+}
+
+// GetLeaderboard ranks whoever the leaderboard is scoped to by their average speed
+func (f *FakeOrganizationRepo) GetLeaderboard(ctx context.Context, id string, teamID string, viewer string,
+	limit int) ([]*organization.LeaderboardEntry, error) {
+	entries := []*organization.LeaderboardEntry{}
+	for email, u := range f.users {
+		in := f.members[key{id, email}] != ""
+		if teamID != "" {
+			in = f.onTeam[key{teamID, email}]
+		}
+		if in {
+			entries = append(entries, &organization.LeaderboardEntry{Email: email, AvgSpeed: u.AvgSpeed})
+		}
+	}
+	for i := range entries {
+		entries[i].Rank = 1
+		for _, other := range entries {
+			if other.AvgSpeed > entries[i].AvgSpeed {
+				entries[i].Rank++
+			}
+		}
+	}
+	return entries, nil
+}
+
+type FakeUserRepo struct {
+	port.UserRepository
+	users map[string]*user.User
+}
+
+func (f *FakeUserRepo) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	return f.users[email], nil
+}
+
+type FakeTransactor struct{}
+
+func (f *FakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// newTestService publishes the TXT record of every organization that
+// claimed acme.io
+func newTestService() (*OrganizationServiceImpl, *FakeOrganizationRepo) {
+	users := map[string]*user.User{
+		"boss@acme.io":  {Name: "boss", Email: "boss@acme.io", AvgSpeed: 60},
+		"dev@acme.io":   {Name: "dev", Email: "dev@acme.io", AvgSpeed: 90},
+		"ops@acme.io":   {Name: "ops", Email: "ops@acme.io", AvgSpeed: 70},
+		"guest@mail.io": {Name: "guest", Email: "guest@mail.io", AvgSpeed: 100},
+	}
+	repo := &FakeOrganizationRepo{
+		users:   users,
+		orgs:    map[string]*organization.Organization{},
+		members: map[key]string{},
+		teams:   map[string]*organization.Team{},
+		onTeam:  map[key]bool{},
+	}
+	svc := NewOrganizationService(repo, &FakeUserRepo{users: users}, &FakeTransactor{}).(*OrganizationServiceImpl)
+	svc.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+		records := []string{"v=spf1 -all"}
+		for _, o := range repo.orgs {
+			if o.Domain == name && name == "acme.io" {
+				records = append(records, organization.VerificationPrefix+o.Token)
+			}
+		}
+		return records, nil
+	}
+	return svc, repo
+}
+
+func TestCreateOrganization(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		org           organization.Organization
+		expectedError error
+	}{
+		{name: "no name", org: organization.Organization{Name: "  "}, expectedError: organization.ErrInvalidOrganization},
+		{name: "too long", org: organization.Organization{Name: strings.Repeat("a", organization.MaxNameLength+1)},
+			expectedError: organization.ErrInvalidOrganization},
+		{name: "public domain", org: organization.Organization{Name: "Acme", Domain: "gmail.com"},
+			expectedError: organization.ErrInvalidDomain},
+		{name: "success", org: organization.Organization{Name: " Acme ", Domain: "@ACME.io"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestService()
+			org, err := svc.CreateOrganization(ctx, "boss@acme.io", &tt.org)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if org.Name != "Acme" || org.Domain != "acme.io" || org.Role != organization.RoleOwner {
+				t.Fatalf("unexpected organization %+v", org)
+			}
+			// nobody joins through an unverified domain
+			if org.Members != 1 || org.Verified || org.Token == "" {
+				t.Fatalf("expected only the owner and an unverified domain, got %+v", org)
+			}
+		})
+	}
+
+	// an unverified claim holds nothing, a verified one holds the domain
+	svc, _ := newTestService()
+	acme, _ := svc.CreateOrganization(ctx, "boss@acme.io", &organization.Organization{Name: "Acme", Domain: "acme.io"})
+	if _, err := svc.CreateOrganization(ctx, "dev@acme.io", &organization.Organization{Name: "Squat", Domain: "acme.io"}); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if _, err := svc.VerifyDomain(ctx, "boss@acme.io", acme.ID); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	_, err := svc.CreateOrganization(ctx, "dev@acme.io", &organization.Organization{Name: "Other", Domain: "acme.io"})
+	if err != organization.ErrDomainTaken {
+		t.Fatalf("expected %v, got %v", organization.ErrDomainTaken, err)
+	}
+}
+
+func TestVerifyDomain(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService()
+
+	// without the TXT record the claim stays unverified
+	other, _ := svc.CreateOrganization(ctx, "guest@mail.io", &organization.Organization{Name: "Mail", Domain: "mail.io"})
+	if _, err := svc.VerifyDomain(ctx, "guest@mail.io", other.ID); err != organization.ErrVerificationFailed {
+		t.Fatalf("expected %v, got %v", organization.ErrVerificationFailed, err)
+	}
+
+	org, _ := svc.CreateOrganization(ctx, "boss@acme.io", &organization.Organization{Name: "Acme", Domain: "acme.io"})
+	if err := svc.Join(ctx, "dev@acme.io", org.ID); err != organization.ErrDomainNotVerified {
+		t.Fatalf("expected %v, got %v", organization.ErrDomainNotVerified, err)
+	}
+	svc.AddMember(ctx, "boss@acme.io", org.ID, "ops@acme.io", "")
+	if _, err := svc.VerifyDomain(ctx, "ops@acme.io", org.ID); err != organization.ErrForbidden {
+		t.Fatalf("expected %v, got %v", organization.ErrForbidden, err)
+	}
+	// members do not see the token
+	visible, _, _ := svc.member(ctx, "ops@acme.io", org.ID)
+	if visible.Token != "" {
+		t.Fatalf("expected no token for a member, got %q", visible.Token)
+	}
+
+	// verifying adds everyone already signed up with the domain
+	verified, err := svc.VerifyDomain(ctx, "boss@acme.io", org.ID)
+	if err != nil || !verified.Verified || verified.Members != 3 {
+		t.Fatalf("expected the domain to be verified with 3 members, got %+v, %v", verified, err)
+	}
+	if repo.members[key{org.ID, "dev@acme.io"}] != organization.RoleMember {
+		t.Fatalf("expected dev to join, got %v", repo.members)
+	}
+
+	// claiming another domain has to be verified again
+	updated, err := svc.UpdateOrganization(ctx, "boss@acme.io", org.ID, &organization.Organization{Name: "Acme", Domain: "acme.dev"})
+	if err != nil || updated.Verified || updated.Token == org.Token {
+		t.Fatalf("expected a new unverified claim, got %+v, %v", updated, err)
+	}
+}
+
+func TestMembers(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService()
+
+	org, _ := svc.CreateOrganization(ctx, "boss@acme.io", &organization.Organization{Name: "Acme"})
+
+	if err := svc.Join(ctx, "dev@acme.io", org.ID); err != organization.ErrDomainMismatch {
+		t.Fatalf("expected joining without a domain to fail, got %v", err)
+	}
+	if err := svc.AddMember(ctx, "boss@acme.io", org.ID, "dev@acme.io", organization.RoleAdmin); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if err := svc.AddMember(ctx, "boss@acme.io", org.ID, "dev@acme.io", ""); err != organization.ErrAlreadyMember {
+		t.Fatalf("expected %v, got %v", organization.ErrAlreadyMember, err)
+	}
+	// admins add members but not admins
+	if err := svc.AddMember(ctx, "dev@acme.io", org.ID, "ops@acme.io", organization.RoleAdmin); err != organization.ErrForbidden {
+		t.Fatalf("expected %v, got %v", organization.ErrForbidden, err)
+	}
+	if err := svc.AddMember(ctx, "dev@acme.io", org.ID, "ops@acme.io", ""); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if err := svc.AddMember(ctx, "ops@acme.io", org.ID, "guest@mail.io", ""); err != organization.ErrForbidden {
+		t.Fatalf("expected members not to add members, got %v", err)
+	}
+	if err := svc.RemoveMember(ctx, "dev@acme.io", org.ID, "boss@acme.io"); err != organization.ErrForbidden {
+		t.Fatalf("expected %v, got %v", organization.ErrForbidden, err)
+	}
+	if _, err := svc.Organization(ctx, "guest@mail.io", org.ID); err != organization.ErrNotMember {
+		t.Fatalf("expected %v, got %v", organization.ErrNotMember, err)
+	}
+
+	// handing over the organization keeps the old owner on as an admin
+	if err := svc.UpdateMemberRole(ctx, "dev@acme.io", org.ID, "ops@acme.io", organization.RoleAdmin); err != organization.ErrForbidden {
+		t.Fatalf("expected only the owner to change roles, got %v", err)
+	}
+	if err := svc.UpdateMemberRole(ctx, "boss@acme.io", org.ID, "ops@acme.io", organization.RoleOwner); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if repo.members[key{org.ID, "ops@acme.io"}] != organization.RoleOwner ||
+		repo.members[key{org.ID, "boss@acme.io"}] != organization.RoleAdmin {
+		t.Fatalf("unexpected roles %v", repo.members)
+	}
+	if err := svc.Leave(ctx, "ops@acme.io", org.ID); err != organization.ErrOwnerCannotLeave {
+		t.Fatalf("expected %v, got %v", organization.ErrOwnerCannotLeave, err)
+	}
+	if err := svc.DeleteOrganization(ctx, "boss@acme.io", org.ID); err != organization.ErrForbidden {
+		t.Fatalf("expected %v, got %v", organization.ErrForbidden, err)
+	}
+	if err := svc.Leave(ctx, "boss@acme.io", org.ID); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	// a verified domain lets the rest of the company join on their own
+	updated, err := svc.UpdateOrganization(ctx, "ops@acme.io", org.ID, &organization.Organization{Name: "Acme", Domain: "acme.io"})
+	if err != nil || updated.Members != 2 {
+		t.Fatalf("expected claiming the domain to add nobody, got %+v, %v", updated, err)
+	}
+	if err := svc.Join(ctx, "boss@acme.io", org.ID); err != organization.ErrDomainNotVerified {
+		t.Fatalf("expected %v, got %v", organization.ErrDomainNotVerified, err)
+	}
+	if _, err := svc.VerifyDomain(ctx, "ops@acme.io", org.ID); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if repo.members[key{org.ID, "boss@acme.io"}] != organization.RoleMember {
+		t.Fatalf("expected verifying the domain to add boss back, got %v", repo.members)
+	}
+	if err := svc.Join(ctx, "guest@mail.io", org.ID); err != organization.ErrDomainMismatch {
+		t.Fatalf("expected %v, got %v", organization.ErrDomainMismatch, err)
+	}
+	if err := svc.Join(ctx, "dev@acme.io", org.ID); err != organization.ErrAlreadyMember {
+		t.Fatalf("expected %v, got %v", organization.ErrAlreadyMember, err)
+	}
+	if err := svc.Join(ctx, "new@acme.io", org.ID); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if repo.members[key{org.ID, "new@acme.io"}] != organization.RoleMember {
+		t.Fatalf("expected joining to make a member, got %v", repo.members)
+	}
+}
+
+func TestTeams(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService()
+
+	org, _ := svc.CreateOrganization(ctx, "boss@acme.io", &organization.Organization{Name: "Acme", Domain: "acme.io"})
+	for _, member := range []string{"dev@acme.io", "ops@acme.io", "guest@mail.io"} {
+		svc.AddMember(ctx, "boss@acme.io", org.ID, member, "")
+	}
+
+	if _, err := svc.CreateTeam(ctx, "dev@acme.io", org.ID, &organization.Team{Name: "Platform"}); err != organization.ErrForbidden {
+		t.Fatalf("expected %v, got %v", organization.ErrForbidden, err)
+	}
+	team, err := svc.CreateTeam(ctx, "boss@acme.io", org.ID, &organization.Team{Name: "Platform"})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if _, err := svc.CreateTeam(ctx, "boss@acme.io", org.ID, &organization.Team{Name: "Platform"}); err != organization.ErrTeamExists {
+		t.Fatalf("expected %v, got %v", organization.ErrTeamExists, err)
+	}
+
+	if err := svc.AddTeamMember(ctx, "boss@acme.io", org.ID, team.ID, "nobody@acme.io"); err != organization.ErrMemberNotFound {
+		t.Fatalf("expected %v, got %v", organization.ErrMemberNotFound, err)
+	}
+	svc.AddTeamMember(ctx, "boss@acme.io", org.ID, team.ID, "dev@acme.io")
+	svc.AddTeamMember(ctx, "boss@acme.io", org.ID, team.ID, "ops@acme.io")
+
+	// the guest is the fastest in the organization but not on the team
+	entries, err := svc.Leaderboard(ctx, "ops@acme.io", org.ID, team.ID)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected the 2 team members, got %v, %v", entries, err)
+	}
+	for _, e := range entries {
+		if (e.Email == "dev@acme.io" && e.Rank != 1) || (e.Email == "ops@acme.io" && (e.Rank != 2 || !e.You)) {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+	}
+	entries, _ = svc.Leaderboard(ctx, "ops@acme.io", org.ID, "")
+	if len(entries) != 4 {
+		t.Fatalf("expected the 4 organization members, got %d", len(entries))
+	}
+	if _, err := svc.Leaderboard(ctx, "ops@acme.io", org.ID, "not-a-team"); err != organization.ErrTeamNotFound {
+		t.Fatalf("expected %v, got %v", organization.ErrTeamNotFound, err)
+	}
+
+	// members can take themselves off a team, but nobody else
+	if err := svc.RemoveTeamMember(ctx, "ops@acme.io", org.ID, team.ID, "dev@acme.io"); err != organization.ErrForbidden {
+		t.Fatalf("expected %v, got %v", organization.ErrForbidden, err)
+	}
+	if err := svc.RemoveTeamMember(ctx, "ops@acme.io", org.ID, team.ID, "ops@acme.io"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	// leaving the organization takes the member off its teams
+	svc.Leave(ctx, "dev@acme.io", org.ID)
+	if len(repo.onTeam) != 0 {
+		t.Fatalf("expected the team to be empty, got %v", repo.onTeam)
+	}
+}
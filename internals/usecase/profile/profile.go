@@ -0,0 +1,68 @@
+package profile
+
+import (
+	"context"
+	"strings"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/profile"
+	"typing-speed/internals/core/user"
+)
+
+type ProfileServiceImpl struct {
+	userSvc         port.UserRepository
+	personalBestSvc port.PersonalBestRepository
+	typingSvc       port.TypingRepository
+	friendSvc       port.FriendRepository
+}
+
+func NewProfileService(users port.UserRepository, pbs port.PersonalBestRepository, tests port.TypingRepository,
+	friends port.FriendRepository) profile.ProfileService {
+	return &ProfileServiceImpl{
+		userSvc:         users,
+		personalBestSvc: pbs,
+		typingSvc:       tests,
+		friendSvc:       friends,
+	}
+}
+
+// Profile shows the user with the username to the viewer, as far as their
+// privacy setting allows. Users who block the viewer have no profile for them.
+func (s *ProfileServiceImpl) Profile(ctx context.Context, email string, username string) (*profile.Profile, error) {
+	u, err := s.userSvc.GetUserByUsername(ctx, strings.ToLower(strings.TrimSpace(username)))
+	if err != nil {
+		return nil, profile.ErrGettingDataFromDB
+	}
+	if u == nil {
+		return nil, profile.ErrProfileNotFound
+	}
+
+	self := u.Email == email
+	friends := false
+	if !self {
+		rel, err := s.friendSvc.GetRelationship(ctx, email, u.Email)
+		if err != nil {
+			return nil, profile.ErrGettingDataFromDB
+		}
+		if rel.BlockedBy {
+			return nil, profile.ErrProfileNotFound
+		}
+		friends = rel.Following && rel.FollowedBy
+	}
+	if !user.CanView(u.Privacy, self, friends) {
+		return nil, profile.ErrPrivateProfile
+	}
+
+	bests, err := s.personalBestSvc.GetPersonalBests(ctx, u.Email)
+	if err != nil {
+		return nil, profile.ErrGettingDataFromDB
+	}
+	tests, err := s.typingSvc.GetLatestTests(ctx, u.Email, profile.RecentTestsLimit)
+	if err != nil {
+		return nil, profile.ErrGettingDataFromDB
+	}
+
+	p := profile.NewProfile(u, bests, tests, time.Now())
+	p.You = self
+	return p, nil
+}
@@ -0,0 +1,105 @@
+package profile
+
+import (
+	"context"
+	"testing"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/friend"
+	"typing-speed/internals/core/profile"
+	"typing-speed/internals/core/typing"
+	"typing-speed/internals/core/user"
+)
+
+type pair struct{ a, b string }
+
+type FakeUserRepo struct {
+	port.UserRepository
+	users []*user.User
+}
+
+func (f *FakeUserRepo) GetUserByUsername(ctx context.Context, username string) (*user.User, error) {
+	for _, u := range f.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+type FakeFriendRepo struct {
+	port.FriendRepository
+	follows map[pair]bool
+	blocks  map[pair]bool
+}
+
+func (f *FakeFriendRepo) GetRelationship(ctx context.Context, email string, other string) (*friend.Relationship, error) {
+	return &friend.Relationship{
+		Following:  f.follows[pair{email, other}],
+		FollowedBy: f.follows[pair{other, email}],
+		Blocking:   f.blocks[pair{email, other}],
+		BlockedBy:  f.blocks[pair{other, email}],
+	}, nil
+}
+
+type FakePersonalBestRepo struct {
+	port.PersonalBestRepository
+}
+
+func (f *FakePersonalBestRepo) GetPersonalBests(ctx context.Context, email string) ([]*typing.PersonalBest, error) {
+	return []*typing.PersonalBest{{Mode: typing.Mode60s, Language: typing.DefaultLanguage, WPM: 90}}, nil
+}
+
+type FakeTypingRepo struct {
+	port.TypingRepository
+}
+
+func (f *FakeTypingRepo) GetLatestTests(ctx context.Context, email string, limit int) ([]*typing.TypingData, error) {
+	return []*typing.TypingData{{ID: "t1", Email: email, WPM: 80, TotalWords: 50, TypedWords: 50, TotalErrors: 5}}, nil
+}
+
+func TestProfile(t *testing.T) {
+	ctx := context.Background()
+	users := &FakeUserRepo{users: []*user.User{
+		{Name: "Pub", Email: "pub@mail.com", Username: "pub", Privacy: user.PrivacyPublic},
+		{Name: "Pal", Email: "pal@mail.com", Username: "pal", Privacy: user.PrivacyFriends},
+		{Name: "Hid", Email: "hid@mail.com", Username: "hid", Privacy: user.PrivacyPrivate},
+	}}
+	friends := &FakeFriendRepo{
+		follows: map[pair]bool{{"a@mail.com", "pal@mail.com"}: true, {"pal@mail.com", "a@mail.com"}: true},
+		blocks:  map[pair]bool{{"pub@mail.com", "x@mail.com"}: true},
+	}
+	svc := NewProfileService(users, &FakePersonalBestRepo{}, &FakeTypingRepo{}, friends)
+
+	tests := []struct {
+		name          string
+		viewer        string
+		username      string
+		expectedError error
+	}{
+		{name: "unknown", viewer: "a@mail.com", username: "nobody", expectedError: profile.ErrProfileNotFound},
+		{name: "public", viewer: "a@mail.com", username: " PUB "},
+		{name: "blocked by the user", viewer: "x@mail.com", username: "pub", expectedError: profile.ErrProfileNotFound},
+		{name: "friends only as a friend", viewer: "a@mail.com", username: "pal"},
+		{name: "friends only as a stranger", viewer: "x@mail.com", username: "pal", expectedError: profile.ErrPrivateProfile},
+		{name: "private", viewer: "a@mail.com", username: "hid", expectedError: profile.ErrPrivateProfile},
+		{name: "private as the owner", viewer: "hid@mail.com", username: "hid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := svc.Profile(ctx, tt.viewer, tt.username)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(p.PersonalBests) != 1 || len(p.RecentTests) != 1 || p.RecentTests[0].Accuracy != 90 {
+				t.Fatalf("expected personal bests and recent tests, got %+v", p)
+			}
+			if p.You != (tt.viewer == "hid@mail.com") {
+				t.Fatalf("expected you to be set for the owner only, got %v", p.You)
+			}
+		})
+	}
+}
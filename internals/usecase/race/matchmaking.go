@@ -0,0 +1,168 @@
+package race
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+	"typing-speed/internals/core/race"
+
+	"github.com/google/uuid"
+)
+
+// ticket is a player waiting in the quick race queue
+type ticket struct {
+	c        *conn
+	rating   float64
+	joinedAt time.Time
+}
+
+// window is the rating gap the player accepts, widening the longer they wait
+func (t *ticket) window(now time.Time, config Config) float64 {
+	waited := now.Sub(t.joinedAt).Seconds()
+	return math.Min(config.MatchRange+config.RangeGrowth*waited, config.MaxMatchRange)
+}
+
+// QuickRace queues the user for a rated race against players of similar rating
+func (s *RaceServiceImpl) QuickRace(ctx context.Context, email string) (race.Session, error) {
+	userData, err := s.userSvc.GetUserByEmail(ctx, email)
+	if err != nil || userData == nil {
+		return nil, race.ErrGettingDataFromDB
+	}
+
+	current, err := s.Rating(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newConn(s, userData.Name, email)
+
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	for _, t := range s.queue {
+		if t.c.p.email == email {
+			return nil, race.ErrAlreadyQueued
+		}
+	}
+
+	s.queue = append(s.queue, &ticket{c: c, rating: current.Rating, joinedAt: time.Now()})
+	c.events <- &race.Event{Type: race.EventQueued}
+
+	if !s.matching {
+		s.matching = true
+		go s.matchLoop()
+	}
+
+	return c, nil
+}
+
+// dequeue takes a waiting player out of the queue, reporting false when they are not in it
+func (s *RaceServiceImpl) dequeue(c *conn) bool {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	for i, t := range s.queue {
+		if t.c == c {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			close(c.events)
+			return true
+		}
+	}
+	return false
+}
+
+// matchLoop matches the queue until it is empty
+func (s *RaceServiceImpl) matchLoop() {
+	ticker := time.NewTicker(s.config.MatchInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.queueMu.Lock()
+		rooms := s.matchQueue(time.Now())
+		empty := len(s.queue) == 0
+		if empty {
+			s.matching = false
+		}
+		s.queueMu.Unlock()
+
+		for _, r := range rooms {
+			go r.run()
+		}
+		if empty {
+			return
+		}
+	}
+}
+
+// matchQueue seats the matched players in new rated rooms and removes them
+// from the queue. The rooms are returned not yet running. Callers hold queueMu.
+func (s *RaceServiceImpl) matchQueue(now time.Time) []*room {
+	groups := matchGroups(s.queue, now, s.config)
+	if len(groups) == 0 {
+		return nil
+	}
+
+	matched := map[*ticket]bool{}
+	rooms := make([]*room, 0, len(groups))
+	for _, group := range groups {
+		r := newRoom(s, uuid.NewString(), "", race.DefaultSettings())
+		r.rated = true
+		for _, t := range group {
+			matched[t] = true
+			r.seat(t.c)
+			t.c.setRoom(r)
+		}
+		s.addRoom(r)
+		rooms = append(rooms, r)
+	}
+
+	waiting := s.queue[:0]
+	for _, t := range s.queue {
+		if !matched[t] {
+			waiting = append(waiting, t)
+		}
+	}
+	s.queue = waiting
+
+	return rooms
+}
+
+// matchGroups groups the queue into races, longest waiting first. A player
+// is grouped with the closest ratings that both sides accept. A group races
+// once it is full, or once its first player has waited long enough for a
+// smaller race to be better than none.
+func matchGroups(queue []*ticket, now time.Time, config Config) [][]*ticket {
+	used := map[*ticket]bool{}
+	groups := [][]*ticket{}
+
+	for _, anchor := range queue {
+		if used[anchor] {
+			continue
+		}
+
+		candidates := []*ticket{}
+		for _, t := range queue {
+			if t == anchor || used[t] || t.c.p.email == anchor.c.p.email {
+				continue
+			}
+			gap := math.Abs(t.rating - anchor.rating)
+			if gap <= anchor.window(now, config) && gap <= t.window(now, config) {
+				candidates = append(candidates, t)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return math.Abs(candidates[i].rating-anchor.rating) < math.Abs(candidates[j].rating-anchor.rating)
+		})
+
+		group := append([]*ticket{anchor}, candidates[:min(len(candidates), race.QuickRacePlayers-1)]...)
+		if len(group) == race.QuickRacePlayers || len(group) >= race.MinPlayers && now.Sub(anchor.joinedAt) >= config.FillWait {
+			for _, t := range group {
+				used[t] = true
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
@@ -0,0 +1,197 @@
+package race
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+	"typing-speed/internals/core/race"
+	"typing-speed/internals/core/rating"
+)
+
+func newTicket(email string, r float64, waited time.Duration, now time.Time) *ticket {
+	return &ticket{c: &conn{p: &participant{email: email}}, rating: r, joinedAt: now.Add(-waited)}
+}
+
+func TestMatchGroups(t *testing.T) {
+	now := time.Now()
+	config := Config{FillWait: 10 * time.Second}.withDefaults()
+
+	tests := []struct {
+		name     string
+		queue    []*ticket
+		expected [][]string
+	}{
+		{
+			name: "full group of close ratings",
+			queue: []*ticket{
+				newTicket("a", 1500, 0, now),
+				newTicket("b", 1520, 0, now),
+				newTicket("c", 1480, 0, now),
+				newTicket("d", 1550, 0, now),
+			},
+			expected: [][]string{{"a", "b", "c", "d"}},
+		},
+		{
+			name: "small group waits to fill",
+			queue: []*ticket{
+				newTicket("a", 1500, 2*time.Second, now),
+				newTicket("b", 1520, 0, now),
+			},
+			expected: [][]string{},
+		},
+		{
+			name: "small group races after the fill wait",
+			queue: []*ticket{
+				newTicket("a", 1500, 11*time.Second, now),
+				newTicket("b", 1520, 0, now),
+			},
+			expected: [][]string{{"a", "b"}},
+		},
+		{
+			name: "distant ratings are not matched",
+			queue: []*ticket{
+				newTicket("a", 1500, 11*time.Second, now),
+				newTicket("b", 1900, 11*time.Second, now),
+			},
+			expected: [][]string{},
+		},
+		{
+			name: "the range widens while both wait",
+			queue: []*ticket{
+				newTicket("a", 1500, 40*time.Second, now),
+				newTicket("b", 1900, 40*time.Second, now),
+			},
+			expected: [][]string{{"a", "b"}},
+		},
+		{
+			name: "the closest ratings are picked",
+			queue: []*ticket{
+				newTicket("a", 1500, 0, now),
+				newTicket("b", 1590, 0, now),
+				newTicket("c", 1510, 0, now),
+				newTicket("d", 1490, 0, now),
+				newTicket("e", 1505, 0, now),
+			},
+			expected: [][]string{{"a", "e", "c", "d"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := matchGroups(tt.queue, now, config)
+
+			got := [][]string{}
+			for _, g := range groups {
+				emails := []string{}
+				for _, tk := range g {
+					emails = append(emails, tk.c.p.email)
+				}
+				got = append(got, emails)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestQuickRace(t *testing.T) {
+	ctx := context.Background()
+	races := newFakeRaceRepo()
+	ratings := newFakeRatingRepo()
+	ratings.saved = make(chan struct{})
+	svc := NewRaceService(&FakeUserRepo{}, races, ratings, &FakeTransactor{}, &FakeTypingService{}, nil, Config{
+		Countdown:     10 * time.Millisecond,
+		TimeLimit:     time.Second,
+		TickInterval:  5 * time.Millisecond,
+		MatchInterval: 5 * time.Millisecond,
+		FillWait:      time.Millisecond,
+		MaxWPM:        testWPM,
+	})
+
+	fast, err := svc.QuickRace(ctx, "fast@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	waitFor(t, fast, race.EventQueued)
+
+	if _, err := svc.QuickRace(ctx, "fast@mail.com"); err != race.ErrAlreadyQueued {
+		t.Fatalf("expected %v, got %v", race.ErrAlreadyQueued, err)
+	}
+
+	slow, err := svc.QuickRace(ctx, "slow@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	// matched rooms start on their own
+	ev := waitFor(t, fast, race.EventCountdown)
+	if !ev.Room.Rated || len(ev.Room.Participants) != 2 {
+		t.Fatalf("expected a rated race of two, got %+v", ev.Room)
+	}
+	text := ev.Room.Text
+
+	waitFor(t, fast, race.EventStart)
+	fast.Send(race.ClientMessage{Type: race.MsgProgress, Typed: len(text)})
+	waitFor(t, slow, race.EventFinish)
+	slow.Leave()
+
+	select {
+	case <-ratings.saved:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("ratings were not updated")
+	}
+
+	if rc := races.waitRecorded(t); !rc.Rated {
+		t.Fatalf("expected the race to be recorded as rated")
+	}
+	winner, loser := ratings.ratings["fast@mail.com"], ratings.ratings["slow@mail.com"]
+	if winner.Rating <= rating.DefaultRating || loser.Rating >= rating.DefaultRating {
+		t.Fatalf("expected the winner to gain and the loser to lose, got %v and %v", winner.Rating, loser.Rating)
+	}
+	if winner.Races != 1 || ratings.changes["fast@mail.com"] <= 0 {
+		t.Fatalf("expected one rated race with a gain, got %+v", winner)
+	}
+}
+
+// The rating update follows the worked example of Glickman's Glicko-2 paper
+func TestRateRace(t *testing.T) {
+	ctx := context.Background()
+	ratings := newFakeRatingRepo()
+	for email, r := range map[string]*rating.Rating{
+		"player@mail.com": {Rating: 1500, Deviation: 200, Volatility: 0.06},
+		"a@mail.com":      {Rating: 1400, Deviation: 30, Volatility: 0.06},
+		"b@mail.com":      {Rating: 1550, Deviation: 100, Volatility: 0.06},
+		"c@mail.com":      {Rating: 1700, Deviation: 300, Volatility: 0.06},
+	} {
+		r.Email = email
+		ratings.ratings[email] = r
+	}
+	svc := NewRaceService(&FakeUserRepo{}, newFakeRaceRepo(), ratings, &FakeTransactor{}, &FakeTypingService{}, nil, Config{})
+
+	rc := &race.Race{
+		ID:    "race-id",
+		Rated: true,
+		Results: []*race.Result{
+			{Email: "c@mail.com", Position: 1},
+			{Email: "b@mail.com", Position: 2},
+			{Email: "player@mail.com", Position: 3},
+			{Email: "a@mail.com", Typed: 40},
+			// a flagged racer neither gets a rating nor changes anyone else's
+			{Email: "cheat@mail.com", Position: 1, Flagged: true},
+		},
+	}
+	if err := svc.(*RaceServiceImpl).rate(ctx, rc); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	if _, ok := ratings.changes["cheat@mail.com"]; ok {
+		t.Fatalf("expected the flagged racer not to be rated")
+	}
+	got := ratings.ratings["player@mail.com"]
+	if math.Abs(got.Rating-1464.06) > 0.01 || math.Abs(got.Deviation-151.52) > 0.01 || math.Abs(got.Volatility-0.05999) > 0.00001 {
+		t.Fatalf("expected 1464.06 / 151.52 / 0.05999, got %v / %v / %v", got.Rating, got.Deviation, got.Volatility)
+	}
+}
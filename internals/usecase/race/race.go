@@ -0,0 +1,399 @@
+package race
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/feed"
+	"typing-speed/internals/core/race"
+	"typing-speed/internals/core/rating"
+	"typing-speed/internals/core/typing"
+
+	"github.com/google/uuid"
+)
+
+// Config tunes the timing of race rooms. Zero fields use the defaults.
+type Config struct {
+	Countdown      time.Duration // from the host's start to the race start
+	TimeLimit      time.Duration // caps the time limit of the room settings
+	TickInterval   time.Duration // how often progress is broadcast
+	IdleTimeout    time.Duration // a room nobody has done anything in for this long is closed
+	ReconnectGrace time.Duration // how long a disconnected participant keeps their seat
+	JoinWindow     time.Duration // how long a match waits for all its players
+	MaxWPM         int           // the fastest a racer's progress may advance
+
+	MatchInterval time.Duration // how often the quick race queue is matched
+	FillWait      time.Duration // how long to wait for a full quick race before starting a smaller one
+	MatchRange    float64       // the rating gap accepted at first
+	RangeGrowth   float64       // how much the accepted gap widens per second of waiting
+	MaxMatchRange float64
+}
+
+func (c Config) withDefaults() Config {
+	if c.Countdown <= 0 {
+		c.Countdown = 5 * time.Second
+	}
+	if c.TimeLimit <= 0 {
+		c.TimeLimit = race.MaxDuration * time.Second
+	}
+	if c.TickInterval <= 0 {
+		c.TickInterval = 500 * time.Millisecond
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = 15 * time.Minute
+	}
+	if c.ReconnectGrace <= 0 {
+		c.ReconnectGrace = 30 * time.Second
+	}
+	if c.JoinWindow <= 0 {
+		c.JoinWindow = 5 * time.Minute
+	}
+	if c.MaxWPM <= 0 {
+		c.MaxWPM = race.MaxWPM
+	}
+	if c.MatchInterval <= 0 {
+		c.MatchInterval = time.Second
+	}
+	if c.FillWait <= 0 {
+		c.FillWait = 10 * time.Second
+	}
+	if c.MatchRange <= 0 {
+		c.MatchRange = 100
+	}
+	if c.RangeGrowth <= 0 {
+		c.RangeGrowth = 10
+	}
+	if c.MaxMatchRange <= 0 {
+		c.MaxMatchRange = 1000
+	}
+	return c
+}
+
+// RaceServiceImpl keeps the live rooms in memory, each one run by its own goroutine
+type RaceServiceImpl struct {
+	userSvc   port.UserRepository
+	raceSvc   port.RaceRepository
+	ratingSvc port.RatingRepository
+	txSvc     port.Transactor
+	typingSvc typing.TypingService
+	feedSvc   feed.FeedService
+	config    Config
+
+	mu    sync.Mutex
+	rooms map[string]*room
+	codes map[string]*room // private rooms by invite code
+
+	queueMu  sync.Mutex
+	queue    []*ticket
+	matching bool // whether the matchmaking goroutine is running
+}
+
+func NewRaceService(users port.UserRepository, races port.RaceRepository, ratings port.RatingRepository,
+	tx port.Transactor, typingSvc typing.TypingService, feeds feed.FeedService, config Config) race.RaceService {
+	return &RaceServiceImpl{
+		userSvc:   users,
+		raceSvc:   races,
+		ratingSvc: ratings,
+		txSvc:     tx,
+		typingSvc: typingSvc,
+		feedSvc:   feeds,
+		config:    config.withDefaults(),
+		rooms:     map[string]*room{},
+		codes:     map[string]*room{},
+	}
+}
+
+// CreateRoom opens a private lobby hosted by the user, who still has to join it.
+// Settings left out take the defaults.
+func (s *RaceServiceImpl) CreateRoom(ctx context.Context, email string, settings *race.Settings) (*race.Room, error) {
+	roomSettings := race.Settings{}
+	if settings != nil {
+		roomSettings = *settings
+	}
+	if err := roomSettings.Normalize(); err != nil {
+		return nil, err
+	}
+
+	r := newRoom(s, uuid.NewString(), email, roomSettings)
+	token, err := race.NewSpectatorToken()
+	if err != nil {
+		return nil, race.ErrSomethingWentWrong
+	}
+	r.spectatorToken = token
+	if err := s.addPrivateRoom(r); err != nil {
+		return nil, race.ErrSomethingWentWrong
+	}
+
+	go r.run()
+
+	return &race.Room{
+		ID:             r.id,
+		InviteCode:     r.inviteCode,
+		SpectatorToken: r.spectatorToken,
+		Status:         race.StatusLobby,
+		Settings:       r.settings,
+		Participants:   []*race.Participant{},
+	}, nil
+}
+
+// CreateMatchRoom opens a room only the players can join. It starts on its
+// own once they are all in, and hands the outcome to done when it closes.
+func (s *RaceServiceImpl) CreateMatchRoom(ctx context.Context, players []string, settings *race.Settings,
+	done func(*race.MatchResult)) (*race.Room, error) {
+	if len(players) < race.MinPlayers || len(players) > race.MaxPlayers {
+		return nil, race.ErrInvalidSettings
+	}
+
+	roomSettings := race.Settings{}
+	if settings != nil {
+		roomSettings = *settings
+	}
+	roomSettings.MaxPlayers = len(players)
+	if err := roomSettings.Normalize(); err != nil {
+		return nil, err
+	}
+
+	r := newRoom(s, uuid.NewString(), "", roomSettings)
+	r.players = make(map[string]bool, len(players))
+	for _, email := range players {
+		r.players[email] = true
+	}
+	r.joinDeadline = time.Now().Add(s.config.JoinWindow)
+	r.onFinish = done
+
+	token, err := race.NewSpectatorToken()
+	if err != nil {
+		return nil, race.ErrSomethingWentWrong
+	}
+	r.spectatorToken = token
+	s.addRoom(r)
+
+	go r.run()
+
+	return &race.Room{
+		ID:             r.id,
+		SpectatorToken: r.spectatorToken,
+		Status:         race.StatusLobby,
+		Settings:       r.settings,
+		Participants:   []*race.Participant{},
+	}, nil
+}
+
+// JoinRoom seats the user in the room with the given id or invite code. A
+// user who already has a seat there gets it back, which is how a dropped
+// connection resumes.
+func (s *RaceServiceImpl) JoinRoom(ctx context.Context, room string, email string) (race.Session, error) {
+	s.mu.Lock()
+	r := s.rooms[room]
+	if r == nil {
+		r = s.codes[strings.ToUpper(room)]
+	}
+	s.mu.Unlock()
+	if r == nil {
+		return nil, race.ErrRoomNotFound
+	}
+
+	userData, err := s.userSvc.GetUserByEmail(ctx, email)
+	if err != nil || userData == nil {
+		return nil, race.ErrGettingDataFromDB
+	}
+
+	c := newConn(s, userData.Name, email)
+	c.room = r
+
+	reply := make(chan error, 1)
+	if !r.send(command{kind: cmdJoin, c: c, reply: reply}) {
+		return nil, race.ErrRoomNotFound
+	}
+	if err := <-reply; err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Race returns a finished race with its results as the user may see them
+func (s *RaceServiceImpl) Race(ctx context.Context, raceID string, email string) (*race.Race, error) {
+	if _, err := uuid.Parse(raceID); err != nil {
+		return nil, race.ErrRaceNotFound
+	}
+
+	data, err := s.raceSvc.GetRace(ctx, raceID, email)
+	if err != nil {
+		return nil, race.ErrGettingDataFromDB
+	}
+	if data == nil {
+		return nil, race.ErrRaceNotFound
+	}
+	return data, nil
+}
+
+// Rating returns the user's rating, the starting one before their first rated race
+func (s *RaceServiceImpl) Rating(ctx context.Context, email string) (*rating.Rating, error) {
+	data, err := s.ratingSvc.GetRating(ctx, email)
+	if err != nil {
+		return nil, race.ErrGettingDataFromDB
+	}
+	if data == nil {
+		return rating.New(email), nil
+	}
+	return data, nil
+}
+
+func (s *RaceServiceImpl) RatingHistory(ctx context.Context, email string) ([]*rating.Change, error) {
+	data, err := s.ratingSvc.GetRatingHistory(ctx, email, rating.HistoryLimit)
+	if err != nil {
+		return nil, race.ErrGettingDataFromDB
+	}
+	return data, nil
+}
+
+func (s *RaceServiceImpl) addRoom(r *room) {
+	s.mu.Lock()
+	s.rooms[r.id] = r
+	s.mu.Unlock()
+}
+
+// addPrivateRoom registers the room under a fresh invite code
+func (s *RaceServiceImpl) addPrivateRoom(r *room) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		code, err := race.NewInviteCode()
+		if err != nil {
+			return err
+		}
+		if _, taken := s.codes[code]; !taken {
+			r.inviteCode = code
+			s.codes[code] = r
+			s.rooms[r.id] = r
+			return nil
+		}
+	}
+}
+
+func (s *RaceServiceImpl) removeRoom(r *room) {
+	s.mu.Lock()
+	delete(s.rooms, r.id)
+	if r.inviteCode != "" {
+		delete(s.codes, r.inviteCode)
+	}
+	s.mu.Unlock()
+}
+
+// record stores the race and submits every finisher's result as a test of their own.
+// Bots are left out, so they never reach the leaderboards or the ratings.
+func (s *RaceServiceImpl) record(ctx context.Context, rc *race.Race) error {
+	results := make([]*race.Result, 0, len(rc.Results))
+	for _, res := range rc.Results {
+		if !res.Bot {
+			results = append(results, res)
+		}
+	}
+	rc.Results = results
+
+	if err := s.raceSvc.InsertRace(ctx, rc); err != nil {
+		return err
+	}
+
+	for _, res := range rc.Results {
+		if res.Position == 0 || res.Typed == 0 || res.Flagged {
+			continue
+		}
+
+		// race tests count keystrokes, so the usual accuracy formula gives the race accuracy
+		seconds := max(1, int(math.Ceil(res.Duration)))
+		totalTime := seconds
+		if duration := typing.ModeDuration(rc.Mode); duration > 0 {
+			totalTime = duration
+			seconds = min(seconds, duration)
+		}
+		result, err := s.typingSvc.AddTestData(ctx, &typing.TypingData{
+			Mode:            rc.Mode,
+			Language:        rc.Language,
+			WPM:             res.WPM,
+			TotalErrors:     res.Errors,
+			TotalWords:      res.Typed + res.Errors,
+			TypedWords:      res.Typed + res.Errors,
+			TotalTime:       totalTime,
+			TimeTakenByUser: seconds,
+			RaceID:          rc.ID,
+		}, res.Email)
+		if err != nil {
+			// the race itself is still recorded without this test
+			log.Println("error saving race test of", res.Email, "in race", rc.ID, ":", err)
+			continue
+		}
+		res.TestID = result.TestID
+	}
+
+	if err := s.raceSvc.InsertRaceResults(ctx, rc.ID, rc.Results); err != nil {
+		return err
+	}
+
+	if win := feed.RaceWinEvent(rc); win != nil && s.feedSvc != nil {
+		if err := s.feedSvc.Publish(ctx, win); err != nil {
+			log.Println("error publishing the win of race", rc.ID, ":", err)
+		}
+	}
+
+	if rc.Rated {
+		return s.rate(ctx, rc)
+	}
+	return nil
+}
+
+// rate updates the ratings of everyone in a rated race, treating it as a
+// game between every pair of racers in one Glicko-2 rating period. Flagged
+// racers are left out, neither rated nor counted as opponents.
+func (s *RaceServiceImpl) rate(ctx context.Context, rc *race.Race) error {
+	results := make([]*race.Result, 0, len(rc.Results))
+	for _, res := range rc.Results {
+		if !res.Flagged {
+			results = append(results, res)
+		}
+	}
+
+	return s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		emails := make([]string, 0, len(results))
+		for _, res := range results {
+			emails = append(emails, res.Email)
+		}
+
+		stored, err := s.ratingSvc.GetRatingsForUpdate(ctx, emails)
+		if err != nil {
+			return err
+		}
+
+		current := make(map[string]*rating.Rating, len(emails))
+		for _, email := range emails {
+			current[email] = rating.New(email)
+		}
+		for _, r := range stored {
+			current[r.Email] = r
+		}
+
+		for _, a := range results {
+			outcomes := make([]rating.Outcome, 0, len(results)-1)
+			for _, b := range results {
+				if a != b {
+					outcomes = append(outcomes, rating.Outcome{Opponent: current[b.Email], Score: race.Score(a, b)})
+				}
+			}
+
+			before := current[a.Email]
+			updated := rating.Update(before, outcomes)
+			updated.Races++
+
+			if err := s.ratingSvc.SaveRating(ctx, rc.ID, updated, updated.Rating-before.Rating); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
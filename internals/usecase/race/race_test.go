@@ -0,0 +1,730 @@
+package race
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/feed"
+	"typing-speed/internals/core/race"
+	"typing-speed/internals/core/rating"
+	"typing-speed/internals/core/typing"
+	"typing-speed/internals/core/user"
+)
+
+// FakeUserRepo only answers GetUserByEmail, the only lookup rooms need
+type FakeUserRepo struct {
+	port.UserRepository
+}
+
+func (f *FakeUserRepo) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	return &user.User{Name: email, Email: email}, nil
+}
+
+// FakeRaceRepo hands every recorded race to the test through recorded
+type FakeRaceRepo struct {
+	mu       sync.Mutex
+	races    map[string]*race.Race
+	recorded chan *race.Race
+}
+
+func newFakeRaceRepo() *FakeRaceRepo {
+	return &FakeRaceRepo{races: map[string]*race.Race{}, recorded: make(chan *race.Race, race.MaxRounds)}
+}
+
+func (f *FakeRaceRepo) InsertRace(ctx context.Context, r *race.Race) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.races[r.ID] = r
+	return nil
+}
+
+func (f *FakeRaceRepo) InsertRaceResults(ctx context.Context, raceID string, results []*race.Result) error {
+	f.mu.Lock()
+	rc := f.races[raceID]
+	f.mu.Unlock()
+	f.recorded <- rc
+	return nil
+}
+
+func (f *FakeRaceRepo) GetRace(ctx context.Context, raceID string, viewer string) (*race.Race, error) {
+	return nil, nil
+}
+
+func (f *FakeRaceRepo) waitRecorded(t *testing.T) *race.Race {
+	t.Helper()
+	select {
+	case rc := <-f.recorded:
+		return rc
+	case <-time.After(2 * time.Second):
+		t.Fatalf("race was not recorded")
+	}
+	return nil
+}
+
+type FakeTypingService struct {
+	typing.TypingService
+	mu    sync.Mutex
+	tests []*typing.TypingData
+}
+
+func (f *FakeTypingService) AddTestData(ctx context.Context, data *typing.TypingData, email string) (*typing.TestResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data.Email = email
+	f.tests = append(f.tests, data)
+	return &typing.TestResult{TestID: fmt.Sprintf("test-%d", len(f.tests))}, nil
+}
+
+type FakeRatingRepo struct {
+	mu      sync.Mutex
+	ratings map[string]*rating.Rating
+	changes map[string]float64
+	saved   chan struct{}
+}
+
+func (f *FakeRatingRepo) GetRating(ctx context.Context, email string) (*rating.Rating, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ratings[email], nil
+}
+
+func (f *FakeRatingRepo) GetRatingsForUpdate(ctx context.Context, emails []string) ([]*rating.Rating, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ratings := []*rating.Rating{}
+	for _, email := range emails {
+		if r, ok := f.ratings[email]; ok {
+			ratings = append(ratings, r)
+		}
+	}
+	return ratings, nil
+}
+
+func (f *FakeRatingRepo) SaveRating(ctx context.Context, raceID string, r *rating.Rating, change float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ratings[r.Email] = r
+	f.changes[r.Email] = change
+	if f.saved != nil && len(f.changes) == 2 {
+		close(f.saved)
+	}
+	return nil
+}
+
+func (f *FakeRatingRepo) GetRatingHistory(ctx context.Context, email string, limit int) ([]*rating.Change, error) {
+	return nil, nil
+}
+
+// FakeTransactor runs fn without a transaction
+type FakeTransactor struct{}
+
+func (f *FakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newFakeRatingRepo() *FakeRatingRepo {
+	return &FakeRatingRepo{ratings: map[string]*rating.Rating{}, changes: map[string]float64{}}
+}
+
+// testWPM lets racers finish in one message, since the tests race on a
+// clock where a second is a whole race
+const testWPM = 1 << 30
+
+func newTestService() (*RaceServiceImpl, *FakeRaceRepo, *FakeTypingService) {
+	races := newFakeRaceRepo()
+	tests := &FakeTypingService{}
+	svc := NewRaceService(&FakeUserRepo{}, races, newFakeRatingRepo(), &FakeTransactor{}, tests, nil, Config{
+		Countdown:      10 * time.Millisecond,
+		TimeLimit:      time.Second,
+		TickInterval:   5 * time.Millisecond,
+		ReconnectGrace: 50 * time.Millisecond,
+		MaxWPM:         testWPM,
+	})
+	return svc.(*RaceServiceImpl), races, tests
+}
+
+// eventSource is a Session or a Spectator
+type eventSource interface {
+	Events() <-chan *race.Event
+}
+
+// waitFor returns the next event of type eventType, skipping the others
+func waitFor(t *testing.T, s eventSource, eventType string) *race.Event {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case ev, ok := <-s.Events():
+			if !ok {
+				t.Fatalf("session closed while waiting for %s", eventType)
+			}
+			if ev.Type == eventType {
+				return ev
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", eventType)
+		}
+	}
+}
+
+// waitState returns the next state event for which ok holds
+func waitState(t *testing.T, s eventSource, ok func(room *race.Room) bool) *race.Event {
+	t.Helper()
+	for {
+		if ev := waitFor(t, s, race.EventState); ok(ev.Room) {
+			return ev
+		}
+	}
+}
+
+// waitClosed waits for the session's events to end
+func waitClosed(t *testing.T, s eventSource) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-s.Events():
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for the session to close")
+		}
+	}
+}
+
+func TestRace(t *testing.T) {
+	ctx := context.Background()
+	svc, races, tests := newTestService()
+
+	room, err := svc.CreateRoom(ctx, "host@mail.com", nil)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	host, err := svc.JoinRoom(ctx, room.ID, "host@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	guest, err := svc.JoinRoom(ctx, room.ID, "guest@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	guest.Send(race.ClientMessage{Type: race.MsgStart})
+	if ev := waitFor(t, guest, race.EventError); ev.Error != race.ErrNotHost.Error() {
+		t.Fatalf("expected %v, got %q", race.ErrNotHost, ev.Error)
+	}
+
+	host.Send(race.ClientMessage{Type: race.MsgStart})
+	countdown := waitFor(t, host, race.EventCountdown).Room
+	if countdown.Text == "" || countdown.RaceID == "" {
+		t.Fatalf("expected the text to be revealed with the countdown")
+	}
+	text := countdown.Text
+
+	if _, err := svc.JoinRoom(ctx, room.ID, "late@mail.com"); err != race.ErrRaceStarted {
+		t.Fatalf("expected %v, got %v", race.ErrRaceStarted, err)
+	}
+
+	waitFor(t, host, race.EventStart)
+	waitFor(t, guest, race.EventStart)
+
+	guest.Send(race.ClientMessage{Type: race.MsgProgress, Typed: len(text), Errors: 2})
+	waitFor(t, guest, race.EventFinish)
+	// a client claiming more than the text is capped to it
+	host.Send(race.ClientMessage{Type: race.MsgProgress, Typed: len(text) + 50})
+
+	results := waitFor(t, host, race.EventResults).Room.Participants
+	positions := map[string]int{}
+	for _, p := range results {
+		positions[p.Name] = p.Position
+	}
+	if positions["guest@mail.com"] != 1 || positions["host@mail.com"] != 2 {
+		t.Fatalf("expected guest first and host second, got %v", positions)
+	}
+	waitFor(t, host, race.EventSeriesEnd)
+
+	rc := races.waitRecorded(t)
+	if rc.ID != countdown.RaceID || rc.Text != text || rc.Mode != typing.ModeCustom {
+		t.Fatalf("expected race %s to be recorded, got %+v", countdown.RaceID, rc)
+	}
+	if len(tests.tests) != 2 || tests.tests[0].RaceID != rc.ID || tests.tests[0].Email != "guest@mail.com" {
+		t.Fatalf("expected both finishers to get a race test, got %+v", tests.tests)
+	}
+	if got := typing.Accuracy(tests.tests[0]); got != race.Accuracy(len(text), 2) {
+		t.Fatalf("expected the test to keep the race accuracy, got %d", got)
+	}
+	if rc.Results[0].TestID != "test-1" {
+		t.Fatalf("expected results to reference the tests, got %+v", rc.Results[0])
+	}
+
+	if _, err := svc.JoinRoom(ctx, room.ID, "host@mail.com"); err != race.ErrRoomNotFound {
+		t.Fatalf("expected %v, got %v", race.ErrRoomNotFound, err)
+	}
+}
+
+func TestOneMessageFinish(t *testing.T) {
+	ctx := context.Background()
+	races := newFakeRaceRepo()
+	tests := &FakeTypingService{}
+	svc := NewRaceService(&FakeUserRepo{}, races, newFakeRatingRepo(), &FakeTransactor{}, tests, nil, Config{
+		Countdown:    10 * time.Millisecond,
+		TimeLimit:    time.Second,
+		TickInterval: 5 * time.Millisecond,
+	})
+
+	room, _ := svc.CreateRoom(ctx, "host@mail.com", nil)
+	host, _ := svc.JoinRoom(ctx, room.ID, "host@mail.com")
+	cheat, _ := svc.JoinRoom(ctx, room.ID, "cheat@mail.com")
+
+	host.Send(race.ClientMessage{Type: race.MsgStart})
+	text := waitFor(t, host, race.EventCountdown).Room.Text
+	waitFor(t, cheat, race.EventStart)
+
+	// the whole text at once is capped to what the top speed allows
+	cheat.Send(race.ClientMessage{Type: race.MsgProgress, Typed: len(text)})
+
+	results := waitFor(t, host, race.EventResults).Room.Participants
+	for _, p := range results {
+		if p.Name == "cheat@mail.com" && (p.Position != 0 || p.Typed >= len(text) || p.WPM > race.MaxWPM) {
+			t.Fatalf("expected the progress to be capped, got %+v", p)
+		}
+	}
+
+	rc := races.waitRecorded(t)
+	for _, res := range rc.Results {
+		if res.Email == "cheat@mail.com" && !res.Flagged {
+			t.Fatalf("expected the racer to be flagged, got %+v", res)
+		}
+	}
+	if len(tests.tests) != 0 {
+		t.Fatalf("expected no race tests, got %+v", tests.tests)
+	}
+}
+
+// FakeFeedService keeps every published event
+type FakeFeedService struct {
+	feed.FeedService
+	events []*feed.Event
+}
+
+func (f *FakeFeedService) Publish(ctx context.Context, events ...*feed.Event) error {
+	f.events = append(f.events, events...)
+	return nil
+}
+
+func TestRaceWinEvent(t *testing.T) {
+	ctx := context.Background()
+	feeds := &FakeFeedService{}
+	svc := NewRaceService(&FakeUserRepo{}, newFakeRaceRepo(), newFakeRatingRepo(), &FakeTransactor{}, &FakeTypingService{},
+		feeds, Config{}).(*RaceServiceImpl)
+
+	newRace := func(id string, flagged bool) *race.Race {
+		return &race.Race{ID: id, Mode: typing.Mode30s, Language: "english", Results: []*race.Result{
+			{Email: "first@mail.com", Position: 1, WPM: 240, Typed: 600, Duration: 30, Flagged: flagged},
+			{Email: "second@mail.com", Position: 2, WPM: 80, Typed: 200, Duration: 30},
+		}}
+	}
+
+	if err := svc.record(ctx, newRace("race-1", false)); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(feeds.events) != 1 || feeds.events[0].Actor != "first@mail.com" || feeds.events[0].Kind != feed.KindRaceWin {
+		t.Fatalf("expected the win to be published, got %+v", feeds.events)
+	}
+
+	// a flagged winner is not announced
+	if err := svc.record(ctx, newRace("race-2", true)); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(feeds.events) != 1 {
+		t.Fatalf("expected no win of a flagged racer, got %+v", feeds.events[1:])
+	}
+}
+
+func TestCreateRoom(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	if _, err := svc.CreateRoom(ctx, "host@mail.com", &race.Settings{Rounds: 2}); err != race.ErrInvalidSettings {
+		t.Fatalf("expected %v, got %v", race.ErrInvalidSettings, err)
+	}
+
+	room, err := svc.CreateRoom(ctx, "host@mail.com", &race.Settings{Mode: typing.Mode15s, MaxPlayers: 2})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(room.InviteCode) != race.InviteCodeLength {
+		t.Fatalf("expected an invite code, got %q", room.InviteCode)
+	}
+	if room.Settings.Mode != typing.Mode15s || room.Settings.Rounds != 1 || room.Settings.Language != typing.DefaultLanguage {
+		t.Fatalf("expected the settings to be filled in, got %+v", room.Settings)
+	}
+
+	// the invite code works in any case
+	host, err := svc.JoinRoom(ctx, strings.ToLower(room.InviteCode), "host@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if _, err := svc.JoinRoom(ctx, room.InviteCode, "guest@mail.com"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if _, err := svc.JoinRoom(ctx, room.InviteCode, "extra@mail.com"); !errors.Is(err, race.ErrRoomFull) {
+		t.Fatalf("expected %v, got %v", race.ErrRoomFull, err)
+	}
+	host.Leave()
+}
+
+func TestJoinRoom(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	if _, err := svc.JoinRoom(ctx, "missing", "a@mail.com"); err != race.ErrRoomNotFound {
+		t.Fatalf("expected %v, got %v", race.ErrRoomNotFound, err)
+	}
+
+	room, _ := svc.CreateRoom(ctx, "host@mail.com", nil)
+	sessions := []race.Session{}
+	for i := 0; i < race.MaxPlayers; i++ {
+		s, err := svc.JoinRoom(ctx, room.ID, fmt.Sprintf("user%d@mail.com", i))
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		sessions = append(sessions, s)
+	}
+
+	if _, err := svc.JoinRoom(ctx, room.ID, "extra@mail.com"); !errors.Is(err, race.ErrRoomFull) {
+		t.Fatalf("expected %v, got %v", race.ErrRoomFull, err)
+	}
+
+	// joining again takes the seat over from the old connection
+	again, err := svc.JoinRoom(ctx, room.ID, "user0@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	waitClosed(t, sessions[0])
+	sessions[0] = again
+
+	// the room closes when the last participant leaves the lobby
+	for _, s := range sessions {
+		s.Leave()
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		svc.mu.Lock()
+		n := len(svc.rooms) + len(svc.codes)
+		svc.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the empty room to close")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHostControls(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	room, _ := svc.CreateRoom(ctx, "host@mail.com", nil)
+	host, _ := svc.JoinRoom(ctx, room.ID, "host@mail.com")
+	guest, _ := svc.JoinRoom(ctx, room.ID, "guest@mail.com")
+	other, _ := svc.JoinRoom(ctx, room.ID, "other@mail.com")
+
+	guest.Send(race.ClientMessage{Type: race.MsgSettings, Settings: &race.Settings{Mode: typing.Mode30s}})
+	if ev := waitFor(t, guest, race.EventError); ev.Error != race.ErrNotHost.Error() {
+		t.Fatalf("expected %v, got %q", race.ErrNotHost, ev.Error)
+	}
+
+	host.Send(race.ClientMessage{Type: race.MsgSettings, Settings: &race.Settings{MaxPlayers: 2}})
+	if ev := waitFor(t, host, race.EventError); ev.Error != race.ErrInvalidSettings.Error() {
+		t.Fatalf("expected %v, got %q", race.ErrInvalidSettings, ev.Error)
+	}
+
+	host.Send(race.ClientMessage{Type: race.MsgSettings, Settings: &race.Settings{Mode: typing.Mode30s, Punctuation: true}})
+	waitState(t, guest, func(room *race.Room) bool { return room.Settings.Mode == typing.Mode30s })
+
+	// the kicked participant is told and cannot come back
+	host.Send(race.ClientMessage{Type: race.MsgKick, Target: 3})
+	waitFor(t, other, race.EventKicked)
+	waitClosed(t, other)
+	if _, err := svc.JoinRoom(ctx, room.ID, "other@mail.com"); err != race.ErrKicked {
+		t.Fatalf("expected %v, got %v", race.ErrKicked, err)
+	}
+
+	host.Send(race.ClientMessage{Type: race.MsgTransferHost, Target: 2})
+	ev := waitState(t, guest, func(room *race.Room) bool { return len(room.Participants) == 2 && room.Participants[1].Host })
+	if ev.Room.Participants[0].Host {
+		t.Fatalf("expected a single host, got %+v", ev.Room.Participants)
+	}
+
+	host.Send(race.ClientMessage{Type: race.MsgStart})
+	if ev := waitFor(t, host, race.EventError); ev.Error != race.ErrNotHost.Error() {
+		t.Fatalf("expected %v, got %q", race.ErrNotHost, ev.Error)
+	}
+	host.Leave()
+	guest.Leave()
+}
+
+func TestSeries(t *testing.T) {
+	ctx := context.Background()
+	svc, races, _ := newTestService()
+
+	room, _ := svc.CreateRoom(ctx, "host@mail.com", &race.Settings{Rounds: 3})
+	host, _ := svc.JoinRoom(ctx, room.ID, "host@mail.com")
+	guest, _ := svc.JoinRoom(ctx, room.ID, "guest@mail.com")
+
+	// the guest wins the first two rounds and the host the last
+	winners := []race.Session{guest, guest, host}
+	var results *race.Room
+	for round, winner := range winners {
+		host.Send(race.ClientMessage{Type: race.MsgStart})
+		text := waitFor(t, winner, race.EventStart).Room.Text
+
+		winner.Send(race.ClientMessage{Type: race.MsgProgress, Typed: len(text)})
+		waitFor(t, winner, race.EventFinish)
+		for _, s := range []race.Session{host, guest} {
+			if s != winner {
+				s.Send(race.ClientMessage{Type: race.MsgProgress, Typed: len(text)})
+			}
+		}
+
+		results = waitFor(t, host, race.EventResults).Room
+		if results.Round != round+1 {
+			t.Fatalf("expected round %d, got %d", round+1, results.Round)
+		}
+		races.waitRecorded(t)
+	}
+	waitFor(t, host, race.EventSeriesEnd)
+
+	points := map[string]int{}
+	for _, p := range results.Participants {
+		points[p.Name] = p.Points
+	}
+	if points["guest@mail.com"] != 5 || points["host@mail.com"] != 4 {
+		t.Fatalf("expected 5 points for the guest and 4 for the host, got %v", points)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	room, _ := svc.CreateRoom(ctx, "host@mail.com", nil)
+	host, _ := svc.JoinRoom(ctx, room.InviteCode, "host@mail.com")
+	guest, _ := svc.JoinRoom(ctx, room.InviteCode, "guest@mail.com")
+
+	host.Send(race.ClientMessage{Type: race.MsgStart})
+	text := waitFor(t, guest, race.EventStart).Room.Text
+	guest.Send(race.ClientMessage{Type: race.MsgProgress, Typed: 5})
+
+	guest.Disconnect()
+	waitState(t, host, func(room *race.Room) bool { return len(room.Participants) == 2 && !room.Participants[1].Connected })
+
+	// the seat and its progress survive the reconnect
+	guest, err := svc.JoinRoom(ctx, room.InviteCode, "guest@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	ev := waitFor(t, guest, race.EventState)
+	if ev.You != 2 || ev.Room.Text != text || ev.Room.Participants[1].Typed != 5 || !ev.Room.Participants[1].Connected {
+		t.Fatalf("expected the race state to be recovered, got %+v", ev.Room)
+	}
+
+	// a seat not reclaimed within the grace period is given up
+	guest.Disconnect()
+	waitState(t, host, func(room *race.Room) bool { return len(room.Participants) == 2 && room.Participants[1].Left })
+	if _, err := svc.JoinRoom(ctx, room.InviteCode, "guest@mail.com"); err != race.ErrRaceStarted {
+		t.Fatalf("expected %v, got %v", race.ErrRaceStarted, err)
+	}
+	host.Leave()
+}
+
+func TestSpectate(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	room, _ := svc.CreateRoom(ctx, "host@mail.com", nil)
+	if room.SpectatorToken == "" {
+		t.Fatalf("expected a spectator token")
+	}
+
+	if _, err := svc.Spectate(ctx, room.ID, "wrong"); err != race.ErrInvalidSpectatorToken {
+		t.Fatalf("expected %v, got %v", race.ErrInvalidSpectatorToken, err)
+	}
+	if _, err := svc.Spectate(ctx, "missing", room.SpectatorToken); err != race.ErrRoomNotFound {
+		t.Fatalf("expected %v, got %v", race.ErrRoomNotFound, err)
+	}
+
+	viewer, err := svc.Spectate(ctx, room.ID, room.SpectatorToken)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	ev := <-viewer.Events()
+	if ev.Room.InviteCode != "" || ev.Room.SpectatorToken != "" {
+		t.Fatalf("expected spectators not to see the invite code or token, got %+v", ev.Room)
+	}
+
+	// a viewer who never reads must not hold the race up
+	stalled, _ := svc.Spectate(ctx, room.ID, room.SpectatorToken)
+
+	host, _ := svc.JoinRoom(ctx, room.ID, "host@mail.com")
+	guest, _ := svc.JoinRoom(ctx, room.ID, "guest@mail.com")
+	host.Send(race.ClientMessage{Type: race.MsgStart})
+	text := waitFor(t, host, race.EventStart).Room.Text
+	for i := 1; i <= len(text); i++ {
+		host.Send(race.ClientMessage{Type: race.MsgProgress, Typed: i})
+	}
+	guest.Send(race.ClientMessage{Type: race.MsgProgress, Typed: len(text)})
+
+	if got := waitFor(t, viewer, race.EventResults).Room; got.Participants[0].Position != 1 || got.Spectators != 2 {
+		t.Fatalf("expected the spectator to see the results, got %+v", got)
+	}
+
+	// the streams end with the room
+	waitClosed(t, viewer)
+	waitClosed(t, stalled)
+}
+
+func TestHostLeavesLobby(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	room, _ := svc.CreateRoom(ctx, "host@mail.com", nil)
+	host, _ := svc.JoinRoom(ctx, room.ID, "host@mail.com")
+	guest, _ := svc.JoinRoom(ctx, room.ID, "guest@mail.com")
+
+	host.Leave()
+
+	ev := waitFor(t, guest, race.EventState)
+	for len(ev.Room.Participants) != 1 {
+		ev = waitFor(t, guest, race.EventState)
+	}
+	if !ev.Room.Participants[0].Host {
+		t.Fatalf("expected the host to pass to the guest, got %+v", ev.Room.Participants[0])
+	}
+	guest.Leave()
+}
+
+func TestMatchRoom(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	results := make(chan *race.MatchResult, 1)
+	room, err := svc.CreateMatchRoom(ctx, []string{"a@mail.com", "b@mail.com"}, nil,
+		func(result *race.MatchResult) { results <- result })
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	if _, err := svc.JoinRoom(ctx, room.ID, "c@mail.com"); err != race.ErrNotInvited {
+		t.Fatalf("expected %v, got %v", race.ErrNotInvited, err)
+	}
+
+	// the race starts on its own once both players are in
+	a, _ := svc.JoinRoom(ctx, room.ID, "a@mail.com")
+	b, _ := svc.JoinRoom(ctx, room.ID, "b@mail.com")
+	start := waitFor(t, b, race.EventStart).Room
+
+	b.Send(race.ClientMessage{Type: race.MsgProgress, Typed: len(start.Text)})
+	waitFor(t, b, race.EventFinish)
+	a.Send(race.ClientMessage{Type: race.MsgProgress, Typed: len(start.Text)})
+
+	select {
+	case result := <-results:
+		if result.RoomID != room.ID || result.RaceID != start.RaceID ||
+			fmt.Sprint(result.Placings) != "[b@mail.com a@mail.com]" {
+			t.Fatalf("unexpected match result %+v", result)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("match result was not reported")
+	}
+}
+
+func TestBotPace(t *testing.T) {
+	bot := race.Bot{WPM: 80, Accuracy: 90}
+	text := race.GenerateText(1, race.Settings{Mode: typing.ModeCustom})
+
+	// the same seed types the same race
+	a, b := race.NewPace(bot, text, 42), race.NewPace(bot, text, 42)
+	other := race.NewPace(bot, text, 43)
+	same := true
+	for elapsed := time.Duration(0); elapsed < time.Minute; elapsed += 250 * time.Millisecond {
+		typedA, errorsA := a.At(elapsed)
+		typedB, errorsB := b.At(elapsed)
+		if typedA != typedB || errorsA != errorsB {
+			t.Fatalf("expected the same plan at %v, got %d/%d and %d/%d", elapsed, typedA, errorsA, typedB, errorsB)
+		}
+		typedOther, errorsOther := other.At(elapsed)
+		same = same && typedA == typedOther && errorsA == errorsOther
+	}
+	if same {
+		t.Fatalf("expected another seed to type another race")
+	}
+
+	// over a long text the bot keeps close to its target
+	long := race.GenerateText(1, race.Settings{Mode: typing.Mode120s})
+	pace := race.NewPace(bot, long, 7)
+	typed, errors := pace.At(time.Minute)
+	if wpm := typed / 5; wpm < 70 || wpm > 90 {
+		t.Fatalf("expected about 80 WPM, got %d", wpm)
+	}
+	if accuracy := race.Accuracy(typed, errors); accuracy < 85 || accuracy > 95 {
+		t.Fatalf("expected about 90%% accuracy, got %d", accuracy)
+	}
+}
+
+func TestBotRace(t *testing.T) {
+	ctx := context.Background()
+	svc, races, tests := newTestService()
+
+	room, _ := svc.CreateRoom(ctx, "host@mail.com", nil)
+	host, _ := svc.JoinRoom(ctx, room.ID, "host@mail.com")
+
+	host.Send(race.ClientMessage{Type: race.MsgAddBot, Bot: &race.Bot{WPM: 5}})
+	if ev := waitFor(t, host, race.EventError); ev.Error != race.ErrInvalidBot.Error() {
+		t.Fatalf("expected %v, got %q", race.ErrInvalidBot, ev.Error)
+	}
+	host.Send(race.ClientMessage{Type: race.MsgAddBot, Bot: &race.Bot{WPM: race.MaxBotWPM, Seed: 1}})
+	lobby := waitState(t, host, func(room *race.Room) bool { return len(room.Participants) == 2 }).Room
+	bot := lobby.Participants[1]
+	if !bot.Bot || !bot.Connected || bot.Host {
+		t.Fatalf("expected a connected bot that is not the host, got %+v", bot)
+	}
+
+	host.Send(race.ClientMessage{Type: race.MsgTransferHost, Target: bot.ID})
+	if ev := waitFor(t, host, race.EventError); ev.Error != race.ErrInvalidBot.Error() {
+		t.Fatalf("expected %v, got %q", race.ErrInvalidBot, ev.Error)
+	}
+
+	// a bot is enough of an opponent to start
+	host.Send(race.ClientMessage{Type: race.MsgStart})
+	text := waitFor(t, host, race.EventStart).Room.Text
+	moved := waitFor(t, host, race.EventProgress).Room.Participants[1]
+	if moved.Typed == 0 || moved.WPM == 0 {
+		t.Fatalf("expected the bot to be typing, got %+v", moved)
+	}
+	host.Send(race.ClientMessage{Type: race.MsgProgress, Typed: len(text)})
+
+	results := waitFor(t, host, race.EventResults).Room.Participants
+	if results[0].Position != 1 {
+		t.Fatalf("expected the host to win, got %+v", results[0])
+	}
+
+	// only the people in the race are recorded
+	rc := races.waitRecorded(t)
+	if len(rc.Results) != 1 || rc.Results[0].Email != "host@mail.com" {
+		t.Fatalf("expected the bot to be left out of the record, got %+v", rc.Results)
+	}
+	if len(tests.tests) != 1 {
+		t.Fatalf("expected only the host to get a race test, got %d", len(tests.tests))
+	}
+}
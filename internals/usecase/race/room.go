@@ -0,0 +1,861 @@
+package race
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+	"typing-speed/internals/core/race"
+
+	"github.com/google/uuid"
+)
+
+const (
+	eventBuffer   = 64
+	recordTimeout = 30 * time.Second
+)
+
+const (
+	cmdJoin = iota
+	cmdLeave
+	cmdDisconnect
+	cmdMessage
+	cmdWatch
+	cmdUnwatch
+)
+
+type command struct {
+	kind  int
+	c     *conn
+	s     *spectator
+	msg   race.ClientMessage
+	reply chan error
+}
+
+// conn is one connection of a participant to a room, and the Session the
+// transport holds. A quick racer's connection has no room until they are matched.
+type conn struct {
+	svc       *RaceServiceImpl
+	p         *participant // the seat, only touched by the room goroutine once joined
+	events    chan *race.Event
+	leaveOnce sync.Once
+
+	mu   sync.Mutex
+	room *room
+}
+
+func newConn(svc *RaceServiceImpl, name string, email string) *conn {
+	return &conn{
+		svc:    svc,
+		p:      &participant{Participant: race.Participant{Name: name}, email: email},
+		events: make(chan *race.Event, eventBuffer),
+	}
+}
+
+func (c *conn) currentRoom() *room {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.room
+}
+
+func (c *conn) setRoom(r *room) {
+	c.mu.Lock()
+	c.room = r
+	c.mu.Unlock()
+}
+
+func (c *conn) Events() <-chan *race.Event {
+	return c.events
+}
+
+// Send passes the message to the room, messages sent while queued are dropped
+func (c *conn) Send(msg race.ClientMessage) {
+	if msg.Type == race.MsgLeave {
+		c.Leave()
+		return
+	}
+	if r := c.currentRoom(); r != nil {
+		r.send(command{kind: cmdMessage, c: c, msg: msg})
+	}
+}
+
+// Leave gives up the seat for good
+func (c *conn) Leave() {
+	c.leaveOnce.Do(func() {
+		if c.svc.dequeue(c) {
+			return
+		}
+		if r := c.currentRoom(); r != nil {
+			r.send(command{kind: cmdLeave, c: c})
+		}
+	})
+}
+
+// Disconnect keeps the seat for the participant to come back to. A queued
+// player has no seat yet, so they just leave the queue.
+func (c *conn) Disconnect() {
+	if c.svc.dequeue(c) {
+		return
+	}
+	if r := c.currentRoom(); r != nil {
+		r.send(command{kind: cmdDisconnect, c: c})
+	}
+}
+
+// participant is a racer's seat in a room. It outlives their connections.
+// A bot's seat never has a connection, the room types for it.
+type participant struct {
+	race.Participant
+	email          string
+	conn           *conn // nil while disconnected
+	disconnectedAt time.Time
+	bot            *race.Bot
+	pace           *race.Pace // the bot's plan for the current round
+	flagged        bool       // claimed progress faster than the config allows this round
+}
+
+// room runs a series of races. All of its state is owned by the run
+// goroutine, everything else talks to it through the inbox.
+type room struct {
+	svc    *RaceServiceImpl
+	config Config
+
+	id             string
+	inviteCode     string // empty for quick races
+	spectatorToken string // empty for quick races
+	host           string // empty in quick races and matches, which start on their own
+	rated          bool
+	settings       race.Settings
+	status         string
+	kicked         map[string]bool
+	lastActivity   time.Time
+
+	participants []*participant
+	nextID       int
+	spectators   map[*spectator]struct{}
+
+	// matches only let their players in, and report the outcome when they close
+	players      map[string]bool
+	joinDeadline time.Time
+	onFinish     func(*race.MatchResult)
+
+	// the current round
+	round     int
+	raceID    string
+	text      string
+	startsAt  time.Time
+	startedAt time.Time
+	racers    int // seated when the round started
+	finishers int
+	changed   bool
+
+	inbox chan command
+	done  chan struct{}
+}
+
+func newRoom(svc *RaceServiceImpl, id string, host string, settings race.Settings) *room {
+	return &room{
+		svc:          svc,
+		config:       svc.config,
+		id:           id,
+		host:         host,
+		settings:     settings,
+		status:       race.StatusLobby,
+		kicked:       map[string]bool{},
+		spectators:   map[*spectator]struct{}{},
+		lastActivity: time.Now(),
+		inbox:        make(chan command),
+		done:         make(chan struct{}),
+	}
+}
+
+// send delivers cmd to the room, or reports false when the room is closed
+func (r *room) send(cmd command) bool {
+	select {
+	case r.inbox <- cmd:
+		return true
+	case <-r.done:
+		return false
+	}
+}
+
+func (r *room) run() {
+	defer r.close()
+
+	ticker := time.NewTicker(r.config.TickInterval)
+	defer ticker.Stop()
+
+	var countdown, timeLimit <-chan time.Time
+
+	for {
+		if r.autoStart() {
+			r.beginCountdown()
+			countdown = time.After(r.config.Countdown)
+		}
+
+		select {
+		case cmd := <-r.inbox:
+			// watching does not keep a room alive
+			if cmd.kind != cmdWatch && cmd.kind != cmdUnwatch {
+				r.lastActivity = time.Now()
+			}
+			switch cmd.kind {
+			case cmdJoin:
+				cmd.reply <- r.join(cmd.c)
+			case cmdLeave:
+				r.leave(cmd.c)
+			case cmdDisconnect:
+				r.disconnect(cmd.c)
+			case cmdMessage:
+				if r.handle(cmd.c, cmd.msg) {
+					countdown = time.After(r.config.Countdown)
+				}
+			case cmdWatch:
+				cmd.reply <- r.watch(cmd.s)
+			case cmdUnwatch:
+				r.unwatch(cmd.s)
+			}
+
+		case <-countdown:
+			countdown = nil
+			timeLimit = time.After(min(r.settings.TimeLimit(), r.config.TimeLimit))
+			r.start()
+
+		case <-timeLimit:
+			timeLimit = nil
+			r.finishRound()
+
+		case <-ticker.C:
+			if time.Since(r.lastActivity) > r.config.IdleTimeout {
+				r.broadcast(race.EventError, "room expired")
+				return
+			}
+			r.dropDisconnected()
+			if r.status == race.StatusRacing {
+				r.moveBots()
+			}
+			if r.status == race.StatusRacing && r.changed {
+				r.changed = false
+				r.broadcast(race.EventProgress, "")
+			}
+		}
+
+		if r.status == race.StatusRacing && r.allDone() {
+			timeLimit = nil
+			r.finishRound()
+		}
+		// the room closes once the series is over or everyone who joined has
+		// left, bots not counting. A match stays open for its join window.
+		if r.status == race.StatusFinished || r.players == nil && r.nextID > 0 && len(r.humans()) == 0 {
+			return
+		}
+		if r.walkover() {
+			r.broadcast(race.EventError, "not enough players showed up")
+			return
+		}
+	}
+}
+
+// close shuts the room and ends every connection and stream still open to it
+func (r *room) close() {
+	if r.onFinish != nil {
+		result := &race.MatchResult{RoomID: r.id, Placings: r.placings()}
+		if r.round > 0 {
+			result.RaceID = r.raceID
+		}
+		go r.onFinish(result)
+	}
+
+	r.svc.removeRoom(r)
+	close(r.done)
+	for _, p := range r.active() {
+		if p.conn != nil {
+			close(p.conn.events)
+		}
+	}
+	for s := range r.spectators {
+		close(s.events)
+	}
+}
+
+// join seats a new participant, or hands a returning one their seat back
+// along with the full state of the room
+func (r *room) join(c *conn) error {
+	if r.players != nil && !r.players[c.p.email] {
+		return race.ErrNotInvited
+	}
+	if r.kicked[c.p.email] {
+		return race.ErrKicked
+	}
+
+	if p := r.seatOf(c.p.email); p != nil {
+		// the newer connection replaces one that is still open
+		if p.conn != nil {
+			close(p.conn.events)
+		}
+		c.p = p
+		p.conn = c
+		r.broadcast(race.EventState, "")
+		return nil
+	}
+
+	if r.status != race.StatusLobby {
+		return race.ErrRaceStarted
+	}
+	if len(r.active()) >= r.settings.MaxPlayers {
+		return race.ErrRoomFull
+	}
+
+	r.seat(c)
+	r.assignHost()
+	r.broadcast(race.EventState, "")
+	return nil
+}
+
+func (r *room) seat(c *conn) {
+	r.nextID++
+	c.p.ID = r.nextID
+	c.p.conn = c
+	r.participants = append(r.participants, c.p)
+}
+
+func (r *room) seatOf(email string) *participant {
+	for _, p := range r.active() {
+		if p.email == email {
+			return p
+		}
+	}
+	return nil
+}
+
+func (r *room) seatByID(id int) *participant {
+	for _, p := range r.active() {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (r *room) leave(c *conn) {
+	// a replaced connection no longer speaks for the seat
+	if c.p.conn != c {
+		return
+	}
+	r.remove(c.p)
+	r.broadcast(race.EventState, "")
+}
+
+func (r *room) disconnect(c *conn) {
+	p := c.p
+	if p.conn != c {
+		return
+	}
+	close(c.events)
+	p.conn = nil
+	p.disconnectedAt = time.Now()
+	r.broadcast(race.EventState, "")
+}
+
+// dropDisconnected frees the seats of those who did not come back in time
+func (r *room) dropDisconnected() {
+	dropped := false
+	for _, p := range r.humans() {
+		if p.conn == nil && time.Since(p.disconnectedAt) > r.config.ReconnectGrace {
+			r.remove(p)
+			dropped = true
+		}
+	}
+	if dropped {
+		r.broadcast(race.EventState, "")
+	}
+}
+
+// remove takes a participant out of the lobby, or marks them as gone once a race is on
+func (r *room) remove(p *participant) {
+	p.Left = true
+	if p.conn != nil {
+		close(p.conn.events)
+		p.conn = nil
+	}
+
+	if r.status == race.StatusLobby {
+		r.participants = r.active()
+	}
+
+	// the host passes to whoever joined first
+	if p.bot == nil && p.email == r.host {
+		if humans := r.humans(); len(humans) > 0 {
+			r.host = humans[0].email
+		}
+	}
+	r.assignHost()
+}
+
+// handle applies a participant's message and reports whether the countdown has to start
+func (r *room) handle(c *conn, msg race.ClientMessage) bool {
+	p := c.p
+	if p.conn != c || p.Left {
+		return false
+	}
+
+	switch msg.Type {
+	case race.MsgStart:
+		if err := r.checkLobbyHost(p); err != nil {
+			r.sendError(p, err)
+			return false
+		}
+		if len(r.active()) < race.MinPlayers {
+			r.sendError(p, race.ErrNotEnoughPlayers)
+			return false
+		}
+
+		r.beginCountdown()
+		return true
+
+	case race.MsgSettings:
+		if err := r.checkLobbyHost(p); err != nil {
+			r.sendError(p, err)
+			return false
+		}
+		if err := r.updateSettings(msg.Settings); err != nil {
+			r.sendError(p, err)
+			return false
+		}
+		r.broadcast(race.EventState, "")
+
+	case race.MsgKick:
+		if p.email != r.host {
+			r.sendError(p, race.ErrNotHost)
+			return false
+		}
+		target := r.seatByID(msg.Target)
+		if target == nil || target == p {
+			r.sendError(p, race.ErrParticipantMissing)
+			return false
+		}
+
+		if target.bot == nil {
+			r.kicked[target.email] = true
+		}
+		r.sendEvent(target, &race.Event{Type: race.EventKicked, You: target.ID, Error: race.ErrKicked.Error()})
+		r.remove(target)
+		r.broadcast(race.EventState, "")
+
+	case race.MsgTransferHost:
+		if p.email != r.host {
+			r.sendError(p, race.ErrNotHost)
+			return false
+		}
+		target := r.seatByID(msg.Target)
+		if target == nil {
+			r.sendError(p, race.ErrParticipantMissing)
+			return false
+		}
+		if target.bot != nil {
+			r.sendError(p, race.ErrInvalidBot)
+			return false
+		}
+
+		r.host = target.email
+		r.assignHost()
+		r.broadcast(race.EventState, "")
+
+	case race.MsgAddBot:
+		if err := r.checkLobbyHost(p); err != nil {
+			r.sendError(p, err)
+			return false
+		}
+		if err := r.addBot(msg.Bot); err != nil {
+			r.sendError(p, err)
+			return false
+		}
+		r.broadcast(race.EventState, "")
+
+	case race.MsgProgress:
+		if r.status == race.StatusRacing {
+			r.progress(p, msg)
+		}
+	}
+
+	return false
+}
+
+// checkLobbyHost checks that p may change the lobby
+func (r *room) checkLobbyHost(p *participant) error {
+	if r.status != race.StatusLobby {
+		return race.ErrRaceStarted
+	}
+	if p.email != r.host {
+		return race.ErrNotHost
+	}
+	return nil
+}
+
+// updateSettings changes the settings, which are fixed once the series has begun
+func (r *room) updateSettings(settings *race.Settings) error {
+	if r.round > 0 {
+		return race.ErrRaceStarted
+	}
+	if settings == nil {
+		return race.ErrInvalidSettings
+	}
+
+	updated := *settings
+	if err := updated.Normalize(); err != nil {
+		return err
+	}
+	if updated.MaxPlayers < len(r.active()) {
+		return race.ErrInvalidSettings
+	}
+
+	r.settings = updated
+	return nil
+}
+
+// addBot seats a bot, which takes a seat like any other racer
+func (r *room) addBot(bot *race.Bot) error {
+	if bot == nil {
+		return race.ErrInvalidBot
+	}
+	b := *bot
+	if err := b.Normalize(); err != nil {
+		return err
+	}
+	if b.Seed == 0 {
+		b.Seed = time.Now().UnixNano()
+	}
+	if len(r.active()) >= r.settings.MaxPlayers {
+		return race.ErrRoomFull
+	}
+
+	r.nextID++
+	r.participants = append(r.participants, &participant{
+		Participant: race.Participant{ID: r.nextID, Name: b.Name(), Bot: true},
+		bot:         &b,
+	})
+	return nil
+}
+
+// moveBots brings every bot still racing to where its plan says it is by now
+func (r *room) moveBots() {
+	elapsed := time.Since(r.startedAt)
+	for _, p := range r.active() {
+		if p.bot == nil || p.Position > 0 {
+			continue
+		}
+		typed, errors := p.pace.At(elapsed)
+		if typed != p.Typed || errors != p.Errors {
+			r.progress(p, race.ClientMessage{Type: race.MsgProgress, Typed: typed, Errors: errors})
+		}
+	}
+}
+
+// autoStart reports whether a room without a host should start its next
+// race. Quick races start right away, matches once all the players are in
+// or, after the join window, with whoever came.
+func (r *room) autoStart() bool {
+	if r.host != "" || r.status != race.StatusLobby {
+		return false
+	}
+	if r.players == nil {
+		return true
+	}
+
+	seated := len(r.active())
+	return seated == len(r.players) || seated >= race.MinPlayers && time.Now().After(r.joinDeadline)
+}
+
+// walkover reports whether a match can no longer be raced because too few
+// players came, or too few are left for the next round
+func (r *room) walkover() bool {
+	if r.players == nil || r.status != race.StatusLobby || len(r.active()) >= race.MinPlayers {
+		return false
+	}
+	return r.round > 0 || time.Now().After(r.joinDeadline)
+}
+
+// placings orders the players by series points, those still in the room first
+func (r *room) placings() []string {
+	seats := append([]*participant{}, r.participants...)
+	sort.SliceStable(seats, func(i, j int) bool {
+		if seats[i].Left != seats[j].Left {
+			return !seats[i].Left
+		}
+		return seats[i].Points > seats[j].Points
+	})
+
+	emails := make([]string, 0, len(seats))
+	for _, p := range seats {
+		emails = append(emails, p.email)
+	}
+	return emails
+}
+
+func (r *room) beginCountdown() {
+	r.status = race.StatusCountdown
+	r.round++
+	r.raceID = uuid.NewString()
+	r.text = race.GenerateText(time.Now().UnixNano(), r.settings)
+	r.startsAt = time.Now().Add(r.config.Countdown)
+	r.racers = len(r.active())
+	r.finishers = 0
+	// every round of a series gets a plan of its own, the same for the same seed
+	for _, p := range r.active() {
+		if p.bot != nil {
+			p.pace = race.NewPace(*p.bot, r.text, p.bot.Seed+int64(r.round))
+		}
+	}
+	r.broadcast(race.EventCountdown, "")
+}
+
+func (r *room) start() {
+	r.status = race.StatusRacing
+	r.startedAt = time.Now()
+	r.broadcast(race.EventStart, "")
+}
+
+// progress moves a racer forward. Typed text can only grow, and only as fast
+// as the config's top speed allows on the server clock since the start, so a
+// client cannot rewind or finish in one message. A client claiming more is
+// capped and flagged.
+func (r *room) progress(p *participant, msg race.ClientMessage) {
+	if p.Position > 0 {
+		return
+	}
+
+	elapsed := time.Since(r.startedAt)
+	typed := min(max(msg.Typed, p.Typed), len(r.text))
+	if limit := race.MaxTyped(r.config.MaxWPM, elapsed); p.bot == nil && typed > limit {
+		p.flagged = true
+		typed = max(limit, p.Typed)
+	}
+	p.Typed = typed
+	p.Errors = max(msg.Errors, p.Errors)
+
+	p.WPM = race.WPM(p.Typed, elapsed)
+	p.Accuracy = race.Accuracy(p.Typed, p.Errors)
+	r.changed = true
+
+	if p.Typed == len(r.text) {
+		r.finishers++
+		p.Position = r.finishers
+		p.FinishTime = elapsed.Seconds()
+		r.broadcast(race.EventFinish, "")
+	}
+}
+
+// finishRound ends the race, adds up the points and sends the race off to
+// be recorded, so nobody waits on the database. The room then goes back to
+// the lobby for the next round, or closes when the series is over.
+func (r *room) finishRound() {
+	if r.settings.Timed() {
+		r.placeByDistance()
+	}
+	for _, p := range r.participants {
+		p.Points += race.Points(p.Position, r.racers)
+	}
+
+	r.status = race.StatusFinished
+	r.broadcast(race.EventResults, "")
+
+	rc := &race.Race{
+		ID:         r.raceID,
+		Text:       r.text,
+		Mode:       r.settings.Mode,
+		Language:   r.settings.Language,
+		Rated:      r.rated,
+		StartedAt:  r.startedAt,
+		FinishedAt: time.Now(),
+		Results:    r.results(),
+	}
+	go r.record(rc)
+
+	if r.round >= r.settings.Rounds {
+		r.broadcast(race.EventSeriesEnd, "")
+		return
+	}
+	r.nextRound()
+}
+
+// placeByDistance ends a timed race for everyone still typing, ranked by how far they got
+func (r *room) placeByDistance() {
+	remaining := []*participant{}
+	for _, p := range r.active() {
+		if p.Position == 0 {
+			remaining = append(remaining, p)
+		}
+	}
+	sort.SliceStable(remaining, func(i, j int) bool {
+		if remaining[i].Typed != remaining[j].Typed {
+			return remaining[i].Typed > remaining[j].Typed
+		}
+		return remaining[i].Errors < remaining[j].Errors
+	})
+
+	elapsed := time.Since(r.startedAt).Seconds()
+	for _, p := range remaining {
+		r.finishers++
+		p.Position = r.finishers
+		p.FinishTime = elapsed
+	}
+}
+
+// nextRound clears the last race and waits in the lobby for the host to start again
+func (r *room) nextRound() {
+	r.status = race.StatusLobby
+	r.participants = r.active()
+	r.text = ""
+	r.startsAt = time.Time{}
+	r.startedAt = time.Time{}
+	for _, p := range r.participants {
+		p.Typed, p.Errors, p.WPM, p.Accuracy = 0, 0, 0, 0
+		p.Position, p.FinishTime = 0, 0
+		p.flagged = false
+	}
+	r.broadcast(race.EventState, "")
+}
+
+func (r *room) record(rc *race.Race) {
+	ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancel()
+
+	if err := r.svc.record(ctx, rc); err != nil {
+		log.Println("error recording race", rc.ID, ":", err)
+	}
+}
+
+// results lists the finishers in order, then everyone else by speed
+func (r *room) results() []*race.Result {
+	results := make([]*race.Result, 0, len(r.participants))
+	for _, p := range r.participants {
+		duration := p.FinishTime
+		if p.Position == 0 {
+			duration = time.Since(r.startedAt).Seconds()
+		}
+		results = append(results, &race.Result{
+			Email:    p.email,
+			Name:     p.Name,
+			Bot:      p.bot != nil,
+			Position: p.Position,
+			WPM:      p.WPM,
+			Accuracy: p.Accuracy,
+			Duration: duration,
+			Typed:    p.Typed,
+			Errors:   p.Errors,
+			Flagged:  p.flagged,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if (a.Position == 0) != (b.Position == 0) {
+			return a.Position != 0
+		}
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		return a.WPM > b.WPM
+	})
+	return results
+}
+
+// allDone reports whether every racer still in the room has finished
+func (r *room) allDone() bool {
+	for _, p := range r.active() {
+		if p.Position == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *room) active() []*participant {
+	active := make([]*participant, 0, len(r.participants))
+	for _, p := range r.participants {
+		if !p.Left {
+			active = append(active, p)
+		}
+	}
+	return active
+}
+
+// humans are the active participants who are not bots
+func (r *room) humans() []*participant {
+	humans := make([]*participant, 0, len(r.participants))
+	for _, p := range r.active() {
+		if p.bot == nil {
+			humans = append(humans, p)
+		}
+	}
+	return humans
+}
+
+func (r *room) assignHost() {
+	for _, p := range r.participants {
+		p.Host = p.bot == nil && p.email == r.host
+	}
+}
+
+func (r *room) snapshot() *race.Room {
+	room := &race.Room{
+		ID:             r.id,
+		InviteCode:     r.inviteCode,
+		SpectatorToken: r.spectatorToken,
+		Status:         r.status,
+		Rated:          r.rated,
+		Settings:       r.settings,
+		Round:          r.round,
+		Spectators:     len(r.spectators),
+		Participants:   make([]*race.Participant, 0, len(r.participants)),
+	}
+	if r.text != "" {
+		room.RaceID = r.raceID
+		room.Text = r.text
+		startsAt := r.startsAt
+		room.StartsAt = &startsAt
+	}
+	if !r.startedAt.IsZero() {
+		startedAt := r.startedAt
+		room.StartedAt = &startedAt
+	}
+	for _, p := range r.participants {
+		snap := p.Participant
+		snap.Connected = p.conn != nil || p.bot != nil
+		room.Participants = append(room.Participants, &snap)
+	}
+	return room
+}
+
+// broadcast sends the room state to every connected participant and spectator
+func (r *room) broadcast(eventType string, errMsg string) {
+	snap := r.snapshot()
+	for _, p := range r.active() {
+		r.sendEvent(p, &race.Event{Type: eventType, You: p.ID, Room: snap, Error: errMsg})
+	}
+
+	if len(r.spectators) == 0 {
+		return
+	}
+	ev := &race.Event{Type: eventType, Room: spectatorView(snap), Error: errMsg}
+	for s := range r.spectators {
+		r.sendSpectator(s, ev)
+	}
+}
+
+// sendEvent never blocks. A participant whose buffer is full misses the
+// event rather than stalling the race; the next one carries the full state anyway.
+func (r *room) sendEvent(p *participant, ev *race.Event) {
+	if p.conn == nil {
+		return
+	}
+	select {
+	case p.conn.events <- ev:
+	default:
+	}
+}
+
+func (r *room) sendError(p *participant, err error) {
+	r.sendEvent(p, &race.Event{Type: race.EventError, You: p.ID, Error: err.Error()})
+}
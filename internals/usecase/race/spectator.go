@@ -0,0 +1,88 @@
+package race
+
+import (
+	"context"
+	"crypto/subtle"
+	"sync"
+	"typing-speed/internals/core/race"
+)
+
+// spectatorBuffer bounds what a slow viewer can fall behind by. Past it
+// their events are dropped, the room never waits on them.
+const spectatorBuffer = 16
+
+// spectator watches a room without taking part
+type spectator struct {
+	room      *room
+	events    chan *race.Event
+	closeOnce sync.Once
+}
+
+func (s *spectator) Events() <-chan *race.Event {
+	return s.events
+}
+
+func (s *spectator) Close() {
+	s.closeOnce.Do(func() {
+		s.room.send(command{kind: cmdUnwatch, s: s})
+	})
+}
+
+// Spectate lets the holder of the room's spectator token watch it
+func (s *RaceServiceImpl) Spectate(ctx context.Context, roomID string, token string) (race.Spectator, error) {
+	s.mu.Lock()
+	r := s.rooms[roomID]
+	s.mu.Unlock()
+	if r == nil {
+		return nil, race.ErrRoomNotFound
+	}
+
+	if r.spectatorToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(r.spectatorToken)) != 1 {
+		return nil, race.ErrInvalidSpectatorToken
+	}
+
+	sp := &spectator{room: r, events: make(chan *race.Event, spectatorBuffer)}
+
+	reply := make(chan error, 1)
+	if !r.send(command{kind: cmdWatch, s: sp, reply: reply}) {
+		return nil, race.ErrRoomNotFound
+	}
+	if err := <-reply; err != nil {
+		return nil, err
+	}
+
+	return sp, nil
+}
+
+func (r *room) watch(s *spectator) error {
+	if len(r.spectators) >= race.MaxSpectators {
+		return race.ErrTooManySpectators
+	}
+
+	r.spectators[s] = struct{}{}
+	r.sendSpectator(s, &race.Event{Type: race.EventState, Room: spectatorView(r.snapshot())})
+	return nil
+}
+
+func (r *room) unwatch(s *spectator) {
+	if _, ok := r.spectators[s]; !ok {
+		return
+	}
+	delete(r.spectators, s)
+	close(s.events)
+}
+
+// spectatorView hides what would let a viewer join the room or pass the stream on
+func spectatorView(snap *race.Room) *race.Room {
+	view := *snap
+	view.InviteCode = ""
+	view.SpectatorToken = ""
+	return &view
+}
+
+func (r *room) sendSpectator(s *spectator, ev *race.Event) {
+	select {
+	case s.events <- ev:
+	default:
+	}
+}
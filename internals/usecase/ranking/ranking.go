@@ -0,0 +1,156 @@
+package ranking
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/ranking"
+)
+
+// RankingServiceImpl ranks the users by their average performance in memory,
+// so that reading the ranking does not sort the users table
+type RankingServiceImpl struct {
+	rankingSvc port.RankingRepository
+
+	reconciling sync.Mutex
+	mu          sync.RWMutex
+	ranks       *ranking.SkipList
+	loaded      bool
+	// updates made while a reconciliation reads the database, replayed on
+	// the ranking it loads. A nil entry is a removal.
+	reloading bool
+	updates   map[string]*ranking.Entry
+}
+
+func NewRankingService(rankings port.RankingRepository) ranking.RankingService {
+	return &RankingServiceImpl{
+		rankingSvc: rankings,
+		ranks:      ranking.NewSkipList(),
+	}
+}
+
+// Top returns the n best users
+func (s *RankingServiceImpl) Top(ctx context.Context, n int) ([]*ranking.Entry, error) {
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ranks.Range(1, n), nil
+}
+
+// Rank returns where the user stands
+func (s *RankingServiceImpl) Rank(ctx context.Context, email string) (*ranking.Position, error) {
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rank, e, ok := s.ranks.Rank(email)
+	if !ok {
+		return nil, ranking.ErrNotRanked
+	}
+	return &ranking.Position{Rank: rank, Users: s.ranks.Len(), Performance: e.Performance}, nil
+}
+
+// Around returns the user with up to limit users ranked on each side of them
+func (s *RankingServiceImpl) Around(ctx context.Context, email string, limit string) (*ranking.Around, error) {
+	n := ranking.AroundSize
+	if limit != "" {
+		v, err := strconv.Atoi(limit)
+		if err != nil || v < 1 || v > ranking.MaxAroundSize {
+			return nil, ranking.ErrInvalidLimit
+		}
+		n = v
+	}
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rank, _, ok := s.ranks.Rank(email)
+	if !ok {
+		return nil, ranking.ErrNotRanked
+	}
+
+	from := max(rank-n, 1)
+	entries := s.ranks.Range(from, rank-from+n+1)
+	for _, e := range entries {
+		e.You = e.Email == email
+	}
+	return &ranking.Around{Rank: rank, Users: s.ranks.Len(), Entries: entries}, nil
+}
+
+// Update ranks the user again from their row, e.g. after a test changed
+// their average or they changed their privacy
+func (s *RankingServiceImpl) Update(ctx context.Context, email string) error {
+	e, err := s.rankingSvc.GetRanking(ctx, email)
+	if err != nil {
+		return ranking.ErrGettingDataFromDB
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reloading {
+		s.updates[email] = e
+	}
+	set(s.ranks, email, e)
+	return nil
+}
+
+func set(ranks *ranking.SkipList, email string, e *ranking.Entry) {
+	if e == nil {
+		ranks.Remove(email)
+		return
+	}
+	ranks.Set(*e)
+}
+
+// Reconcile loads the ranking again from the database, correcting any drift
+func (s *RankingServiceImpl) Reconcile(ctx context.Context) error {
+	s.reconciling.Lock()
+	defer s.reconciling.Unlock()
+
+	s.mu.Lock()
+	s.reloading = true
+	s.updates = map[string]*ranking.Entry{}
+	s.mu.Unlock()
+
+	entries, err := s.rankingSvc.GetRankings(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reloading = false
+	if err != nil {
+		return ranking.ErrGettingDataFromDB
+	}
+
+	ranks := ranking.NewSkipList()
+	for _, e := range entries {
+		ranks.Set(*e)
+	}
+	for email, e := range s.updates {
+		set(ranks, email, e)
+	}
+	s.ranks = ranks
+	s.loaded = true
+	s.updates = nil
+	return nil
+}
+
+// load reads the ranking the first time it is needed, in case the
+// reconciliation at startup failed
+func (s *RankingServiceImpl) load(ctx context.Context) error {
+	s.mu.RLock()
+	loaded := s.loaded
+	s.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+	return s.Reconcile(ctx)
+}
@@ -0,0 +1,166 @@
+package ranking
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/ranking"
+)
+
+// FakeRankingRepo holds the public users by email
+type FakeRankingRepo struct {
+	port.RankingRepository
+	users map[string]*ranking.Entry
+	loads int
+	// during is run while GetRankings reads, like an update racing a reconciliation
+	during func()
+}
+
+func (f *FakeRankingRepo) GetRankings(ctx context.Context) ([]*ranking.Entry, error) {
+	f.loads++
+	entries := []*ranking.Entry{}
+	for _, e := range f.users {
+		copied := *e
+		entries = append(entries, &copied)
+	}
+	if f.during != nil {
+		f.during()
+	}
+	return entries, nil
+}
+
+func (f *FakeRankingRepo) GetRanking(ctx context.Context, email string) (*ranking.Entry, error) {
+	e, ok := f.users[email]
+	if !ok {
+		return nil, nil
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func TestRankingMatchesSort(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeRankingRepo{users: map[string]*ranking.Entry{}}
+	svc := NewRankingService(repo)
+	rnd := rand.New(rand.NewSource(1))
+
+	if err := svc.Reconcile(ctx); err != nil {
+		t.Fatalf("expected %v, got %v", nil, err)
+	}
+
+	// random inserts, moves and removals, checked against a plain sort
+	for i := 0; i < 2000; i++ {
+		email := fmt.Sprintf("u%d@mail.com", rnd.Intn(200))
+		if rnd.Intn(5) == 0 {
+			delete(repo.users, email)
+		} else {
+			repo.users[email] = &ranking.Entry{Email: email, Name: email, Performance: rnd.Intn(50) * 100}
+		}
+		if err := svc.Update(ctx, email); err != nil {
+			t.Fatalf("expected %v, got %v", nil, err)
+		}
+	}
+
+	want := []*ranking.Entry{}
+	for _, e := range repo.users {
+		want = append(want, e)
+	}
+	sort.Slice(want, func(i, j int) bool {
+		if want[i].Performance != want[j].Performance {
+			return want[i].Performance > want[j].Performance
+		}
+		return want[i].Email < want[j].Email
+	})
+
+	top, _ := svc.Top(ctx, len(want)+10)
+	if len(top) != len(want) {
+		t.Fatalf("expected %v, got %v", len(want), len(top))
+	}
+	for i, e := range want {
+		if top[i].Email != e.Email || top[i].Rank != i+1 {
+			t.Fatalf("expected %v at %v, got %v at %v", e.Email, i+1, top[i].Email, top[i].Rank)
+		}
+		got, err := svc.Rank(ctx, e.Email)
+		if err != nil || got.Rank != i+1 || got.Users != len(want) {
+			t.Fatalf("expected %v of %v, got %+v", i+1, len(want), got)
+		}
+	}
+}
+
+func TestAround(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeRankingRepo{users: map[string]*ranking.Entry{}}
+	for i := 1; i <= 20; i++ {
+		email := fmt.Sprintf("u%02d@mail.com", i)
+		repo.users[email] = &ranking.Entry{Email: email, Performance: 10000 - i*100}
+	}
+	svc := NewRankingService(repo)
+
+	tests := []struct {
+		name          string
+		email         string
+		limit         string
+		expectedFirst int
+		expectedLen   int
+		expectedError error
+	}{
+		{name: "middle", email: "u10@mail.com", expectedFirst: 5, expectedLen: 11},
+		{name: "top", email: "u02@mail.com", limit: "3", expectedFirst: 1, expectedLen: 5},
+		{name: "bottom", email: "u20@mail.com", limit: "2", expectedFirst: 18, expectedLen: 3},
+		{name: "not ranked", email: "private@mail.com", expectedError: ranking.ErrNotRanked},
+		{name: "limit", email: "u10@mail.com", limit: "26", expectedError: ranking.ErrInvalidLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Around(ctx, tt.email, tt.limit)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(got.Entries) != tt.expectedLen || got.Entries[0].Rank != tt.expectedFirst {
+				t.Fatalf("expected %v from %v, got %v from %v", tt.expectedLen, tt.expectedFirst,
+					len(got.Entries), got.Entries[0].Rank)
+			}
+			for _, e := range got.Entries {
+				if e.You != (e.Email == tt.email) {
+					t.Fatalf("expected %v, got %v", e.Email == tt.email, e.You)
+				}
+			}
+		})
+	}
+
+	if repo.loads != 1 {
+		t.Fatalf("expected %v, got %v", 1, repo.loads)
+	}
+}
+
+func TestReconcileKeepsConcurrentUpdates(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeRankingRepo{users: map[string]*ranking.Entry{
+		"a@mail.com": {Email: "a@mail.com", Performance: 5000},
+		"b@mail.com": {Email: "b@mail.com", Performance: 4000},
+	}}
+	svc := NewRankingService(repo)
+
+	// b overtakes a after the reconciliation read the users
+	repo.during = func() {
+		repo.users["b@mail.com"] = &ranking.Entry{Email: "b@mail.com", Performance: 6000}
+		if err := svc.Update(ctx, "b@mail.com"); err != nil {
+			t.Fatalf("expected %v, got %v", nil, err)
+		}
+	}
+	if err := svc.Reconcile(ctx); err != nil {
+		t.Fatalf("expected %v, got %v", nil, err)
+	}
+
+	got, err := svc.Rank(ctx, "b@mail.com")
+	if err != nil || got.Rank != 1 || got.Performance != 6000 {
+		t.Fatalf("expected %v, got %+v", "rank 1 at 6000", got)
+	}
+}
@@ -0,0 +1,93 @@
+package replay
+
+import (
+	"context"
+	"strings"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/replay"
+	"typing-speed/internals/core/typing"
+)
+
+type ReplayServiceImpl struct {
+	replaySvc port.ReplayRepository
+}
+
+func NewReplayService(svc port.ReplayRepository) replay.ReplayService {
+	return &ReplayServiceImpl{
+		replaySvc: svc,
+	}
+}
+
+// Replay returns the replay of one of the user's own tests
+func (r *ReplayServiceImpl) Replay(ctx context.Context, email string, testID string) (*replay.Replay, error) {
+	if !replay.ValidTestID(testID) {
+		return nil, replay.ErrReplayNotFound
+	}
+
+	data, err := r.replaySvc.GetReplay(ctx, email, testID)
+	if err != nil {
+		return nil, replay.ErrGettingDataFromDB
+	}
+	if data == nil {
+		return nil, replay.ErrReplayNotFound
+	}
+	return data, nil
+}
+
+// PersonalBestReplay returns the replay of the user's personal best in mode and language
+func (r *ReplayServiceImpl) PersonalBestReplay(ctx context.Context, email string, mode string, language string) (*replay.Replay, error) {
+	if !typing.ValidMode(mode) {
+		return nil, replay.ErrInvalidMode
+	}
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language == "" {
+		language = typing.DefaultLanguage
+	}
+
+	data, err := r.replaySvc.GetPersonalBestReplay(ctx, email, mode, language)
+	if err != nil {
+		return nil, replay.ErrGettingDataFromDB
+	}
+	if data == nil {
+		return nil, replay.ErrReplayNotFound
+	}
+	return data, nil
+}
+
+// ShareReplay makes the replay public. Sharing it again returns the same token.
+func (r *ReplayServiceImpl) ShareReplay(ctx context.Context, email string, testID string) (*replay.Share, error) {
+	if !replay.ValidTestID(testID) {
+		return nil, replay.ErrReplayNotFound
+	}
+
+	token, err := replay.NewShareToken()
+	if err != nil {
+		return nil, replay.ErrSomethingWentWrong
+	}
+
+	token, err = r.replaySvc.SetShareToken(ctx, email, testID, token)
+	if err != nil {
+		return nil, replay.ErrSomethingWentWrong
+	}
+	if token == "" {
+		return nil, replay.ErrReplayNotFound
+	}
+
+	return &replay.Share{TestID: testID, Token: token}, nil
+}
+
+// SharedReplay returns a replay anyone with its token can watch
+func (r *ReplayServiceImpl) SharedReplay(ctx context.Context, token string) (*replay.Replay, error) {
+	if token == "" {
+		return nil, replay.ErrReplayNotFound
+	}
+
+	data, err := r.replaySvc.GetSharedReplay(ctx, token)
+	if err != nil {
+		return nil, replay.ErrGettingDataFromDB
+	}
+	if data == nil {
+		return nil, replay.ErrReplayNotFound
+	}
+	return data, nil
+}
@@ -0,0 +1,181 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"typing-speed/internals/core/replay"
+)
+
+const testID = "6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"
+
+type FakeReplayRepo struct {
+	GetReplayFn             func(ctx context.Context, email string, testID string) (*replay.Replay, error)
+	GetPersonalBestReplayFn func(ctx context.Context, email string, mode string, language string) (*replay.Replay, error)
+	SetShareTokenFn         func(ctx context.Context, email string, testID string, token string) (string, error)
+	GetSharedReplayFn       func(ctx context.Context, token string) (*replay.Replay, error)
+}
+
+func (f *FakeReplayRepo) GetReplay(ctx context.Context, email string, testID string) (*replay.Replay, error) {
+	if f.GetReplayFn != nil {
+		return f.GetReplayFn(ctx, email, testID)
+	}
+	return nil, nil
+}
+
+func (f *FakeReplayRepo) GetPersonalBestReplay(ctx context.Context, email string, mode string, language string) (*replay.Replay, error) {
+	if f.GetPersonalBestReplayFn != nil {
+		return f.GetPersonalBestReplayFn(ctx, email, mode, language)
+	}
+	return nil, nil
+}
+
+func (f *FakeReplayRepo) SetShareToken(ctx context.Context, email string, testID string, token string) (string, error) {
+	if f.SetShareTokenFn != nil {
+		return f.SetShareTokenFn(ctx, email, testID, token)
+	}
+	return "", nil
+}
+
+func (f *FakeReplayRepo) GetSharedReplay(ctx context.Context, token string) (*replay.Replay, error) {
+	if f.GetSharedReplayFn != nil {
+		return f.GetSharedReplayFn(ctx, token)
+	}
+	return nil, nil
+}
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		testID        string
+		repo          *FakeReplayRepo
+		expectedError error
+	}{
+		{name: "malformed id", testID: "1; DROP TABLE", repo: &FakeReplayRepo{}, expectedError: replay.ErrReplayNotFound},
+		{name: "not the user's test", testID: testID, repo: &FakeReplayRepo{}, expectedError: replay.ErrReplayNotFound},
+		{
+			name:   "db error",
+			testID: testID,
+			repo: &FakeReplayRepo{
+				GetReplayFn: func(ctx context.Context, email string, id string) (*replay.Replay, error) {
+					return nil, errors.New("db error")
+				},
+			},
+			expectedError: replay.ErrGettingDataFromDB,
+		},
+		{
+			name:   "success",
+			testID: testID,
+			repo: &FakeReplayRepo{
+				GetReplayFn: func(ctx context.Context, email string, id string) (*replay.Replay, error) {
+					return &replay.Replay{TestID: id, Timeline: []int{0, 6, 11}}, nil
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewReplayService(tt.repo)
+
+			data, err := service.Replay(ctx, "test@mail.com", tt.testID)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if err == nil && data.TestID != tt.testID {
+				t.Fatalf("expected replay of %s, got %+v", tt.testID, data)
+			}
+		})
+	}
+}
+
+func TestPersonalBestReplay(t *testing.T) {
+	ctx := context.Background()
+
+	var gotLanguage string
+	service := NewReplayService(&FakeReplayRepo{
+		GetPersonalBestReplayFn: func(ctx context.Context, email string, mode string, language string) (*replay.Replay, error) {
+			gotLanguage = language
+			return &replay.Replay{Mode: mode, Language: language}, nil
+		},
+	})
+
+	if _, err := service.PersonalBestReplay(ctx, "test@mail.com", "5s", ""); err != replay.ErrInvalidMode {
+		t.Fatalf("expected %v, got %v", replay.ErrInvalidMode, err)
+	}
+
+	if _, err := service.PersonalBestReplay(ctx, "test@mail.com", "60s", ""); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if gotLanguage != "english" {
+		t.Fatalf("expected the default language, got %q", gotLanguage)
+	}
+}
+
+func TestShareReplay(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("new token", func(t *testing.T) {
+		service := NewReplayService(&FakeReplayRepo{
+			SetShareTokenFn: func(ctx context.Context, email string, id string, token string) (string, error) {
+				return token, nil
+			},
+		})
+
+		share, err := service.ShareReplay(ctx, "test@mail.com", testID)
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if len(share.Token) < 22 {
+			t.Fatalf("expected an unguessable token, got %q", share.Token)
+		}
+	})
+
+	t.Run("already shared", func(t *testing.T) {
+		service := NewReplayService(&FakeReplayRepo{
+			SetShareTokenFn: func(ctx context.Context, email string, id string, token string) (string, error) {
+				return "existing-token", nil
+			},
+		})
+
+		share, err := service.ShareReplay(ctx, "test@mail.com", testID)
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if share.Token != "existing-token" {
+			t.Fatalf("expected the existing token, got %q", share.Token)
+		}
+	})
+
+	t.Run("no replay", func(t *testing.T) {
+		service := NewReplayService(&FakeReplayRepo{})
+
+		if _, err := service.ShareReplay(ctx, "test@mail.com", testID); err != replay.ErrReplayNotFound {
+			t.Fatalf("expected %v, got %v", replay.ErrReplayNotFound, err)
+		}
+	})
+}
+
+func TestSharedReplay(t *testing.T) {
+	ctx := context.Background()
+
+	service := NewReplayService(&FakeReplayRepo{
+		GetSharedReplayFn: func(ctx context.Context, token string) (*replay.Replay, error) {
+			if token != "abc" {
+				return nil, nil
+			}
+			return &replay.Replay{Name: "Navneet"}, nil
+		},
+	})
+
+	if _, err := service.SharedReplay(ctx, "nope"); err != replay.ErrReplayNotFound {
+		t.Fatalf("expected %v, got %v", replay.ErrReplayNotFound, err)
+	}
+
+	data, err := service.SharedReplay(ctx, "abc")
+	if err != nil || data.Name != "Navneet" {
+		t.Fatalf("expected shared replay, got %+v, %v", data, err)
+	}
+}
@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"context"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/stats"
+	"typing-speed/internals/core/user"
+)
+
+type StatsServiceImpl struct {
+	userSvc  port.UserRepository
+	statsSvc port.StatsRepository
+}
+
+func NewStatsService(users port.UserRepository, statsRepo port.StatsRepository) stats.StatsService {
+	return &StatsServiceImpl{
+		userSvc:  users,
+		statsSvc: statsRepo,
+	}
+}
+
+// Progress returns the user's progress over the range, one point per
+// bucket. Buckets follow the calendar of the user's time zone.
+func (s *StatsServiceImpl) Progress(ctx context.Context, email string, filter *stats.Filter) (*stats.Series, error) {
+	u, err := s.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, stats.ErrGettingDataFromDB
+	}
+	if u == nil {
+		return nil, stats.ErrUserNotFound
+	}
+
+	loc := user.Location(u.TimeZone)
+	q, err := stats.NewQuery(email, filter, loc, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := s.statsSvc.GetProgress(ctx, q)
+	if err != nil {
+		return nil, stats.ErrGettingDataFromDB
+	}
+
+	return &stats.Series{
+		Bucket:   q.Bucket,
+		Mode:     q.Mode,
+		From:     q.From.Format(stats.DateLayout),
+		To:       q.To.Format(stats.DateLayout),
+		TimeZone: loc.String(),
+		Points:   points,
+	}, nil
+}
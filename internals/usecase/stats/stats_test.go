@@ -0,0 +1,94 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/stats"
+	"typing-speed/internals/core/user"
+)
+
+type FakeUserRepo struct {
+	port.UserRepository
+	user *user.User
+}
+
+func (f *FakeUserRepo) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	return f.user, nil
+}
+
+// FakeStatsRepo records the query it is asked
+type FakeStatsRepo struct {
+	port.StatsRepository
+	query *stats.Query
+	err   error
+}
+
+func (f *FakeStatsRepo) GetProgress(ctx context.Context, q *stats.Query) ([]*stats.Point, error) {
+	f.query = q
+	return []*stats.Point{}, f.err
+}
+
+func TestProgress(t *testing.T) {
+	ctx := context.Background()
+	users := &FakeUserRepo{user: &user.User{Email: "a@mail.com", TimeZone: "Asia/Kolkata"}}
+
+	tests := []struct {
+		name          string
+		filter        *stats.Filter
+		repo          *FakeStatsRepo
+		expectedFrom  string
+		expectedTo    string
+		expectedError error
+	}{
+		{name: "days", filter: &stats.Filter{From: "2026-10-01", To: "2026-10-19"}, repo: &FakeStatsRepo{},
+			expectedFrom: "2026-10-01", expectedTo: "2026-10-19"},
+		{name: "weeks start on monday", filter: &stats.Filter{From: "2026-10-01", To: "2026-10-19", Bucket: "week"},
+			repo: &FakeStatsRepo{}, expectedFrom: "2026-09-28", expectedTo: "2026-10-19"},
+		{name: "months", filter: &stats.Filter{From: "2026-01-15", To: "2026-10-19", Bucket: "month"},
+			repo: &FakeStatsRepo{}, expectedFrom: "2026-01-01", expectedTo: "2026-10-01"},
+		{name: "default range", filter: &stats.Filter{To: "2026-10-19"}, repo: &FakeStatsRepo{},
+			expectedFrom: "2026-09-20", expectedTo: "2026-10-19"},
+		{name: "bucket", filter: &stats.Filter{Bucket: "year"}, repo: &FakeStatsRepo{},
+			expectedError: stats.ErrInvalidBucket},
+		{name: "mode", filter: &stats.Filter{Mode: "marathon"}, repo: &FakeStatsRepo{},
+			expectedError: stats.ErrInvalidMode},
+		{name: "date", filter: &stats.Filter{From: "19/10/2026"}, repo: &FakeStatsRepo{},
+			expectedError: stats.ErrInvalidDate},
+		{name: "reversed", filter: &stats.Filter{From: "2026-10-19", To: "2026-10-01"}, repo: &FakeStatsRepo{},
+			expectedError: stats.ErrInvalidRange},
+		{name: "too long", filter: &stats.Filter{From: "2025-01-01", To: "2026-10-19"}, repo: &FakeStatsRepo{},
+			expectedError: stats.ErrInvalidRange},
+		{name: "db error", filter: &stats.Filter{}, repo: &FakeStatsRepo{err: errors.New("db error")},
+			expectedError: stats.ErrGettingDataFromDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewStatsService(users, tt.repo)
+
+			got, err := svc.Progress(ctx, "a@mail.com", tt.filter)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if got.From != tt.expectedFrom || got.To != tt.expectedTo {
+				t.Fatalf("expected %v to %v, got %v to %v", tt.expectedFrom, tt.expectedTo, got.From, got.To)
+			}
+			if got.TimeZone != "Asia/Kolkata" || tt.repo.query.Location.String() != "Asia/Kolkata" {
+				t.Fatalf("expected %v, got %v", "Asia/Kolkata", got.TimeZone)
+			}
+		})
+	}
+}
+
+func TestProgressUserNotFound(t *testing.T) {
+	svc := NewStatsService(&FakeUserRepo{}, &FakeStatsRepo{})
+
+	if _, err := svc.Progress(context.Background(), "a@mail.com", &stats.Filter{}); err != stats.ErrUserNotFound {
+		t.Fatalf("expected %v, got %v", stats.ErrUserNotFound, err)
+	}
+}
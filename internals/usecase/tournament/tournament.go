@@ -0,0 +1,424 @@
+package tournament
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/race"
+	"typing-speed/internals/core/rating"
+	"typing-speed/internals/core/tournament"
+	"typing-speed/internals/core/typing"
+
+	"github.com/google/uuid"
+)
+
+const (
+	listLimit     = 50
+	resultTimeout = 30 * time.Second
+)
+
+type TournamentServiceImpl struct {
+	tournamentSvc   port.TournamentRepository
+	ratingSvc       port.RatingRepository
+	personalBestSvc port.PersonalBestRepository
+	txSvc           port.Transactor
+	raceSvc         race.RaceService
+}
+
+func NewTournamentService(tournaments port.TournamentRepository, ratings port.RatingRepository,
+	pbs port.PersonalBestRepository, tx port.Transactor, raceSvc race.RaceService) tournament.TournamentService {
+	return &TournamentServiceImpl{
+		tournamentSvc:   tournaments,
+		ratingSvc:       ratings,
+		personalBestSvc: pbs,
+		txSvc:           tx,
+		raceSvc:         raceSvc,
+	}
+}
+
+func (s *TournamentServiceImpl) CreateTournament(ctx context.Context, email string, t *tournament.Tournament) (*tournament.Tournament, error) {
+	newTournament := &tournament.Tournament{
+		ID:                   uuid.NewString(),
+		Name:                 t.Name,
+		Format:               t.Format,
+		Seeding:              t.Seeding,
+		Mode:                 t.Mode,
+		BestOf:               t.BestOf,
+		MaxEntrants:          t.MaxEntrants,
+		RegistrationOpensAt:  t.RegistrationOpensAt,
+		RegistrationClosesAt: t.RegistrationClosesAt,
+		Status:               tournament.StatusRegistration,
+		CreatedBy:            email,
+	}
+	if err := newTournament.Validate(time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.tournamentSvc.CreateTournament(ctx, newTournament); err != nil {
+		return nil, tournament.ErrSomethingWentWrong
+	}
+
+	return newTournament, nil
+}
+
+func (s *TournamentServiceImpl) Tournaments(ctx context.Context) ([]*tournament.Tournament, error) {
+	data, err := s.tournamentSvc.GetTournaments(ctx, listLimit)
+	if err != nil {
+		return nil, tournament.ErrGettingDataFromDB
+	}
+	return data, nil
+}
+
+// Register enters the user while registration is open. Registering twice is not an error.
+func (s *TournamentServiceImpl) Register(ctx context.Context, id string, email string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return tournament.ErrTournamentNotFound
+	}
+
+	return s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		t, err := s.tournamentSvc.GetTournamentForUpdate(ctx, id)
+		if err != nil {
+			return tournament.ErrGettingDataFromDB
+		}
+		if t == nil {
+			return tournament.ErrTournamentNotFound
+		}
+		if !t.RegistrationOpen(time.Now()) {
+			return tournament.ErrRegistrationClosed
+		}
+		if t.Entrants >= t.MaxEntrants {
+			return tournament.ErrTournamentFull
+		}
+
+		if err := s.tournamentSvc.AddEntrant(ctx, id, email); err != nil {
+			return tournament.ErrSomethingWentWrong
+		}
+		return nil
+	})
+}
+
+func (s *TournamentServiceImpl) Withdraw(ctx context.Context, id string, email string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return tournament.ErrTournamentNotFound
+	}
+
+	return s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		t, err := s.tournamentSvc.GetTournamentForUpdate(ctx, id)
+		if err != nil {
+			return tournament.ErrGettingDataFromDB
+		}
+		if t == nil {
+			return tournament.ErrTournamentNotFound
+		}
+		if !t.RegistrationOpen(time.Now()) {
+			return tournament.ErrRegistrationClosed
+		}
+
+		removed, err := s.tournamentSvc.RemoveEntrant(ctx, id, email)
+		if err != nil {
+			return tournament.ErrSomethingWentWrong
+		}
+		if !removed {
+			return tournament.ErrNotRegistered
+		}
+		return nil
+	})
+}
+
+// Start seeds the entrants, draws the matches and opens the rooms of the
+// first ones. Only the organizer can start, once registration has closed.
+func (s *TournamentServiceImpl) Start(ctx context.Context, id string, email string) (*tournament.Bracket, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, tournament.ErrTournamentNotFound
+	}
+
+	var t *tournament.Tournament
+	var entrants []*tournament.Entrant
+	var matches []*tournament.Match
+
+	err := s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		t, err = s.tournamentSvc.GetTournamentForUpdate(ctx, id)
+		if err != nil {
+			return tournament.ErrGettingDataFromDB
+		}
+		if t == nil {
+			return tournament.ErrTournamentNotFound
+		}
+		if t.CreatedBy != email {
+			return tournament.ErrNotOrganizer
+		}
+		if t.Status != tournament.StatusRegistration {
+			return tournament.ErrRegistrationClosed
+		}
+		now := time.Now()
+		if now.Before(t.RegistrationClosesAt) {
+			return tournament.ErrRegistrationOpen
+		}
+
+		entrants, err = s.tournamentSvc.GetEntrants(ctx, id, email)
+		if err != nil {
+			return tournament.ErrGettingDataFromDB
+		}
+		if len(entrants) < tournament.MinEntrants {
+			return tournament.ErrNotEnoughEntrants
+		}
+
+		if err := s.seed(ctx, t, entrants); err != nil {
+			return err
+		}
+		if err := s.tournamentSvc.SetSeeds(ctx, id, entrants); err != nil {
+			return tournament.ErrSomethingWentWrong
+		}
+
+		if t.Format == tournament.FormatRoundRobin {
+			matches = tournament.RoundRobin(entrants)
+		} else {
+			matches = tournament.SingleElimination(entrants)
+		}
+		for _, m := range matches {
+			m.ID = uuid.NewString()
+			if m.Status == tournament.MatchDone {
+				m.FinishedAt = &now
+			}
+		}
+		if err := s.tournamentSvc.InsertMatches(ctx, id, matches); err != nil {
+			return tournament.ErrSomethingWentWrong
+		}
+
+		if err := s.tournamentSvc.UpdateTournamentStatus(ctx, id, tournament.StatusRunning, now); err != nil {
+			return tournament.ErrSomethingWentWrong
+		}
+		t.Status = tournament.StatusRunning
+		t.StartedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.schedule(ctx, t, matches)
+
+	tournament.Link(matches, entrants)
+	return &tournament.Bracket{Tournament: t, Entrants: entrants, Matches: matches}, nil
+}
+
+// seed ranks the entrants by rating or personal best, best first. Unrated
+// players count at the starting rating and players without a personal best
+// come last. Ties keep the order of registration.
+func (s *TournamentServiceImpl) seed(ctx context.Context, t *tournament.Tournament, entrants []*tournament.Entrant) error {
+	for _, e := range entrants {
+		if t.Seeding == tournament.SeedingPersonalBest {
+			pb, err := s.personalBestSvc.GetPersonalBest(ctx, e.Email, t.Mode, typing.DefaultLanguage)
+			if err != nil {
+				return tournament.ErrGettingDataFromDB
+			}
+			e.SeedScore = 0
+			if pb != nil {
+				e.SeedScore = float64(pb.WPM)
+			}
+			continue
+		}
+
+		r, err := s.ratingSvc.GetRating(ctx, e.Email)
+		if err != nil {
+			return tournament.ErrGettingDataFromDB
+		}
+		e.SeedScore = rating.DefaultRating
+		if r != nil {
+			e.SeedScore = r.Rating
+		}
+	}
+
+	sort.SliceStable(entrants, func(i, j int) bool {
+		return entrants[i].SeedScore > entrants[j].SeedScore
+	})
+	for i, e := range entrants {
+		e.Seed = i + 1
+	}
+	return nil
+}
+
+func (s *TournamentServiceImpl) Bracket(ctx context.Context, id string, email string) (*tournament.Bracket, error) {
+	t, entrants, matches, err := s.load(ctx, id, email)
+	if err != nil {
+		return nil, err
+	}
+
+	tournament.Link(matches, entrants)
+	return &tournament.Bracket{Tournament: t, Entrants: entrants, Matches: matches}, nil
+}
+
+// Standings ranks the entrants, provisionally until the tournament is over
+func (s *TournamentServiceImpl) Standings(ctx context.Context, id string, email string) (*tournament.Standings, error) {
+	t, entrants, matches, err := s.load(ctx, id, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tournament.Standings{
+		Tournament: t,
+		Final:      t.Status == tournament.StatusFinished,
+		Standings:  tournament.Rank(t.Format, entrants, matches),
+	}, nil
+}
+
+// load returns the tournament with the entrants as the viewer may see them
+func (s *TournamentServiceImpl) load(ctx context.Context, id string, viewer string) (*tournament.Tournament, []*tournament.Entrant, []*tournament.Match, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil, nil, tournament.ErrTournamentNotFound
+	}
+
+	t, err := s.tournamentSvc.GetTournament(ctx, id)
+	if err != nil {
+		return nil, nil, nil, tournament.ErrGettingDataFromDB
+	}
+	if t == nil {
+		return nil, nil, nil, tournament.ErrTournamentNotFound
+	}
+
+	entrants, err := s.tournamentSvc.GetEntrants(ctx, id, viewer)
+	if err != nil {
+		return nil, nil, nil, tournament.ErrGettingDataFromDB
+	}
+	matches, err := s.tournamentSvc.GetMatches(ctx, id)
+	if err != nil {
+		return nil, nil, nil, tournament.ErrGettingDataFromDB
+	}
+
+	return t, entrants, matches, nil
+}
+
+// Resume reopens the rooms of the matches that were live when the server
+// stopped, since rooms only live in memory
+func (s *TournamentServiceImpl) Resume(ctx context.Context) error {
+	running, err := s.tournamentSvc.GetRunningTournaments(ctx)
+	if err != nil {
+		return tournament.ErrGettingDataFromDB
+	}
+
+	for _, t := range running {
+		matches, err := s.tournamentSvc.GetMatches(ctx, t.ID)
+		if err != nil {
+			return tournament.ErrGettingDataFromDB
+		}
+		for _, m := range matches {
+			if m.Status == tournament.MatchLive {
+				m.Status = tournament.MatchPending
+				m.RoomID = ""
+			}
+		}
+		s.schedule(ctx, t, matches)
+	}
+	return nil
+}
+
+// schedule opens a race room for every match that is ready to be raced
+func (s *TournamentServiceImpl) schedule(ctx context.Context, t *tournament.Tournament, matches []*tournament.Match) {
+	settings := &race.Settings{Mode: t.Mode, Rounds: t.BestOf}
+
+	for _, m := range tournament.Ready(t.Format, matches) {
+		room, err := s.raceSvc.CreateMatchRoom(ctx, []string{m.PlayerA, m.PlayerB}, settings, s.matchFinished(t.ID, m.ID))
+		if err != nil {
+			log.Println("error opening the room of match", m.ID, ":", err)
+			continue
+		}
+
+		m.Status = tournament.MatchLive
+		m.RoomID = room.ID
+		if err := s.tournamentSvc.UpdateMatch(ctx, m); err != nil {
+			log.Println("error saving the room of match", m.ID, ":", err)
+		}
+	}
+}
+
+// matchFinished records the outcome of a match room when it closes
+func (s *TournamentServiceImpl) matchFinished(tournamentID string, matchID string) func(*race.MatchResult) {
+	return func(result *race.MatchResult) {
+		ctx, cancel := context.WithTimeout(context.Background(), resultTimeout)
+		defer cancel()
+
+		if err := s.finishMatch(ctx, tournamentID, matchID, result); err != nil {
+			log.Println("error recording the result of match", matchID, ":", err)
+		}
+	}
+}
+
+// finishMatch decides the match, advances the winner and opens the rooms
+// of the matches that became ready. The last match finishes the tournament.
+func (s *TournamentServiceImpl) finishMatch(ctx context.Context, tournamentID string, matchID string, result *race.MatchResult) error {
+	var t *tournament.Tournament
+	var matches []*tournament.Match
+	decided := false
+
+	err := s.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		t, err = s.tournamentSvc.GetTournamentForUpdate(ctx, tournamentID)
+		if err != nil {
+			return err
+		}
+		if t == nil || t.Status != tournament.StatusRunning {
+			return nil
+		}
+
+		matches, err = s.tournamentSvc.GetMatches(ctx, tournamentID)
+		if err != nil {
+			return err
+		}
+		var m *tournament.Match
+		for _, candidate := range matches {
+			if candidate.ID == matchID {
+				m = candidate
+			}
+		}
+		// a room reopened after a restart may report a match that is already decided
+		if m == nil || m.Status == tournament.MatchDone || m.RoomID != result.RoomID {
+			return nil
+		}
+
+		// only the seeds are needed, so no one views the names
+		entrants, err := s.tournamentSvc.GetEntrants(ctx, tournamentID, "")
+		if err != nil {
+			return err
+		}
+		seeds := make(map[string]int, len(entrants))
+		for _, e := range entrants {
+			seeds[e.Email] = e.Seed
+		}
+
+		now := time.Now()
+		m.Winner = tournament.Decide(t.Format, m, result.Placings, seeds)
+		m.RaceID = result.RaceID
+		m.Status = tournament.MatchDone
+		m.FinishedAt = &now
+		if err := s.tournamentSvc.UpdateMatch(ctx, m); err != nil {
+			return err
+		}
+		decided = true
+
+		if t.Format == tournament.FormatSingleElimination {
+			if next := tournament.Advance(matches, m); next != nil {
+				if err := s.tournamentSvc.UpdateMatch(ctx, next); err != nil {
+					return err
+				}
+			}
+		}
+
+		if tournament.Finished(matches) {
+			t.Status = tournament.StatusFinished
+			return s.tournamentSvc.UpdateTournamentStatus(ctx, tournamentID, tournament.StatusFinished, now)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// only the result that made a match ready opens its room
+	if decided && t.Status == tournament.StatusRunning {
+		s.schedule(ctx, t, matches)
+	}
+	return nil
+}
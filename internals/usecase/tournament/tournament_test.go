@@ -0,0 +1,439 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+	"typing-speed/internals/core/race"
+	"typing-speed/internals/core/rating"
+	"typing-speed/internals/core/tournament"
+	"typing-speed/internals/core/typing"
+)
+
+// FakeTournamentRepo keeps a single tournament in memory
+type FakeTournamentRepo struct {
+	tournament *tournament.Tournament
+	entrants   []*tournament.Entrant
+	matches    []*tournament.Match
+}
+
+func (f *FakeTournamentRepo) CreateTournament(ctx context.Context, t *tournament.Tournament) error {
+	f.tournament = t
+	return nil
+}
+
+func (f *FakeTournamentRepo) GetTournament(ctx context.Context, id string) (*tournament.Tournament, error) {
+	if f.tournament == nil || f.tournament.ID != id {
+		return nil, nil
+	}
+	t := *f.tournament
+	t.Entrants = len(f.entrants)
+	return &t, nil
+}
+
+func (f *FakeTournamentRepo) GetTournamentForUpdate(ctx context.Context, id string) (*tournament.Tournament, error) {
+	return f.GetTournament(ctx, id)
+}
+
+func (f *FakeTournamentRepo) GetTournaments(ctx context.Context, limit int) ([]*tournament.Tournament, error) {
+	return []*tournament.Tournament{f.tournament}, nil
+}
+
+func (f *FakeTournamentRepo) GetRunningTournaments(ctx context.Context) ([]*tournament.Tournament, error) {
+	if f.tournament.Status != tournament.StatusRunning {
+		return nil, nil
+	}
+	return []*tournament.Tournament{f.tournament}, nil
+}
+
+func (f *FakeTournamentRepo) UpdateTournamentStatus(ctx context.Context, id string, status string, at time.Time) error {
+	f.tournament.Status = status
+	return nil
+}
+
+func (f *FakeTournamentRepo) AddEntrant(ctx context.Context, id string, email string) error {
+	for _, e := range f.entrants {
+		if e.Email == email {
+			return nil
+		}
+	}
+	f.entrants = append(f.entrants, &tournament.Entrant{Email: email, Name: email})
+	return nil
+}
+
+func (f *FakeTournamentRepo) RemoveEntrant(ctx context.Context, id string, email string) (bool, error) {
+	for i, e := range f.entrants {
+		if e.Email == email {
+			f.entrants = append(f.entrants[:i], f.entrants[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *FakeTournamentRepo) GetEntrants(ctx context.Context, id string, viewer string) ([]*tournament.Entrant, error) {
+	entrants := []*tournament.Entrant{}
+	for _, e := range f.entrants {
+		copied := *e
+		entrants = append(entrants, &copied)
+	}
+	sort.SliceStable(entrants, func(i, j int) bool { return entrants[i].Seed < entrants[j].Seed })
+	return entrants, nil
+}
+
+func (f *FakeTournamentRepo) SetSeeds(ctx context.Context, id string, entrants []*tournament.Entrant) error {
+	for _, seeded := range entrants {
+		for _, e := range f.entrants {
+			if e.Email == seeded.Email {
+				e.Seed, e.SeedScore = seeded.Seed, seeded.SeedScore
+			}
+		}
+	}
+	return nil
+}
+
+func (f *FakeTournamentRepo) InsertMatches(ctx context.Context, id string, matches []*tournament.Match) error {
+	for _, m := range matches {
+		copied := *m
+		f.matches = append(f.matches, &copied)
+	}
+	return nil
+}
+
+func (f *FakeTournamentRepo) GetMatches(ctx context.Context, id string) ([]*tournament.Match, error) {
+	matches := []*tournament.Match{}
+	for _, m := range f.matches {
+		copied := *m
+		matches = append(matches, &copied)
+	}
+	return matches, nil
+}
+
+func (f *FakeTournamentRepo) UpdateMatch(ctx context.Context, m *tournament.Match) error {
+	for i, stored := range f.matches {
+		if stored.ID == m.ID {
+			copied := *m
+			f.matches[i] = &copied
+		}
+	}
+	return nil
+}
+
+type FakeRatingRepo struct {
+	ratings map[string]float64
+}
+
+func (f *FakeRatingRepo) GetRating(ctx context.Context, email string) (*rating.Rating, error) {
+	r, ok := f.ratings[email]
+	if !ok {
+		return nil, nil
+	}
+	return &rating.Rating{Email: email, Rating: r}, nil
+}
+
+func (f *FakeRatingRepo) GetRatingsForUpdate(ctx context.Context, emails []string) ([]*rating.Rating, error) {
+	return nil, nil
+}
+
+func (f *FakeRatingRepo) SaveRating(ctx context.Context, raceID string, r *rating.Rating, change float64) error {
+	return nil
+}
+
+func (f *FakeRatingRepo) GetRatingHistory(ctx context.Context, email string, limit int) ([]*rating.Change, error) {
+	return nil, nil
+}
+
+type FakePersonalBestRepo struct {
+	wpm map[string]int
+}
+
+func (f *FakePersonalBestRepo) GetPersonalBest(ctx context.Context, email string, mode string, language string) (*typing.PersonalBest, error) {
+	wpm, ok := f.wpm[email]
+	if !ok {
+		return nil, nil
+	}
+	return &typing.PersonalBest{WPM: wpm}, nil
+}
+
+func (f *FakePersonalBestRepo) GetPersonalBests(ctx context.Context, email string) ([]*typing.PersonalBest, error) {
+	return nil, nil
+}
+
+func (f *FakePersonalBestRepo) UpsertPersonalBest(ctx context.Context, pb *typing.PersonalBest, previousWPM int) error {
+	return nil
+}
+
+func (f *FakePersonalBestRepo) GetPersonalBestHistory(ctx context.Context, email string, mode string, language string) ([]*typing.PersonalBestRecord, error) {
+	return nil, nil
+}
+
+type FakeTransactor struct{}
+
+func (f *FakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// FakeRaceService opens match rooms that the test finishes by hand
+type FakeRaceService struct {
+	race.RaceService
+	rooms map[string]func(*race.MatchResult)
+}
+
+func (f *FakeRaceService) CreateMatchRoom(ctx context.Context, players []string, settings *race.Settings,
+	done func(*race.MatchResult)) (*race.Room, error) {
+	id := fmt.Sprintf("room-%d", len(f.rooms)+1)
+	f.rooms[id] = done
+	return &race.Room{ID: id}, nil
+}
+
+type fixture struct {
+	svc    *TournamentServiceImpl
+	repo   *FakeTournamentRepo
+	races  *FakeRaceService
+	closed time.Time
+}
+
+func newFixture(format string, seeding string, ratings map[string]float64, pbs map[string]int) *fixture {
+	repo := &FakeTournamentRepo{}
+	races := &FakeRaceService{rooms: map[string]func(*race.MatchResult){}}
+	svc := NewTournamentService(repo, &FakeRatingRepo{ratings: ratings}, &FakePersonalBestRepo{wpm: pbs},
+		&FakeTransactor{}, races)
+
+	closed := time.Now().Add(-time.Minute)
+	repo.tournament = &tournament.Tournament{
+		ID:                   "7f1c1b4e-2a43-4b4c-8c1b-0c7c1d0e5a11",
+		Name:                 "October cup",
+		Format:               format,
+		Seeding:              seeding,
+		Mode:                 typing.Mode30s,
+		BestOf:               1,
+		MaxEntrants:          tournament.MaxEntrants,
+		RegistrationOpensAt:  closed.Add(-time.Hour),
+		RegistrationClosesAt: closed,
+		Status:               tournament.StatusRegistration,
+		CreatedBy:            "org@mail.com",
+	}
+	return &fixture{svc: svc.(*TournamentServiceImpl), repo: repo, races: races, closed: closed}
+}
+
+func (f *fixture) enter(emails ...string) {
+	for _, email := range emails {
+		f.repo.AddEntrant(context.Background(), f.repo.tournament.ID, email)
+	}
+}
+
+// play finishes the live match of a against b with the winner first
+func (f *fixture) play(t *testing.T, winner string, loser string) {
+	t.Helper()
+	for _, m := range f.repo.matches {
+		if m.Status != tournament.MatchLive {
+			continue
+		}
+		if (m.PlayerA == winner && m.PlayerB == loser) || (m.PlayerA == loser && m.PlayerB == winner) {
+			f.races.rooms[m.RoomID](&race.MatchResult{RoomID: m.RoomID, RaceID: "race-" + m.ID, Placings: []string{winner, loser}})
+			return
+		}
+	}
+	t.Fatalf("no live match between %s and %s", winner, loser)
+}
+
+func (f *fixture) live() int {
+	n := 0
+	for _, m := range f.repo.matches {
+		if m.Status == tournament.MatchLive {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCreateTournament(t *testing.T) {
+	ctx := context.Background()
+	future := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		input         *tournament.Tournament
+		expectedError error
+	}{
+		{name: "missing name", input: &tournament.Tournament{RegistrationClosesAt: future}, expectedError: tournament.ErrInvalidTournament},
+		{name: "unknown format", input: &tournament.Tournament{Name: "Cup", Format: "swiss", RegistrationClosesAt: future}, expectedError: tournament.ErrInvalidTournament},
+		{name: "even best of", input: &tournament.Tournament{Name: "Cup", BestOf: 2, RegistrationClosesAt: future}, expectedError: tournament.ErrInvalidTournament},
+		{name: "registration already closed", input: &tournament.Tournament{Name: "Cup", RegistrationClosesAt: time.Now().Add(-time.Hour)}, expectedError: tournament.ErrInvalidTournament},
+		{name: "defaults", input: &tournament.Tournament{Name: " Cup ", RegistrationClosesAt: future}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewTournamentService(&FakeTournamentRepo{}, &FakeRatingRepo{}, &FakePersonalBestRepo{}, &FakeTransactor{},
+				&FakeRaceService{})
+
+			data, err := svc.CreateTournament(ctx, "org@mail.com", tt.input)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if data.Name != "Cup" || data.Format != tournament.FormatSingleElimination || data.BestOf != 1 ||
+				data.Status != tournament.StatusRegistration || data.CreatedBy != "org@mail.com" {
+				t.Fatalf("expected the defaults to be filled in, got %+v", data)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(tournament.FormatSingleElimination, tournament.SeedingRating, nil, nil)
+	id := f.repo.tournament.ID
+
+	if err := f.svc.Register(ctx, id, "a@mail.com"); err != tournament.ErrRegistrationClosed {
+		t.Fatalf("expected %v, got %v", tournament.ErrRegistrationClosed, err)
+	}
+
+	f.repo.tournament.RegistrationClosesAt = time.Now().Add(time.Hour)
+	f.repo.tournament.MaxEntrants = 2
+	for _, email := range []string{"a@mail.com", "b@mail.com"} {
+		if err := f.svc.Register(ctx, id, email); err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+	}
+	if err := f.svc.Register(ctx, id, "c@mail.com"); err != tournament.ErrTournamentFull {
+		t.Fatalf("expected %v, got %v", tournament.ErrTournamentFull, err)
+	}
+
+	if err := f.svc.Withdraw(ctx, id, "c@mail.com"); err != tournament.ErrNotRegistered {
+		t.Fatalf("expected %v, got %v", tournament.ErrNotRegistered, err)
+	}
+	if err := f.svc.Withdraw(ctx, id, "b@mail.com"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	if _, err := f.svc.Start(ctx, id, "org@mail.com"); err != tournament.ErrRegistrationOpen {
+		t.Fatalf("expected %v, got %v", tournament.ErrRegistrationOpen, err)
+	}
+	if err := f.svc.Register(ctx, "not-a-uuid", "a@mail.com"); err != tournament.ErrTournamentNotFound {
+		t.Fatalf("expected %v, got %v", tournament.ErrTournamentNotFound, err)
+	}
+}
+
+func TestSingleElimination(t *testing.T) {
+	ctx := context.Background()
+	ratings := map[string]float64{"a": 1900, "b": 1800, "c": 1700, "d": 1600}
+	f := newFixture(tournament.FormatSingleElimination, tournament.SeedingRating, ratings, nil)
+	f.enter("e", "d", "c", "b", "a")
+	id := f.repo.tournament.ID
+
+	if _, err := f.svc.Start(ctx, id, "a"); err != tournament.ErrNotOrganizer {
+		t.Fatalf("expected %v, got %v", tournament.ErrNotOrganizer, err)
+	}
+
+	bracket, err := f.svc.Start(ctx, id, "org@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	// an unrated player seeds at the starting rating
+	if bracket.Entrants[0].Email != "a" || bracket.Entrants[4].Email != "e" || bracket.Entrants[4].Seed != 5 {
+		t.Fatalf("expected the entrants seeded by rating, got %+v", bracket.Entrants)
+	}
+	// five players fill a bracket of eight, so seeds 1 to 3 get a bye: 4 plays 5 and
+	// the second semi final between 2 and 3 is ready from the start
+	if len(bracket.Matches) != 7 || f.live() != 2 {
+		t.Fatalf("expected 7 matches with 2 live, got %d with %d live", len(bracket.Matches), f.live())
+	}
+
+	f.play(t, "e", "d")
+	// the upset winner meets the top seed
+	if f.live() != 2 {
+		t.Fatalf("expected both semi finals live, got %d", f.live())
+	}
+	f.play(t, "a", "e")
+	f.play(t, "c", "b")
+	f.play(t, "c", "a")
+
+	standings, err := f.svc.Standings(ctx, id, "organizer@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if !standings.Final || f.repo.tournament.Status != tournament.StatusFinished {
+		t.Fatalf("expected the tournament to be finished")
+	}
+
+	places := []string{}
+	for _, s := range standings.Standings {
+		places = append(places, fmt.Sprintf("%d:%s", s.Place, s.Name))
+	}
+	if fmt.Sprint(places) != "[1:c 2:a 3:b 3:e 5:d]" {
+		t.Fatalf("unexpected standings %v", places)
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	pbs := map[string]int{"a": 120, "b": 100, "c": 80}
+	f := newFixture(tournament.FormatRoundRobin, tournament.SeedingPersonalBest, nil, pbs)
+	f.enter("c", "b", "a")
+	id := f.repo.tournament.ID
+
+	bracket, err := f.svc.Start(ctx, id, "org@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	// three players need three rounds of one match each
+	if len(bracket.Matches) != 3 || f.live() != 1 {
+		t.Fatalf("expected 3 matches with 1 live, got %d with %d live", len(bracket.Matches), f.live())
+	}
+
+	for _, m := range bracket.Matches {
+		live := f.repo.matches[0]
+		for _, stored := range f.repo.matches {
+			if stored.Status == tournament.MatchLive {
+				live = stored
+			}
+		}
+		if live.Round != m.Round {
+			t.Fatalf("expected round %d to be live, got round %d", m.Round, live.Round)
+		}
+		// the better seed always wins
+		winner, loser := live.PlayerA, live.PlayerB
+		if pbs[loser] > pbs[winner] {
+			winner, loser = loser, winner
+		}
+		f.play(t, winner, loser)
+	}
+
+	standings, err := f.svc.Standings(ctx, id, "organizer@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if !standings.Final || standings.Standings[0].Name != "a" || standings.Standings[0].Wins != 2 ||
+		standings.Standings[2].Name != "c" || standings.Standings[2].Losses != 2 {
+		t.Fatalf("unexpected standings %+v", standings.Standings)
+	}
+}
+
+func TestMatchNoShow(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(tournament.FormatSingleElimination, tournament.SeedingRating, map[string]float64{"a": 1600}, nil)
+	f.enter("b", "a")
+
+	if _, err := f.svc.Start(ctx, f.repo.tournament.ID, "org@mail.com"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	// nobody showed up, so the better seed goes through
+	m := f.repo.matches[0]
+	f.races.rooms[m.RoomID](&race.MatchResult{RoomID: m.RoomID})
+	if f.repo.matches[0].Winner != "a" || f.repo.tournament.Status != tournament.StatusFinished {
+		t.Fatalf("expected the top seed to win by default, got %+v", f.repo.matches[0])
+	}
+
+	// a late report of the same room changes nothing
+	f.races.rooms[m.RoomID](&race.MatchResult{RoomID: m.RoomID, Placings: []string{"b", "a"}})
+	if f.repo.matches[0].Winner != "a" {
+		t.Fatalf("expected the decided match to stay decided")
+	}
+}
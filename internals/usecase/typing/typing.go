@@ -2,85 +2,326 @@ package typing
 
 import (
 	"context"
+	"log"
 	"math/rand"
-	"strconv"
 	"strings"
 	"time"
 	"typing-speed/internals/adapter/external/sendmail"
 	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/achievement"
+	"typing-speed/internals/core/challenge"
+	"typing-speed/internals/core/feed"
+	"typing-speed/internals/core/goal"
+	"typing-speed/internals/core/progress"
+	"typing-speed/internals/core/ranking"
 	"typing-speed/internals/core/typing"
+	"typing-speed/internals/core/user"
 )
 
 type TypingServiceImpl struct {
-	userSvc port.UserRepository
-	mailSvc sendmail.MailSender
-	testSvc port.TypingRepository
+	userSvc        port.UserRepository
+	mailSvc        sendmail.MailSender
+	testSvc        port.TypingRepository
+	txSvc          port.Transactor
+	achievementSvc port.AchievementRepository
+	pbSvc          port.PersonalBestRepository
+	goalSvc        port.GoalRepository
+	curve          progress.LevelCurve
+	feedSvc        feed.FeedService
+	rankingSvc     ranking.RankingService
 }
 
-func NewTypingService(svc port.UserRepository, mail sendmail.MailSender, test port.TypingRepository) typing.TypingService {
+func NewTypingService(svc port.UserRepository, mail sendmail.MailSender, test port.TypingRepository, tx port.Transactor,
+	achievements port.AchievementRepository, pb port.PersonalBestRepository, goals port.GoalRepository,
+	curve progress.LevelCurve, feeds feed.FeedService, ranks ranking.RankingService) typing.TypingService {
 	return &TypingServiceImpl{
-		userSvc: svc,
-		mailSvc: mail,
-		testSvc: test,
+		userSvc:        svc,
+		mailSvc:        mail,
+		testSvc:        test,
+		txSvc:          tx,
+		achievementSvc: achievements,
+		pbSvc:          pb,
+		goalSvc:        goals,
+		curve:          curve,
+		feedSvc:        feeds,
+		rankingSvc:     ranks,
 	}
 }
 
-func (t *TypingServiceImpl) AddTestData(ctx context.Context, data *typing.TypingData, email string) error {
-
-	// insert data into db
+func (t *TypingServiceImpl) AddTestData(ctx context.Context, data *typing.TypingData, email string) (*typing.TestResult, error) {
 	data.Email = email
-	err := t.testSvc.InsertTestData(ctx, data)
+	if err := typing.NormalizeTestData(data); err != nil {
+		return nil, err
+	}
+	// only today's challenge can be raced; a test that missed it still counts,
+	// just not for the challenge
+	if data.ChallengeDate != "" && !challenge.Accepts(data.ChallengeDate, time.Now()) {
+		data.ChallengeDate = ""
+	}
+
+	result := &typing.TestResult{}
+
+	// the insert and the aggregate update commit together, and the user row
+	// stays locked in between so concurrent submissions cannot lose an update
+	err := t.txSvc.WithinTransaction(ctx, func(ctx context.Context) error {
+		userData, err := t.userSvc.GetUserByEmailForUpdate(ctx, email)
+		if err != nil || userData == nil {
+			return typing.ErrGettingDataFromDB
+		}
+
+		// insert data into db
+		err = t.testSvc.InsertTestData(ctx, data)
+		if err != nil {
+			return typing.ErrInsertingData
+		}
+		result.TestID = data.ID
+
+		currentAccuracy := typing.Accuracy(data)
+
+		pb, err := t.updatePersonalBest(ctx, data, currentAccuracy)
+		if err != nil {
+			return typing.ErrUpdatingPersonalBest
+		}
+		result.PersonalBest = pb
+
+		// update the total test of user to +1
+		updatedAccuracy := (userData.AvgAccuracy*userData.TotalTest + currentAccuracy) / (userData.TotalTest + 1)
+		bestSpeed := data.WPM
+		if userData.BestSpeed > (bestSpeed) {
+			bestSpeed = int(userData.BestSpeed)
+		}
+		updatedSpeed := (data.WPM + (userData.AvgSpeed * userData.TotalTest)) / (userData.TotalTest + 1)
+		currentPerformance := (data.WPM * currentAccuracy)
+
+		updatedPerformance := (userData.AvgPerformance*(userData.TotalTest) + currentPerformance) /
+			(userData.TotalTest + 1)
+
+		err = t.userSvc.UpdateUser(ctx, email, updatedSpeed, updatedAccuracy, updatedPerformance, bestSpeed)
+		if err != nil {
+			return typing.ErrUpdatingTotalTest
+		}
+
+		// streak and practice days are counted in the user's own time zone
+		loc := user.Location(userData.TimeZone)
+		streak := user.NextStreak(userData.Streak, userData.LastTestTime, time.Now(), loc)
+		longestStreak := max(userData.LongestStreak, streak)
+
+		err = t.userSvc.UpdateStreak(ctx, email, streak, longestStreak)
+		if err != nil {
+			return typing.ErrUpdatingStreak
+		}
+
+		completedGoals, err := t.evaluateGoals(ctx, email, goal.TestInput{
+			Mode:     data.Mode,
+			WPM:      data.WPM,
+			Accuracy: currentAccuracy,
+			Seconds:  data.TimeTakenByUser,
+		}, loc)
+		if err != nil {
+			return typing.ErrUpdatingGoals
+		}
+		result.CompletedGoals = completedGoals
+
+		gain := progress.CalculateXP(progress.XPInput{
+			Duration:     data.TotalTime,
+			Accuracy:     currentAccuracy,
+			Mode:         data.Mode,
+			PersonalBest: pb.NewPersonalBest && pb.PreviousBestWPM > 0,
+			Streak:       streak,
+		})
+		xp := userData.XP + gain.Total
+		level := t.curve.LevelForXP(xp)
+
+		err = t.userSvc.UpdateProgress(ctx, email, xp, level)
+		if err != nil {
+			return typing.ErrUpdatingProgress
+		}
+
+		result.XPGained = gain.Total
+		result.XP = xp
+		result.Level = level
+		result.LeveledUp = level > userData.Level
+
+		// evaluated last so the rules see this test, the new streak and the new level
+		unlocked, err := t.unlockAchievements(ctx, email)
+		if err != nil {
+			return typing.ErrUpdatingAchievements
+		}
+		result.Achievements = unlocked
+
+		return nil
+	})
 	if err != nil {
-		return typing.ErrInsertingData
+		return nil, err
 	}
 
-	// update the total test of user to +1
+	t.publish(ctx, email, result)
+	t.rank(ctx, email)
 
-	userData, err := t.userSvc.GetUserByEmail(ctx, email)
+	return result, nil
+}
+
+// rank moves the user to their new average in the ranking. Should this
+// fail, the next reconciliation puts them right.
+func (t *TypingServiceImpl) rank(ctx context.Context, email string) {
+	if t.rankingSvc == nil {
+		return
+	}
+	if err := t.rankingSvc.Update(ctx, email); err != nil {
+		log.Println("error ranking", email, ":", err)
+	}
+}
+
+// publish announces the milestones of the test to the user's followers.
+// The test is saved even when this fails.
+func (t *TypingServiceImpl) publish(ctx context.Context, email string, result *typing.TestResult) {
+	if t.feedSvc == nil {
+		return
+	}
+	events := feed.TestEvents(email, result)
+	if len(events) == 0 {
+		return
+	}
+	if err := t.feedSvc.Publish(ctx, events...); err != nil {
+		log.Println("error publishing feed events of", email, ":", err)
+	}
+}
+
+// updatePersonalBest replaces the personal best of the test's mode and language when the test beats it
+func (t *TypingServiceImpl) updatePersonalBest(ctx context.Context, data *typing.TypingData, accuracy int) (*typing.PersonalBestResult, error) {
+	current, err := t.pbSvc.GetPersonalBest(ctx, data.Email, data.Mode, data.Language)
 	if err != nil {
-		return typing.ErrGettingDataFromDB
+		return nil, err
+	}
+
+	result := &typing.PersonalBestResult{
+		Mode:     data.Mode,
+		Language: data.Language,
+		WPM:      data.WPM,
+	}
+	if current != nil {
+		result.PreviousBestWPM = current.WPM
+	}
 
+	if !current.Beaten(data.WPM, accuracy) {
+		return result, nil
 	}
-	var currentAccuracy int
-	if data.TotalWords == 0 {
-		currentAccuracy = 0
-	} else {
-		currentAccuracy = ((data.TypedWords - data.TotalErrors) * 100) / (data.TotalWords)
+
+	pb := &typing.PersonalBest{
+		Email:      data.Email,
+		Mode:       data.Mode,
+		Language:   data.Language,
+		WPM:        data.WPM,
+		Accuracy:   accuracy,
+		TestID:     data.ID,
+		AchievedAt: time.Now(),
 	}
-	updatedAccuracy := (userData.AvgAccuracy*userData.TotalTest + currentAccuracy) / (userData.TotalTest + 1)
-	bestSpeed := data.WPM
-	if userData.BestSpeed > (bestSpeed) {
-		bestSpeed = int(userData.BestSpeed)
+	if err := t.pbSvc.UpsertPersonalBest(ctx, pb, result.PreviousBestWPM); err != nil {
+		return nil, err
 	}
-	updatedSpeed := (data.WPM + (userData.AvgSpeed * userData.TotalTest)) / (userData.TotalTest + 1)
-	currentPerformance := (data.WPM * currentAccuracy)
 
-	updatedPerformance := (userData.AvgPerformance*(userData.TotalTest) + currentPerformance) /
-		(userData.TotalTest + 1)
+	result.NewPersonalBest = true
+	result.Improvement = data.WPM - result.PreviousBestWPM
 
-	err = t.userSvc.UpdateUser(ctx, email, updatedSpeed, updatedAccuracy, updatedPerformance, bestSpeed)
+	return result, nil
+}
+
+// evaluateGoals applies the test to the user's active goals and returns the ones it completed
+func (t *TypingServiceImpl) evaluateGoals(ctx context.Context, email string, in goal.TestInput, loc *time.Location) ([]*goal.Goal, error) {
+	goals, err := t.goalSvc.GetActiveGoals(ctx, email)
 	if err != nil {
-		return typing.ErrUpdatingTotalTest
+		return nil, err
+	}
+
+	now := time.Now()
+	completed := []*goal.Goal{}
+	for _, g := range goals {
+		if !g.Evaluate(in, now, loc) {
+			continue
+		}
+		if err := t.goalSvc.UpdateGoalProgress(ctx, g); err != nil {
+			return nil, err
+		}
+		if g.CompletedAt != nil {
+			completed = append(completed, g)
+		}
 	}
 
-	return nil
+	return completed, nil
 }
 
-func (t *TypingServiceImpl) RecentTestForProfile(ctx context.Context, email string, month string) ([]*typing.TypingData, error) {
-	var m int
-	var err error
-	if month != "" {
-		m, err = strconv.Atoi(month)
-		if err != nil {
-			return nil, typing.ErrSomethingWentWrong
-		}
+// unlockAchievements stores and returns the achievements the user has just earned
+func (t *TypingServiceImpl) unlockAchievements(ctx context.Context, email string) ([]*achievement.Achievement, error) {
+	stats, err := t.achievementSvc.GetAchievementStats(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked, err := t.achievementSvc.GetUnlockedAchievements(ctx, email)
+	if err != nil {
+		return nil, err
 	}
-	m = -1
-	data, err := t.testSvc.GetRecentTestData(ctx, email, m)
+
+	earned := make(map[string]bool, len(unlocked))
+	for _, u := range unlocked {
+		earned[u.Code] = true
+	}
+
+	achievements := []*achievement.Achievement{}
+	defs := achievement.Evaluate(achievement.Catalog, stats, earned)
+	if len(defs) == 0 {
+		return achievements, nil
+	}
+
+	now := time.Now()
+	codes := make([]string, 0, len(defs))
+	for _, def := range defs {
+		codes = append(codes, def.Code)
+		achievements = append(achievements, achievement.NewAchievement(def, stats, &now))
+	}
+
+	if err := t.achievementSvc.InsertUnlockedAchievements(ctx, email, codes, now); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+// History returns a page of the user's tests. The cursor is the NextCursor
+// of the page before.
+func (t *TypingServiceImpl) History(ctx context.Context, email string, filter *typing.HistoryFilter) (*typing.HistoryPage, error) {
+	userData, err := t.userSvc.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, typing.ErrGettingDataFromDB
 	}
-	return data, nil
+	loc := time.UTC
+	if userData != nil {
+		loc = user.Location(userData.TimeZone)
+	}
+
+	q, err := typing.NewHistoryQuery(email, filter, loc)
+	if err != nil {
+		return nil, err
+	}
+	limit := q.Limit
+
+	// one extra test tells whether there is another page
+	q.Limit++
+	data, err := t.testSvc.GetHistory(ctx, q)
+	if err != nil {
+		return nil, typing.ErrGettingDataFromDB
+	}
+
+	page := &typing.HistoryPage{}
+	if len(data) > limit {
+		data = data[:limit]
+		page.NextCursor = typing.EncodeHistoryCursor(data[limit-1])
+	}
+	page.Tests = make([]*typing.HistoryTest, 0, len(data))
+	for _, d := range data {
+		page.Tests = append(page.Tests, &typing.HistoryTest{TypingData: d, Accuracy: typing.Accuracy(d)})
+	}
+	return page, nil
 }
 
 func (t *TypingServiceImpl) SendTypingSentence(ctx context.Context) string {
@@ -97,3 +338,25 @@ func (t *TypingServiceImpl) SendTypingSentence(ctx context.Context) string {
 	return str.String()
 
 }
+
+// PersonalBests lists the user's personal best for every mode and language played
+func (t *TypingServiceImpl) PersonalBests(ctx context.Context, email string) ([]*typing.PersonalBest, error) {
+	data, err := t.pbSvc.GetPersonalBests(ctx, email)
+	if err != nil {
+		return nil, typing.ErrGettingDataFromDB
+	}
+	return data, nil
+}
+
+// PersonalBestHistory lists every time the user broke a personal best, optionally for one mode and language
+func (t *TypingServiceImpl) PersonalBestHistory(ctx context.Context, email string, mode string, language string) ([]*typing.PersonalBestRecord, error) {
+	if mode != "" && !typing.ValidMode(mode) {
+		return nil, typing.ErrInvalidMode
+	}
+
+	data, err := t.pbSvc.GetPersonalBestHistory(ctx, email, mode, strings.ToLower(language))
+	if err != nil {
+		return nil, typing.ErrGettingDataFromDB
+	}
+	return data, nil
+}
@@ -3,27 +3,37 @@ package typing
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
+	"typing-speed/internals/core/achievement"
+	"typing-speed/internals/core/challenge"
+	"typing-speed/internals/core/feed"
+	"typing-speed/internals/core/goal"
+	"typing-speed/internals/core/progress"
 	"typing-speed/internals/core/typing"
 	"typing-speed/internals/core/user"
 )
 
 type FakeTypingRepo struct {
-	InsertFn    func(ctx context.Context, data *typing.TypingData) error
-	GetRecentFn func(ctx context.Context, email string, month int) ([]*typing.TypingData, error)
+	InsertFn     func(ctx context.Context, data *typing.TypingData) error
+	GetHistoryFn func(ctx context.Context, q *typing.HistoryQuery) ([]*typing.TypingData, error)
 }
 type FakeUserRepo struct {
 	GetByEmailFn          func(ctx context.Context, email string) (*user.User, error)
 	CreateFn              func(ctx context.Context, u *user.User) error
 	GetTopPerformerFn     func(ctx context.Context) ([]*user.TopPerformer, error)
-	GetAllUserFn          func(ctx context.Context) ([]*user.User, error)
+	GetAllUserFn          func(ctx context.Context) ([]*user.PublicUser, error)
 	GetDashboardTopDataFn func(ctx context.Context) (*user.DashboardTopData, error)
+	UpdateTimeZoneFn      func(ctx context.Context, email string, timeZone string) error
 	UpdateUserFn          func(ctx context.Context, email string, speed, acc, perf, best int) error
+	UpdateStreakFn        func(ctx context.Context, email string, streak, longest int) error
+	UpdateProgressFn      func(ctx context.Context, email string, xp, level int) error
 }
 
 // GetAllUser implements port.UserRepository.
-func (f *FakeUserRepo) GetAllUser(ctx context.Context) ([]*user.User, error) {
+func (f *FakeUserRepo) GetAllUser(ctx context.Context) ([]*user.PublicUser, error) {
 	if f.GetAllUserFn != nil {
 		return f.GetAllUserFn(ctx)
 	}
@@ -61,6 +71,54 @@ func (f *FakeUserRepo) GetUserByEmail(ctx context.Context, email string) (*user.
 	return nil, nil
 }
 
+// UpdateStreak implements port.UserRepository.
+func (f *FakeUserRepo) UpdateStreak(ctx context.Context, email string, streak int, longestStreak int) error {
+	if f.UpdateStreakFn != nil {
+		return f.UpdateStreakFn(ctx, email, streak, longestStreak)
+	}
+	return nil
+}
+
+// UpdateTimeZone implements port.UserRepository.
+func (f *FakeUserRepo) UpdateTimeZone(ctx context.Context, email string, timeZone string) error {
+	if f.UpdateTimeZoneFn != nil {
+		return f.UpdateTimeZoneFn(ctx, email, timeZone)
+	}
+	return nil
+}
+
+// UpdateProgress implements port.UserRepository.
+func (f *FakeUserRepo) UpdateProgress(ctx context.Context, email string, xp int, level int) error {
+	if f.UpdateProgressFn != nil {
+		return f.UpdateProgressFn(ctx, email, xp, level)
+	}
+	return nil
+}
+
+// GetUserByUsername implements port.UserRepository.
+func (f *FakeUserRepo) GetUserByUsername(ctx context.Context, username string) (*user.User, error) {
+	return nil, nil
+}
+
+// UpdateUsername implements port.UserRepository.
+func (f *FakeUserRepo) UpdateUsername(ctx context.Context, email string, username string) (bool, error) {
+	return true, nil
+}
+
+// UpdateCountry implements port.UserRepository.
+func (f *FakeUserRepo) UpdateCountry(ctx context.Context, email string, country string) error {
+	return nil
+}
+
+// UpdatePrivacy implements port.UserRepository.
+func (f *FakeUserRepo) UpdatePrivacy(ctx context.Context, email string, privacy string) error {
+	return nil
+}
+
+func (f *FakeUserRepo) GetUserByEmailForUpdate(ctx context.Context, email string) (*user.User, error) {
+	return f.GetUserByEmail(ctx, email)
+}
+
 func (f *FakeUserRepo) CreateUser(ctx context.Context, u *user.User) error {
 	if f.CreateFn != nil {
 		return f.CreateFn(ctx, u)
@@ -75,13 +133,104 @@ func (f *FakeTypingRepo) InsertTestData(ctx context.Context, data *typing.Typing
 	return nil
 }
 
-func (f *FakeTypingRepo) GetRecentTestData(ctx context.Context, email string, month int) ([]*typing.TypingData, error) {
-	if f.GetRecentFn != nil {
-		return f.GetRecentFn(ctx, email, month)
+func (f *FakeTypingRepo) GetLatestTests(ctx context.Context, email string, limit int) ([]*typing.TypingData, error) {
+	return nil, nil
+}
+
+func (f *FakeTypingRepo) GetHistory(ctx context.Context, q *typing.HistoryQuery) ([]*typing.TypingData, error) {
+	if f.GetHistoryFn != nil {
+		return f.GetHistoryFn(ctx, q)
 	}
 	return nil, nil
 }
 
+type FakeAchievementRepo struct {
+	Stats    *achievement.Stats
+	Unlocked []*achievement.Unlocked
+	Inserted []string
+}
+
+func (f *FakeAchievementRepo) GetAchievementStats(ctx context.Context, email string) (*achievement.Stats, error) {
+	if f.Stats != nil {
+		return f.Stats, nil
+	}
+	return &achievement.Stats{}, nil
+}
+
+func (f *FakeAchievementRepo) GetUnlockedAchievements(ctx context.Context, email string) ([]*achievement.Unlocked, error) {
+	return f.Unlocked, nil
+}
+
+func (f *FakeAchievementRepo) InsertUnlockedAchievements(ctx context.Context, email string, codes []string, unlockedAt time.Time) error {
+	f.Inserted = append(f.Inserted, codes...)
+	return nil
+}
+
+type FakePersonalBestRepo struct {
+	Current  *typing.PersonalBest
+	Upserted *typing.PersonalBest
+	Previous int
+}
+
+func (f *FakePersonalBestRepo) GetPersonalBest(ctx context.Context, email string, mode string, language string) (*typing.PersonalBest, error) {
+	return f.Current, nil
+}
+
+func (f *FakePersonalBestRepo) GetPersonalBests(ctx context.Context, email string) ([]*typing.PersonalBest, error) {
+	return nil, nil
+}
+
+func (f *FakePersonalBestRepo) UpsertPersonalBest(ctx context.Context, pb *typing.PersonalBest, previousWPM int) error {
+	f.Upserted, f.Previous = pb, previousWPM
+	return nil
+}
+
+func (f *FakePersonalBestRepo) GetPersonalBestHistory(ctx context.Context, email string, mode string, language string) ([]*typing.PersonalBestRecord, error) {
+	return nil, nil
+}
+
+type FakeGoalRepo struct {
+	Active  []*goal.Goal
+	Updated []*goal.Goal
+}
+
+func (f *FakeGoalRepo) CreateGoal(ctx context.Context, g *goal.Goal) error { return nil }
+
+func (f *FakeGoalRepo) GetGoals(ctx context.Context, email string) ([]*goal.Goal, error) {
+	return f.Active, nil
+}
+
+func (f *FakeGoalRepo) GetActiveGoals(ctx context.Context, email string) ([]*goal.Goal, error) {
+	return f.Active, nil
+}
+
+func (f *FakeGoalRepo) GetGoal(ctx context.Context, email string, id int64) (*goal.Goal, error) {
+	return nil, nil
+}
+
+func (f *FakeGoalRepo) UpdateGoal(ctx context.Context, g *goal.Goal) error { return nil }
+
+func (f *FakeGoalRepo) UpdateGoalProgress(ctx context.Context, g *goal.Goal) error {
+	f.Updated = append(f.Updated, g)
+	return nil
+}
+
+func (f *FakeGoalRepo) DeleteGoal(ctx context.Context, email string, id int64) (bool, error) {
+	return false, nil
+}
+
+// FakeTransactor runs fn directly and records the error it returned
+type FakeTransactor struct {
+	Calls   int
+	LastErr error
+}
+
+func (f *FakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.Calls++
+	f.LastErr = fn(ctx)
+	return f.LastErr
+}
+
 func TestAddTestData(t *testing.T) {
 	//ctx := context.Background()
 
@@ -101,6 +250,11 @@ func TestAddTestData(t *testing.T) {
 					return errors.New("db error")
 				},
 			},
+			userRepo: &FakeUserRepo{
+				GetByEmailFn: func(ctx context.Context, email string) (*user.User, error) {
+					return &user.User{TotalTest: 1}, nil
+				},
+			},
 			expectErr:     true,
 			expectedError: typing.ErrInsertingData,
 		},
@@ -120,6 +274,19 @@ func TestAddTestData(t *testing.T) {
 			expectErr:     true,
 			expectedError: typing.ErrGettingDataFromDB,
 		},
+		{
+			name: "user missing",
+			data: &typing.TypingData{TotalWords: 10, TypedWords: 8},
+			testRepo: &FakeTypingRepo{
+				InsertFn: func(ctx context.Context, data *typing.TypingData) error {
+					t.Fatalf("insert must not run for a missing user")
+					return nil
+				},
+			},
+			userRepo:      &FakeUserRepo{},
+			expectErr:     true,
+			expectedError: typing.ErrGettingDataFromDB,
+		},
 		{
 			name: "update failure",
 			data: &typing.TypingData{
@@ -145,7 +312,7 @@ func TestAddTestData(t *testing.T) {
 					return errors.New("db error")
 				},
 			},
-			expectErr: true,
+			expectErr:     true,
 			expectedError: typing.ErrUpdatingTotalTest,
 		},
 		{
@@ -179,12 +346,24 @@ func TestAddTestData(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			tx := &FakeTransactor{}
 			service := &TypingServiceImpl{
-				userSvc: tt.userRepo,
-				testSvc: tt.testRepo,
+				userSvc:        tt.userRepo,
+				testSvc:        tt.testRepo,
+				txSvc:          tx,
+				achievementSvc: &FakeAchievementRepo{},
+				pbSvc:          &FakePersonalBestRepo{},
+				goalSvc:        &FakeGoalRepo{},
 			}
 
-			err := service.AddTestData(context.Background(), tt.data, "test@mail.com")
+			_, err := service.AddTestData(context.Background(), tt.data, "test@mail.com")
+
+			if tx.Calls != 1 {
+				t.Fatalf("expected one transaction, got %d", tx.Calls)
+			}
+			if tx.LastErr != err {
+				t.Fatalf("expected transaction to see %v, got %v", err, tx.LastErr)
+			}
 
 			if tt.expectErr {
 				if err == nil {
@@ -202,70 +381,117 @@ func TestAddTestData(t *testing.T) {
 	}
 }
 
-func TestRecentTestForProfile(t *testing.T) {
-	//ctx := context.Background()
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+	// five tests, latest first, each id a valid uuid
+	tests := []*typing.TypingData{}
+	for i := 0; i < 5; i++ {
+		tests = append(tests, &typing.TypingData{
+			ID:         fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i),
+			WPM:        60 + i,
+			TypedWords: 50,
+			TotalWords: 50,
+			CreatedAt:  base.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+
+	var queries []*typing.HistoryQuery
+	repo := &FakeTypingRepo{
+		GetHistoryFn: func(ctx context.Context, q *typing.HistoryQuery) ([]*typing.TypingData, error) {
+			queries = append(queries, q)
+			page := []*typing.TypingData{}
+			past := q.Cursor == nil
+			for _, d := range tests {
+				if past && len(page) < q.Limit {
+					page = append(page, d)
+				}
+				if q.Cursor != nil && d.ID == q.Cursor.ID {
+					past = true
+				}
+			}
+			return page, nil
+		},
+	}
+	users := &FakeUserRepo{
+		GetByEmailFn: func(ctx context.Context, email string) (*user.User, error) {
+			return &user.User{Email: email, TimeZone: "Asia/Kolkata"}, nil
+		},
+	}
+	service := &TypingServiceImpl{testSvc: repo, userSvc: users}
+
+	filter := &typing.HistoryFilter{Limit: "2", To: "2026-10-19"}
+	seen := 0
+	for pages := 0; ; pages++ {
+		page, err := service.History(ctx, "test@example.com", filter)
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		for _, d := range page.Tests {
+			if d.ID != tests[seen].ID || d.Accuracy != 100 {
+				t.Fatalf("expected %v, got %v", tests[seen].ID, d.ID)
+			}
+			seen++
+		}
+		if page.NextCursor == "" {
+			if pages != 2 {
+				t.Fatalf("expected %v, got %v", 2, pages)
+			}
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	if seen != len(tests) {
+		t.Fatalf("expected %v, got %v", len(tests), seen)
+	}
+
+	// the day ends at midnight in the user's time zone
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	if until := queries[0].Until; until == nil || !until.Equal(time.Date(2026, 10, 20, 0, 0, 0, 0, kolkata)) {
+		t.Fatalf("expected %v, got %v", time.Date(2026, 10, 20, 0, 0, 0, 0, kolkata), until)
+	}
+}
+
+func TestHistoryFilter(t *testing.T) {
+	ctx := context.Background()
 
 	tests := []struct {
 		name          string
-		month         string
+		filter        *typing.HistoryFilter
 		repo          *FakeTypingRepo
-		expectErr     bool
 		expectedError error
 	}{
+		{name: "mode", filter: &typing.HistoryFilter{Mode: "marathon"}, expectedError: typing.ErrInvalidMode},
+		{name: "sort", filter: &typing.HistoryFilter{Sort: "accuracy"}, expectedError: typing.ErrInvalidSort},
+		{name: "date", filter: &typing.HistoryFilter{From: "19/10/2026"}, expectedError: typing.ErrInvalidDate},
+		{name: "range", filter: &typing.HistoryFilter{From: "2026-10-19", To: "2026-10-18"}, expectedError: typing.ErrInvalidRange},
+		{name: "accuracy", filter: &typing.HistoryFilter{MinAccuracy: "101"}, expectedError: typing.ErrInvalidAccuracy},
+		{name: "limit", filter: &typing.HistoryFilter{Limit: "101"}, expectedError: typing.ErrInvalidLimit},
+		{name: "cursor", filter: &typing.HistoryFilter{Cursor: "not-a-cursor"}, expectedError: typing.ErrInvalidCursor},
 		{
-			name:          "invalid month format",
-			month:         "abc",
-			repo:          &FakeTypingRepo{},
-			expectErr:     true,
-			expectedError: typing.ErrSomethingWentWrong,
-		},
-		{
-			name:  "db error",
-			month: "2",
+			name:   "db error",
+			filter: &typing.HistoryFilter{Sort: "wpm", From: "2026-10-19", To: "2026-10-19", MinAccuracy: "90"},
 			repo: &FakeTypingRepo{
-				GetRecentFn: func(ctx context.Context, email string, month int) ([]*typing.TypingData, error) {
+				GetHistoryFn: func(ctx context.Context, q *typing.HistoryQuery) ([]*typing.TypingData, error) {
 					return nil, errors.New("db error")
 				},
 			},
-			expectErr:     true,
 			expectedError: typing.ErrGettingDataFromDB,
 		},
-		{
-			name:  "success",
-			month: "1",
-			repo: &FakeTypingRepo{
-				GetRecentFn: func(ctx context.Context, email string, month int) ([]*typing.TypingData, error) {
-					return []*typing.TypingData{
-						{TypedWords: 100, TotalWords: 120},
-					}, nil
-				},
-			},
-			expectErr: false,
-		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := &TypingServiceImpl{
-				testSvc: tt.repo,
+			repo := tt.repo
+			if repo == nil {
+				repo = &FakeTypingRepo{}
 			}
+			service := &TypingServiceImpl{testSvc: repo, userSvc: &FakeUserRepo{}}
 
-			data, err := service.RecentTestForProfile(context.Background(), "test@example.com", tt.month)
-
-			if tt.expectErr {
-				if err == nil {
-					t.Fatalf("expected error, got nil")
-				}
-				if tt.expectedError != nil && err != tt.expectedError {
-					t.Fatalf("expected %v, got %v", tt.expectedError, err.Error())
-				}
-			} else {
-				if err != nil {
-					t.Fatalf("expected success, got error")
-				}
-				if data == nil {
-					t.Fatalf("expected data, got nil")
-				}
+			_, err := service.History(ctx, "test@example.com", tt.filter)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
 			}
 		})
 	}
@@ -289,3 +515,415 @@ func TestSendTypingSentence(t *testing.T) {
 		}
 	}
 }
+
+func TestAddTestDataStreak(t *testing.T) {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	threeDaysAgo := now.Add(-72 * time.Hour)
+
+	tests := []struct {
+		name            string
+		stored          *user.User
+		expectedStreak  int
+		expectedLongest int
+	}{
+		{
+			name:            "first test starts a streak",
+			stored:          &user.User{},
+			expectedStreak:  1,
+			expectedLongest: 1,
+		},
+		{
+			name:            "test on the next day extends the streak",
+			stored:          &user.User{Streak: 3, LongestStreak: 3, LastTestTime: &yesterday},
+			expectedStreak:  4,
+			expectedLongest: 4,
+		},
+		{
+			name:            "test on the same day keeps the streak",
+			stored:          &user.User{Streak: 3, LongestStreak: 5, LastTestTime: &now},
+			expectedStreak:  3,
+			expectedLongest: 5,
+		},
+		{
+			name:            "missed day resets the streak",
+			stored:          &user.User{Streak: 6, LongestStreak: 6, LastTestTime: &threeDaysAgo},
+			expectedStreak:  1,
+			expectedLongest: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotStreak, gotLongest int
+			userRepo := &FakeUserRepo{
+				GetByEmailFn: func(ctx context.Context, email string) (*user.User, error) {
+					return tt.stored, nil
+				},
+				UpdateStreakFn: func(ctx context.Context, email string, streak, longest int) error {
+					gotStreak, gotLongest = streak, longest
+					return nil
+				},
+			}
+			service := &TypingServiceImpl{
+				userSvc:        userRepo,
+				testSvc:        &FakeTypingRepo{},
+				txSvc:          &FakeTransactor{},
+				achievementSvc: &FakeAchievementRepo{},
+				pbSvc:          &FakePersonalBestRepo{},
+				goalSvc:        &FakeGoalRepo{},
+			}
+
+			_, err := service.AddTestData(context.Background(), &typing.TypingData{WPM: 50, TotalWords: 10, TypedWords: 10}, "test@mail.com")
+			if err != nil {
+				t.Fatalf("expected success, got %v", err)
+			}
+			if gotStreak != tt.expectedStreak || gotLongest != tt.expectedLongest {
+				t.Fatalf("expected streak %d/%d, got %d/%d", tt.expectedStreak, tt.expectedLongest, gotStreak, gotLongest)
+			}
+		})
+	}
+}
+
+func TestAddTestDataProgress(t *testing.T) {
+	curve := progress.NewLevelCurve(100, 1.5)
+
+	tests := []struct {
+		name          string
+		data          *typing.TypingData
+		stored        *user.User
+		expectedError error
+		expectedLevel int
+		leveledUp     bool
+	}{
+		{
+			name:          "invalid mode",
+			data:          &typing.TypingData{Mode: "marathon"},
+			stored:        &user.User{},
+			expectedError: typing.ErrInvalidMode,
+		},
+		{
+			name:          "timeline going backwards",
+			data:          &typing.TypingData{TotalTime: 15, Timeline: []int{0, 5, 4}},
+			stored:        &user.User{},
+			expectedError: typing.ErrInvalidTimeline,
+		},
+		{
+			name:          "mode does not match its duration",
+			data:          &typing.TypingData{Mode: typing.Mode120s, TotalTime: 15, WPM: 40, TotalWords: 10, TypedWords: 10},
+			stored:        &user.User{},
+			expectedError: typing.ErrModeDurationMismatch,
+		},
+		{
+			name:          "timeline longer than the test",
+			data:          &typing.TypingData{TotalTime: 2, TimeTakenByUser: 2, Timeline: []int{0, 5, 9, 12}},
+			stored:        &user.User{},
+			expectedError: typing.ErrInvalidTimeline,
+		},
+		{
+			name:          "xp without level up",
+			data:          &typing.TypingData{TotalTime: 60, WPM: 40, TotalWords: 10, TypedWords: 10},
+			stored:        &user.User{BestSpeed: 80},
+			expectedLevel: 0,
+		},
+		{
+			name:          "xp crosses a level",
+			data:          &typing.TypingData{TotalTime: 60, WPM: 40, TotalWords: 10, TypedWords: 10},
+			stored:        &user.User{BestSpeed: 80, XP: 90},
+			expectedLevel: 1,
+			leveledUp:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var storedXP, storedLevel int
+			userRepo := &FakeUserRepo{
+				GetByEmailFn: func(ctx context.Context, email string) (*user.User, error) {
+					return tt.stored, nil
+				},
+				UpdateProgressFn: func(ctx context.Context, email string, xp, level int) error {
+					storedXP, storedLevel = xp, level
+					return nil
+				},
+			}
+			service := &TypingServiceImpl{
+				userSvc:        userRepo,
+				testSvc:        &FakeTypingRepo{},
+				txSvc:          &FakeTransactor{},
+				achievementSvc: &FakeAchievementRepo{},
+				pbSvc:          &FakePersonalBestRepo{},
+				goalSvc:        &FakeGoalRepo{},
+				curve:          curve,
+			}
+
+			result, err := service.AddTestData(context.Background(), tt.data, "test@mail.com")
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectedError != nil {
+				return
+			}
+
+			if tt.data.Mode != typing.Mode60s {
+				t.Fatalf("expected mode %s, got %s", typing.Mode60s, tt.data.Mode)
+			}
+			if result.XPGained <= 0 || result.XP != tt.stored.XP+result.XPGained {
+				t.Fatalf("unexpected xp result %+v", result)
+			}
+			if storedXP != result.XP || storedLevel != result.Level {
+				t.Fatalf("expected stored xp %d level %d, got %d %d", result.XP, result.Level, storedXP, storedLevel)
+			}
+			if result.Level != tt.expectedLevel || result.LeveledUp != tt.leveledUp {
+				t.Fatalf("expected level %d (up %v), got %d (up %v)", tt.expectedLevel, tt.leveledUp, result.Level, result.LeveledUp)
+			}
+		})
+	}
+}
+
+func TestAddTestDataChallengeDate(t *testing.T) {
+	today := challenge.DateKey(time.Now())
+
+	tests := []struct {
+		name     string
+		date     string
+		expected string
+	}{
+		{name: "today", date: today, expected: today},
+		// the test still counts, just not for the challenge
+		{name: "stale", date: "2001-01-01", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &TypingServiceImpl{
+				userSvc: &FakeUserRepo{
+					GetByEmailFn: func(ctx context.Context, email string) (*user.User, error) {
+						return &user.User{}, nil
+					},
+				},
+				testSvc:        &FakeTypingRepo{},
+				txSvc:          &FakeTransactor{},
+				achievementSvc: &FakeAchievementRepo{},
+				pbSvc:          &FakePersonalBestRepo{},
+				goalSvc:        &FakeGoalRepo{},
+			}
+
+			data := &typing.TypingData{TotalTime: 60, WPM: 40, TotalWords: 10, TypedWords: 10, ChallengeDate: tt.date}
+			if _, err := service.AddTestData(context.Background(), data, "test@mail.com"); err != nil {
+				t.Fatalf("expected success, got %v", err)
+			}
+			if data.ChallengeDate != tt.expected {
+				t.Fatalf("expected challenge date %q, got %q", tt.expected, data.ChallengeDate)
+			}
+		})
+	}
+}
+
+func TestAddTestDataOversized(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         *typing.TypingData
+		expectedXP   int
+		expectedTime int
+	}{
+		// a custom test earns at most the XP of MaxCustomDuration
+		{
+			name:         "custom test",
+			data:         &typing.TypingData{Mode: typing.ModeCustom, TotalTime: 1000000000, TimeTakenByUser: 1000000000, WPM: 40, TotalWords: 10, TypedWords: 10},
+			expectedXP:   300,
+			expectedTime: 1000000000,
+		},
+		{
+			name:         "time taken past the end of a timed test",
+			data:         &typing.TypingData{TotalTime: 60, TimeTakenByUser: 1000000000, WPM: 40, TotalWords: 10, TypedWords: 10},
+			expectedXP:   33,
+			expectedTime: 60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &TypingServiceImpl{
+				userSvc: &FakeUserRepo{
+					GetByEmailFn: func(ctx context.Context, email string) (*user.User, error) {
+						return &user.User{}, nil
+					},
+				},
+				testSvc:        &FakeTypingRepo{},
+				txSvc:          &FakeTransactor{},
+				achievementSvc: &FakeAchievementRepo{},
+				pbSvc:          &FakePersonalBestRepo{},
+				goalSvc:        &FakeGoalRepo{},
+				curve:          progress.NewLevelCurve(100, 1.5),
+			}
+
+			result, err := service.AddTestData(context.Background(), tt.data, "test@mail.com")
+			if err != nil {
+				t.Fatalf("expected success, got %v", err)
+			}
+			if result.XPGained != tt.expectedXP {
+				t.Fatalf("expected %v, got %v", tt.expectedXP, result.XPGained)
+			}
+			if tt.data.TimeTakenByUser != tt.expectedTime {
+				t.Fatalf("expected %v, got %v", tt.expectedTime, tt.data.TimeTakenByUser)
+			}
+		})
+	}
+}
+
+// FakeFeedService collects what is published
+type FakeFeedService struct {
+	feed.FeedService
+	Published []*feed.Event
+}
+
+func (f *FakeFeedService) Publish(ctx context.Context, events ...*feed.Event) error {
+	f.Published = append(f.Published, events...)
+	return nil
+}
+
+func TestAddTestDataAchievements(t *testing.T) {
+	feeds := &FakeFeedService{}
+	achievements := &FakeAchievementRepo{
+		Stats:    &achievement.Stats{TotalTests: 1, BestWPM: 104, LongestStreak: 1},
+		Unlocked: []*achievement.Unlocked{{Code: "first_test", UnlockedAt: time.Now()}},
+	}
+	service := &TypingServiceImpl{
+		userSvc: &FakeUserRepo{
+			GetByEmailFn: func(ctx context.Context, email string) (*user.User, error) {
+				return &user.User{}, nil
+			},
+		},
+		testSvc:        &FakeTypingRepo{},
+		txSvc:          &FakeTransactor{},
+		achievementSvc: achievements,
+		pbSvc:          &FakePersonalBestRepo{},
+		goalSvc:        &FakeGoalRepo{},
+		feedSvc:        feeds,
+	}
+
+	result, err := service.AddTestData(context.Background(), &typing.TypingData{TotalTime: 60, WPM: 104, TotalWords: 10, TypedWords: 10}, "test@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	expected := []string{"wpm_50", "wpm_100"}
+	if strings.Join(achievements.Inserted, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected unlocks %v, got %v", expected, achievements.Inserted)
+	}
+	if len(result.Achievements) != 2 || !result.Achievements[1].Earned || result.Achievements[1].UnlockedAt == nil {
+		t.Fatalf("unexpected achievements in result %+v", result.Achievements)
+	}
+
+	// a first result in a mode is not announced as a personal best
+	if len(feeds.Published) != 2 || feeds.Published[0].Kind != feed.KindAchievement ||
+		feeds.Published[1].Achievement != result.Achievements[1].Name {
+		t.Fatalf("expected the unlocks in the feed, got %+v", feeds.Published)
+	}
+}
+
+func TestAddTestDataPersonalBest(t *testing.T) {
+	tests := []struct {
+		name            string
+		current         *typing.PersonalBest
+		wpm             int
+		expectNew       bool
+		expectedPrev    int
+		expectedImprove int
+	}{
+		{name: "first test in the mode", current: nil, wpm: 60, expectNew: true, expectedPrev: 0, expectedImprove: 60},
+		{name: "faster than the pb", current: &typing.PersonalBest{WPM: 70, Accuracy: 95}, wpm: 78, expectNew: true, expectedPrev: 70, expectedImprove: 8},
+		{name: "slower than the pb", current: &typing.PersonalBest{WPM: 90, Accuracy: 95}, wpm: 78, expectNew: false, expectedPrev: 90, expectedImprove: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pbRepo := &FakePersonalBestRepo{Current: tt.current}
+			service := &TypingServiceImpl{
+				userSvc: &FakeUserRepo{
+					GetByEmailFn: func(ctx context.Context, email string) (*user.User, error) {
+						return &user.User{}, nil
+					},
+				},
+				testSvc: &FakeTypingRepo{
+					InsertFn: func(ctx context.Context, data *typing.TypingData) error {
+						data.ID = "test-id"
+						return nil
+					},
+				},
+				txSvc:          &FakeTransactor{},
+				achievementSvc: &FakeAchievementRepo{},
+				pbSvc:          pbRepo,
+				goalSvc:        &FakeGoalRepo{},
+			}
+
+			data := &typing.TypingData{TotalTime: 30, WPM: tt.wpm, TotalWords: 10, TypedWords: 10, Language: "English"}
+			result, err := service.AddTestData(context.Background(), data, "test@mail.com")
+			if err != nil {
+				t.Fatalf("expected success, got %v", err)
+			}
+
+			pb := result.PersonalBest
+			if pb.NewPersonalBest != tt.expectNew || pb.PreviousBestWPM != tt.expectedPrev || pb.Improvement != tt.expectedImprove {
+				t.Fatalf("unexpected personal best result %+v", pb)
+			}
+			if pb.Mode != typing.Mode30s || pb.Language != "english" {
+				t.Fatalf("expected 30s/english, got %s/%s", pb.Mode, pb.Language)
+			}
+			if tt.expectNew && (pbRepo.Upserted == nil || pbRepo.Upserted.TestID != "test-id" || pbRepo.Previous != tt.expectedPrev) {
+				t.Fatalf("expected personal best to be stored with test id, got %+v", pbRepo.Upserted)
+			}
+			if !tt.expectNew && pbRepo.Upserted != nil {
+				t.Fatalf("expected personal best to be kept")
+			}
+		})
+	}
+}
+
+func TestAddTestDataGoals(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	goals := &FakeGoalRepo{
+		Active: []*goal.Goal{
+			{ID: 1, Type: goal.TypeSpeed, Target: 80, Mode: typing.Mode60s, Progress: 70, Baseline: 60},
+			{ID: 2, Type: goal.TypeSpeed, Target: 80, Mode: typing.Mode15s},
+			{ID: 3, Type: goal.TypeTestCount, Target: 10, Progress: 3},
+			{ID: 4, Type: goal.TypeDailyPractice, Target: 1},
+			{ID: 5, Type: goal.TypeTestCount, Target: 10, Progress: 3, Deadline: &expired},
+		},
+	}
+	service := &TypingServiceImpl{
+		userSvc: &FakeUserRepo{
+			GetByEmailFn: func(ctx context.Context, email string) (*user.User, error) {
+				return &user.User{TimeZone: "UTC"}, nil
+			},
+		},
+		testSvc:        &FakeTypingRepo{},
+		txSvc:          &FakeTransactor{},
+		achievementSvc: &FakeAchievementRepo{},
+		pbSvc:          &FakePersonalBestRepo{},
+		goalSvc:        goals,
+	}
+
+	data := &typing.TypingData{TotalTime: 60, TimeTakenByUser: 60, WPM: 85, TotalWords: 10, TypedWords: 10}
+	result, err := service.AddTestData(context.Background(), data, "test@mail.com")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	if len(result.CompletedGoals) != 1 || result.CompletedGoals[0].ID != 1 {
+		t.Fatalf("expected the 60s speed goal to complete, got %+v", result.CompletedGoals)
+	}
+	if len(goals.Updated) != 3 {
+		t.Fatalf("expected 3 goals to change, got %d", len(goals.Updated))
+	}
+	if goals.Active[2].Progress != 4 {
+		t.Fatalf("expected test count 4, got %d", goals.Active[2].Progress)
+	}
+	if practice := goals.Active[3]; practice.Progress != 60 || practice.DaysMet != 1 || practice.CompletedAt != nil {
+		t.Fatalf("expected daily practice met for today, got %+v", practice)
+	}
+	if goals.Active[4].Progress != 3 {
+		t.Fatalf("expected the expired goal not to count the test, got %d", goals.Active[4].Progress)
+	}
+}
@@ -3,8 +3,13 @@ package user
 import (
 	"context"
 	"log"
+	"time"
+	"typing-speed/internals/adapter/external/geoip"
 	"typing-speed/internals/adapter/external/sendmail"
 	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/organization"
+	"typing-speed/internals/core/progress"
+	"typing-speed/internals/core/ranking"
 	"typing-speed/internals/core/user"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,12 +18,23 @@ import (
 type UserServiceImpl struct {
 	userSvc port.UserRepository
 	mailSvc sendmail.MailSender
+	curve   progress.LevelCurve
+	orgSvc  port.OrganizationRepository
+	// rankingSvc serves the top performers from memory when set
+	rankingSvc ranking.RankingService
+	// geoSvc infers the country of users who do not give one, when set
+	geoSvc geoip.Locator
 }
 
-func NewUserService(svc port.UserRepository, mail sendmail.MailSender) user.UserService {
+func NewUserService(svc port.UserRepository, mail sendmail.MailSender, curve progress.LevelCurve,
+	orgs port.OrganizationRepository, ranks ranking.RankingService, geo geoip.Locator) user.UserService {
 	return &UserServiceImpl{
-		userSvc: svc,
-		mailSvc: mail,
+		userSvc:    svc,
+		mailSvc:    mail,
+		curve:      curve,
+		orgSvc:     orgs,
+		rankingSvc: ranks,
+		geoSvc:     geo,
 	}
 }
 
@@ -38,6 +54,32 @@ func (a *UserServiceImpl) RegisterUser(ctx context.Context, userData *user.User)
 		return user.ErrUserAlreadyRegistered
 	}
 
+	// the username is optional at signup and can be picked later
+	if userData.Username != "" {
+		username, err := user.NormalizeUsername(userData.Username)
+		if err != nil {
+			return err
+		}
+		taken, err := a.userSvc.GetUserByUsername(ctx, username)
+		if err != nil {
+			return user.ErrSomethingWentWrong
+		}
+		if taken != nil {
+			return user.ErrUsernameTaken
+		}
+		userData.Username = username
+	}
+
+	country, err := user.NormalizeCountry(userData.Country)
+	if err != nil {
+		return err
+	}
+	userData.Country = country
+	userData.Region = ""
+	if country == "" {
+		a.locate(userData)
+	}
+
 	hash, err := user.HashPassword(userData.Password)
 	if err != nil {
 		return user.ErrSomethingWentWrong
@@ -49,11 +91,34 @@ func (a *UserServiceImpl) RegisterUser(ctx context.Context, userData *user.User)
 		return user.ErrSomethingWentWrong
 	}
 
+	a.joinOrganization(ctx, userData.Email)
+
 	//err = a.mailSvc.SendMail("typing@gmail.com", userData.Email, "Register", "User registered successfully")
 
 	return nil
 }
 
+// joinOrganization adds a new user to the organization that verified their
+// email domain. Signing up does not fail when this does.
+func (a *UserServiceImpl) joinOrganization(ctx context.Context, email string) {
+	if a.orgSvc == nil {
+		return
+	}
+
+	org, err := a.orgSvc.GetOrganizationByDomain(ctx, organization.EmailDomain(email))
+	if err != nil {
+		log.Println("Error getting organization for ", email, ": ", err)
+		return
+	}
+	if org == nil {
+		return
+	}
+
+	if _, err := a.orgSvc.AddMember(ctx, org.ID, email, organization.RoleMember); err != nil {
+		log.Println("Error adding ", email, " to organization ", org.ID, ": ", err)
+	}
+}
+
 // LoginUser userenticates user credentials and generates tokens
 func (a *UserServiceImpl) LoginUser(ctx context.Context, userData *user.LoginUser) (*user.LoginResponse, error) {
 
@@ -136,11 +201,28 @@ func (a *UserServiceImpl) UserByEmail(ctx context.Context, email string) (*user.
 	if err != nil {
 		return nil, user.ErrGettingDataFromDB
 	}
+	if userData != nil {
+		// the stored streak is only written on submission, so drop it once a day was missed
+		userData.Streak = user.CurrentStreak(userData.Streak, userData.LastTestTime, time.Now(), user.Location(userData.TimeZone))
+		userData.Progress = a.curve.Progress(userData.XP)
+	}
 	return userData, nil
 
 }
 
 func (t *UserServiceImpl) TopPerformer(ctx context.Context) ([]*user.TopPerformer, error) {
+	if t.rankingSvc != nil {
+		entries, err := t.rankingSvc.Top(ctx, ranking.TopSize)
+		if err != nil {
+			return nil, user.ErrGettingDataFromDB
+		}
+		data := make([]*user.TopPerformer, 0, len(entries))
+		for _, e := range entries {
+			data = append(data, &user.TopPerformer{Name: e.Name, Username: e.Username, Performance: e.Performance})
+		}
+		return data, nil
+	}
+
 	data, err := t.userSvc.GetTopPerformer(ctx)
 	if err != nil {
 
@@ -167,3 +249,95 @@ func (a *UserServiceImpl) GetDataForDashboard(ctx context.Context) (*user.Dashbo
 	return response, nil
 
 }
+
+// UpdateTimeZone sets the IANA time zone used to evaluate the user's streak
+func (a *UserServiceImpl) UpdateTimeZone(ctx context.Context, email string, timeZone string) error {
+	if timeZone == "" {
+		return user.ErrInvalidTimeZone
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return user.ErrInvalidTimeZone
+	}
+
+	err := a.userSvc.UpdateTimeZone(ctx, email, timeZone)
+	if err != nil {
+		return user.ErrSomethingWentWrong
+	}
+	return nil
+}
+
+// UpdateUsername changes the unique name the user's public profile is found by
+func (a *UserServiceImpl) UpdateUsername(ctx context.Context, email string, username string) error {
+	username, err := user.NormalizeUsername(username)
+	if err != nil {
+		return err
+	}
+
+	updated, err := a.userSvc.UpdateUsername(ctx, email, username)
+	if err != nil {
+		return user.ErrSomethingWentWrong
+	}
+	if !updated {
+		return user.ErrUsernameTaken
+	}
+	a.rank(ctx, email)
+	return nil
+}
+
+// UpdatePrivacy sets who may see the user's profile and find them on leaderboards
+func (a *UserServiceImpl) UpdatePrivacy(ctx context.Context, email string, privacy string) error {
+	if !user.ValidPrivacy(privacy) {
+		return user.ErrInvalidPrivacy
+	}
+
+	err := a.userSvc.UpdatePrivacy(ctx, email, privacy)
+	if err != nil {
+		return user.ErrSomethingWentWrong
+	}
+	a.rank(ctx, email)
+	return nil
+}
+
+// UpdateCountry sets the country the user is ranked in. An empty country
+// clears it.
+func (a *UserServiceImpl) UpdateCountry(ctx context.Context, email string, country string) error {
+	country, err := user.NormalizeCountry(country)
+	if err != nil {
+		return err
+	}
+
+	err = a.userSvc.UpdateCountry(ctx, email, country)
+	if err != nil {
+		return user.ErrSomethingWentWrong
+	}
+	return nil
+}
+
+// locate infers the country and region of a new user from their IP address.
+// Countries the database gets wrong are left out.
+func (a *UserServiceImpl) locate(userData *user.User) {
+	if a.geoSvc == nil || userData.IP == "" {
+		return
+	}
+	country, region, ok := a.geoSvc.Locate(userData.IP)
+	if !ok {
+		return
+	}
+	country, err := user.NormalizeCountry(country)
+	if err != nil || country == "" {
+		return
+	}
+	userData.Country = country
+	userData.Region = region
+}
+
+// rank shows the user's new name or privacy in the ranking right away
+// rather than at the next reconciliation
+func (a *UserServiceImpl) rank(ctx context.Context, email string) {
+	if a.rankingSvc == nil {
+		return
+	}
+	if err := a.rankingSvc.Update(ctx, email); err != nil {
+		log.Println("error ranking", email, ":", err)
+	}
+}
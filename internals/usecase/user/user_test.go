@@ -5,6 +5,9 @@ import (
 	"errors"
 	"testing"
 	"time"
+	"typing-speed/internals/adapter/port"
+	"typing-speed/internals/core/organization"
+	"typing-speed/internals/core/progress"
 	"typing-speed/internals/core/user"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,12 +17,16 @@ type FakeUserRepo struct {
 	GetByEmailFn          func(ctx context.Context, email string) (*user.User, error)
 	CreateFn              func(ctx context.Context, u *user.User) error
 	GetTopPerformerFn     func(ctx context.Context) ([]*user.TopPerformer, error)
-	GetAllUserFn          func(ctx context.Context) ([]*user.User, error)
+	GetAllUserFn          func(ctx context.Context) ([]*user.PublicUser, error)
 	GetDashboardTopDataFn func(ctx context.Context) (*user.DashboardTopData, error)
+	UpdateTimeZoneFn      func(ctx context.Context, email string, timeZone string) error
+	GetByUsernameFn       func(ctx context.Context, username string) (*user.User, error)
+	UpdateUsernameFn      func(ctx context.Context, email string, username string) (bool, error)
+	UpdateCountryFn       func(ctx context.Context, email string, country string) error
 }
 
 // GetAllUser implements port.UserRepository.
-func (f *FakeUserRepo) GetAllUser(ctx context.Context) ([]*user.User, error) {
+func (f *FakeUserRepo) GetAllUser(ctx context.Context) ([]*user.PublicUser, error) {
 	if f.GetAllUserFn != nil {
 		return f.GetAllUserFn(ctx)
 	}
@@ -54,6 +61,57 @@ func (f *FakeUserRepo) GetUserByEmail(ctx context.Context, email string) (*user.
 	return nil, nil
 }
 
+// UpdateStreak implements port.UserRepository.
+func (f *FakeUserRepo) UpdateStreak(ctx context.Context, email string, streak int, longestStreak int) error {
+	return nil
+}
+
+// UpdateTimeZone implements port.UserRepository.
+func (f *FakeUserRepo) UpdateTimeZone(ctx context.Context, email string, timeZone string) error {
+	if f.UpdateTimeZoneFn != nil {
+		return f.UpdateTimeZoneFn(ctx, email, timeZone)
+	}
+	return nil
+}
+
+// UpdateProgress implements port.UserRepository.
+func (f *FakeUserRepo) UpdateProgress(ctx context.Context, email string, xp int, level int) error {
+	return nil
+}
+
+// GetUserByUsername implements port.UserRepository.
+func (f *FakeUserRepo) GetUserByUsername(ctx context.Context, username string) (*user.User, error) {
+	if f.GetByUsernameFn != nil {
+		return f.GetByUsernameFn(ctx, username)
+	}
+	return nil, nil
+}
+
+// UpdateUsername implements port.UserRepository.
+func (f *FakeUserRepo) UpdateUsername(ctx context.Context, email string, username string) (bool, error) {
+	if f.UpdateUsernameFn != nil {
+		return f.UpdateUsernameFn(ctx, email, username)
+	}
+	return true, nil
+}
+
+// UpdatePrivacy implements port.UserRepository.
+func (f *FakeUserRepo) UpdatePrivacy(ctx context.Context, email string, privacy string) error {
+	return nil
+}
+
+// UpdateCountry implements port.UserRepository.
+func (f *FakeUserRepo) UpdateCountry(ctx context.Context, email string, country string) error {
+	if f.UpdateCountryFn != nil {
+		return f.UpdateCountryFn(ctx, email, country)
+	}
+	return nil
+}
+
+func (f *FakeUserRepo) GetUserByEmailForUpdate(ctx context.Context, email string) (*user.User, error) {
+	return f.GetUserByEmail(ctx, email)
+}
+
 func (f *FakeUserRepo) CreateUser(ctx context.Context, u *user.User) error {
 	if f.CreateFn != nil {
 		return f.CreateFn(ctx, u)
@@ -170,7 +228,7 @@ func TestRegisterUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			service := NewUserService(tt.repo, tt.mail)
+			service := NewUserService(tt.repo, tt.mail, progress.LevelCurve{}, nil, nil, nil)
 
 			err := service.RegisterUser(ctx, tt.input)
 
@@ -190,6 +248,99 @@ func TestRegisterUser(t *testing.T) {
 	}
 }
 
+// FakeOrganizationRepo has one organization, which verified acme.io
+type FakeOrganizationRepo struct {
+	port.OrganizationRepository
+	joined []string
+}
+
+func (f *FakeOrganizationRepo) GetOrganizationByDomain(ctx context.Context, domain string) (*organization.Organization, error) {
+	if domain != "acme.io" {
+		return nil, nil
+	}
+	return &organization.Organization{ID: "org-1", Domain: domain, Verified: true}, nil
+}
+
+func (f *FakeOrganizationRepo) AddMember(ctx context.Context, id string, email string, role string) (bool, error) {
+	f.joined = append(f.joined, email)
+	return true, nil
+}
+
+func TestRegisterUserJoinsOrganization(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeUserRepo{
+		CreateFn: func(ctx context.Context, u *user.User) error {
+			return nil
+		},
+	}
+	orgs := &FakeOrganizationRepo{}
+	service := NewUserService(repo, &FakeMailSender{}, progress.LevelCurve{}, orgs, nil, nil)
+
+	for _, email := range []string{"dev@ACME.io", "navneet@gmail.com"} {
+		if err := service.RegisterUser(ctx, &user.User{Name: "Navneet", Email: email, Password: "12345"}); err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+	}
+
+	if len(orgs.joined) != 1 || orgs.joined[0] != "dev@ACME.io" {
+		t.Fatalf("expected only the acme.io user to join, got %v", orgs.joined)
+	}
+}
+
+// FakeLocator places every address in one country
+type FakeLocator struct {
+	country string
+	region  string
+}
+
+func (f *FakeLocator) Locate(ip string) (string, string, bool) {
+	return f.country, f.region, f.country != ""
+}
+
+func TestRegisterUserCountry(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		country         string
+		locator         *FakeLocator
+		expectedCountry string
+		expectedRegion  string
+		expectedError   error
+	}{
+		{name: "given", country: "in", locator: &FakeLocator{country: "DE", region: "Berlin"}, expectedCountry: "IN"},
+		{name: "inferred", locator: &FakeLocator{country: "de", region: "Berlin"}, expectedCountry: "DE", expectedRegion: "Berlin"},
+		{name: "unknown address", locator: &FakeLocator{}},
+		{name: "unassigned inferred", locator: &FakeLocator{country: "ZZ"}},
+		{name: "invalid", country: "India", locator: &FakeLocator{}, expectedError: user.ErrInvalidCountry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *user.User
+			repo := &FakeUserRepo{
+				CreateFn: func(ctx context.Context, u *user.User) error {
+					created = u
+					return nil
+				},
+			}
+			service := NewUserService(repo, &FakeMailSender{}, progress.LevelCurve{}, nil, nil, tt.locator)
+
+			err := service.RegisterUser(ctx, &user.User{Name: "Navneet", Email: "navneet@gmail.com", Password: "12345",
+				Country: tt.country, IP: "81.2.69.142"})
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if created.Country != tt.expectedCountry || created.Region != tt.expectedRegion {
+				t.Fatalf("expected %v %v, got %v %v", tt.expectedCountry, tt.expectedRegion, created.Country, created.Region)
+			}
+		})
+	}
+}
+
 func TestLogin(t *testing.T) {
 	ctx := context.Background()
 
@@ -270,7 +421,7 @@ func TestLogin(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			service := NewUserService(tt.repo, tt.mail)
+			service := NewUserService(tt.repo, tt.mail, progress.LevelCurve{}, nil, nil, nil)
 
 			_, err := service.LoginUser(ctx, tt.input)
 
@@ -327,7 +478,7 @@ func TestUserByEmail(t *testing.T) {
 	}}
 
 	for _, tt := range tests {
-		service := NewUserService(tt.repo, tt.mail)
+		service := NewUserService(tt.repo, tt.mail, progress.LevelCurve{}, nil, nil, nil)
 		_, err := service.UserByEmail(ctx, tt.input.Email)
 		if tt.expectErr {
 			if err == nil {
@@ -379,7 +530,7 @@ func TestTopPerformer(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := NewUserService(tt.repo, nil)
+			service := NewUserService(tt.repo, nil, progress.LevelCurve{}, nil, nil, nil)
 
 			data, err := service.TopPerformer(ctx)
 
@@ -414,7 +565,7 @@ func TestGetDataForDashboard(t *testing.T) {
 		{
 			name: "error while fetching users",
 			repo: &FakeUserRepo{
-				GetAllUserFn: func(ctx context.Context) ([]*user.User, error) {
+				GetAllUserFn: func(ctx context.Context) ([]*user.PublicUser, error) {
 					return nil, errors.New("db error")
 				},
 			},
@@ -424,8 +575,8 @@ func TestGetDataForDashboard(t *testing.T) {
 		{
 			name: "error while fetching dashboard data",
 			repo: &FakeUserRepo{
-				GetAllUserFn: func(ctx context.Context) ([]*user.User, error) {
-					return []*user.User{}, nil
+				GetAllUserFn: func(ctx context.Context) ([]*user.PublicUser, error) {
+					return []*user.PublicUser{}, nil
 				},
 				GetDashboardTopDataFn: func(ctx context.Context) (*user.DashboardTopData, error) {
 					return nil, errors.New("db error")
@@ -437,9 +588,9 @@ func TestGetDataForDashboard(t *testing.T) {
 		{
 			name: "successful dashboard fetch",
 			repo: &FakeUserRepo{
-				GetAllUserFn: func(ctx context.Context) ([]*user.User, error) {
-					return []*user.User{
-						{Email: "test@test.com"},
+				GetAllUserFn: func(ctx context.Context) ([]*user.PublicUser, error) {
+					return []*user.PublicUser{
+						{Name: "test", Username: "test"},
 					}, nil
 				},
 				GetDashboardTopDataFn: func(ctx context.Context) (*user.DashboardTopData, error) {
@@ -456,7 +607,7 @@ func TestGetDataForDashboard(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := NewUserService(tt.repo, nil)
+			service := NewUserService(tt.repo, nil, progress.LevelCurve{}, nil, nil, nil)
 
 			result, err := service.GetDataForDashboard(ctx)
 
@@ -523,7 +674,7 @@ func TestRefreshToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := NewUserService(nil, nil)
+			service := NewUserService(nil, nil, progress.LevelCurve{}, nil, nil, nil)
 
 			accessToken, refreshToken, err := service.RefreshToken(ctx, tt.refreshToken)
 
@@ -545,3 +696,139 @@ func TestRefreshToken(t *testing.T) {
 		})
 	}
 }
+
+func TestUserByEmailStreak(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		lastTest *time.Time
+		expected int
+	}{
+		{name: "streak alive after yesterday's test", lastTest: &yesterday, expected: 4},
+		{name: "streak reset after a missed day", lastTest: &lastWeek, expected: 0},
+		{name: "no tests yet", lastTest: nil, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FakeUserRepo{
+				GetByEmailFn: func(ctx context.Context, email string) (*user.User, error) {
+					return &user.User{Streak: 4, LongestStreak: 9, LastTestTime: tt.lastTest, TimeZone: "UTC"}, nil
+				},
+			}
+			service := NewUserService(repo, &FakeMailSender{}, progress.LevelCurve{}, nil, nil, nil)
+
+			data, err := service.UserByEmail(ctx, "navneet@gmail.com")
+			if err != nil {
+				t.Fatalf("expected success, got error")
+			}
+			if data.Streak != tt.expected {
+				t.Fatalf("expected streak %d, got %d", tt.expected, data.Streak)
+			}
+			if data.LongestStreak != 9 {
+				t.Fatalf("expected longest streak 9, got %d", data.LongestStreak)
+			}
+		})
+	}
+}
+
+func TestUpdateTimeZone(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		timeZone      string
+		repo          *FakeUserRepo
+		expectedError error
+	}{
+		{name: "empty time zone", timeZone: "", repo: &FakeUserRepo{}, expectedError: user.ErrInvalidTimeZone},
+		{name: "unknown time zone", timeZone: "Mars/Olympus", repo: &FakeUserRepo{}, expectedError: user.ErrInvalidTimeZone},
+		{
+			name:     "db error",
+			timeZone: "Asia/Kolkata",
+			repo: &FakeUserRepo{
+				UpdateTimeZoneFn: func(ctx context.Context, email string, timeZone string) error {
+					return errors.New("db error")
+				},
+			},
+			expectedError: user.ErrSomethingWentWrong,
+		},
+		{name: "success", timeZone: "Asia/Kolkata", repo: &FakeUserRepo{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewUserService(tt.repo, &FakeMailSender{}, progress.LevelCurve{}, nil, nil, nil)
+
+			err := service.UpdateTimeZone(ctx, "navneet@gmail.com", tt.timeZone)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestUpdateUsername(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		username      string
+		repo          *FakeUserRepo
+		expectedError error
+	}{
+		{name: "too short", username: "ab", repo: &FakeUserRepo{}, expectedError: user.ErrInvalidUsername},
+		{name: "starts with a digit", username: "1navneet", repo: &FakeUserRepo{}, expectedError: user.ErrInvalidUsername},
+		{name: "invalid character", username: "nav-neet", repo: &FakeUserRepo{}, expectedError: user.ErrInvalidUsername},
+		{name: "reserved", username: "Admin", repo: &FakeUserRepo{}, expectedError: user.ErrReservedUsername},
+		{name: "profane", username: "sh1t_typer", repo: &FakeUserRepo{}, expectedError: user.ErrReservedUsername},
+		{
+			name:     "taken",
+			username: "navneet",
+			repo: &FakeUserRepo{
+				UpdateUsernameFn: func(ctx context.Context, email string, username string) (bool, error) {
+					return false, nil
+				},
+			},
+			expectedError: user.ErrUsernameTaken,
+		},
+		{
+			name:     "success",
+			username: " Navneet_01 ",
+			repo: &FakeUserRepo{
+				UpdateUsernameFn: func(ctx context.Context, email string, username string) (bool, error) {
+					if username != "navneet_01" {
+						t.Fatalf("expected navneet_01, got %v", username)
+					}
+					return true, nil
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewUserService(tt.repo, &FakeMailSender{}, progress.LevelCurve{}, nil, nil, nil)
+
+			err := service.UpdateUsername(ctx, "navneet@gmail.com", tt.username)
+			if err != tt.expectedError {
+				t.Fatalf("expected %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestUpdatePrivacy(t *testing.T) {
+	service := NewUserService(&FakeUserRepo{}, &FakeMailSender{}, progress.LevelCurve{}, nil, nil, nil)
+
+	if err := service.UpdatePrivacy(context.Background(), "navneet@gmail.com", "secret"); err != user.ErrInvalidPrivacy {
+		t.Fatalf("expected %v, got %v", user.ErrInvalidPrivacy, err)
+	}
+	if err := service.UpdatePrivacy(context.Background(), "navneet@gmail.com", user.PrivacyFriends); err != nil {
+		t.Fatalf("expected %v, got %v", nil, err)
+	}
+}
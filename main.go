@@ -8,12 +8,31 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
+	"typing-speed/internals/adapter/external/geoip"
 	"typing-speed/internals/adapter/external/sendmail"
 	db "typing-speed/internals/adapter/persistence"
+	"typing-speed/internals/core/leaderboard"
+	"typing-speed/internals/core/progress"
+	"typing-speed/internals/core/ranking"
 	routes "typing-speed/internals/interface/rest/api"
 	"typing-speed/internals/interface/rest/api/handler"
+	achievementSvc "typing-speed/internals/usecase/achievement"
+	challengeSvc "typing-speed/internals/usecase/challenge"
+	classSvc "typing-speed/internals/usecase/classroom"
+	feedSvc "typing-speed/internals/usecase/feed"
+	friendSvc "typing-speed/internals/usecase/friend"
+	goalSvc "typing-speed/internals/usecase/goal"
+	leaderboardSvc "typing-speed/internals/usecase/leaderboard"
+	organizationSvc "typing-speed/internals/usecase/organization"
+	profileSvc "typing-speed/internals/usecase/profile"
+	raceSvc "typing-speed/internals/usecase/race"
+	rankingSvc "typing-speed/internals/usecase/ranking"
+	replaySvc "typing-speed/internals/usecase/replay"
+	statsSvc "typing-speed/internals/usecase/stats"
+	tournamentSvc "typing-speed/internals/usecase/tournament"
 	typeSvc "typing-speed/internals/usecase/typing"
 	userSvc "typing-speed/internals/usecase/user"
 	"typing-speed/pkg/logs"
@@ -43,13 +62,108 @@ func main() {
 	}
 	var mailSvc sendmail.MailSender
 	//mailSvc = sendmail.NewGoMail("localhost", 1025)
+
+	// LEVEL_BASE_XP and LEVEL_EXPONENT tune the level curve
+	baseXP, _ := strconv.Atoi(os.Getenv("LEVEL_BASE_XP"))
+	exponent, _ := strconv.ParseFloat(os.Getenv("LEVEL_EXPONENT"), 64)
+	levelCurve := progress.NewLevelCurve(baseXP, exponent)
+
+	// the top performers are ranked in memory, loaded here and reconciled
+	// against the database every few minutes
+	rankingDBService := db.NewRankingRepository(dbConn)
+	rankingUseCase := rankingSvc.NewRankingService(rankingDBService)
+	go func() {
+		ticker := time.NewTicker(ranking.ReconcileInterval)
+		defer ticker.Stop()
+		for {
+			if err := rankingUseCase.Reconcile(context.Background()); err != nil {
+				log.Println("error reconciling the ranking:", err)
+			}
+			<-ticker.C
+		}
+	}()
+
+	// GEOIP_CSV optionally points at a GeoIP database that the country of new
+	// users is inferred from
+	var locator geoip.Locator
+	if path := os.Getenv("GEOIP_CSV"); path != "" {
+		geoDB, err := geoip.Load(path)
+		if err != nil {
+			log.Println("Error loading the GeoIP database:", err)
+		} else {
+			locator = geoDB
+		}
+	}
+
 	userDBService := db.NewUserRepository(dbConn)
-	userUseCase := userSvc.NewUserService(userDBService, mailSvc)
+	organizationDBService := db.NewOrganizationRepository(dbConn)
+	userUseCase := userSvc.NewUserService(userDBService, mailSvc, levelCurve, organizationDBService, rankingUseCase,
+		locator)
 
 	typingDBService := db.NewTestRepository(dbConn)
-	typingUseCase := typeSvc.NewTypingService(userDBService, mailSvc, typingDBService)
+	transactor := db.NewTransactor(dbConn)
+	achievementDBService := db.NewAchievementRepository(dbConn)
+	personalBestDBService := db.NewPersonalBestRepository(dbConn)
+	goalDBService := db.NewGoalRepository(dbConn)
+	feedDBService := db.NewFeedRepository(dbConn)
+	feedUseCase := feedSvc.NewFeedService(feedDBService, userDBService, transactor)
+	typingUseCase := typeSvc.NewTypingService(userDBService, mailSvc, typingDBService, transactor,
+		achievementDBService, personalBestDBService, goalDBService, levelCurve, feedUseCase,
+		rankingUseCase)
+
+	achievementUseCase := achievementSvc.NewAchievementService(achievementDBService)
+
+	challengeDBService := db.NewChallengeRepository(dbConn)
+	challengeUseCase := challengeSvc.NewChallengeService(challengeDBService)
+
+	goalUseCase := goalSvc.NewGoalService(goalDBService, userDBService)
+
+	replayDBService := db.NewReplayRepository(dbConn)
+	replayUseCase := replaySvc.NewReplayService(replayDBService)
+
+	raceDBService := db.NewRaceRepository(dbConn)
+	ratingDBService := db.NewRatingRepository(dbConn)
+	raceUseCase := raceSvc.NewRaceService(userDBService, raceDBService, ratingDBService, transactor, typingUseCase,
+		feedUseCase, raceSvc.Config{})
+
+	tournamentDBService := db.NewTournamentRepository(dbConn)
+	tournamentUseCase := tournamentSvc.NewTournamentService(tournamentDBService, ratingDBService,
+		personalBestDBService, transactor, raceUseCase)
+	// match rooms live in memory, so the live matches get new ones
+	if err := tournamentUseCase.Resume(context.Background()); err != nil {
+		log.Println("Error resuming tournaments:", err)
+	}
+
+	friendDBService := db.NewFriendRepository(dbConn)
+	friendUseCase := friendSvc.NewFriendService(friendDBService, userDBService, personalBestDBService, transactor)
+
+	organizationUseCase := organizationSvc.NewOrganizationService(organizationDBService, userDBService, transactor)
+
+	classDBService := db.NewClassRepository(dbConn)
+	classUseCase := classSvc.NewClassService(classDBService, typingUseCase)
+
+	profileUseCase := profileSvc.NewProfileService(userDBService, personalBestDBService, typingDBService,
+		friendDBService)
+
+	leaderboardDBService := db.NewLeaderboardRepository(dbConn)
+	leaderboardUseCase := leaderboardSvc.NewLeaderboardService(leaderboardDBService)
+	go func() {
+		ticker := time.NewTicker(leaderboard.DistributionRefresh)
+		defer ticker.Stop()
+		for {
+			if err := leaderboardUseCase.RefreshDistributions(context.Background()); err != nil {
+				log.Println("error refreshing the leaderboard distributions:", err)
+			}
+			<-ticker.C
+		}
+	}()
+
+	statsDBService := db.NewStatsRepository(dbConn)
+	statsUseCase := statsSvc.NewStatsService(userDBService, statsDBService)
 
-	handler := handler.NewHandler(typingUseCase, userUseCase, logChan)
+	handler := handler.NewHandler(typingUseCase, userUseCase, achievementUseCase, challengeUseCase, goalUseCase,
+		replayUseCase, raceUseCase, tournamentUseCase, friendUseCase, organizationUseCase, classUseCase,
+		profileUseCase, feedUseCase, leaderboardUseCase, rankingUseCase, statsUseCase, logChan)
 	router := routes.SetUpRoutes(handler)
 
 	port := os.Getenv("PORT")
@@ -21,30 +21,47 @@ func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		tokenString := c.GetHeader("Authorization")
-		if tokenString == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token missing"})
-			c.Abort()
-			return
-		}
+		authenticate(c, tokenString)
+	}
+}
 
-		token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, func(t *jwt.Token) (interface{}, error) {
-			return []byte(ACCESS_SECRET), nil
-		})
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
-			return
-		}
+// WebSocketAuthMiddleware also accepts the token as a query parameter,
+// because browsers cannot set headers on a WebSocket handshake
+func WebSocketAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
 
-		claims, ok := token.Claims.(*AccessClaims)
-		if !ok || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
-			return
+		tokenString := c.GetHeader("Authorization")
+		if tokenString == "" {
+			tokenString = c.Query("token")
 		}
+		authenticate(c, tokenString)
+	}
+}
 
-		c.Set("email", claims.Email)
+func authenticate(c *gin.Context, tokenString string) {
+	if tokenString == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token missing"})
+		c.Abort()
+		return
+	}
 
-		c.Next()
+	token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, func(t *jwt.Token) (interface{}, error) {
+		return []byte(ACCESS_SECRET), nil
+	})
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return
 	}
+
+	claims, ok := token.Claims.(*AccessClaims)
+	if !ok || !token.Valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+		c.Abort()
+		return
+	}
+
+	c.Set("email", claims.Email)
+
+	c.Next()
 }
@@ -0,0 +1,5 @@
+ALTER TABLE users
+ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+ADD COLUMN longest_streak INTEGER NOT NULL DEFAULT 0 CHECK (longest_streak >= 0);
+
+UPDATE users SET longest_streak = streak;
@@ -0,0 +1,3 @@
+ALTER TABLE user_typing_data
+DROP COLUMN mode,
+DROP COLUMN language;
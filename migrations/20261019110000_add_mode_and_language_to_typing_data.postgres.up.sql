@@ -0,0 +1,12 @@
+ALTER TABLE user_typing_data
+ADD COLUMN mode VARCHAR(16) NOT NULL DEFAULT 'custom',
+ADD COLUMN language VARCHAR(32) NOT NULL DEFAULT 'english';
+
+UPDATE user_typing_data
+SET mode = CASE total_time
+    WHEN 15 THEN '15s'
+    WHEN 30 THEN '30s'
+    WHEN 60 THEN '60s'
+    WHEN 120 THEN '120s'
+    ELSE 'custom'
+END;
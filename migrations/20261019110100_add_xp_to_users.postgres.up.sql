@@ -0,0 +1,2 @@
+ALTER TABLE users
+ADD COLUMN xp INTEGER NOT NULL DEFAULT 0 CHECK (xp >= 0);
@@ -0,0 +1 @@
+Drop table if exists user_achievements;
@@ -0,0 +1,11 @@
+CREATE TABLE user_achievements (
+    email VARCHAR(255) NOT NULL,
+    code VARCHAR(64) NOT NULL,
+    unlocked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (email, code),
+    CONSTRAINT fk_user_achievements_email
+        FOREIGN KEY (email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_typing_data_challenge;
+
+ALTER TABLE user_typing_data
+DROP COLUMN challenge_date;
@@ -0,0 +1,6 @@
+ALTER TABLE user_typing_data
+ADD COLUMN challenge_date DATE;
+
+CREATE INDEX idx_user_typing_data_challenge
+    ON user_typing_data (challenge_date, email, created_at)
+    WHERE challenge_date IS NOT NULL;
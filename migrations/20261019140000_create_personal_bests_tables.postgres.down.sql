@@ -0,0 +1,2 @@
+Drop table if exists personal_best_history;
+Drop table if exists personal_bests;
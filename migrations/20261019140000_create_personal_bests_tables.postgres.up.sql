@@ -0,0 +1,43 @@
+CREATE TABLE personal_bests (
+    email VARCHAR(255) NOT NULL,
+    mode VARCHAR(16) NOT NULL,
+    language VARCHAR(32) NOT NULL,
+    wpm INT NOT NULL,
+    accuracy INT NOT NULL,
+    test_id UUID NOT NULL REFERENCES user_typing_data(id) ON DELETE CASCADE,
+    achieved_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (email, mode, language),
+    CONSTRAINT fk_personal_bests_email
+        FOREIGN KEY (email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE TABLE personal_best_history (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    mode VARCHAR(16) NOT NULL,
+    language VARCHAR(32) NOT NULL,
+    wpm INT NOT NULL,
+    accuracy INT NOT NULL,
+    previous_wpm INT NOT NULL DEFAULT 0,
+    test_id UUID NOT NULL REFERENCES user_typing_data(id) ON DELETE CASCADE,
+    achieved_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_personal_best_history_email
+        FOREIGN KEY (email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_personal_best_history_email ON personal_best_history (email, achieved_at DESC);
+
+-- seed the current personal bests from the tests already taken
+INSERT INTO personal_bests (email, mode, language, wpm, accuracy, test_id, achieved_at)
+SELECT DISTINCT ON (email, mode, language)
+       email, mode, language, wpm,
+       CASE WHEN total_words = 0 THEN 0 ELSE (typed_words - total_error) * 100 / total_words END,
+       id, created_at
+FROM user_typing_data
+ORDER BY email, mode, language, wpm DESC, created_at ASC;
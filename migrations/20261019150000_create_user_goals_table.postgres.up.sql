@@ -0,0 +1,21 @@
+CREATE TABLE user_goals (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    target INT NOT NULL CHECK (target > 0),
+    mode VARCHAR(16) NOT NULL DEFAULT '',
+    deadline TIMESTAMPTZ,
+    baseline INT NOT NULL DEFAULT 0,
+    progress INT NOT NULL DEFAULT 0,
+    progress_day DATE,
+    days_met INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user_goals_email
+        FOREIGN KEY (email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_user_goals_email ON user_goals (email) WHERE completed_at IS NULL;
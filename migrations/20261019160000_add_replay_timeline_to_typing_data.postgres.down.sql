@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_user_typing_data_share_token;
+
+ALTER TABLE user_typing_data
+DROP COLUMN share_token,
+DROP COLUMN timeline;
@@ -0,0 +1,7 @@
+ALTER TABLE user_typing_data
+ADD COLUMN timeline BYTEA,
+ADD COLUMN share_token VARCHAR(32);
+
+CREATE UNIQUE INDEX idx_user_typing_data_share_token
+    ON user_typing_data (share_token)
+    WHERE share_token IS NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE user_typing_data
+DROP COLUMN race_id;
+
+Drop table if exists race_results;
+Drop table if exists races;
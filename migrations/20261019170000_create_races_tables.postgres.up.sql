@@ -0,0 +1,27 @@
+CREATE TABLE races (
+    id UUID PRIMARY KEY,
+    text TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE race_results (
+    race_id UUID NOT NULL REFERENCES races(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    wpm INT NOT NULL,
+    accuracy INT NOT NULL,
+    duration DOUBLE PRECISION NOT NULL,
+    test_id UUID REFERENCES user_typing_data(id) ON DELETE SET NULL,
+    PRIMARY KEY (race_id, email),
+    CONSTRAINT fk_race_results_email
+        FOREIGN KEY (email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_race_results_email ON race_results (email);
+
+ALTER TABLE user_typing_data
+ADD COLUMN race_id UUID REFERENCES races(id) ON DELETE SET NULL;
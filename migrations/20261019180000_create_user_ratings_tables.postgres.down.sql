@@ -0,0 +1,5 @@
+ALTER TABLE races
+DROP COLUMN rated;
+
+Drop table if exists rating_history;
+Drop table if exists user_ratings;
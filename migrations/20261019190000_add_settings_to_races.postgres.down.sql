@@ -0,0 +1,3 @@
+ALTER TABLE races
+DROP COLUMN mode,
+DROP COLUMN language;
@@ -0,0 +1,3 @@
+ALTER TABLE races
+ADD COLUMN mode VARCHAR(50) NOT NULL DEFAULT 'custom',
+ADD COLUMN language VARCHAR(50) NOT NULL DEFAULT 'english';
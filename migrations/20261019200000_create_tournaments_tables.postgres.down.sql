@@ -0,0 +1,3 @@
+Drop table if exists tournament_matches;
+Drop table if exists tournament_entrants;
+Drop table if exists tournaments;
@@ -0,0 +1,52 @@
+CREATE TABLE tournaments (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    format VARCHAR(30) NOT NULL,
+    seeding VARCHAR(30) NOT NULL,
+    mode VARCHAR(50) NOT NULL,
+    best_of INT NOT NULL DEFAULT 1,
+    max_entrants INT NOT NULL,
+    registration_opens_at TIMESTAMPTZ NOT NULL,
+    registration_closes_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'registration',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    CONSTRAINT fk_tournaments_created_by
+        FOREIGN KEY (created_by)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_tournaments_status ON tournaments (status);
+
+CREATE TABLE tournament_entrants (
+    tournament_id UUID NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    seed INT NOT NULL DEFAULT 0,
+    seed_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    registered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tournament_id, email),
+    CONSTRAINT fk_tournament_entrants_email
+        FOREIGN KEY (email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE TABLE tournament_matches (
+    id UUID PRIMARY KEY,
+    tournament_id UUID NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    round INT NOT NULL,
+    slot INT NOT NULL,
+    player_a VARCHAR(255),
+    player_b VARCHAR(255),
+    winner VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    room_id UUID,
+    race_id UUID,
+    finished_at TIMESTAMPTZ,
+    UNIQUE (tournament_id, round, slot)
+);
@@ -0,0 +1,3 @@
+Drop table if exists user_blocks;
+Drop table if exists friend_requests;
+Drop table if exists user_follows;
@@ -0,0 +1,61 @@
+CREATE TABLE user_follows (
+    follower VARCHAR(255) NOT NULL,
+    followee VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower, followee),
+    CHECK (follower <> followee),
+    CONSTRAINT fk_user_follows_follower
+        FOREIGN KEY (follower)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE,
+    CONSTRAINT fk_user_follows_followee
+        FOREIGN KEY (followee)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_user_follows_followee ON user_follows (followee);
+
+CREATE TABLE friend_requests (
+    id BIGSERIAL PRIMARY KEY,
+    from_email VARCHAR(255) NOT NULL,
+    to_email VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMPTZ,
+    CHECK (from_email <> to_email),
+    CONSTRAINT fk_friend_requests_from
+        FOREIGN KEY (from_email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE,
+    CONSTRAINT fk_friend_requests_to
+        FOREIGN KEY (to_email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+-- at most one open request from one user to another
+CREATE UNIQUE INDEX idx_friend_requests_pending ON friend_requests (from_email, to_email) WHERE status = 'pending';
+CREATE INDEX idx_friend_requests_to ON friend_requests (to_email) WHERE status = 'pending';
+
+CREATE TABLE user_blocks (
+    blocker VARCHAR(255) NOT NULL,
+    blocked VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker, blocked),
+    CHECK (blocker <> blocked),
+    CONSTRAINT fk_user_blocks_blocker
+        FOREIGN KEY (blocker)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE,
+    CONSTRAINT fk_user_blocks_blocked
+        FOREIGN KEY (blocked)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
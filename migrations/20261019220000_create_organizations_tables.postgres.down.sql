@@ -0,0 +1,4 @@
+Drop table if exists team_members;
+Drop table if exists teams;
+Drop table if exists organization_members;
+Drop table if exists organizations;
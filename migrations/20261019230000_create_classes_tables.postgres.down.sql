@@ -0,0 +1,4 @@
+Drop table if exists class_submissions;
+Drop table if exists class_assignments;
+Drop table if exists class_students;
+Drop table if exists classes;
@@ -0,0 +1,59 @@
+CREATE TABLE classes (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    teacher VARCHAR(255) NOT NULL,
+    join_code VARCHAR(16) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_classes_teacher
+        FOREIGN KEY (teacher)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_classes_teacher ON classes (teacher);
+
+CREATE TABLE class_students (
+    class_id UUID NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (class_id, email),
+    CONSTRAINT fk_class_students_email
+        FOREIGN KEY (email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_class_students_email ON class_students (email);
+
+CREATE TABLE class_assignments (
+    id UUID PRIMARY KEY,
+    class_id UUID NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    title VARCHAR(100) NOT NULL,
+    mode VARCHAR(50) NOT NULL,
+    text TEXT NOT NULL,
+    min_accuracy INT NOT NULL DEFAULT 0,
+    due_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_class_assignments_class ON class_assignments (class_id, due_at);
+
+-- each submission is a test the student took for the assignment
+CREATE TABLE class_submissions (
+    test_id UUID PRIMARY KEY REFERENCES user_typing_data(id) ON DELETE CASCADE,
+    assignment_id UUID NOT NULL REFERENCES class_assignments(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    wpm INT NOT NULL,
+    accuracy INT NOT NULL,
+    passed BOOLEAN NOT NULL,
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_class_submissions_email
+        FOREIGN KEY (email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_class_submissions_assignment ON class_submissions (assignment_id, email);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_username;
+
+ALTER TABLE users
+DROP COLUMN username,
+DROP COLUMN privacy;
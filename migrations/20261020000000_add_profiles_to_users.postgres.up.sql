@@ -0,0 +1,6 @@
+ALTER TABLE users
+ADD COLUMN username VARCHAR(20),
+ADD COLUMN privacy VARCHAR(20) NOT NULL DEFAULT 'public' CHECK (privacy IN ('public', 'friends', 'private'));
+
+-- usernames are stored lowercase, so this also ignores case
+CREATE UNIQUE INDEX idx_users_username ON users (username);
@@ -0,0 +1,3 @@
+Drop table if exists feed_mutes;
+Drop table if exists feed_items;
+Drop table if exists feed_events;
@@ -0,0 +1,50 @@
+CREATE TABLE feed_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    mode VARCHAR(20),
+    language VARCHAR(32),
+    wpm INTEGER,
+    achievement VARCHAR(100),
+    level INTEGER,
+    race_id UUID REFERENCES races(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_feed_events_actor
+        FOREIGN KEY (actor)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+-- an event is copied into the feed of every follower when it happens
+CREATE TABLE feed_items (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    event_id BIGINT NOT NULL REFERENCES feed_events(id) ON DELETE CASCADE,
+    CONSTRAINT fk_feed_items_email
+        FOREIGN KEY (email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_feed_items_email ON feed_items (email, id DESC);
+CREATE INDEX idx_feed_items_event ON feed_items (event_id);
+
+CREATE TABLE feed_mutes (
+    email VARCHAR(255) NOT NULL,
+    muted VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (email, muted),
+    CHECK (email <> muted),
+    CONSTRAINT fk_feed_mutes_email
+        FOREIGN KEY (email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE,
+    CONSTRAINT fk_feed_mutes_muted
+        FOREIGN KEY (muted)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_user_typing_data_leaderboard;
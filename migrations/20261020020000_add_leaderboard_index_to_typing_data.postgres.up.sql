@@ -0,0 +1,3 @@
+-- leaderboards scan the tests of one mode and language in a time window
+CREATE INDEX idx_user_typing_data_leaderboard
+    ON user_typing_data (mode, language, created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_country;
+
+ALTER TABLE users
+DROP COLUMN country,
+DROP COLUMN region;
@@ -0,0 +1,6 @@
+ALTER TABLE users
+ADD COLUMN country CHAR(2),
+ADD COLUMN region VARCHAR(64);
+
+CREATE INDEX idx_users_country
+    ON users (country, region);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_typing_data_history;
+DROP INDEX IF EXISTS idx_user_typing_data_history_wpm;
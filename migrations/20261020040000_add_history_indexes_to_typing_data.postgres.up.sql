@@ -0,0 +1,6 @@
+-- keyset pagination of a user's test history, by date and by speed
+CREATE INDEX idx_user_typing_data_history
+    ON user_typing_data (email, created_at DESC, id DESC);
+
+CREATE INDEX idx_user_typing_data_history_wpm
+    ON user_typing_data (email, wpm DESC, created_at DESC, id DESC);
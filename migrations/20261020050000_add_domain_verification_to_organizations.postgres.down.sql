@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_organizations_verified_domain;
+
+ALTER TABLE organizations
+DROP COLUMN domain_token,
+DROP COLUMN domain_verified,
+ADD CONSTRAINT organizations_domain_key UNIQUE (domain);
@@ -0,0 +1,14 @@
+-- a domain only counts once a DNS TXT record proves the organization owns it,
+-- so claims made before this have to be verified again
+ALTER TABLE organizations
+DROP CONSTRAINT IF EXISTS organizations_domain_key,
+ADD COLUMN domain_token VARCHAR(64),
+ADD COLUMN domain_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE organizations
+SET domain_token = gen_random_uuid()::text
+WHERE domain IS NOT NULL;
+
+CREATE UNIQUE INDEX idx_organizations_verified_domain
+    ON organizations (domain)
+    WHERE domain_verified;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_feed_events_actor;
+DROP INDEX IF EXISTS idx_feed_items_email_event;
@@ -0,0 +1,4 @@
+-- feeds are paged by event, and the events of actors with too many
+-- followers to fan out to are pulled straight from feed_events
+CREATE INDEX idx_feed_items_email_event ON feed_items (email, event_id DESC);
+CREATE INDEX idx_feed_events_actor ON feed_events (actor, id DESC);